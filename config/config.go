@@ -3,11 +3,26 @@ package config
 import "os"
 
 var (
-	PORT           = os.Getenv("PORT")
-	JWT_SECRET     = os.Getenv("JWT_SECRET")
-	MYSQL_USER     = os.Getenv("MYSQL_USER")
-	MYSQL_PASSWORD = os.Getenv("MYSQL_PASSWORD")
-	MYSQL_HOST     = os.Getenv("MYSQL_HOST")
-	MYSQL_PORT     = os.Getenv("MYSQL_PORT")
-	MYSQL_DATABASE = os.Getenv("MYSQL_DATABASE")
+	PORT                       = os.Getenv("PORT")
+	REQUEST_TIMEOUT            = os.Getenv("REQUEST_TIMEOUT")
+	ORDER_EXPIRY_SLA           = os.Getenv("ORDER_EXPIRY_SLA")
+	ORDER_EXPIRY_INTERVAL      = os.Getenv("ORDER_EXPIRY_INTERVAL")
+	IDEMPOTENCY_KEY_TTL        = os.Getenv("IDEMPOTENCY_KEY_TTL")
+	PAYMENT_GATEWAY            = os.Getenv("PAYMENT_GATEWAY")
+	PAYMENT_GATEWAY_URL        = os.Getenv("PAYMENT_GATEWAY_URL")
+	PAYMENT_GATEWAY_KEY        = os.Getenv("PAYMENT_GATEWAY_KEY")
+	PAYMENT_CURRENCY           = os.Getenv("PAYMENT_CURRENCY")
+	PAYMENT_WEBHOOK_SECRET     = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	SHIPPING_RATE_TABLE        = os.Getenv("SHIPPING_RATE_TABLE")
+	SHIPMENT_TRACKING_INTERVAL = os.Getenv("SHIPMENT_TRACKING_INTERVAL")
+	COURIER_TRACKER            = os.Getenv("COURIER_TRACKER")
+	TAX_RULES                  = os.Getenv("TAX_RULES")
+	SALES_ROLLUP_INTERVAL      = os.Getenv("SALES_ROLLUP_INTERVAL")
+	REPORT_CACHE_TTL           = os.Getenv("REPORT_CACHE_TTL")
+	JWT_SECRET                 = os.Getenv("JWT_SECRET")
+	MYSQL_USER                 = os.Getenv("MYSQL_USER")
+	MYSQL_PASSWORD             = os.Getenv("MYSQL_PASSWORD")
+	MYSQL_HOST                 = os.Getenv("MYSQL_HOST")
+	MYSQL_PORT                 = os.Getenv("MYSQL_PORT")
+	MYSQL_DATABASE             = os.Getenv("MYSQL_DATABASE")
 )
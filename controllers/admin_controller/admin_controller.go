@@ -0,0 +1,77 @@
+package admincontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/config"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type AdminController interface {
+	Login(c *fiber.Ctx) error
+}
+
+type adminController struct {
+	adminUsecase entity.AdminUseCase
+	validate     *validator.Validate
+}
+
+// NewAdminController will create a object with AdminController interface representation
+func NewAdminController(u entity.AdminUseCase, v *validator.Validate) AdminController {
+	return &adminController{
+		adminUsecase: u,
+		validate:     v,
+	}
+}
+
+// Login issues an admin token
+func (actr *adminController) Login(c *fiber.Ctx) error {
+	loginReq := new(entity.AdminDTOLogin)
+	if err := c.BodyParser(loginReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := actr.validate.Struct(loginReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	admin, err := actr.adminUsecase.Login(c.UserContext(), &entity.Admin{
+		Email:    loginReq.Email,
+		Password: loginReq.Password,
+	})
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	// create jwt token
+	jwtUserType := helpers.ADMIN_TYPE
+	token, tokenErr := helpers.GenerateToken(&helpers.UserJWTPayload{
+		ID:    admin.ID,
+		Email: admin.Email,
+		Name:  admin.Name,
+		Type:  jwtUserType,
+	}, []byte(config.JWT_SECRET))
+	if tokenErr != nil {
+		rErr := resterrors.NewInternalServerError("generate token error ", tokenErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	res := helpers.JWTResponse{
+		Data: entity.AdminDTOResponse{
+			ID:    admin.ID,
+			Email: admin.Email,
+			Name:  admin.Name,
+		},
+		Type:  jwtUserType,
+		Token: token,
+	}
+	return c.Status(http.StatusCreated).JSON(res)
+}
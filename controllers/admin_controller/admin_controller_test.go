@@ -0,0 +1,83 @@
+package admincontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	admincontroller "github.com/hieronimusbudi/komodo-backend/controllers/admin_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockAdminUCase *mocks.AdminUseCase
+	app            *fiber.App
+	validate       *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockAdminUCase = new(mocks.AdminUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+
+	handler := admincontroller.NewAdminController(suite.mockAdminUCase, suite.validate)
+	suite.app.Post("/admins/login", handler.Login)
+}
+
+func TestAdminController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) login(body string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, "/admins/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	return resp
+}
+
+func (suite *TestSuite) TestLogin() {
+	suite.mockAdminUCase.On("Login", mock.Anything, &entity.Admin{Email: "admin@mail.com", Password: "12345"}).
+		Return(entity.Admin{ID: 1, Email: "admin@mail.com", Name: "john admin"}, nil).Once()
+
+	resp := suite.login(`{"email":"admin@mail.com","password":"12345"}`)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	var res struct {
+		Data  entity.AdminDTOResponse `json:"data"`
+		Type  helpers.UserTypeEnum    `json:"type"`
+		Token string                  `json:"token"`
+	}
+	suite.NoError(json.Unmarshal(body, &res))
+	suite.Equal(helpers.ADMIN_TYPE, res.Type)
+	suite.Equal(int64(1), res.Data.ID)
+	suite.NotEmpty(res.Token)
+}
+
+func (suite *TestSuite) TestLoginWrongPassword() {
+	suite.mockAdminUCase.On("Login", mock.Anything, mock.Anything).
+		Return(entity.Admin{}, resterrors.NewUnauthorizedError("invalid email or password")).Once()
+
+	resp := suite.login(`{"email":"admin@mail.com","password":"54321"}`)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *TestSuite) TestLoginInvalidRequest() {
+	resp := suite.login(`{"email":"admin","password":""}`)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.mockAdminUCase.AssertNotCalled(suite.T(), "Login", mock.Anything, mock.Anything)
+}
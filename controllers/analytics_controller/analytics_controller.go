@@ -0,0 +1,109 @@
+package analyticscontroller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const dateLayout = "2006-01-02"
+
+type AnalyticsController interface {
+	SellerSales(c *fiber.Ctx) error
+}
+
+type analyticsController struct {
+	analyticsUsecase entity.AnalyticsUseCase
+	validate         *validator.Validate
+}
+
+// NewAnalyticsController will create a object with AnalyticsController interface representation
+func NewAnalyticsController(a entity.AnalyticsUseCase, v *validator.Validate) AnalyticsController {
+	return &analyticsController{
+		analyticsUsecase: a,
+		validate:         v,
+	}
+}
+
+// SellerSales returns the sales of the logged in seller from from to to, both days included
+func (actr *analyticsController) SellerSales(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse & validate range from query string
+	salesReq := new(entity.SalesAnalyticsDTORequest)
+	if err := c.QueryParser(salesReq); err != nil {
+		rErr := resterrors.NewBadRequestError(err.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	vErr := actr.validate.Struct(salesReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	filter := entity.SalesFilter{Group: entity.SalesGroupEnum(salesReq.Group), Top: salesReq.Top}
+	if salesReq.From != "" {
+		filter.From, _ = time.Parse(dateLayout, salesReq.From)
+	}
+	if salesReq.To != "" {
+		to, _ := time.Parse(dateLayout, salesReq.To)
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	sales, err := actr.analyticsUsecase.GetSellerSales(c.UserContext(), user.ID, filter)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	// transform SalesAnalytics to SalesAnalyticsDTOResponse
+	res := entity.SalesAnalyticsDTOResponse{
+		From:        sales.Filter.From.Format(dateLayout),
+		To:          sales.Filter.To.AddDate(0, 0, -1).Format(dateLayout),
+		Group:       sales.Filter.Group,
+		Total:       toSalesResponse(sales.Total),
+		Periods:     []entity.SalesDTOResponse{},
+		TopProducts: []entity.ProductSalesDTOResponse{},
+	}
+	for _, period := range sales.Periods {
+		res.Periods = append(res.Periods, toSalesResponse(period))
+	}
+	for _, product := range sales.TopProducts {
+		fR, _ := product.Revenue.Float64()
+		res.TopProducts = append(res.TopProducts, entity.ProductSalesDTOResponse{
+			ProductID: product.Product.ID,
+			SKU:       product.Product.SKU,
+			Name:      product.Product.Name,
+			UnitsSold: product.UnitsSold,
+			Revenue:   fR,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+func toSalesResponse(sales entity.Sales) entity.SalesDTOResponse {
+	fR, _ := sales.Revenue.Float64()
+	fA, _ := sales.AverageOrderValue().Float64()
+	res := entity.SalesDTOResponse{
+		Orders:            sales.Orders,
+		Revenue:           fR,
+		AverageOrderValue: fA,
+		UnitsSold:         sales.UnitsSold,
+	}
+	// the total of a range has no start
+	if !sales.Start.IsZero() {
+		res.Start = sales.Start.Format(dateLayout)
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package analyticscontroller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	analyticscontroller "github.com/hieronimusbudi/komodo-backend/controllers/analytics_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockAnalyticsUCase *mocks.AnalyticsUseCase
+	seller             helpers.UserJWTPayload
+	app                *fiber.App
+	validate           *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockAnalyticsUCase = new(mocks.AnalyticsUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+}
+
+func TestAnalyticsController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestSellerSales() {
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	filter := entity.SalesFilter{From: from, To: to, Group: entity.SALES_GROUP_MONTH, Top: 3}
+	suite.mockAnalyticsUCase.On("GetSellerSales", mock.Anything, suite.seller.ID, filter).Return(entity.SalesAnalytics{
+		Filter: filter,
+		Total:  entity.Sales{Orders: 3, Revenue: decimal.NewFromInt(150001), UnitsSold: 4},
+		Periods: []entity.Sales{
+			{Start: from, Orders: 3, Revenue: decimal.NewFromInt(150001), UnitsSold: 4},
+		},
+		TopProducts: []entity.ProductSales{
+			{Product: entity.Product{ID: 7, SKU: "KOPI-1", Name: "Kopi Toraja"}, UnitsSold: 4, Revenue: decimal.NewFromInt(150001)},
+		},
+	}, nil).Once()
+
+	handler := analyticscontroller.NewAnalyticsController(suite.mockAnalyticsUCase, suite.validate)
+	suite.app.Get("/sellers/me/analytics", testhelpers.WithClaims(suite.seller), handler.SellerSales)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/analytics?from=2021-05-01&to=2021-05-31&group=month&top=3", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data entity.SalesAnalyticsDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	// to is the last day of the range
+	suite.Equal("2021-05-01", body.Data.From)
+	suite.Equal("2021-05-31", body.Data.To)
+	suite.Equal(entity.SALES_GROUP_MONTH, body.Data.Group)
+	suite.Equal(entity.SalesDTOResponse{Orders: 3, Revenue: 150001, AverageOrderValue: 50000.33, UnitsSold: 4}, body.Data.Total)
+	suite.Equal([]entity.SalesDTOResponse{
+		{Start: "2021-05-01", Orders: 3, Revenue: 150001, AverageOrderValue: 50000.33, UnitsSold: 4},
+	}, body.Data.Periods)
+	suite.Equal([]entity.ProductSalesDTOResponse{
+		{ProductID: 7, SKU: "KOPI-1", Name: "Kopi Toraja", UnitsSold: 4, Revenue: 150001},
+	}, body.Data.TopProducts)
+	suite.mockAnalyticsUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestSellerSalesInvalid() {
+	handler := analyticscontroller.NewAnalyticsController(suite.mockAnalyticsUCase, suite.validate)
+	suite.app.Get("/sellers/me/analytics", testhelpers.WithClaims(suite.seller), handler.SellerSales)
+
+	for _, query := range []string{"group=year", "from=01-05-2021", "top=100"} {
+		resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/analytics?"+query, nil))
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, resp.StatusCode, query)
+		suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+	}
+	suite.mockAnalyticsUCase.AssertNotCalled(suite.T(), "GetSellerSales", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestSellerSalesError() {
+	suite.mockAnalyticsUCase.On("GetSellerSales", mock.Anything, suite.seller.ID, mock.AnythingOfType("entity.SalesFilter")).
+		Return(entity.SalesAnalytics{}, resterrors.NewBadRequestError("from must be before to")).Once()
+
+	handler := analyticscontroller.NewAnalyticsController(suite.mockAnalyticsUCase, suite.validate)
+	suite.app.Get("/sellers/me/analytics", testhelpers.WithClaims(suite.seller), handler.SellerSales)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/analytics?from=2021-06-01&to=2021-05-01", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
@@ -32,16 +32,15 @@ func NewBuyerController(u entity.BuyerUseCase, v *validator.Validate) BuyerContr
 func (bctr *buyerController) Register(c *fiber.Ctx) error {
 	buyerReq := new(entity.BuyerDTORequest)
 	if err := c.BodyParser(buyerReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := bctr.validate.Struct(buyerReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	buyer := entity.Buyer{
@@ -50,9 +49,9 @@ func (bctr *buyerController) Register(c *fiber.Ctx) error {
 		Password:       buyerReq.Password,
 		SendingAddress: buyerReq.SendingAddress,
 	}
-	err := bctr.buyerUsecase.Register(&buyer)
+	err := bctr.buyerUsecase.Register(c.UserContext(), &buyer)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	buyerRes := entity.BuyerDTOResponse{
@@ -69,25 +68,24 @@ func (bctr *buyerController) Register(c *fiber.Ctx) error {
 func (bctr *buyerController) Login(c *fiber.Ctx) error {
 	loginReq := new(entity.BuyerDTOLogin)
 	if err := c.BodyParser(loginReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := bctr.validate.Struct(loginReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	buyer := entity.Buyer{
 		Email:    loginReq.Email,
 		Password: loginReq.Password,
 	}
-	buyer, err := bctr.buyerUsecase.Login(&buyer)
+	buyer, err := bctr.buyerUsecase.Login(c.UserContext(), &buyer)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	// create jwt token
@@ -100,7 +98,7 @@ func (bctr *buyerController) Login(c *fiber.Ctx) error {
 	}, []byte(config.JWT_SECRET))
 	if tokenErr != nil {
 		rErr := resterrors.NewInternalServerError("generate token error ", tokenErr)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	res := helpers.JWTResponse{
@@ -55,7 +55,7 @@ func TestBuyerController(t *testing.T) {
 }
 
 func (suite *TestSuite) TestRegister() {
-	suite.mockBuyerUCase.On("Register", mock.AnythingOfType("*entity.Buyer")).Return(nil).Once()
+	suite.mockBuyerUCase.On("Register", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(nil).Once()
 
 	j, err := json.Marshal(suite.mockBuyerDTOReq)
 	suite.NoError(err)
@@ -73,7 +73,7 @@ func (suite *TestSuite) TestRegister() {
 }
 
 func (suite *TestSuite) TestLogin() {
-	suite.mockBuyerUCase.On("Login", mock.AnythingOfType("*entity.Buyer")).Return(suite.mockBuyer, nil).Once()
+	suite.mockBuyerUCase.On("Login", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(suite.mockBuyer, nil).Once()
 
 	j, err := json.Marshal(suite.mockBuyerLoginReq)
 	suite.NoError(err)
@@ -0,0 +1,59 @@
+package invoicecontroller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type InvoiceController interface {
+	Invoice(c *fiber.Ctx) error
+}
+
+type invoiceController struct {
+	invoiceUsecase entity.InvoiceUseCase
+	validate       *validator.Validate
+}
+
+// NewInvoiceController will create a object with InvoiceController interface representation
+func NewInvoiceController(i entity.InvoiceUseCase, v *validator.Validate) InvoiceController {
+	return &invoiceController{
+		invoiceUsecase: i,
+		validate:       v,
+	}
+}
+
+func (ictr *invoiceController) Invoice(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	invoice, err := ictr.invoiceUsecase.GetByOrderID(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	// the document is rendered before anything is sent so a failure is still answered with an error response
+	var buf bytes.Buffer
+	if err := ictr.invoiceUsecase.Render(c.UserContext(), invoice, &buf); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.pdf"`, invoice.FileName()))
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
@@ -0,0 +1,96 @@
+package invoicecontroller_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	invoicecontroller "github.com/hieronimusbudi/komodo-backend/controllers/invoice_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockInvoiceUCase *mocks.InvoiceUseCase
+	mockInvoice      entity.Invoice
+	buyer            helpers.UserJWTPayload
+	app              *fiber.App
+	validate         *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockInvoiceUCase = new(mocks.InvoiceUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.buyer = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	suite.mockInvoice = entity.Invoice{
+		ID:       3,
+		OrderID:  1,
+		SellerID: 2,
+		Number:   7,
+		IssuedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Order:    entity.Order{ID: 1, Status: entity.ACCEPTED},
+	}
+}
+
+func TestInvoiceController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestInvoice() {
+	suite.mockInvoiceUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.buyer).Return(suite.mockInvoice, nil).Once()
+	suite.mockInvoiceUCase.On("Render", mock.Anything, suite.mockInvoice, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		io.WriteString(args.Get(2).(io.Writer), "%PDF-1.3")
+	}).Once()
+
+	handler := invoicecontroller.NewInvoiceController(suite.mockInvoiceUCase, suite.validate)
+	suite.app.Get("/orders/:id/invoice.pdf", testhelpers.WithClaims(suite.buyer), handler.Invoice)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1/invoice.pdf", nil)
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("application/pdf", res.Header.Get("Content-Type"))
+	suite.Equal(`inline; filename="INV-2-000007.pdf"`, res.Header.Get("Content-Disposition"))
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	suite.Equal("%PDF-1.3", string(body))
+	suite.mockInvoiceUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestInvoiceNotAccepted() {
+	suite.mockInvoiceUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.buyer).
+		Return(entity.Invoice{}, resterrors.NewConflictError("only accepted orders have an invoice")).Once()
+
+	handler := invoicecontroller.NewInvoiceController(suite.mockInvoiceUCase, suite.validate)
+	suite.app.Get("/orders/:id/invoice.pdf", testhelpers.WithClaims(suite.buyer), handler.Invoice)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1/invoice.pdf", nil)
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.mockInvoiceUCase.AssertNotCalled(suite.T(), "Render", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestInvoiceWithoutToken() {
+	handler := invoicecontroller.NewInvoiceController(suite.mockInvoiceUCase, suite.validate)
+	suite.app.Get("/orders/:id/invoice.pdf", handler.Invoice)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1/invoice.pdf", nil)
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+}
@@ -1,20 +1,32 @@
 package ordercontroller
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
 )
 
 type OrderController interface {
 	Store(c *fiber.Ctx) error
 	GetByUserID(c *fiber.Ctx) error
+	Export(c *fiber.Ctx) error
+	GetByID(c *fiber.Ctx) error
 	AcceptOrder(c *fiber.Ctx) error
+	CancelOrder(c *fiber.Ctx) error
+	RejectOrder(c *fiber.Ctx) error
 }
 
 type orderController struct {
@@ -31,27 +43,38 @@ func NewOrderController(o entity.OrderUseCase, v *validator.Validate) OrderContr
 }
 
 func (octr *orderController) Store(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
 	// parse order from request body
 	oDTOReq := new(entity.OrderDTORequest)
 	if err := c.BodyParser(oDTOReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := octr.validate.Struct(oDTOReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
+	// the order is placed for the logged in buyer
 	order := entity.Order{
-		Buyer:                      entity.Buyer{ID: oDTOReq.BuyerID},
+		Buyer:                      entity.Buyer{ID: user.ID},
 		Seller:                     entity.Seller{ID: oDTOReq.SellerID},
 		DeliverySourceAddress:      oDTOReq.DeliverySourceAddress,
 		DeliveryDestinationAddress: oDTOReq.DeliveryDestinationAddress,
 		Status:                     entity.PENDING,
+		VoucherCode:                oDTOReq.VoucherCode,
+		ShippingService:            oDTOReq.Shipping.Service,
+		ShippingDestination: entity.ShippingAddress{
+			Province:   oDTOReq.Shipping.Destination.Province,
+			PostalCode: oDTOReq.Shipping.Destination.PostalCode,
+		},
 	}
 
 	for _, od := range oDTOReq.Items {
@@ -64,13 +87,17 @@ func (octr *orderController) Store(c *fiber.Ctx) error {
 	}
 
 	// store Order
-	err := octr.orderUsecase.Store(&order)
+	err := octr.orderUsecase.Store(c.UserContext(), &order, user)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	// transform Order to OrderDTOResponse
 	fTP, _ := order.TotalPrice.Float64()
+	fST, _ := order.Subtotal().Float64()
+	fD, _ := order.Discount.Float64()
+	fSF, _ := order.ShippingFee.Float64()
+	fTax, _ := order.Tax.Float64()
 	res := entity.OrderDTOResponse{
 		ID:                         order.ID,
 		BuyerID:                    order.Buyer.ID,
@@ -78,6 +105,13 @@ func (octr *orderController) Store(c *fiber.Ctx) error {
 		DeliverySourceAddress:      order.DeliverySourceAddress,
 		DeliveryDestinationAddress: order.DeliveryDestinationAddress,
 		TotalQuantity:              order.TotalQuantity,
+		Subtotal:                   fST,
+		Discount:                   fD,
+		VoucherCode:                order.VoucherCode,
+		ShippingService:            order.ShippingService,
+		ShippingFee:                fSF,
+		Tax:                        fTax,
+		TaxInclusive:               order.TaxInclusive,
 		TotalPrice:                 fTP,
 		Status:                     order.Status,
 		OrderDate:                  order.OrderDate,
@@ -85,7 +119,11 @@ func (octr *orderController) Store(c *fiber.Ctx) error {
 
 	for _, od := range order.Items {
 		fP, _ := od.Product.Price.Float64()
+		fOdP, _ := od.Price.Float64()
+		fTR, _ := od.TaxRate.Float64()
+		fOdT, _ := od.Tax.Float64()
 		res.Items = append(res.Items, entity.OrderDetailDTOResponse{
+			ID: od.ID,
 			Product: entity.ProductDTOResponse{
 				ID:          od.Product.ID,
 				Name:        od.Product.Name,
@@ -93,7 +131,9 @@ func (octr *orderController) Store(c *fiber.Ctx) error {
 				Price:       fP,
 				SellerID:    od.Product.Seller.ID,
 			},
-			Price:    fP,
+			Price:    fOdP,
+			TaxRate:  fTR,
+			Tax:      fOdT,
 			Quantity: od.Quantity,
 		})
 	}
@@ -104,28 +144,44 @@ func (octr *orderController) Store(c *fiber.Ctx) error {
 }
 
 func (octr *orderController) GetByUserID(c *fiber.Ctx) error {
-	// take token from user value context
-	tokenClaims, ok := c.Context().UserValue("tokenClaims").(jwt.MapClaims)
-	if !ok {
-		rErr := resterrors.NewUnauthorizedError("token claims not exists")
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse & validate filters from query string
+	listReq := new(entity.OrderListDTORequest)
+	if err := c.QueryParser(listReq); err != nil {
+		rErr := resterrors.NewBadRequestError(err.Error())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
-	// get id & user type
-	userID := int64(tokenClaims["id"].(float64))
-	userType := helpers.UserTypeEnum(tokenClaims["type"].(float64))
+	vErr := octr.validate.Struct(listReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	filter, rErr := toOrderFilter(listReq)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
 
 	// get orders by buyer/seller id
-	orders, err := octr.orderUsecase.GetByUserID(userID, userType)
+	page, err := octr.orderUsecase.GetByUserID(c.UserContext(), user.ID, user.Type, filter)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	// transform Order to OrderDTOResponse
 	var orderRes entity.OrderDTOResponse
-	var res []entity.OrderDTOResponse
-	for _, order := range orders {
+	res := []entity.OrderDTOResponse{}
+	for _, order := range page.Orders {
 		fTP, _ := order.TotalPrice.Float64()
+		fST, _ := order.Subtotal().Float64()
+		fD, _ := order.Discount.Float64()
+		fSF, _ := order.ShippingFee.Float64()
+		fTax, _ := order.Tax.Float64()
 		orderRes = entity.OrderDTOResponse{
 			ID:                         order.ID,
 			BuyerID:                    order.Buyer.ID,
@@ -133,19 +189,34 @@ func (octr *orderController) GetByUserID(c *fiber.Ctx) error {
 			DeliverySourceAddress:      order.DeliverySourceAddress,
 			DeliveryDestinationAddress: order.DeliveryDestinationAddress,
 			TotalQuantity:              order.TotalQuantity,
+			Subtotal:                   fST,
+			Discount:                   fD,
+			VoucherCode:                order.VoucherCode,
+			ShippingService:            order.ShippingService,
+			ShippingFee:                fSF,
+			Tax:                        fTax,
+			TaxInclusive:               order.TaxInclusive,
 			TotalPrice:                 fTP,
 			Status:                     order.Status,
 			OrderDate:                  order.OrderDate,
+			ReasonCode:                 order.ReasonCode,
+			ReasonNote:                 order.ReasonNote,
 		}
 
 		odRow := entity.OrderDetailDTOResponse{}
 		orderRes.Items = []entity.OrderDetailDTOResponse{}
 
 		for _, od := range order.Items {
+			odRow.ID = od.ID
 			odRow.Quantity = od.Quantity
 			fP, _ := od.Product.Price.Float64()
+			fOdP, _ := od.Price.Float64()
+			fTR, _ := od.TaxRate.Float64()
+			fOdT, _ := od.Tax.Float64()
 
-			odRow.Price = fP
+			odRow.Price = fOdP
+			odRow.TaxRate = fTR
+			odRow.Tax = fOdT
 			odRow.Product.ID = od.Product.ID
 			odRow.Product.Price = fP
 			odRow.Product.Description = od.Product.Description
@@ -160,40 +231,411 @@ func (octr *orderController) GetByUserID(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
 		Data: res,
+		Meta: helpers.CursorMeta{
+			Limit:      page.Limit,
+			NextCursor: page.NextCursor,
+		},
 	})
 }
 
-func (octr *orderController) AcceptOrder(c *fiber.Ctx) error {
+// Export streams the orders of the seller matching the filters of the listing as a CSV or XLSX file
+func (octr *orderController) Export(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse & validate format and filters from query string
+	exportReq := new(entity.OrderExportDTORequest)
+	if err := c.QueryParser(exportReq); err != nil {
+		rErr := resterrors.NewBadRequestError(err.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	vErr := octr.validate.Struct(exportReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	filter, rErr := toOrderFilter(&exportReq.OrderListDTORequest)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// the body is written after this handler returned, when the request context is already done. The export reads
+	// the orders with the context of the connection instead, it ends with the server or when a write to the client fails
+	ctx, cancel := context.WithCancel(c.Context())
+	export, err := octr.orderUsecase.ExportBySellerID(ctx, user.ID, filter)
+	if err != nil {
+		cancel()
+		return helpers.ErrorResponse(c, err)
+	}
+
+	format := exportReq.Format
+	if format == "xlsx" {
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="orders.%s"`, format))
+	c.Status(http.StatusOK)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		cw := &clientWriter{w: w, cancel: cancel}
+		var sheet entity.SheetWriter = exports.NewCSVWriter(cw)
+		if format == "xlsx" {
+			sheet = exports.NewXLSXWriter(cw, "Orders")
+		}
+		// the status is already sent, a failed export only ends the file early
+		if err := export(sheet); err != nil {
+			log.Println("order export error", err)
+		}
+	})
+	return nil
+}
+
+func (octr *orderController) GetByID(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
 	// extract params
 	orderId, idErr := c.ParamsInt("id")
 	if idErr != nil {
 		rErr := resterrors.NewBadRequestError(idErr.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
-	// accept order
+	// get order, only its buyer, seller or an admin may see it
 	order := new(entity.Order)
 	order.ID = int64(orderId)
-	uOrderRes, err := octr.orderUsecase.AcceptOrder(order)
+	uOrderRes, err := octr.orderUsecase.GetByID(c.UserContext(), order, user)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
+	c.Set(fiber.HeaderETag, orderETag(uOrderRes))
 
-	// transform Order to OrderDTOSimpleResponse
+	// transform Order to OrderDTODetailResponse
 	fTP, _ := uOrderRes.TotalPrice.Float64()
-	res := entity.OrderDTOSimpleResponse{
-		ID:                         uOrderRes.ID,
-		BuyerID:                    order.Buyer.ID,
-		SellerID:                   order.Seller.ID,
+	fST, _ := uOrderRes.Subtotal().Float64()
+	fD, _ := uOrderRes.Discount.Float64()
+	fSF, _ := uOrderRes.ShippingFee.Float64()
+	fTax, _ := uOrderRes.Tax.Float64()
+	fPaid, _ := uOrderRes.Paid.Float64()
+	fRefunded, _ := uOrderRes.Refunded.Float64()
+	fNet, _ := uOrderRes.Paid.Sub(uOrderRes.Refunded).Float64()
+	res := entity.OrderDTODetailResponse{
+		ID: uOrderRes.ID,
+		Buyer: entity.OrderParticipantDTOResponse{
+			ID:    uOrderRes.Buyer.ID,
+			Name:  uOrderRes.Buyer.Name,
+			Email: uOrderRes.Buyer.Email,
+		},
+		Seller: entity.OrderParticipantDTOResponse{
+			ID:    uOrderRes.Seller.ID,
+			Name:  uOrderRes.Seller.Name,
+			Email: uOrderRes.Seller.Email,
+		},
 		DeliverySourceAddress:      uOrderRes.DeliverySourceAddress,
 		DeliveryDestinationAddress: uOrderRes.DeliveryDestinationAddress,
 		TotalQuantity:              uOrderRes.TotalQuantity,
+		Subtotal:                   fST,
+		Discount:                   fD,
+		VoucherCode:                uOrderRes.VoucherCode,
+		ShippingService:            uOrderRes.ShippingService,
+		ShippingFee:                fSF,
+		Tax:                        fTax,
+		TaxInclusive:               uOrderRes.TaxInclusive,
 		TotalPrice:                 fTP,
 		Status:                     uOrderRes.Status,
 		OrderDate:                  uOrderRes.OrderDate,
+		ReasonCode:                 uOrderRes.ReasonCode,
+		ReasonNote:                 uOrderRes.ReasonNote,
+		Paid:                       fPaid,
+		Refunded:                   fRefunded,
+		Net:                        fNet,
+		Items:                      []entity.OrderDetailDTOResponse{},
+		StatusHistory:              []entity.OrderStatusHistoryDTOResponse{},
+	}
+
+	for _, od := range uOrderRes.Items {
+		fP, _ := od.Product.Price.Float64()
+		fOdP, _ := od.Price.Float64()
+		fTR, _ := od.TaxRate.Float64()
+		fOdT, _ := od.Tax.Float64()
+		res.Items = append(res.Items, entity.OrderDetailDTOResponse{
+			ID: od.ID,
+			Product: entity.ProductDTOResponse{
+				ID:          od.Product.ID,
+				Name:        od.Product.Name,
+				Description: od.Product.Description,
+				Price:       fP,
+				SellerID:    od.Product.Seller.ID,
+			},
+			Price:    fOdP,
+			TaxRate:  fTR,
+			Tax:      fOdT,
+			Quantity: od.Quantity,
+		})
+	}
+
+	for _, sh := range uOrderRes.StatusHistory {
+		shRow := entity.OrderStatusHistoryDTOResponse{
+			PreviousStatus: sh.PreviousStatus,
+			Status:         sh.Status,
+			Reason:         sh.Reason,
+			CreatedAt:      sh.CreatedAt,
+		}
+		if sh.ActorID != 0 {
+			actorType := sh.ActorType
+			shRow.ActorID = sh.ActorID
+			shRow.ActorType = &actorType
+		}
+
+		res.StatusHistory = append(res.StatusHistory, shRow)
 	}
 
 	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
 		Data: res,
 	})
 }
+
+// toOrderFilter converts a validated OrderListDTORequest to an entity.OrderFilter,
+// the to date is inclusive so the filter ends at the start of the next day
+func toOrderFilter(req *entity.OrderListDTORequest) (entity.OrderFilter, resterrors.RestErr) {
+	filter := entity.OrderFilter{
+		CounterpartID: req.CounterpartID,
+		Sort:          entity.OrderSortEnum(req.Sort),
+		Limit:         req.Limit,
+		Cursor:        req.Cursor,
+	}
+
+	for _, status := range req.Status {
+		filter.Statuses = append(filter.Statuses, entity.OrderStatusEnum(status))
+	}
+
+	if req.From != "" {
+		from, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			return filter, resterrors.NewBadRequestError(err.Error())
+		}
+		filter.From = from
+	}
+	if req.To != "" {
+		to, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return filter, resterrors.NewBadRequestError(err.Error())
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if req.MinTotal != "" {
+		minTotal, err := decimal.NewFromString(req.MinTotal)
+		if err != nil {
+			return filter, resterrors.NewBadRequestError(err.Error())
+		}
+		filter.MinTotal = &minTotal
+	}
+	if req.MaxTotal != "" {
+		maxTotal, err := decimal.NewFromString(req.MaxTotal)
+		if err != nil {
+			return filter, resterrors.NewBadRequestError(err.Error())
+		}
+		filter.MaxTotal = &maxTotal
+	}
+
+	return filter, nil
+}
+
+func (octr *orderController) AcceptOrder(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	version, rErr := ifMatchVersion(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// accept order
+	order := new(entity.Order)
+	order.ID = int64(orderId)
+	order.Version = version
+	uOrderRes, err := octr.orderUsecase.AcceptOrder(c.UserContext(), order, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, orderETag(uOrderRes))
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toOrderDTOSimpleResponse(uOrderRes),
+	})
+}
+
+func (octr *orderController) CancelOrder(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse reason from request body, the body is optional
+	cDTOReq := new(entity.OrderCancelDTORequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(cDTOReq); err != nil {
+			rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+			return helpers.ErrorResponse(c, rErr)
+		}
+	}
+
+	// validate request
+	vErr := octr.validate.Struct(cDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	version, rErr := ifMatchVersion(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// cancel order
+	order := new(entity.Order)
+	order.ID = int64(orderId)
+	order.Version = version
+	order.ReasonCode = entity.OrderReasonCodeEnum(cDTOReq.ReasonCode)
+	order.ReasonNote = cDTOReq.ReasonNote
+	uOrderRes, err := octr.orderUsecase.CancelOrder(c.UserContext(), order, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, orderETag(uOrderRes))
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toOrderDTOSimpleResponse(uOrderRes),
+	})
+}
+
+func (octr *orderController) RejectOrder(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse reason from request body
+	rDTOReq := new(entity.OrderRejectDTORequest)
+	if err := c.BodyParser(rDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := octr.validate.Struct(rDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	version, rErr := ifMatchVersion(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// reject order
+	order := new(entity.Order)
+	order.ID = int64(orderId)
+	order.Version = version
+	order.ReasonCode = entity.OrderReasonCodeEnum(rDTOReq.ReasonCode)
+	order.ReasonNote = rDTOReq.ReasonNote
+	uOrderRes, err := octr.orderUsecase.RejectOrder(c.UserContext(), order, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, orderETag(uOrderRes))
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toOrderDTOSimpleResponse(uOrderRes),
+	})
+}
+
+// orderETag is the ETag of the current version of order
+func orderETag(order entity.Order) string {
+	return fmt.Sprintf(`"%d"`, order.Version)
+}
+
+// ifMatchVersion takes the order version from the If-Match header, 0 when the header is missing or "*".
+// Only a single strong ETag returned by the order endpoints can match
+func ifMatchVersion(c *fiber.Ctx) (int64, resterrors.RestErr) {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || version <= 0 || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+		return 0, resterrors.NewPreconditionFailedError("If-Match doesn't match the ETag of the order")
+	}
+	return version, nil
+}
+
+// toOrderDTOSimpleResponse transforms Order to OrderDTOSimpleResponse
+func toOrderDTOSimpleResponse(order entity.Order) entity.OrderDTOSimpleResponse {
+	fTP, _ := order.TotalPrice.Float64()
+	return entity.OrderDTOSimpleResponse{
+		ID:                         order.ID,
+		BuyerID:                    order.Buyer.ID,
+		SellerID:                   order.Seller.ID,
+		DeliverySourceAddress:      order.DeliverySourceAddress,
+		DeliveryDestinationAddress: order.DeliveryDestinationAddress,
+		TotalQuantity:              order.TotalQuantity,
+		TotalPrice:                 fTP,
+		Status:                     order.Status,
+		OrderDate:                  order.OrderDate,
+		ReasonCode:                 order.ReasonCode,
+		ReasonNote:                 order.ReasonNote,
+	}
+}
+
+// clientWriter sends what is written to the client right away, so a client that went away is noticed on the next write.
+// cancel is called when a write fails
+type clientWriter struct {
+	w      *bufio.Writer
+	cancel context.CancelFunc
+}
+
+func (cw *clientWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if err == nil {
+		err = cw.w.Flush()
+	}
+	if err != nil {
+		cw.cancel()
+	}
+	return n, err
+}
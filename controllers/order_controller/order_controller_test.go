@@ -1,20 +1,26 @@
 package ordercontroller_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	ordercontroller "github.com/hieronimusbudi/komodo-backend/controllers/order_controller"
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -42,6 +48,7 @@ func (suite *TestSuite) SetupTest() {
 	suite.validate = validator.New()
 
 	suite.mockBuyer = entity.Buyer{
+		ID:             1,
 		Email:          "buyer1@mail.com",
 		Name:           "buyer",
 		Password:       "$2a$10$634oWhFDuTohq7suxGn5TuRQ8BGmWu9wFfiHZelLwfqSgWk/45vzu",
@@ -49,6 +56,7 @@ func (suite *TestSuite) SetupTest() {
 	}
 
 	suite.mockSeller = entity.Seller{
+		ID:            2,
 		Email:         "seller1@mail.com",
 		Name:          "seller",
 		Password:      "$2a$10$634oWhFDuTohq7suxGn5TuRQ8BGmWu9wFfiHZelLwfqSgWk/45vzu",
@@ -91,10 +99,13 @@ func (suite *TestSuite) SetupTest() {
 	}
 
 	suite.mockOrderDTOReq = entity.OrderDTORequest{
-		BuyerID:                    suite.mockBuyer.ID,
 		SellerID:                   suite.mockSeller.ID,
 		DeliverySourceAddress:      "pickup address",
 		DeliveryDestinationAddress: "sending address",
+		Shipping: &entity.OrderShippingDTORequest{
+			Service:     "JNE_REG",
+			Destination: entity.ShippingAddressDTORequest{Province: "Jawa Barat"},
+		},
 		Items: []entity.OrderDetailDTORequest{
 			suite.mockOrderDetailDTORequest,
 		},
@@ -106,7 +117,9 @@ func TestOrderController(t *testing.T) {
 }
 
 func (suite *TestSuite) TestStore() {
-	suite.mockOrderUCase.On("Store", mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+	suite.mockOrderUCase.On("Store", mock.Anything, mock.AnythingOfType("*entity.Order"), helpers.UserJWTPayload{
+		ID: suite.mockOrder.Buyer.ID, Type: helpers.BUYER_TYPE,
+	}).Return(nil).Once()
 
 	j, err := json.Marshal(suite.mockOrderDTOReq)
 	suite.NoError(err)
@@ -115,17 +128,102 @@ func (suite *TestSuite) TestStore() {
 	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
 	ctx.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
 	ctx.Request().SetBody(j)
+	ctx.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
 	defer suite.app.ReleaseCtx(ctx)
 
 	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
 
 	hErr := handler.Store(ctx)
 	suite.NoError(hErr)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestStoreForLoggedInBuyer() {
+	// a buyerId in the body doesn't place the order for another buyer
+	suite.mockOrderUCase.On("Store", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.Buyer.ID == suite.mockOrder.Buyer.ID
+	}), helpers.UserJWTPayload{
+		ID: suite.mockOrder.Buyer.ID, Type: helpers.BUYER_TYPE,
+	}).Return(nil).Once()
+
+	j, err := json.Marshal(suite.mockOrderDTOReq)
+	suite.NoError(err)
+	j = append([]byte(`{"buyerId":99,`), j[1:]...)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+	ctx.Request().SetBody(j)
+	ctx.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
+	defer suite.app.ReleaseCtx(ctx)
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+
+	hErr := handler.Store(ctx)
+	suite.NoError(hErr)
+	suite.Equal(http.StatusCreated, ctx.Response().StatusCode())
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestStoreWithShipping() {
+	// the origin isn't given by the buyer, it is the pickup region of the seller
+	suite.mockOrderUCase.On("Store", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.ShippingService == "JNE_REG" && o.ShippingOrigin == entity.ShippingAddress{} &&
+			o.ShippingDestination == entity.ShippingAddress{PostalCode: "40115"}
+	}), helpers.UserJWTPayload{
+		ID: suite.mockOrder.Buyer.ID, Type: helpers.BUYER_TYPE,
+	}).Return(nil).Once()
+
+	suite.mockOrderDTOReq.Shipping = &entity.OrderShippingDTORequest{
+		Service:     "JNE_REG",
+		Destination: entity.ShippingAddressDTORequest{PostalCode: "40115"},
+	}
+	j, err := json.Marshal(suite.mockOrderDTOReq)
+	suite.NoError(err)
+
+	// setup fiber ctx
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+	ctx.Request().SetBody(j)
+	ctx.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
+	defer suite.app.ReleaseCtx(ctx)
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+
+	hErr := handler.Store(ctx)
+	suite.NoError(hErr)
+	suite.Equal(http.StatusCreated, ctx.Response().StatusCode())
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestStoreWithoutShipping() {
+	for name, shipping := range map[string]*entity.OrderShippingDTORequest{
+		"no shipping": nil,
+		"no service":  {Destination: entity.ShippingAddressDTORequest{Province: "Jawa Barat"}},
+	} {
+		suite.Run(name, func() {
+			suite.mockOrderDTOReq.Shipping = shipping
+			j, err := json.Marshal(suite.mockOrderDTOReq)
+			suite.NoError(err)
+
+			ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+			ctx.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+			ctx.Request().SetBody(j)
+			ctx.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
+			defer suite.app.ReleaseCtx(ctx)
+
+			handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+
+			hErr := handler.Store(ctx)
+			suite.NoError(hErr)
+			suite.Equal(http.StatusBadRequest, ctx.Response().StatusCode())
+		})
+	}
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "Store", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (suite *TestSuite) TestStoreError() {
-	suite.mockOrderDTOReq.BuyerID = 0
 	suite.mockOrderDTOReq.SellerID = 0
+	suite.mockOrderDTOReq.Items = nil
 
 	j, err := json.Marshal(suite.mockOrderDTOReq)
 	suite.NoError(err)
@@ -136,6 +234,7 @@ func (suite *TestSuite) TestStoreError() {
 			c.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
 			return c.Next()
 		},
+		suite.withBuyerClaims,
 		handler.Store,
 	)
 
@@ -147,30 +246,321 @@ func (suite *TestSuite) TestStoreError() {
 		))
 	suite.NoError(err)
 	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+
+	// validation failures are reported per field
+	var body map[string]interface{}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(string(resterrors.CodeValidationFailed), body["code"])
+	suite.Equal("/orders", body["instance"])
+	suite.Len(body["errors"], 2)
 }
 
 func (suite *TestSuite) TestGetByUserID() {
-	suite.mockOrderUCase.On("GetByUserID", suite.mockOrder.Buyer.ID, helpers.BUYER_TYPE).Return([]entity.Order{suite.mockOrder}, nil).Once()
+	suite.mockOrderUCase.On("GetByUserID", mock.Anything, suite.mockOrder.Buyer.ID, helpers.BUYER_TYPE, entity.OrderFilter{}).
+		Return(entity.OrderPage{Orders: []entity.Order{suite.mockOrder}, Limit: 20}, nil).Once()
 
 	// setup fiber ctx
 	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
 	ctx.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+	ctx.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
 	defer suite.app.ReleaseCtx(ctx)
 
 	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
 
 	hErr := handler.GetByUserID(ctx)
 	suite.NoError(hErr)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestGetByUserIDInvalidClaims() {
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/orders/find/byuser", func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": "1"})
+		return c.Next()
+	}, handler.GetByUserID)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/find/byuser", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "GetByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestGetByUserIDFilters() {
+	minTotal := decimal.NewFromInt(100)
+	expectedFilter := entity.OrderFilter{
+		Statuses:      []entity.OrderStatusEnum{entity.PENDING, entity.ACCEPTED},
+		From:          time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		CounterpartID: 2,
+		MinTotal:      &minTotal,
+		Sort:          entity.SORT_TOTAL_PRICE_DESC,
+		Limit:         10,
+		Cursor:        "abc",
+	}
+	suite.mockOrderUCase.On("GetByUserID", mock.Anything, suite.mockOrder.Buyer.ID, helpers.BUYER_TYPE, mock.MatchedBy(func(f entity.OrderFilter) bool {
+		return suite.Equal(expectedFilter.Statuses, f.Statuses) &&
+			suite.True(expectedFilter.From.Equal(f.From)) &&
+			suite.True(expectedFilter.To.Equal(f.To)) &&
+			suite.Equal(expectedFilter.CounterpartID, f.CounterpartID) &&
+			suite.True(minTotal.Equal(*f.MinTotal)) &&
+			suite.Nil(f.MaxTotal) &&
+			suite.Equal(expectedFilter.Sort, f.Sort) &&
+			suite.Equal(expectedFilter.Limit, f.Limit) &&
+			suite.Equal(expectedFilter.Cursor, f.Cursor)
+	})).Return(entity.OrderPage{Orders: []entity.Order{suite.mockOrder}, Limit: 10, NextCursor: "next"}, nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/orders/find/byuser", suite.withBuyerClaims, handler.GetByUserID)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet,
+		"/orders/find/byuser?status=0,1&from=2021-05-01&to=2021-05-31&counterpartId=2&minTotal=100&sort=-total_price&limit=10&cursor=abc", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []entity.OrderDTOResponse `json:"data"`
+		Meta helpers.CursorMeta        `json:"meta"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Len(body.Data, 1)
+	suite.Equal(helpers.CursorMeta{Limit: 10, NextCursor: "next"}, body.Meta)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestGetByUserIDInvalidFilters() {
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/orders/find/byuser", suite.withBuyerClaims, handler.GetByUserID)
+
+	for _, query := range []string{"status=7", "from=01-05-2021", "minTotal=abc", "sort=name", "limit=1000"} {
+		resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/find/byuser?"+query, nil))
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, resp.StatusCode, query)
+	}
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "GetByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestExport() {
+	var exportCtx context.Context
+	var exported entity.OrderFilter
+	suite.mockOrderUCase.On("ExportBySellerID", mock.Anything, suite.mockOrder.Seller.ID, mock.AnythingOfType("entity.OrderFilter")).
+		Return(entity.OrderExport(func(w entity.SheetWriter) resterrors.RestErr {
+			// the export is written after the handler returned, its context is still usable
+			suite.NoError(exportCtx.Err())
+			w.WriteRow("Order ID", "Total price")
+			w.WriteRow(int64(1), decimal.RequireFromString("181818.1"))
+			w.Close()
+			return nil
+		}), nil).Run(func(args mock.Arguments) {
+		exportCtx = args.Get(0).(context.Context)
+		exported = args.Get(2).(entity.OrderFilter)
+	}).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/sellers/me/orders/export", suite.withSellerClaims, handler.Export)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet,
+		"/sellers/me/orders/export?format=csv&status=1&from=2021-05-01&to=2021-05-31", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	suite.Equal(`attachment; filename="orders.csv"`, resp.Header.Get("Content-Disposition"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	suite.Equal("Order ID,Total price\n1,181818.10\n", string(body))
+	// and it is cancelled once the export ended
+	suite.Error(exportCtx.Err())
+	suite.Equal([]entity.OrderStatusEnum{entity.ACCEPTED}, exported.Statuses)
+	suite.True(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC).Equal(exported.To))
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestExportXLSX() {
+	suite.mockOrderUCase.On("ExportBySellerID", mock.Anything, suite.mockOrder.Seller.ID, entity.OrderFilter{}).
+		Return(entity.OrderExport(func(w entity.SheetWriter) resterrors.RestErr {
+			w.WriteRow("Order ID")
+			w.Close()
+			return nil
+		}), nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/sellers/me/orders/export", suite.withSellerClaims, handler.Export)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/orders/export?format=xlsx", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", resp.Header.Get("Content-Type"))
+	suite.Equal(`attachment; filename="orders.xlsx"`, resp.Header.Get("Content-Disposition"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	_, zErr := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	suite.NoError(zErr)
+}
+
+func (suite *TestSuite) TestExportInvalid() {
+	suite.mockOrderUCase.On("ExportBySellerID", mock.Anything, suite.mockOrder.Seller.ID, mock.AnythingOfType("entity.OrderFilter")).
+		Return(nil, resterrors.NewBadRequestError("from must be before to")).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/sellers/me/orders/export", suite.withSellerClaims, handler.Export)
+
+	for _, query := range []string{"", "format=pdf", "format=csv&status=7", "format=csv&from=2021-05-02&to=2021-05-01"} {
+		resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/orders/export?"+query, nil))
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, resp.StatusCode, query)
+		suite.Equal("application/problem+json", resp.Header.Get("Content-Type"), query)
+	}
+	suite.mockOrderUCase.AssertNumberOfCalls(suite.T(), "ExportBySellerID", 1)
+}
+
+func (suite *TestSuite) TestGetByID() {
+	previous := entity.PENDING
+	suite.mockOrder.Status = entity.ACCEPTED
+	suite.mockOrder.StatusHistory = []entity.OrderStatusHistory{
+		{ID: 1, OrderID: suite.mockOrder.ID, Status: entity.PENDING, ActorID: 1, ActorType: helpers.BUYER_TYPE},
+		{ID: 2, OrderID: suite.mockOrder.ID, PreviousStatus: &previous, Status: entity.ACCEPTED},
+	}
+	suite.mockOrder.Version = 2
+	suite.mockOrder.Paid = decimal.RequireFromString("181818.10")
+	suite.mockOrder.Refunded = decimal.RequireFromString("50000.50")
+	expectedUser := helpers.UserJWTPayload{ID: suite.mockOrder.Buyer.ID, Type: helpers.BUYER_TYPE}
+	suite.mockOrderUCase.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order"), expectedUser).Return(suite.mockOrder, nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/orders/:id", suite.withBuyerClaims, handler.GetByID)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/%d", suite.mockOrder.ID), nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(`"2"`, resp.Header.Get(fiber.HeaderETag))
+
+	var body struct {
+		Data entity.OrderDTODetailResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(suite.mockOrder.ID, body.Data.ID)
+	suite.Equal(suite.mockBuyer.Email, body.Data.Buyer.Email)
+	suite.Equal(suite.mockSeller.Name, body.Data.Seller.Name)
+	suite.Len(body.Data.Items, 1)
+	suite.Len(body.Data.StatusHistory, 2)
+	suite.Equal(181818.1, body.Data.Paid)
+	suite.Equal(131817.6, body.Data.Net)
+	suite.Equal(helpers.BUYER_TYPE, *body.Data.StatusHistory[0].ActorType)
+	// changes not made by a user have no actor
+	suite.Nil(body.Data.StatusHistory[1].ActorType)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestGetByIDForbidden() {
+	suite.mockOrderUCase.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order"), mock.AnythingOfType("helpers.UserJWTPayload")).
+		Return(entity.Order{}, resterrors.NewForbiddenError("you are not allowed to access this order")).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Get("/orders/:id", suite.withBuyerClaims, handler.GetByID)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/%d", suite.mockOrder.ID), nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+}
+
+func (suite *TestSuite) TestCancelOrder() {
+	cancelledOrder := suite.mockOrder
+	cancelledOrder.Status = entity.CANCELLED
+	suite.mockOrderUCase.On("CancelOrder", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.ID == suite.mockOrder.ID && o.ReasonCode == ""
+	}), mock.AnythingOfType("helpers.UserJWTPayload")).Return(cancelledOrder, nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Post("/orders/:id/cancel", suite.withBuyerClaims, handler.CancelOrder)
+
+	// the reason is optional when cancelling
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", suite.mockOrder.ID), nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestCancelOrderInvalidReason() {
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Post("/orders/:id/cancel", suite.withBuyerClaims, handler.CancelOrder)
+
+	for _, body := range []string{`{"reasonCode":"OUT_OF_STOCK"}`, `{"reasonCode":"OTHER"}`} {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", suite.mockOrder.ID), strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, resp.StatusCode, body)
+	}
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestRejectOrder() {
+	rejectedOrder := suite.mockOrder
+	rejectedOrder.Status = entity.REJECTED
+	rejectedOrder.ReasonCode = entity.REASON_OUT_OF_STOCK
+	suite.mockOrderUCase.On("RejectOrder", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.ID == suite.mockOrder.ID && o.ReasonCode == entity.REASON_OUT_OF_STOCK
+	}), mock.AnythingOfType("helpers.UserJWTPayload")).Return(rejectedOrder, nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Post("/orders/:id/reject", suite.withBuyerClaims, handler.RejectOrder)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orders/%d/reject", suite.mockOrder.ID),
+		strings.NewReader(`{"reasonCode":"OUT_OF_STOCK"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data entity.OrderDTOSimpleResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(entity.REJECTED, body.Data.Status)
+	suite.Equal(entity.REASON_OUT_OF_STOCK, body.Data.ReasonCode)
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestRejectOrderWithoutReason() {
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Post("/orders/:id/reject", suite.withBuyerClaims, handler.RejectOrder)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orders/%d/reject", suite.mockOrder.ID), strings.NewReader(`{}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "RejectOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// withBuyerClaims stands in for the ValidateRequest middleware
+func (suite *TestSuite) withBuyerClaims(c *fiber.Ctx) error {
+	c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Buyer.ID), "type": float64(helpers.BUYER_TYPE)})
+	return c.Next()
+}
+
+// withSellerClaims stands in for the ValidateRequest middleware
+func (suite *TestSuite) withSellerClaims(c *fiber.Ctx) error {
+	c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.mockOrder.Seller.ID), "type": float64(helpers.SELLER_TYPE)})
+	return c.Next()
 }
 
 func (suite *TestSuite) TestAcceptOrder() {
-	suite.mockOrderUCase.On("AcceptOrder", mock.AnythingOfType("*entity.Order")).Return(suite.mockOrder, nil).Once()
+	suite.mockOrderUCase.On("AcceptOrder", mock.Anything, mock.AnythingOfType("*entity.Order"), helpers.UserJWTPayload{
+		ID: suite.mockOrder.Seller.ID, Type: helpers.SELLER_TYPE,
+	}).Return(suite.mockOrder, nil).Once()
 
 	j, err := json.Marshal(suite.mockOrderDTOReq)
 	suite.NoError(err)
 
 	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
-	suite.app.Put("/orders/:id/accept", func(c *fiber.Ctx) error {
+	suite.app.Put("/orders/:id/accept", suite.withSellerClaims, func(c *fiber.Ctx) error {
 		hErr := handler.AcceptOrder(c)
 		suite.NoError(hErr)
 
@@ -186,3 +576,53 @@ func (suite *TestSuite) TestAcceptOrder() {
 			strings.NewReader(string(j))))
 	suite.NoError(err)
 }
+
+func (suite *TestSuite) TestAcceptOrderIfMatch() {
+	acceptedOrder := suite.mockOrder
+	acceptedOrder.Status = entity.ACCEPTED
+	acceptedOrder.Version = 4
+	suite.mockOrderUCase.On("AcceptOrder", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.ID == suite.mockOrder.ID && o.Version == 3
+	}), mock.AnythingOfType("helpers.UserJWTPayload")).Return(acceptedOrder, nil).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Put("/orders/:id/accept", suite.withSellerClaims, handler.AcceptOrder)
+
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/orders/%d/accept", suite.mockOrder.ID), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"3"`)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(`"4"`, resp.Header.Get(fiber.HeaderETag))
+	suite.mockOrderUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestAcceptOrderInvalidIfMatch() {
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Put("/orders/:id/accept", suite.withSellerClaims, handler.AcceptOrder)
+
+	for _, ifMatch := range []string{`W/"3"`, "3", `"abc"`} {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/orders/%d/accept", suite.mockOrder.ID), nil)
+		req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		resp, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusPreconditionFailed, resp.StatusCode, ifMatch)
+	}
+	suite.mockOrderUCase.AssertNotCalled(suite.T(), "AcceptOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestCancelOrderOutdatedVersion() {
+	suite.mockOrderUCase.On("CancelOrder", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool { return o.Version == 1 }),
+		mock.AnythingOfType("helpers.UserJWTPayload")).
+		Return(entity.Order{}, resterrors.NewPreconditionFailedError("order with id 1 is at version 2")).Once()
+
+	handler := ordercontroller.NewOrderController(suite.mockOrderUCase, suite.validate)
+	suite.app.Post("/orders/:id/cancel", suite.withBuyerClaims, handler.CancelOrder)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", suite.mockOrder.ID), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusPreconditionFailed, resp.StatusCode)
+	suite.Empty(resp.Header.Get(fiber.HeaderETag))
+}
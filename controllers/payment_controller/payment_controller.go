@@ -0,0 +1,253 @@
+package paymentcontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type PaymentController interface {
+	Create(c *fiber.Ctx) error
+	GetByOrderID(c *fiber.Ctx) error
+	HandleWebhook(c *fiber.Ctx) error
+	ReplayWebhookEvent(c *fiber.Ctx) error
+	Refund(c *fiber.Ctx) error
+	GetRefunds(c *fiber.Ctx) error
+}
+
+type paymentController struct {
+	paymentUsecase entity.PaymentUseCase
+	validate       *validator.Validate
+}
+
+// NewPaymentController will create a object with PaymentController interface representation
+func NewPaymentController(p entity.PaymentUseCase, v *validator.Validate) PaymentController {
+	return &paymentController{
+		paymentUsecase: p,
+		validate:       v,
+	}
+}
+
+func (pctr *paymentController) Create(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	payment, err := pctr.paymentUsecase.Create(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toPaymentDTOResponse(payment),
+	})
+}
+
+func (pctr *paymentController) GetByOrderID(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	payment, err := pctr.paymentUsecase.GetByOrderID(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toPaymentDTOResponse(payment),
+	})
+}
+
+// HandleWebhook receives the callbacks of a payment gateway, any response other than 2xx makes the gateway send the event again
+func (pctr *paymentController) HandleWebhook(c *fiber.Ctx) error {
+	// the body is signed as it was sent, it is copied since fiber reuses its buffer
+	body := append([]byte(nil), c.Body()...)
+	header := http.Header{}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+
+	event, err := pctr.paymentUsecase.HandleWebhook(c.UserContext(), c.Params("provider"), header, body)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toPaymentWebhookEventDTOResponse(event),
+	})
+}
+
+func (pctr *paymentController) ReplayWebhookEvent(c *fiber.Ctx) error {
+	// extract params
+	eventId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	event, err := pctr.paymentUsecase.ReplayWebhookEvent(c.UserContext(), int64(eventId))
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toPaymentWebhookEventDTOResponse(event),
+	})
+}
+
+func (pctr *paymentController) Refund(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse refund from request body, the body is optional for a full refund
+	rDTOReq := new(entity.RefundDTORequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(rDTOReq); err != nil {
+			rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+			return helpers.ErrorResponse(c, rErr)
+		}
+	}
+
+	// validate request
+	vErr := pctr.validate.Struct(rDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	request := entity.Refund{Reason: rDTOReq.Reason}
+	for _, ri := range rDTOReq.Items {
+		request.Items = append(request.Items, entity.RefundItem{OrderDetailID: ri.OrderItemID, Quantity: ri.Quantity})
+	}
+
+	refund, err := pctr.paymentUsecase.Refund(c.UserContext(), &entity.Order{ID: int64(orderId)}, request, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toRefundDTOResponse(refund),
+	})
+}
+
+func (pctr *paymentController) GetRefunds(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	summary, err := pctr.paymentUsecase.GetRefunds(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	fPaid, _ := summary.Paid.Float64()
+	fRefunded, _ := summary.Refunded.Float64()
+	fNet, _ := summary.Net().Float64()
+	res := entity.RefundSummaryDTOResponse{
+		Paid:     fPaid,
+		Refunded: fRefunded,
+		Net:      fNet,
+		Refunds:  []entity.RefundDTOResponse{},
+	}
+	for _, refund := range summary.Refunds {
+		res.Refunds = append(res.Refunds, toRefundDTOResponse(refund))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+func toPaymentDTOResponse(payment entity.Payment) entity.PaymentDTOResponse {
+	fA, _ := payment.Amount.Float64()
+	return entity.PaymentDTOResponse{
+		ID:           payment.ID,
+		OrderID:      payment.OrderID,
+		Provider:     payment.Provider,
+		ClientSecret: payment.ClientSecret,
+		Amount:       fA,
+		Currency:     payment.Currency,
+		Status:       payment.Status,
+		CreatedAt:    payment.CreatedAt,
+		UpdatedAt:    payment.UpdatedAt,
+	}
+}
+
+func toPaymentWebhookEventDTOResponse(event entity.PaymentWebhookEvent) entity.PaymentWebhookEventDTOResponse {
+	return entity.PaymentWebhookEventDTOResponse{
+		ID:                event.ID,
+		Provider:          event.Provider,
+		ProviderEventID:   event.ProviderEventID,
+		Type:              event.Type,
+		ProviderPaymentID: event.ProviderPaymentID,
+		Status:            event.Status,
+		ReceivedAt:        event.ReceivedAt,
+		ProcessedAt:       event.ProcessedAt,
+		Error:             event.Error,
+	}
+}
+
+func toRefundDTOResponse(refund entity.Refund) entity.RefundDTOResponse {
+	fA, _ := refund.Amount.Float64()
+	res := entity.RefundDTOResponse{
+		ID:        refund.ID,
+		OrderID:   refund.OrderID,
+		PaymentID: refund.PaymentID,
+		Amount:    fA,
+		Reason:    refund.Reason,
+		CreatedAt: refund.CreatedAt,
+		Items:     []entity.RefundItemDTOResponse{},
+	}
+	if refund.ActorID != 0 {
+		actorType := refund.ActorType
+		res.ActorID = refund.ActorID
+		res.ActorType = &actorType
+	}
+
+	for _, ri := range refund.Items {
+		fRiA, _ := ri.Amount.Float64()
+		res.Items = append(res.Items, entity.RefundItemDTOResponse{
+			OrderItemID: ri.OrderDetailID,
+			Quantity:    ri.Quantity,
+			Amount:      fRiA,
+		})
+	}
+	return res
+}
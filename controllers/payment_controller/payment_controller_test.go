@@ -0,0 +1,247 @@
+package paymentcontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	paymentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/payment_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockPaymentUCase *mocks.PaymentUseCase
+	mockPayment      entity.Payment
+	buyer            helpers.UserJWTPayload
+	app              *fiber.App
+	validate         *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockPaymentUCase = new(mocks.PaymentUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.buyer = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	suite.mockPayment = entity.Payment{
+		ID:                7,
+		OrderID:           1,
+		Provider:          "fake",
+		ProviderPaymentID: "fake_pi_1",
+		ClientSecret:      "fake_pi_1_secret",
+		Amount:            decimal.NewFromFloat(181818.11),
+		Currency:          "IDR",
+		Status:            entity.PAYMENT_AUTHORIZED,
+		CreatedAt:         time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:         time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPaymentController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+// withBuyerClaims stands in for the ValidateRequest middleware
+func (suite *TestSuite) withBuyerClaims(c *fiber.Ctx) error {
+	c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(suite.buyer.ID), "type": float64(suite.buyer.Type)})
+	return c.Next()
+}
+
+func (suite *TestSuite) TestCreate() {
+	suite.mockPaymentUCase.On("Create", mock.Anything, &entity.Order{ID: 1}, suite.buyer).Return(suite.mockPayment, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/orders/:id/payment", suite.withBuyerClaims, handler.Create)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/orders/1/payment", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.PaymentDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(int64(7), resBody.Data.ID)
+	suite.Equal("fake_pi_1_secret", resBody.Data.ClientSecret)
+	suite.Equal(181818.11, resBody.Data.Amount)
+	suite.Equal(entity.PAYMENT_AUTHORIZED, resBody.Data.Status)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestCreateConflict() {
+	suite.mockPaymentUCase.On("Create", mock.Anything, &entity.Order{ID: 1}, suite.buyer).
+		Return(entity.Payment{}, resterrors.NewConflictError("order with id 1 already has a payment")).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/orders/:id/payment", suite.withBuyerClaims, handler.Create)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/orders/1/payment", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusConflict, res.StatusCode)
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mockPaymentUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.buyer).Return(suite.mockPayment, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Get("/orders/:id/payment", suite.withBuyerClaims, handler.GetByOrderID)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/payment", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestGetByOrderIDUnauthorized() {
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Get("/orders/:id/payment", handler.GetByOrderID)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/payment", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func (suite *TestSuite) TestHandleWebhook() {
+	body := `{"id":"evt_1","type":"payment_intent.requires_capture","data":{"id":"fake_pi_1","status":"requires_capture"}}`
+	suite.mockPaymentUCase.On("HandleWebhook", mock.Anything, "fake", mock.MatchedBy(func(h http.Header) bool {
+		return h.Get("Webhook-Signature") == "t=1619863200,v1=abc"
+	}), []byte(body)).Return(entity.PaymentWebhookEvent{ID: 3, Provider: "fake", ProviderEventID: "evt_1"}, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/webhooks/payments/:provider", handler.HandleWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payments/fake", strings.NewReader(body))
+	req.Header.Set("Webhook-Signature", "t=1619863200,v1=abc")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestHandleWebhookUnauthorized() {
+	suite.mockPaymentUCase.On("HandleWebhook", mock.Anything, "fake", mock.Anything, mock.Anything).
+		Return(entity.PaymentWebhookEvent{}, resterrors.NewUnauthorizedError("invalid webhook signature")).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/webhooks/payments/:provider", handler.HandleWebhook)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/webhooks/payments/fake", strings.NewReader(`{}`)))
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func (suite *TestSuite) TestReplayWebhookEvent() {
+	suite.mockPaymentUCase.On("ReplayWebhookEvent", mock.Anything, int64(3)).
+		Return(entity.PaymentWebhookEvent{ID: 3, Provider: "fake", ProviderEventID: "evt_1"}, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/admin/payment-webhook-events/:id/replay", handler.ReplayWebhookEvent)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/admin/payment-webhook-events/3/replay", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.PaymentWebhookEventDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal("evt_1", resBody.Data.ProviderEventID)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestRefund() {
+	seller := helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+	suite.mockPaymentUCase.On("Refund", mock.Anything, &entity.Order{ID: 1}, entity.Refund{
+		Reason: "damaged",
+		Items:  []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}},
+	}, seller).Return(entity.Refund{
+		ID:        3,
+		OrderID:   1,
+		PaymentID: 7,
+		Amount:    decimal.RequireFromString("50000.50"),
+		Reason:    "damaged",
+		ActorID:   2,
+		ActorType: helpers.SELLER_TYPE,
+		Items:     []entity.RefundItem{{OrderDetailID: 11, Quantity: 1, Amount: decimal.RequireFromString("50000.50")}},
+	}, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/orders/:id/refunds", func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(seller.ID), "type": float64(seller.Type)})
+		return c.Next()
+	}, handler.Refund)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/1/refunds",
+		strings.NewReader(`{"reason":"damaged","items":[{"orderItemId":11,"quantity":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.RefundDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(50000.5, resBody.Data.Amount)
+	suite.Equal(int64(11), resBody.Data.Items[0].OrderItemID)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestRefundInvalidQuantity() {
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Post("/orders/:id/refunds", suite.withBuyerClaims, handler.Refund)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/1/refunds", strings.NewReader(`{"items":[{"orderItemId":11,"quantity":0}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+	suite.mockPaymentUCase.AssertNotCalled(suite.T(), "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestGetRefunds() {
+	suite.mockPaymentUCase.On("GetRefunds", mock.Anything, &entity.Order{ID: 1}, suite.buyer).Return(entity.RefundSummary{
+		Paid:     decimal.RequireFromString("181818.10"),
+		Refunded: decimal.RequireFromString("50000.50"),
+		Refunds:  []entity.Refund{{ID: 3, Amount: decimal.RequireFromString("50000.50")}},
+	}, nil).Once()
+
+	handler := paymentcontroller.NewPaymentController(suite.mockPaymentUCase, suite.validate)
+	suite.app.Get("/orders/:id/refunds", suite.withBuyerClaims, handler.GetRefunds)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/refunds", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.RefundSummaryDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(181818.1, resBody.Data.Paid)
+	suite.Equal(50000.5, resBody.Data.Refunded)
+	suite.Equal(131817.6, resBody.Data.Net)
+	suite.Len(resBody.Data.Refunds, 1)
+	suite.mockPaymentUCase.AssertExpectations(suite.T())
+}
@@ -1,11 +1,14 @@
 package productcontroller
 
 import (
+	"bytes"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"github.com/shopspring/decimal"
@@ -14,6 +17,9 @@ import (
 type ProductController interface {
 	Store(c *fiber.Ctx) error
 	GetAll(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Import(c *fiber.Ctx) error
+	Export(c *fiber.Ctx) error
 }
 
 type productController struct {
@@ -34,38 +40,43 @@ func (pctr *productController) Store(c *fiber.Ctx) error {
 	productReq := new(entity.ProductDTORequest)
 
 	if err := c.BodyParser(productReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := pctr.validate.Struct(productReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// store Product
 	dP := decimal.NewFromFloat(productReq.Price)
 	product := entity.Product{
+		SKU:         productReq.SKU,
 		Name:        productReq.Name,
 		Description: productReq.Description,
 		Price:       dP,
+		Weight:      productReq.Weight,
+		Category:    productReq.Category,
 		Seller:      entity.Seller{ID: productReq.SellerID},
 	}
-	err := pctr.productUsecase.Store(&product)
+	err := pctr.productUsecase.Store(c.UserContext(), &product)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	// transform Product to ProductResponse
 	fP, _ := product.Price.Float64()
 	res := entity.ProductDTOResponse{
 		ID:          product.ID,
+		SKU:         product.SKU,
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       fP,
+		Weight:      product.Weight,
+		Category:    product.Category,
 		SellerID:    product.Seller.ID,
 	}
 
@@ -75,10 +86,9 @@ func (pctr *productController) Store(c *fiber.Ctx) error {
 }
 
 func (pctr *productController) GetAll(c *fiber.Ctx) error {
-	products, err := pctr.productUsecase.GetAll()
+	products, err := pctr.productUsecase.GetAll(c.UserContext())
 	if err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	var productRes entity.ProductDTOResponse
@@ -88,9 +98,12 @@ func (pctr *productController) GetAll(c *fiber.Ctx) error {
 		fP, _ := product.Price.Float64()
 		productRes = entity.ProductDTOResponse{
 			ID:          product.ID,
+			SKU:         product.SKU,
 			Name:        product.Name,
 			Description: product.Description,
 			Price:       fP,
+			Weight:      product.Weight,
+			Category:    product.Category,
 			SellerID:    product.Seller.ID,
 		}
 
@@ -101,3 +114,125 @@ func (pctr *productController) GetAll(c *fiber.Ctx) error {
 		Data: res,
 	})
 }
+
+// Update saves the product with the id param of the logged in seller, its SKU is kept
+func (pctr *productController) Update(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	productId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse product from request body
+	productReq := new(entity.ProductUpdateDTORequest)
+	if err := c.BodyParser(productReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := pctr.validate.Struct(productReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	product := entity.Product{
+		ID:          int64(productId),
+		Name:        productReq.Name,
+		Description: productReq.Description,
+		Price:       decimal.NewFromFloat(productReq.Price),
+		Weight:      productReq.Weight,
+		Category:    productReq.Category,
+	}
+	if err := pctr.productUsecase.Update(c.UserContext(), &product, user.ID); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	// transform Product to ProductResponse
+	fP, _ := product.Price.Float64()
+	res := entity.ProductDTOResponse{
+		ID:          product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       fP,
+		Weight:      product.Weight,
+		Category:    product.Category,
+		SellerID:    product.Seller.ID,
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+// Import creates or updates the products of the logged in seller from the CSV file uploaded as file,
+// with dryRun=true the import is only checked and nothing is saved
+func (pctr *productController) Import(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	dryRun, err := strconv.ParseBool(c.Query("dryRun", "false"))
+	if err != nil {
+		rErr := resterrors.NewBadRequestError("dryRun must be true or false")
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		rErr := resterrors.NewBadRequestError("a CSV file is required as file")
+		return helpers.ErrorResponse(c, rErr)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		rErr := resterrors.NewBadRequestError(err.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+	defer f.Close()
+
+	products, rErr := readProductCSV(f, user.ID, pctr.validate)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	result, rErr := pctr.productUsecase.Import(c.UserContext(), user.ID, products, dryRun)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	res := entity.ProductImportDTOResponse{
+		DryRun:  result.DryRun,
+		Created: result.Created,
+		Updated: result.Updated,
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+// Export downloads the products of the logged in seller as a CSV file that can be imported again
+func (pctr *productController) Export(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	var buf bytes.Buffer
+	err := pctr.productUsecase.ExportBySellerID(c.UserContext(), user.ID, exports.NewCSVWriter(&buf))
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products.csv"`)
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
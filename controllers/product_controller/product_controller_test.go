@@ -1,17 +1,25 @@
 package productcontroller_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	productcontroller "github.com/hieronimusbudi/komodo-backend/controllers/product_controller"
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -62,7 +70,7 @@ func TestProductController(t *testing.T) {
 }
 
 func (suite *TestSuite) TestStore() {
-	suite.mockProductUCase.On("Store", mock.AnythingOfType("*entity.Product")).Return(nil).Once()
+	suite.mockProductUCase.On("Store", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(nil).Once()
 
 	j, err := json.Marshal(suite.mockProductDTOReq)
 	suite.NoError(err)
@@ -110,7 +118,7 @@ func (suite *TestSuite) TestStoreError() {
 }
 
 func (suite *TestSuite) TestGetAll() {
-	suite.mockProductUCase.On("GetAll").Return([]entity.Product{suite.mockProduct}, nil).Once()
+	suite.mockProductUCase.On("GetAll", mock.Anything, mock.Anything).Return([]entity.Product{suite.mockProduct}, nil).Once()
 
 	// setup fiber ctx
 	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
@@ -122,3 +130,221 @@ func (suite *TestSuite) TestGetAll() {
 	hErr := handler.GetAll(ctx)
 	suite.NoError(hErr)
 }
+
+func (suite *TestSuite) withSellerClaims(c *fiber.Ctx) error {
+	c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(1), "type": float64(helpers.SELLER_TYPE)})
+	return c.Next()
+}
+
+// importRequest uploads content as the CSV file of a product import
+func importRequest(target, content string) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "products.csv")
+	io.WriteString(fw, content)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set(fiber.HeaderContentType, mw.FormDataContentType())
+	return req
+}
+
+func (suite *TestSuite) TestImport() {
+	expected := []entity.Product{
+		{SKU: "KOPI-1", Name: "Kopi Toraja", Description: "arabica", Price: decimal.RequireFromString("50000.50"),
+			Weight: 250, Category: "coffee", Seller: entity.Seller{ID: 1}},
+		{SKU: "KOPI-2", Name: "Kopi Gayo", Description: "arabica, dark roast", Price: decimal.NewFromInt(45000),
+			Seller: entity.Seller{ID: 1}},
+	}
+	suite.mockProductUCase.On("Import", mock.Anything, int64(1), expected, true).
+		Return(entity.ProductImport{DryRun: true, Created: 1, Updated: 1}, nil).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Post("/sellers/me/products/import", suite.withSellerClaims, handler.Import)
+
+	// columns may come in any order and weight and category may be empty
+	content := "\ufeffSKU,price,name,description,weight,category\n" +
+		"KOPI-1,50000.50,Kopi Toraja,arabica,250,coffee\n" +
+		"KOPI-2,45000,Kopi Gayo,\"arabica, dark roast\",,\n"
+	resp, err := suite.app.Test(importRequest("/sellers/me/products/import?dryRun=true", content))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data entity.ProductImportDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(entity.ProductImportDTOResponse{DryRun: true, Created: 1, Updated: 1}, body.Data)
+	suite.mockProductUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestImportRowErrors() {
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Post("/sellers/me/products/import", suite.withSellerClaims, handler.Import)
+
+	content := "sku,name,description,price,weight\n" +
+		"KOPI-1,Kopi Toraja,arabica,abc,250\n" +
+		",Kopi Gayo,arabica,45000,\n" +
+		"KOPI-1,,arabica,40000,-1\n"
+	resp, err := suite.app.Test(importRequest("/sellers/me/products/import", content))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var body struct {
+		Code   string                  `json:"code"`
+		Errors []resterrors.FieldError `json:"errors"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(string(resterrors.CodeValidationFailed), body.Code)
+
+	// the header is row 1
+	fields := []string{}
+	for _, field := range body.Errors {
+		fields = append(fields, field.Field+" "+field.Tag)
+	}
+	suite.Equal([]string{
+		"rows[2].price number",
+		"rows[3].sku required",
+		"rows[4].sku unique",
+		"rows[4].name required",
+		"rows[4].weight gte",
+	}, fields)
+	suite.mockProductUCase.AssertNotCalled(suite.T(), "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestImportInvalidFile() {
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Post("/sellers/me/products/import", suite.withSellerClaims, handler.Import)
+
+	for _, content := range []string{
+		"",
+		"sku,name,description\n",
+		"sku,name,description,price,colour\n",
+		"sku,name,description,price\n",
+	} {
+		resp, err := suite.app.Test(importRequest("/sellers/me/products/import", content))
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, resp.StatusCode, content)
+	}
+
+	// the file is required
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/sellers/me/products/import", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *TestSuite) TestExport() {
+	suite.mockProductUCase.On("ExportBySellerID", mock.Anything, int64(1), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		w := args.Get(2).(entity.SheetWriter)
+		w.WriteRow("sku", "name", "price")
+		w.WriteRow("KOPI-1", "Kopi Toraja", decimal.NewFromInt(50000))
+		w.Close()
+	}).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Get("/sellers/me/products/export", suite.withSellerClaims, handler.Export)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/products/export", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+	suite.Equal(`attachment; filename="products.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	suite.Equal("sku,name,price\nKOPI-1,Kopi Toraja,50000.00\n", string(body))
+}
+
+func (suite *TestSuite) TestExportImportRoundTrip() {
+	// text starting like a formula is quoted by the export and must be imported as it was
+	products := []entity.Product{
+		{SKU: "-KOPI-1", Name: "=Kopi Toraja", Description: "+arabica", Price: decimal.RequireFromString("50000.50"),
+			Weight: 250, Category: "@coffee", Seller: entity.Seller{ID: 1}},
+		{SKU: "KOPI-2", Name: "Kopi 'Gayo'", Description: "arabica, dark roast", Price: decimal.RequireFromString("45000.00"),
+			Seller: entity.Seller{ID: 1}},
+	}
+	suite.mockProductUCase.On("ExportBySellerID", mock.Anything, int64(1), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		w := args.Get(2).(entity.SheetWriter)
+		w.WriteRow("sku", "name", "description", "price", "weight", "category")
+		for _, p := range products {
+			w.WriteRow(p.SKU, p.Name, p.Description, p.Price, p.Weight, p.Category)
+		}
+		w.Close()
+	}).Once()
+	suite.mockProductUCase.On("Import", mock.Anything, int64(1), products, false).
+		Return(entity.ProductImport{Updated: 2}, nil).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Get("/sellers/me/products/export", suite.withSellerClaims, handler.Export)
+	suite.app.Post("/sellers/me/products/import", suite.withSellerClaims, handler.Import)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/products/export", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	exported, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	suite.Contains(string(exported), "'-KOPI-1,'=Kopi Toraja,'+arabica,50000.50,250,'@coffee\n")
+
+	resp, err = suite.app.Test(importRequest("/sellers/me/products/import", string(exported)))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.mockProductUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestExportError() {
+	suite.mockProductUCase.On("ExportBySellerID", mock.Anything, int64(1), mock.Anything).
+		Return(resterrors.NewInternalServerError("error when trying to get data", errors.New("connection refused"))).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Get("/sellers/me/products/export", suite.withSellerClaims, handler.Export)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/sellers/me/products/export", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+}
+
+func (suite *TestSuite) TestUpdate() {
+	suite.mockProductUCase.On("Update", mock.Anything, mock.MatchedBy(func(p *entity.Product) bool {
+		return p.ID == 1 && p.Name == "Kopi Toraja" && p.Price.Equal(decimal.NewFromInt(45000))
+	}), int64(1)).Return(nil).Run(func(args mock.Arguments) {
+		p := args.Get(1).(*entity.Product)
+		p.SKU = "KOPI-1"
+		p.Seller.ID = 1
+	}).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Put("/products/:id", suite.withSellerClaims, handler.Update)
+
+	body := `{"name":"Kopi Toraja","description":"desc","price":45000,"weight":250}`
+	req := httptest.NewRequest(http.MethodPut, "/products/1", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var res struct {
+		Data entity.ProductDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&res))
+	suite.Equal("KOPI-1", res.Data.SKU)
+	suite.Equal(float64(45000), res.Data.Price)
+	suite.Equal(int64(1), res.Data.SellerID)
+	suite.mockProductUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestUpdateForbidden() {
+	suite.mockProductUCase.On("Update", mock.Anything, mock.AnythingOfType("*entity.Product"), int64(1)).
+		Return(resterrors.NewForbiddenError("product with id 2 is not sold by the seller")).Once()
+
+	handler := productcontroller.NewProductController(suite.mockProductUCase, suite.validate)
+	suite.app.Put("/products/:id", suite.withSellerClaims, handler.Update)
+
+	body := `{"name":"Kopi Toraja","description":"desc","price":45000}`
+	req := httptest.NewRequest(http.MethodPut, "/products/2", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+}
@@ -0,0 +1,160 @@
+package productcontroller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// maxImportRows is how many products can be imported at once
+const maxImportRows = 5000
+
+// requiredColumns must be in the header of an import, weight and category may be left out
+var requiredColumns = []string{"sku", "name", "description", "price"}
+
+// readProductCSV reads the products of the seller from a CSV file with a header of entity.ProductColumns in any order.
+// Every row is checked like a ProductDTORequest and must have a SKU given once in the file, the errors of all rows
+// are returned together with their field named after the row and the column, rows[2].price is the price of the first product
+func readProductCSV(r io.Reader, sellerID int64, validate *validator.Validate) ([]entity.Product, resterrors.RestErr) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, resterrors.NewBadRequestError("the file is empty")
+	}
+	if err != nil {
+		return nil, resterrors.NewBadRequestError(err.Error())
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// spreadsheet applications may start UTF-8 files with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isProductColumn(name) {
+			return nil, resterrors.NewBadRequestError(fmt.Sprintf("unknown column %s", name))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, resterrors.NewBadRequestError(fmt.Sprintf("column %s is given more than once", name))
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, resterrors.NewBadRequestError(fmt.Sprintf("column %s is missing", name))
+		}
+	}
+
+	products := []entity.Product{}
+	fields := []resterrors.FieldError{}
+	skuRows := map[string]int{}
+	for row := 2; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, resterrors.NewBadRequestError(err.Error())
+		}
+		if len(products) == maxImportRows {
+			return nil, resterrors.NewBadRequestError(fmt.Sprintf("at most %d products can be imported at once", maxImportRows))
+		}
+
+		// the export quotes text starting like a formula, the quote isn't part of the value
+		value := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(exports.UnquoteText(record[i]))
+			}
+			return ""
+		}
+		fieldError := func(column, tag, message string) resterrors.FieldError {
+			return resterrors.FieldError{Field: fmt.Sprintf("rows[%d].%s", row, column), Tag: tag, Message: message}
+		}
+
+		productReq := entity.ProductDTORequest{
+			SKU:         value("sku"),
+			Name:        value("name"),
+			Description: value("description"),
+			Category:    value("category"),
+			SellerID:    sellerID,
+		}
+		// columns that don't parse aren't validated again
+		parsed := map[string]bool{}
+
+		price, pErr := decimal.NewFromString(value("price"))
+		if pErr != nil {
+			fields = append(fields, fieldError("price", "number", "price must be a number"))
+			parsed["price"] = true
+		}
+		productReq.Price, _ = price.Float64()
+
+		if weight := value("weight"); weight != "" {
+			w, wErr := strconv.ParseInt(weight, 10, 64)
+			if wErr != nil {
+				fields = append(fields, fieldError("weight", "number", "weight must be a whole number of grams"))
+				parsed["weight"] = true
+			}
+			productReq.Weight = w
+		}
+
+		if productReq.SKU == "" {
+			fields = append(fields, fieldError("sku", "required", "sku is required to import a product"))
+		} else if first, ok := skuRows[productReq.SKU]; ok {
+			fields = append(fields, fieldError("sku", "unique", fmt.Sprintf("sku is already given in row %d", first)))
+		} else {
+			skuRows[productReq.SKU] = row
+		}
+
+		if vErr := validate.Struct(productReq); vErr != nil {
+			vFields, ok := helpers.ValidationFieldErrors(vErr)
+			if !ok {
+				return nil, resterrors.NewInternalServerError("error when trying to validate request", vErr)
+			}
+			for _, vField := range vFields {
+				column := strings.ToLower(vField.Field)
+				if parsed[column] || (column == "sku" && productReq.SKU == "") {
+					continue
+				}
+				vField.Field = fmt.Sprintf("rows[%d].%s", row, column)
+				fields = append(fields, vField)
+			}
+		}
+
+		products = append(products, entity.Product{
+			SKU:         productReq.SKU,
+			Name:        productReq.Name,
+			Description: productReq.Description,
+			Price:       price,
+			Weight:      productReq.Weight,
+			Category:    productReq.Category,
+			Seller:      entity.Seller{ID: sellerID},
+		})
+	}
+
+	if len(fields) > 0 {
+		return nil, resterrors.NewValidationError("product import failed", fields)
+	}
+	if len(products) == 0 {
+		return nil, resterrors.NewBadRequestError("the file has no products")
+	}
+	return products, nil
+}
+
+func isProductColumn(name string) bool {
+	for _, column := range entity.ProductColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
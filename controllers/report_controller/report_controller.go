@@ -0,0 +1,137 @@
+package reportcontroller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const dateLayout = "2006-01-02"
+
+type ReportController interface {
+	PlatformReport(c *fiber.Ctx) error
+	ExportPlatformReport(c *fiber.Ctx) error
+}
+
+type reportController struct {
+	reportUsecase entity.ReportUseCase
+	validate      *validator.Validate
+}
+
+// NewReportController will create a object with ReportController interface representation
+func NewReportController(r entity.ReportUseCase, v *validator.Validate) ReportController {
+	return &reportController{
+		reportUsecase: r,
+		validate:      v,
+	}
+}
+
+// PlatformReport returns what happened on the platform from from to to, both days included
+func (rctr *reportController) PlatformReport(c *fiber.Ctx) error {
+	filter, rErr := rctr.reportFilter(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	report, err := rctr.reportUsecase.GetPlatformReport(c.UserContext(), filter)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	// transform PlatformReport to PlatformReportDTOResponse
+	res := entity.PlatformReportDTOResponse{
+		From:        report.Filter.From.Format(dateLayout),
+		To:          report.Filter.To.AddDate(0, 0, -1).Format(dateLayout),
+		Group:       report.Filter.Group,
+		GeneratedAt: report.GeneratedAt,
+		Total:       toReportPeriodResponse(report.Total),
+		Periods:     []entity.ReportPeriodDTOResponse{},
+	}
+	for _, period := range report.Periods {
+		res.Periods = append(res.Periods, toReportPeriodResponse(period))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+// ExportPlatformReport downloads the report of PlatformReport as a CSV file
+func (rctr *reportController) ExportPlatformReport(c *fiber.Ctx) error {
+	filter, rErr := rctr.reportFilter(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	var buf bytes.Buffer
+	err := rctr.reportUsecase.ExportPlatformReport(c.UserContext(), filter, exports.NewCSVWriter(&buf))
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="platform-report-%s.csv"`, filter.Group))
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
+
+// reportFilter parses & validates the range of a report from query string, to is the last day included
+func (rctr *reportController) reportFilter(c *fiber.Ctx) (entity.ReportFilter, resterrors.RestErr) {
+	reportReq := new(entity.ReportDTORequest)
+	if err := c.QueryParser(reportReq); err != nil {
+		return entity.ReportFilter{}, resterrors.NewBadRequestError(err.Error())
+	}
+
+	vErr := rctr.validate.Struct(reportReq)
+	if vErr != nil {
+		return entity.ReportFilter{}, helpers.CreateValidationError(vErr)
+	}
+
+	filter := entity.ReportFilter{Group: entity.SalesGroupEnum(reportReq.Group)}
+	if reportReq.From != "" {
+		filter.From, _ = time.Parse(dateLayout, reportReq.From)
+	}
+	if reportReq.To != "" {
+		to, _ := time.Parse(dateLayout, reportReq.To)
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	return filter, nil
+}
+
+func toReportPeriodResponse(period entity.ReportPeriod) entity.ReportPeriodDTOResponse {
+	fG, _ := period.GMV.Float64()
+	f := period.Funnel
+	acceptance, _ := f.AcceptanceRate().Float64()
+	completion, _ := f.CompletionRate().Float64()
+	cancellation, _ := f.CancellationRate().Float64()
+	rejection, _ := f.RejectionRate().Float64()
+
+	res := entity.ReportPeriodDTOResponse{
+		GMV:        fG,
+		NewBuyers:  period.NewBuyers,
+		NewSellers: period.NewSellers,
+		Orders: entity.OrderFunnelDTOResponse{
+			Placed:           f.Placed,
+			Accepted:         f.Accepted,
+			Completed:        f.Completed,
+			Cancelled:        f.Cancelled,
+			Rejected:         f.Rejected,
+			AcceptanceRate:   acceptance,
+			CompletionRate:   completion,
+			CancellationRate: cancellation,
+			RejectionRate:    rejection,
+		},
+	}
+	// the total of a range has no start
+	if !period.Start.IsZero() {
+		res.Start = period.Start.Format(dateLayout)
+	}
+	return res
+}
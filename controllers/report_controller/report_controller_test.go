@@ -0,0 +1,127 @@
+package reportcontroller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	reportcontroller "github.com/hieronimusbudi/komodo-backend/controllers/report_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockReportUCase *mocks.ReportUseCase
+	app             *fiber.App
+	validate        *validator.Validate
+	filter          entity.ReportFilter
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockReportUCase = new(mocks.ReportUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.filter = entity.ReportFilter{
+		From:  time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		Group: entity.SALES_GROUP_MONTH,
+	}
+}
+
+func TestReportController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestPlatformReport() {
+	period := entity.ReportPeriod{Start: suite.filter.From, GMV: decimal.NewFromInt(300000), NewBuyers: 2, NewSellers: 1,
+		Funnel: entity.OrderFunnel{Placed: 4, Accepted: 3, Completed: 2, Cancelled: 1}}
+	total := period
+	total.Start = time.Time{}
+	generatedAt := time.Date(2021, 6, 2, 8, 0, 0, 0, time.UTC)
+	suite.mockReportUCase.On("GetPlatformReport", mock.Anything, suite.filter).Return(entity.PlatformReport{
+		Filter: suite.filter, GeneratedAt: generatedAt, Total: total, Periods: []entity.ReportPeriod{period},
+	}, nil).Once()
+
+	handler := reportcontroller.NewReportController(suite.mockReportUCase, suite.validate)
+	suite.app.Get("/admin/reports/platform", handler.PlatformReport)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/admin/reports/platform?from=2021-05-01&to=2021-05-31&group=month", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data entity.PlatformReportDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	// to is the last day of the range
+	suite.Equal("2021-05-01", body.Data.From)
+	suite.Equal("2021-05-31", body.Data.To)
+	suite.Equal(entity.SALES_GROUP_MONTH, body.Data.Group)
+	suite.True(generatedAt.Equal(body.Data.GeneratedAt))
+	suite.Equal("", body.Data.Total.Start)
+	suite.Equal(float64(300000), body.Data.Total.GMV)
+	suite.Equal(0.75, body.Data.Total.Orders.AcceptanceRate)
+	suite.Equal(0.5, body.Data.Total.Orders.CompletionRate)
+	suite.Len(body.Data.Periods, 1)
+	suite.Equal("2021-05-01", body.Data.Periods[0].Start)
+	suite.Equal(int64(2), body.Data.Periods[0].NewBuyers)
+	suite.mockReportUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestPlatformReportInvalidQuery() {
+	handler := reportcontroller.NewReportController(suite.mockReportUCase, suite.validate)
+	suite.app.Get("/admin/reports/platform", handler.PlatformReport)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/admin/reports/platform?from=01-05-2021&group=year", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+	suite.mockReportUCase.AssertNotCalled(suite.T(), "GetPlatformReport", mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestExportPlatformReport() {
+	suite.mockReportUCase.On("ExportPlatformReport", mock.Anything, suite.filter, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		w := args.Get(2).(entity.SheetWriter)
+		w.WriteRow("Period start", "GMV")
+		w.WriteRow("2021-05-01", decimal.NewFromInt(300000))
+		w.Close()
+	}).Once()
+
+	handler := reportcontroller.NewReportController(suite.mockReportUCase, suite.validate)
+	suite.app.Get("/admin/reports/platform/export", handler.ExportPlatformReport)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/admin/reports/platform/export?from=2021-05-01&to=2021-05-31&group=month", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+	suite.Equal(`attachment; filename="platform-report-month.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	suite.Equal("Period start,GMV\n2021-05-01,300000.00\n", string(body))
+}
+
+func (suite *TestSuite) TestExportPlatformReportError() {
+	suite.mockReportUCase.On("ExportPlatformReport", mock.Anything, suite.filter, mock.Anything).
+		Return(resterrors.NewInternalServerError("error when trying to get data", errors.New("connection refused"))).Once()
+
+	handler := reportcontroller.NewReportController(suite.mockReportUCase, suite.validate)
+	suite.app.Get("/admin/reports/platform/export", handler.ExportPlatformReport)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/admin/reports/platform/export?from=2021-05-01&to=2021-05-31&group=month", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+}
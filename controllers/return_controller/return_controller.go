@@ -0,0 +1,314 @@
+package returncontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type ReturnController interface {
+	Request(c *fiber.Ctx) error
+	GetByOrderID(c *fiber.Ctx) error
+	GetByID(c *fiber.Ctx) error
+	Approve(c *fiber.Ctx) error
+	Reject(c *fiber.Ctx) error
+	Cancel(c *fiber.Ctx) error
+	Ship(c *fiber.Ctx) error
+	Receive(c *fiber.Ctx) error
+}
+
+type returnController struct {
+	returnUsecase entity.ReturnUseCase
+	validate      *validator.Validate
+}
+
+// NewReturnController will create a object with ReturnController interface representation
+func NewReturnController(r entity.ReturnUseCase, v *validator.Validate) ReturnController {
+	return &returnController{
+		returnUsecase: r,
+		validate:      v,
+	}
+}
+
+func (rctr *returnController) Request(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse return from request body
+	rDTOReq := new(entity.ReturnDTORequest)
+	if err := c.BodyParser(rDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := rctr.validate.Struct(rDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret := entity.ReturnRequest{
+		OrderID: int64(orderId),
+		Reason:  entity.ReturnReasonEnum(rDTOReq.Reason),
+		Note:    rDTOReq.Note,
+		Photos:  rDTOReq.Photos,
+	}
+	for _, ri := range rDTOReq.Items {
+		ret.Items = append(ret.Items, entity.ReturnItem{OrderDetailID: ri.OrderItemID, Quantity: ri.Quantity})
+	}
+
+	if err := rctr.returnUsecase.Request(c.UserContext(), &ret, user); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) GetByOrderID(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	returns, err := rctr.returnUsecase.GetByOrderID(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	res := []entity.ReturnDTOResponse{}
+	for _, ret := range returns {
+		res = append(res, toReturnDTOResponse(ret))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+func (rctr *returnController) GetByID(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret, err := rctr.returnUsecase.GetByID(c.UserContext(), int64(returnId), user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) Approve(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret, err := rctr.returnUsecase.Approve(c.UserContext(), int64(returnId), user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) Reject(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse rejection from request body
+	rDTOReq := new(entity.ReturnRejectDTORequest)
+	if err := c.BodyParser(rDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := rctr.validate.Struct(rDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret, err := rctr.returnUsecase.Reject(c.UserContext(), int64(returnId), rDTOReq.Reason, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) Cancel(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret, err := rctr.returnUsecase.Cancel(c.UserContext(), int64(returnId), user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) Ship(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse shipment from request body
+	sDTOReq := new(entity.ReturnShipmentDTORequest)
+	if err := c.BodyParser(sDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := rctr.validate.Struct(sDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	ret, err := rctr.returnUsecase.Ship(c.UserContext(), int64(returnId), sDTOReq.Carrier, sDTOReq.TrackingNumber, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func (rctr *returnController) Receive(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	returnId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse receipt from request body, the body is optional when the items aren't restocked
+	rDTOReq := new(entity.ReturnReceiveDTORequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(rDTOReq); err != nil {
+			rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+			return helpers.ErrorResponse(c, rErr)
+		}
+	}
+
+	ret, err := rctr.returnUsecase.Receive(c.UserContext(), int64(returnId), rDTOReq.Restock, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toReturnDTOResponse(ret),
+	})
+}
+
+func toReturnDTOResponse(ret entity.ReturnRequest) entity.ReturnDTOResponse {
+	res := entity.ReturnDTOResponse{
+		ID:             ret.ID,
+		OrderID:        ret.OrderID,
+		BuyerID:        ret.BuyerID,
+		SellerID:       ret.SellerID,
+		Status:         ret.Status,
+		Reason:         ret.Reason,
+		Note:           ret.Note,
+		Photos:         ret.Photos,
+		RejectReason:   ret.RejectReason,
+		Carrier:        ret.Carrier,
+		TrackingNumber: ret.TrackingNumber,
+		Restock:        ret.Restock,
+		RefundID:       ret.RefundID,
+		CreatedAt:      ret.CreatedAt,
+		UpdatedAt:      ret.UpdatedAt,
+		ShippedAt:      ret.ShippedAt,
+		ReceivedAt:     ret.ReceivedAt,
+		Items:          []entity.ReturnItemDTOResponse{},
+	}
+	if res.Photos == nil {
+		res.Photos = []string{}
+	}
+	for _, ri := range ret.Items {
+		res.Items = append(res.Items, entity.ReturnItemDTOResponse{OrderItemID: ri.OrderDetailID, Quantity: ri.Quantity})
+	}
+	return res
+}
@@ -0,0 +1,212 @@
+package returncontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	returncontroller "github.com/hieronimusbudi/komodo-backend/controllers/return_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockReturnUCase *mocks.ReturnUseCase
+	mockReturn      entity.ReturnRequest
+	buyer           helpers.UserJWTPayload
+	seller          helpers.UserJWTPayload
+	app             *fiber.App
+	validate        *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockReturnUCase = new(mocks.ReturnUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.buyer = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	suite.seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+	suite.mockReturn = entity.ReturnRequest{
+		ID:        4,
+		OrderID:   1,
+		BuyerID:   1,
+		SellerID:  2,
+		Status:    entity.RETURN_REQUESTED,
+		Reason:    entity.RETURN_REASON_DAMAGED,
+		Photos:    []string{"https://img.example.com/1.jpg"},
+		CreatedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Items:     []entity.ReturnItem{{ID: 8, ReturnID: 4, OrderDetailID: 11, Quantity: 1}},
+	}
+}
+
+func TestReturnController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestRequest() {
+	suite.mockReturnUCase.On("Request", mock.Anything, &entity.ReturnRequest{
+		OrderID: 1,
+		Reason:  entity.RETURN_REASON_DAMAGED,
+		Photos:  []string{"https://img.example.com/1.jpg"},
+		Items:   []entity.ReturnItem{{OrderDetailID: 11, Quantity: 1}},
+	}, suite.buyer).Return(nil).Run(func(args mock.Arguments) {
+		ret := args.Get(1).(*entity.ReturnRequest)
+		ret.ID = 4
+	}).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/orders/:id/returns", testhelpers.WithClaims(suite.buyer), handler.Request)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/1/returns", strings.NewReader(
+		`{"reason":"DAMAGED","photos":["https://img.example.com/1.jpg"],"items":[{"orderItemId":11,"quantity":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.ReturnDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(int64(4), resBody.Data.ID)
+	suite.Equal(int64(11), resBody.Data.Items[0].OrderItemID)
+	suite.mockReturnUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestRequestInvalid() {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "no items", body: `{"reason":"DAMAGED","items":[]}`},
+		{name: "unknown reason", body: `{"reason":"BORED","items":[{"orderItemId":11,"quantity":1}]}`},
+		{name: "other without note", body: `{"reason":"OTHER","items":[{"orderItemId":11,"quantity":1}]}`},
+		{name: "photo not an url", body: `{"reason":"DAMAGED","photos":["photo"],"items":[{"orderItemId":11,"quantity":1}]}`},
+	}
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/orders/:id/returns", testhelpers.WithClaims(suite.buyer), handler.Request)
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/orders/1/returns", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, tc.name)
+	}
+	suite.mockReturnUCase.AssertNotCalled(suite.T(), "Request", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mockReturnUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.seller).
+		Return([]entity.ReturnRequest{suite.mockReturn}, nil).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Get("/orders/:id/returns", testhelpers.WithClaims(suite.seller), handler.GetByOrderID)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/returns", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data []entity.ReturnDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Len(resBody.Data, 1)
+	suite.Equal([]string{"https://img.example.com/1.jpg"}, resBody.Data[0].Photos)
+}
+
+func (suite *TestSuite) TestShip() {
+	shipped := suite.mockReturn
+	shipped.Status = entity.RETURN_SHIPPED
+	shipped.Carrier = "JNE"
+	shipped.TrackingNumber = "JNE123"
+	suite.mockReturnUCase.On("Ship", mock.Anything, int64(4), "JNE", "JNE123", suite.buyer).Return(shipped, nil).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/returns/:id/shipment", testhelpers.WithClaims(suite.buyer), handler.Ship)
+
+	req := httptest.NewRequest(http.MethodPost, "/returns/4/shipment", strings.NewReader(`{"carrier":"JNE","trackingNumber":"JNE123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.ReturnDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(entity.RETURN_SHIPPED, resBody.Data.Status)
+	suite.Equal("JNE123", resBody.Data.TrackingNumber)
+}
+
+func (suite *TestSuite) TestReject() {
+	suite.mockReturnUCase.On("Reject", mock.Anything, int64(4), "used item", suite.seller).
+		Return(entity.ReturnRequest{}, resterrors.NewConflictError("return with id 4 can't be moved from status 4 to 2")).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/returns/:id/reject", testhelpers.WithClaims(suite.seller), handler.Reject)
+
+	req := httptest.NewRequest(http.MethodPost, "/returns/4/reject", strings.NewReader(`{"reason":"used item"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusConflict, res.StatusCode)
+}
+
+func (suite *TestSuite) TestReceive() {
+	received := suite.mockReturn
+	received.Status = entity.RETURN_REFUNDED
+	received.Restock = true
+	received.RefundID = 6
+	suite.mockReturnUCase.On("Receive", mock.Anything, int64(4), true, suite.seller).Return(received, nil).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/returns/:id/receive", testhelpers.WithClaims(suite.seller), handler.Receive)
+
+	req := httptest.NewRequest(http.MethodPost, "/returns/4/receive", strings.NewReader(`{"restock":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.ReturnDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(int64(6), resBody.Data.RefundID)
+	suite.True(resBody.Data.Restock)
+}
+
+func (suite *TestSuite) TestReceiveWithoutBody() {
+	suite.mockReturnUCase.On("Receive", mock.Anything, int64(4), false, suite.seller).Return(suite.mockReturn, nil).Once()
+
+	handler := returncontroller.NewReturnController(suite.mockReturnUCase, suite.validate)
+	suite.app.Post("/returns/:id/receive", testhelpers.WithClaims(suite.seller), handler.Receive)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodPost, "/returns/4/receive", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.mockReturnUCase.AssertExpectations(suite.T())
+}
@@ -31,34 +31,39 @@ func NewSellerController(u entity.SellerUseCase, v *validator.Validate) SellerCo
 func (sctr *sellerController) Register(c *fiber.Ctx) error {
 	sellerReq := new(entity.SellerDTORequest)
 	if err := c.BodyParser(sellerReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := sctr.validate.Struct(sellerReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	seller := entity.Seller{
-		Email:         sellerReq.Email,
-		Name:          sellerReq.Name,
-		Password:      sellerReq.Password,
-		PickUpAddress: sellerReq.PickUpAddress,
+		Email:            sellerReq.Email,
+		Name:             sellerReq.Name,
+		Password:         sellerReq.Password,
+		PickUpAddress:    sellerReq.PickUpAddress,
+		PickUpProvince:   sellerReq.PickUpProvince,
+		PickUpPostalCode: sellerReq.PickUpPostalCode,
+		TaxStatus:        entity.SellerTaxStatusEnum(sellerReq.TaxStatus),
 	}
-	err := sctr.sellerUseCase.Register(&seller)
+	err := sctr.sellerUseCase.Register(c.UserContext(), &seller)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	sellerRes := entity.SellerDTOResponse{
-		ID:            seller.ID,
-		Email:         seller.Email,
-		Name:          seller.Name,
-		PickUpAddress: seller.PickUpAddress,
+		ID:               seller.ID,
+		Email:            seller.Email,
+		Name:             seller.Name,
+		PickUpAddress:    seller.PickUpAddress,
+		PickUpProvince:   seller.PickUpProvince,
+		PickUpPostalCode: seller.PickUpPostalCode,
+		TaxStatus:        seller.TaxStatus,
 	}
 	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
 		Data: sellerRes,
@@ -68,25 +73,24 @@ func (sctr *sellerController) Register(c *fiber.Ctx) error {
 func (sctr *sellerController) Login(c *fiber.Ctx) error {
 	loginReq := new(entity.SellerDTOLogin)
 	if err := c.BodyParser(loginReq); err != nil {
-		rErr := resterrors.NewRestError("unprocessable entity", http.StatusUnprocessableEntity, err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// validate request
 	vErr := sctr.validate.Struct(loginReq)
 	if vErr != nil {
-		message, _ := helpers.CreateValidationMessage(vErr)
-		rErr := resterrors.NewBadRequestError(message)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	seller := entity.Seller{
 		Email:    loginReq.Email,
 		Password: loginReq.Password,
 	}
-	seller, err := sctr.sellerUseCase.Login(&seller)
+	seller, err := sctr.sellerUseCase.Login(c.UserContext(), &seller)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	// create jwt token
@@ -99,15 +103,18 @@ func (sctr *sellerController) Login(c *fiber.Ctx) error {
 	}, []byte(config.JWT_SECRET))
 	if tokenErr != nil {
 		rErr := resterrors.NewInternalServerError("generate token error ", tokenErr)
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	res := helpers.JWTResponse{
 		Data: entity.SellerDTOResponse{
-			ID:            seller.ID,
-			Email:         seller.Email,
-			Name:          seller.Name,
-			PickUpAddress: seller.PickUpAddress,
+			ID:               seller.ID,
+			Email:            seller.Email,
+			Name:             seller.Name,
+			PickUpAddress:    seller.PickUpAddress,
+			PickUpProvince:   seller.PickUpProvince,
+			PickUpPostalCode: seller.PickUpPostalCode,
+			TaxStatus:        seller.TaxStatus,
 		},
 		Type:  jwtUserType,
 		Token: token,
@@ -55,7 +55,7 @@ func TestSellerController(t *testing.T) {
 }
 
 func (suite *TestSuite) TestRegister() {
-	suite.mockSellerUCase.On("Register", mock.AnythingOfType("*entity.Seller")).Return(nil).Once()
+	suite.mockSellerUCase.On("Register", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(nil).Once()
 
 	j, err := json.Marshal(suite.mockSellerDTOReq)
 	suite.NoError(err)
@@ -73,7 +73,7 @@ func (suite *TestSuite) TestRegister() {
 }
 
 func (suite *TestSuite) TestLogin() {
-	suite.mockSellerUCase.On("Login", mock.AnythingOfType("*entity.Seller")).Return(suite.mockSeller, nil).Once()
+	suite.mockSellerUCase.On("Login", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(suite.mockSeller, nil).Once()
 
 	j, err := json.Marshal(suite.mockSellerLoginReq)
 	suite.NoError(err)
@@ -0,0 +1,115 @@
+package shipmentcontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type ShipmentController interface {
+	Ship(c *fiber.Ctx) error
+	Tracking(c *fiber.Ctx) error
+}
+
+type shipmentController struct {
+	shipmentUsecase entity.ShipmentUseCase
+	validate        *validator.Validate
+}
+
+// NewShipmentController will create a object with ShipmentController interface representation
+func NewShipmentController(s entity.ShipmentUseCase, v *validator.Validate) ShipmentController {
+	return &shipmentController{
+		shipmentUsecase: s,
+		validate:        v,
+	}
+}
+
+func (sctr *shipmentController) Ship(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse shipment from request body
+	sDTOReq := new(entity.ShipmentDTORequest)
+	if err := c.BodyParser(sDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := sctr.validate.Struct(sDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	shipment := entity.Shipment{
+		OrderID:        int64(orderId),
+		Courier:        sDTOReq.Courier,
+		TrackingNumber: sDTOReq.TrackingNumber,
+	}
+	if err := sctr.shipmentUsecase.Ship(c.UserContext(), &shipment, user); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toShipmentDTOResponse(shipment),
+	})
+}
+
+func (sctr *shipmentController) Tracking(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// extract params
+	orderId, idErr := c.ParamsInt("id")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	shipment, err := sctr.shipmentUsecase.GetByOrderID(c.UserContext(), &entity.Order{ID: int64(orderId)}, user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: toShipmentDTOResponse(shipment),
+	})
+}
+
+func toShipmentDTOResponse(shipment entity.Shipment) entity.ShipmentDTOResponse {
+	res := entity.ShipmentDTOResponse{
+		OrderID:        shipment.OrderID,
+		Courier:        shipment.Courier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		CheckedAt:      shipment.CheckedAt,
+		Events:         []entity.TrackingEventDTOResponse{},
+	}
+	for _, te := range shipment.Events {
+		res.Events = append(res.Events, entity.TrackingEventDTOResponse{
+			Code:        te.Code,
+			Description: te.Description,
+			Location:    te.Location,
+			OccurredAt:  te.OccurredAt,
+		})
+	}
+	return res
+}
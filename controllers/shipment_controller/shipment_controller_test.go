@@ -0,0 +1,142 @@
+package shipmentcontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	shipmentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipment_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockShipmentUCase *mocks.ShipmentUseCase
+	mockShipment      entity.Shipment
+	buyer             helpers.UserJWTPayload
+	seller            helpers.UserJWTPayload
+	app               *fiber.App
+	validate          *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockShipmentUCase = new(mocks.ShipmentUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.buyer = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	suite.seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+	suite.mockShipment = entity.Shipment{
+		ID:             4,
+		OrderID:        1,
+		Courier:        "JNE",
+		TrackingNumber: "JNE123",
+		Status:         entity.SHIPMENT_IN_TRANSIT,
+		ShippedAt:      time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Events: []entity.TrackingEvent{
+			{ID: 7, ShipmentID: 4, Code: "PICKED_UP", Description: "Parcel picked up by courier", Location: "Jakarta",
+				OccurredAt: time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestShipmentController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestShip() {
+	suite.mockShipmentUCase.On("Ship", mock.Anything, &entity.Shipment{OrderID: 1, Courier: "JNE", TrackingNumber: "JNE123"}, suite.seller).
+		Return(nil).Run(func(args mock.Arguments) {
+		shipment := args.Get(1).(*entity.Shipment)
+		shipment.ID = 4
+		shipment.ShippedAt = suite.mockShipment.ShippedAt
+	}).Once()
+
+	handler := shipmentcontroller.NewShipmentController(suite.mockShipmentUCase, suite.validate)
+	suite.app.Post("/orders/:id/shipment", testhelpers.WithClaims(suite.seller), handler.Ship)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/1/shipment", strings.NewReader(`{"courier":"JNE","trackingNumber":"JNE123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.ShipmentDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal("JNE123", resBody.Data.TrackingNumber)
+	suite.Equal(suite.mockShipment.ShippedAt, resBody.Data.ShippedAt)
+	suite.Empty(resBody.Data.Events)
+	suite.mockShipmentUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestShipInvalid() {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "no courier", body: `{"trackingNumber":"JNE123"}`},
+		{name: "no tracking number", body: `{"courier":"JNE"}`},
+		{name: "tracking number with spaces", body: `{"courier":"JNE","trackingNumber":"JNE 123"}`},
+	}
+
+	handler := shipmentcontroller.NewShipmentController(suite.mockShipmentUCase, suite.validate)
+	suite.app.Post("/orders/:id/shipment", testhelpers.WithClaims(suite.seller), handler.Ship)
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/orders/1/shipment", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, tc.name)
+	}
+	suite.mockShipmentUCase.AssertNotCalled(suite.T(), "Ship", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestTracking() {
+	suite.mockShipmentUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.buyer).Return(suite.mockShipment, nil).Once()
+
+	handler := shipmentcontroller.NewShipmentController(suite.mockShipmentUCase, suite.validate)
+	suite.app.Get("/orders/:id/tracking", testhelpers.WithClaims(suite.buyer), handler.Tracking)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/tracking", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.ShipmentDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Len(resBody.Data.Events, 1)
+	suite.Equal("Jakarta", resBody.Data.Events[0].Location)
+	suite.Nil(resBody.Data.DeliveredAt)
+}
+
+func (suite *TestSuite) TestTrackingNotShipped() {
+	suite.mockShipmentUCase.On("GetByOrderID", mock.Anything, &entity.Order{ID: 1}, suite.buyer).
+		Return(entity.Shipment{}, resterrors.NewNotFoundError("order with id 1 wasn't shipped")).Once()
+
+	handler := shipmentcontroller.NewShipmentController(suite.mockShipmentUCase, suite.validate)
+	suite.app.Get("/orders/:id/tracking", testhelpers.WithClaims(suite.buyer), handler.Tracking)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/orders/1/tracking", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+}
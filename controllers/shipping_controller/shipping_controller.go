@@ -0,0 +1,73 @@
+package shippingcontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type ShippingController interface {
+	Quote(c *fiber.Ctx) error
+}
+
+type shippingController struct {
+	shippingUsecase entity.ShippingUseCase
+	validate        *validator.Validate
+}
+
+// NewShippingController will create a object with ShippingController interface representation
+func NewShippingController(s entity.ShippingUseCase, v *validator.Validate) ShippingController {
+	return &shippingController{
+		shippingUsecase: s,
+		validate:        v,
+	}
+}
+
+func (sctr *shippingController) Quote(c *fiber.Ctx) error {
+	// parse quote from request body
+	qDTOReq := new(entity.ShippingQuoteDTORequest)
+	if err := c.BodyParser(qDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := sctr.validate.Struct(qDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	quote := entity.ShippingQuote{
+		Destination: entity.ShippingAddress{Province: qDTOReq.Destination.Province, PostalCode: qDTOReq.Destination.PostalCode},
+	}
+	for _, od := range qDTOReq.Items {
+		quote.Items = append(quote.Items, entity.OrderDetail{Product: entity.Product{ID: od.ProductId}, Quantity: od.Quantity})
+	}
+
+	rates, err := sctr.shippingUsecase.Quote(c.UserContext(), quote)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	res := []entity.ShippingRateDTOResponse{}
+	for _, r := range rates {
+		fF, _ := r.Fee.Float64()
+		res = append(res, entity.ShippingRateDTOResponse{
+			Service:       r.Service,
+			Carrier:       r.Carrier,
+			Name:          r.Name,
+			Fee:           fF,
+			Weight:        r.Weight,
+			EstimatedDays: r.EstimatedDays,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
@@ -0,0 +1,90 @@
+package shippingcontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	shippingcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipping_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockShippingUCase *mocks.ShippingUseCase
+	app               *fiber.App
+	validate          *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockShippingUCase = new(mocks.ShippingUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+}
+
+func TestShippingController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestQuote() {
+	suite.mockShippingUCase.On("Quote", mock.Anything, entity.ShippingQuote{
+		Destination: entity.ShippingAddress{Province: "Jawa Barat"},
+		Items:       []entity.OrderDetail{{Product: entity.Product{ID: 1}, Quantity: 2}},
+	}).Return([]entity.ShippingRate{
+		{Service: "JNE_REG", Carrier: "JNE", Name: "Reguler", Fee: decimal.NewFromInt(20000), Weight: 1000, EstimatedDays: 4},
+	}, nil).Once()
+
+	handler := shippingcontroller.NewShippingController(suite.mockShippingUCase, suite.validate)
+	suite.app.Post("/shipping/quotes", handler.Quote)
+
+	req := httptest.NewRequest(http.MethodPost, "/shipping/quotes", strings.NewReader(
+		`{"destination":{"province":"Jawa Barat"},"items":[{"productId":1,"quantity":2}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data []entity.ShippingRateDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Len(resBody.Data, 1)
+	suite.Equal("JNE_REG", resBody.Data[0].Service)
+	suite.Equal(float64(20000), resBody.Data[0].Fee)
+	suite.mockShippingUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestQuoteInvalid() {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "no items", body: `{"destination":{"postalCode":"40115"},"items":[]}`},
+		{name: "empty destination", body: `{"destination":{},"items":[{"productId":1,"quantity":1}]}`},
+		{name: "short postal code", body: `{"destination":{"postalCode":"401"},"items":[{"productId":1,"quantity":1}]}`},
+	}
+
+	handler := shippingcontroller.NewShippingController(suite.mockShippingUCase, suite.validate)
+	suite.app.Post("/shipping/quotes", handler.Quote)
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/shipping/quotes", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, tc.name)
+	}
+	suite.mockShippingUCase.AssertNotCalled(suite.T(), "Quote", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,114 @@
+package vouchercontroller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type VoucherController interface {
+	Store(c *fiber.Ctx) error
+	Fetch(c *fiber.Ctx) error
+}
+
+type voucherController struct {
+	voucherUsecase entity.VoucherUseCase
+	validate       *validator.Validate
+}
+
+// NewVoucherController will create a object with VoucherController interface representation
+func NewVoucherController(v entity.VoucherUseCase, validate *validator.Validate) VoucherController {
+	return &voucherController{
+		voucherUsecase: v,
+		validate:       validate,
+	}
+}
+
+func (vctr *voucherController) Store(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse voucher from request body
+	vDTOReq := new(entity.VoucherDTORequest)
+	if err := c.BodyParser(vDTOReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := vctr.validate.Struct(vDTOReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	voucher := entity.Voucher{
+		Code:          strings.ToUpper(vDTOReq.Code),
+		Type:          entity.VoucherTypeEnum(vDTOReq.Type),
+		Value:         decimal.NewFromFloat(vDTOReq.Value),
+		SellerID:      vDTOReq.SellerID,
+		MinSpend:      decimal.NewFromFloat(vDTOReq.MinSpend),
+		MaxDiscount:   decimal.NewFromFloat(vDTOReq.MaxDiscount),
+		StartsAt:      vDTOReq.StartsAt,
+		EndsAt:        vDTOReq.EndsAt,
+		UsageLimit:    vDTOReq.UsageLimit,
+		PerBuyerLimit: vDTOReq.PerBuyerLimit,
+	}
+	if err := vctr.voucherUsecase.Store(c.UserContext(), &voucher, user); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toVoucherDTOResponse(voucher),
+	})
+}
+
+func (vctr *voucherController) Fetch(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	vouchers, err := vctr.voucherUsecase.Fetch(c.UserContext(), user)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	res := []entity.VoucherDTOResponse{}
+	for _, v := range vouchers {
+		res = append(res, toVoucherDTOResponse(v))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+func toVoucherDTOResponse(v entity.Voucher) entity.VoucherDTOResponse {
+	fV, _ := v.Value.Float64()
+	fMS, _ := v.MinSpend.Float64()
+	fMD, _ := v.MaxDiscount.Float64()
+	return entity.VoucherDTOResponse{
+		ID:            v.ID,
+		Code:          v.Code,
+		Type:          v.Type,
+		Value:         fV,
+		SellerID:      v.SellerID,
+		MinSpend:      fMS,
+		MaxDiscount:   fMD,
+		StartsAt:      v.StartsAt,
+		EndsAt:        v.EndsAt,
+		UsageLimit:    v.UsageLimit,
+		PerBuyerLimit: v.PerBuyerLimit,
+		UsedCount:     v.UsedCount,
+		CreatedAt:     v.CreatedAt,
+	}
+}
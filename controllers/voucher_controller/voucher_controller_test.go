@@ -0,0 +1,144 @@
+package vouchercontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	vouchercontroller "github.com/hieronimusbudi/komodo-backend/controllers/voucher_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockVoucherUCase *mocks.VoucherUseCase
+	mockVoucher      entity.Voucher
+	seller           helpers.UserJWTPayload
+	app              *fiber.App
+	validate         *validator.Validate
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockVoucherUCase = new(mocks.VoucherUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+	suite.mockVoucher = entity.Voucher{
+		ID:          3,
+		Code:        "HEMAT10",
+		Type:        entity.VOUCHER_PERCENTAGE,
+		Value:       decimal.NewFromInt(10),
+		SellerID:    2,
+		MinSpend:    decimal.NewFromInt(50000),
+		MaxDiscount: decimal.NewFromInt(25000),
+		StartsAt:    time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:      time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		UsageLimit:  100,
+		UsedCount:   7,
+	}
+}
+
+func TestVoucherController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mockVoucherUCase.On("Store", mock.Anything, mock.MatchedBy(func(v *entity.Voucher) bool {
+		return v.Code == "HEMAT10" && v.Type == entity.VOUCHER_PERCENTAGE && v.Value.Equal(decimal.NewFromInt(10)) &&
+			v.MaxDiscount.Equal(decimal.NewFromInt(25000)) && v.UsageLimit == 100
+	}), suite.seller).Return(nil).Run(func(args mock.Arguments) {
+		v := args.Get(1).(*entity.Voucher)
+		v.ID = 3
+	}).Once()
+
+	handler := vouchercontroller.NewVoucherController(suite.mockVoucherUCase, suite.validate)
+	suite.app.Post("/vouchers", testhelpers.WithClaims(suite.seller), handler.Store)
+
+	req := httptest.NewRequest(http.MethodPost, "/vouchers", strings.NewReader(`{"code":"hemat10","type":"PERCENTAGE","value":10,`+
+		`"minSpend":50000,"maxDiscount":25000,"startsAt":"2021-05-01T00:00:00Z","endsAt":"2021-06-01T00:00:00Z","usageLimit":100}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data entity.VoucherDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Equal(int64(3), resBody.Data.ID)
+	suite.Equal(float64(25000), resBody.Data.MaxDiscount)
+	suite.mockVoucherUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestStoreInvalid() {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown type", body: `{"code":"HEMAT10","type":"FREE","value":10,"startsAt":"2021-05-01T00:00:00Z","endsAt":"2021-06-01T00:00:00Z"}`},
+		{name: "code with space", body: `{"code":"HEMAT 10","type":"FIXED","value":10,"startsAt":"2021-05-01T00:00:00Z","endsAt":"2021-06-01T00:00:00Z"}`},
+		{name: "no value", body: `{"code":"HEMAT10","type":"FIXED","startsAt":"2021-05-01T00:00:00Z","endsAt":"2021-06-01T00:00:00Z"}`},
+		{name: "ends before start", body: `{"code":"HEMAT10","type":"FIXED","value":10,"startsAt":"2021-06-01T00:00:00Z","endsAt":"2021-05-01T00:00:00Z"}`},
+	}
+
+	handler := vouchercontroller.NewVoucherController(suite.mockVoucherUCase, suite.validate)
+	suite.app.Post("/vouchers", testhelpers.WithClaims(suite.seller), handler.Store)
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/vouchers", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := suite.app.Test(req)
+		suite.NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, tc.name)
+	}
+	suite.mockVoucherUCase.AssertNotCalled(suite.T(), "Store", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestFetch() {
+	suite.mockVoucherUCase.On("Fetch", mock.Anything, suite.seller).Return([]entity.Voucher{suite.mockVoucher}, nil).Once()
+
+	handler := vouchercontroller.NewVoucherController(suite.mockVoucherUCase, suite.validate)
+	suite.app.Get("/vouchers", testhelpers.WithClaims(suite.seller), handler.Fetch)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/vouchers", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	suite.NoError(err)
+	var resBody struct {
+		Data []entity.VoucherDTOResponse `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(body, &resBody))
+	suite.Len(resBody.Data, 1)
+	suite.Equal(int64(7), resBody.Data[0].UsedCount)
+}
+
+func (suite *TestSuite) TestFetchForbidden() {
+	buyer := helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	suite.mockVoucherUCase.On("Fetch", mock.Anything, buyer).
+		Return(nil, resterrors.NewForbiddenError("only sellers and admins can list vouchers")).Once()
+
+	handler := vouchercontroller.NewVoucherController(suite.mockVoucherUCase, suite.validate)
+	suite.app.Get("/vouchers", testhelpers.WithClaims(buyer), handler.Fetch)
+
+	res, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/vouchers", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+}
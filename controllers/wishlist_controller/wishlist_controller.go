@@ -0,0 +1,203 @@
+package wishlistcontroller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type WishlistController interface {
+	AddItem(c *fiber.Ctx) error
+	GetItems(c *fiber.Ctx) error
+	RemoveItem(c *fiber.Ctx) error
+	Follow(c *fiber.Ctx) error
+	GetFollowing(c *fiber.Ctx) error
+	Unfollow(c *fiber.Ctx) error
+}
+
+type wishlistController struct {
+	wishlistUsecase entity.WishlistUseCase
+	validate        *validator.Validate
+}
+
+// NewWishlistController will create a object with WishlistController interface representation
+func NewWishlistController(w entity.WishlistUseCase, v *validator.Validate) WishlistController {
+	return &wishlistController{
+		wishlistUsecase: w,
+		validate:        v,
+	}
+}
+
+// AddItem saves a product to the wishlist of the logged in buyer
+func (wctr *wishlistController) AddItem(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse item from request body
+	itemReq := new(entity.WishlistItemDTORequest)
+	if err := c.BodyParser(itemReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := wctr.validate.Struct(itemReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	item, err := wctr.wishlistUsecase.AddItem(c.UserContext(), user.ID, itemReq.ProductID)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toWishlistItemResponse(item),
+	})
+}
+
+// GetItems returns the wishlist of the logged in buyer
+func (wctr *wishlistController) GetItems(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	items, err := wctr.wishlistUsecase.GetItems(c.UserContext(), user.ID)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	res := []entity.WishlistItemDTOResponse{}
+	for _, item := range items {
+		res = append(res, toWishlistItemResponse(item))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+// RemoveItem takes the product with the productId param out of the wishlist of the logged in buyer
+func (wctr *wishlistController) RemoveItem(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	productId, idErr := c.ParamsInt("productId")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	if err := wctr.wishlistUsecase.RemoveItem(c.UserContext(), user.ID, int64(productId)); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// Follow makes the logged in buyer follow a seller
+func (wctr *wishlistController) Follow(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// parse seller from request body
+	followReq := new(entity.FollowedSellerDTORequest)
+	if err := c.BodyParser(followReq); err != nil {
+		rErr := resterrors.NewUnprocessableEntityError("unprocessable entity", err)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	// validate request
+	vErr := wctr.validate.Struct(followReq)
+	if vErr != nil {
+		rErr := helpers.CreateValidationError(vErr)
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	followed, err := wctr.wishlistUsecase.Follow(c.UserContext(), user.ID, followReq.SellerID)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(helpers.SuccessResponse{
+		Data: toFollowedSellerResponse(followed),
+	})
+}
+
+// GetFollowing returns the sellers the logged in buyer follows
+func (wctr *wishlistController) GetFollowing(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	following, err := wctr.wishlistUsecase.GetFollowing(c.UserContext(), user.ID)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+
+	res := []entity.FollowedSellerDTOResponse{}
+	for _, followed := range following {
+		res = append(res, toFollowedSellerResponse(followed))
+	}
+
+	return c.Status(http.StatusOK).JSON(helpers.SuccessResponse{
+		Data: res,
+	})
+}
+
+// Unfollow makes the logged in buyer stop following the seller with the sellerId param
+func (wctr *wishlistController) Unfollow(c *fiber.Ctx) error {
+	user, rErr := helpers.LoggedInUser(c)
+	if rErr != nil {
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	sellerId, idErr := c.ParamsInt("sellerId")
+	if idErr != nil {
+		rErr := resterrors.NewBadRequestError(idErr.Error())
+		return helpers.ErrorResponse(c, rErr)
+	}
+
+	if err := wctr.wishlistUsecase.Unfollow(c.UserContext(), user.ID, int64(sellerId)); err != nil {
+		return helpers.ErrorResponse(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func toWishlistItemResponse(item entity.WishlistItem) entity.WishlistItemDTOResponse {
+	p := item.Product
+	fP, _ := p.Price.Float64()
+	return entity.WishlistItemDTOResponse{
+		Product: entity.ProductDTOResponse{
+			ID:          p.ID,
+			SKU:         p.SKU,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       fP,
+			Weight:      p.Weight,
+			Category:    p.Category,
+			SellerID:    p.Seller.ID,
+		},
+		AddedAt: item.AddedAt,
+	}
+}
+
+func toFollowedSellerResponse(followed entity.FollowedSeller) entity.FollowedSellerDTOResponse {
+	return entity.FollowedSellerDTOResponse{
+		SellerID:   followed.Seller.ID,
+		Name:       followed.Seller.Name,
+		FollowedAt: followed.FollowedAt,
+	}
+}
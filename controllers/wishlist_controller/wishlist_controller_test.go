@@ -0,0 +1,177 @@
+package wishlistcontroller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	wishlistcontroller "github.com/hieronimusbudi/komodo-backend/controllers/wishlist_controller"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuite struct {
+	suite.Suite
+	mockWishlistUCase *mocks.WishlistUseCase
+	buyer             helpers.UserJWTPayload
+	app               *fiber.App
+	validate          *validator.Validate
+	now               time.Time
+}
+
+// for each test
+func (suite *TestSuite) SetupTest() {
+	suite.mockWishlistUCase = new(mocks.WishlistUseCase)
+	suite.app = fiber.New()
+	suite.validate = validator.New()
+	suite.buyer = helpers.UserJWTPayload{ID: 3, Type: helpers.BUYER_TYPE}
+	suite.now = time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func TestWishlistController(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func jsonRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return req
+}
+
+func (suite *TestSuite) TestAddItem() {
+	product := entity.Product{ID: 7, SKU: "KOPI-1", Name: "Kopi Toraja", Price: decimal.NewFromInt(50000), Seller: entity.Seller{ID: 1}}
+	suite.mockWishlistUCase.On("AddItem", mock.Anything, int64(3), int64(7)).
+		Return(entity.WishlistItem{BuyerID: 3, Product: product, AddedAt: suite.now}, nil).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Post("/buyers/me/wishlist", testhelpers.WithClaims(suite.buyer), handler.AddItem)
+
+	resp, err := suite.app.Test(jsonRequest(http.MethodPost, "/buyers/me/wishlist", `{"productId":7}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+
+	var body struct {
+		Data entity.WishlistItemDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(int64(7), body.Data.Product.ID)
+	suite.Equal(float64(50000), body.Data.Product.Price)
+	suite.True(suite.now.Equal(body.Data.AddedAt))
+	suite.mockWishlistUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestAddItemInvalid() {
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Post("/buyers/me/wishlist", testhelpers.WithClaims(suite.buyer), handler.AddItem)
+
+	resp, err := suite.app.Test(jsonRequest(http.MethodPost, "/buyers/me/wishlist", `{}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(resterrors.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+	suite.mockWishlistUCase.AssertNotCalled(suite.T(), "AddItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestGetItems() {
+	suite.mockWishlistUCase.On("GetItems", mock.Anything, int64(3)).Return([]entity.WishlistItem{}, nil).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Get("/buyers/me/wishlist", testhelpers.WithClaims(suite.buyer), handler.GetItems)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/buyers/me/wishlist", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []entity.WishlistItemDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	// an empty wishlist is an empty list
+	suite.NotNil(body.Data)
+	suite.Len(body.Data, 0)
+}
+
+func (suite *TestSuite) TestRemoveItem() {
+	suite.mockWishlistUCase.On("RemoveItem", mock.Anything, int64(3), int64(7)).Return(nil).Once()
+	suite.mockWishlistUCase.On("RemoveItem", mock.Anything, int64(3), int64(8)).
+		Return(resterrors.NewNotFoundError("product with id 8 is not in the wishlist")).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Delete("/buyers/me/wishlist/:productId", testhelpers.WithClaims(suite.buyer), handler.RemoveItem)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodDelete, "/buyers/me/wishlist/7", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = suite.app.Test(httptest.NewRequest(http.MethodDelete, "/buyers/me/wishlist/8", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+	suite.mockWishlistUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestFollow() {
+	suite.mockWishlistUCase.On("Follow", mock.Anything, int64(3), int64(1)).Return(entity.FollowedSeller{
+		BuyerID: 3, Seller: entity.Seller{ID: 1, Name: "john seller"}, FollowedAt: suite.now,
+	}, nil).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Post("/buyers/me/following", testhelpers.WithClaims(suite.buyer), handler.Follow)
+
+	resp, err := suite.app.Test(jsonRequest(http.MethodPost, "/buyers/me/following", `{"sellerId":1}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+
+	var body struct {
+		Data entity.FollowedSellerDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(int64(1), body.Data.SellerID)
+	suite.Equal("john seller", body.Data.Name)
+	suite.mockWishlistUCase.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestGetFollowing() {
+	suite.mockWishlistUCase.On("GetFollowing", mock.Anything, int64(3)).Return([]entity.FollowedSeller{
+		{BuyerID: 3, Seller: entity.Seller{ID: 1, Name: "john seller"}, FollowedAt: suite.now},
+	}, nil).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Get("/buyers/me/following", testhelpers.WithClaims(suite.buyer), handler.GetFollowing)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/buyers/me/following", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []entity.FollowedSellerDTOResponse `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Len(body.Data, 1)
+	suite.Equal("john seller", body.Data[0].Name)
+}
+
+func (suite *TestSuite) TestUnfollow() {
+	suite.mockWishlistUCase.On("Unfollow", mock.Anything, int64(3), int64(1)).Return(nil).Once()
+
+	handler := wishlistcontroller.NewWishlistController(suite.mockWishlistUCase, suite.validate)
+	suite.app.Delete("/buyers/me/following/:sellerId", testhelpers.WithClaims(suite.buyer), handler.Unfollow)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodDelete, "/buyers/me/following/1", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = suite.app.Test(httptest.NewRequest(http.MethodDelete, "/buyers/me/following/abc", nil))
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.mockWishlistUCase.AssertExpectations(suite.T())
+}
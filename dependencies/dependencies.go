@@ -2,21 +2,116 @@ package dependencies
 
 import (
 	"database/sql"
+	"log"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/hieronimusbudi/komodo-backend/config"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/events"
+	"github.com/hieronimusbudi/komodo-backend/framework/invoices"
+	"github.com/hieronimusbudi/komodo-backend/framework/payments"
 	mysqlpersistence "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql"
+	"github.com/hieronimusbudi/komodo-backend/framework/shipping"
+	"github.com/hieronimusbudi/komodo-backend/framework/tax"
+)
+
+const (
+	defaultPaymentCurrency = "IDR"
+	// invoiceIssuer is the marketplace named on the invoices it issues for its sellers
+	invoiceIssuer = "Komodo"
 )
 
 type Dependencies struct {
-	Conn     *sql.DB
-	Validate *validator.Validate
+	Conn            *sql.DB
+	Validate        *validator.Validate
+	Publisher       entity.EventPublisher
+	PaymentGateway  entity.PaymentGateway
+	PaymentCurrency string
+	ShippingRates   entity.ShippingRateProvider
+	CourierTracker  entity.CourierTracker
+	TaxPolicy       entity.TaxPolicy
+	InvoiceRenderer entity.InvoiceRenderer
+	Usecases        *Usecases
 }
 
 func NewDependencies() *Dependencies {
 	conn := mysqlpersistence.Client
 	validate := validator.New()
-	return &Dependencies{
-		Conn:     conn,
-		Validate: validate,
+	publisher := events.NewLogPublisher(nil)
+	currency := paymentCurrency()
+	d := &Dependencies{
+		Conn:            conn,
+		Validate:        validate,
+		Publisher:       publisher,
+		PaymentGateway:  newPaymentGateway(),
+		PaymentCurrency: currency,
+		ShippingRates:   newShippingRates(),
+		CourierTracker:  newCourierTracker(),
+		TaxPolicy:       newTaxPolicy(),
+		InvoiceRenderer: invoices.NewPDFRenderer(invoiceIssuer, currency),
+	}
+	d.Usecases = NewUsecases(d)
+	return d
+}
+
+// newPaymentGateway returns the gateway named by PAYMENT_GATEWAY, the fake one authorizing every payment
+// is only used when it is named, the app doesn't start when no gateway is configured
+func newPaymentGateway() entity.PaymentGateway {
+	switch config.PAYMENT_GATEWAY {
+	case "":
+		log.Fatalln("PAYMENT_GATEWAY is not configured")
+	case payments.FakeGatewayName:
+		return payments.NewFakeGateway(true, config.PAYMENT_WEBHOOK_SECRET)
+	}
+	if config.PAYMENT_GATEWAY_URL == "" {
+		log.Fatalln("PAYMENT_GATEWAY_URL is not configured for payment gateway", config.PAYMENT_GATEWAY)
+	}
+	return payments.NewHTTPGateway(config.PAYMENT_GATEWAY, config.PAYMENT_GATEWAY_URL, config.PAYMENT_GATEWAY_KEY,
+		config.PAYMENT_WEBHOOK_SECRET, nil)
+}
+
+func paymentCurrency() string {
+	if config.PAYMENT_CURRENCY == "" {
+		return defaultPaymentCurrency
+	}
+	return config.PAYMENT_CURRENCY
+}
+
+// newShippingRates quotes from the rate table saved as JSON at SHIPPING_RATE_TABLE, the default table is used
+// when none is configured. The app doesn't start when the configured table can't be read
+func newShippingRates() entity.ShippingRateProvider {
+	if config.SHIPPING_RATE_TABLE == "" {
+		return shipping.NewTableRateProvider(shipping.DefaultRateTable())
+	}
+	table, err := shipping.LoadRateTable(config.SHIPPING_RATE_TABLE)
+	if err != nil {
+		log.Fatalln("shipping rate table error", err)
+	}
+	return shipping.NewTableRateProvider(table)
+}
+
+// newCourierTracker returns the tracker named by COURIER_TRACKER, only the fake one moving parcels one step
+// each time they are tracked exists as no courier is integrated yet. Shipments aren't tracked when none is configured
+func newCourierTracker() entity.CourierTracker {
+	switch config.COURIER_TRACKER {
+	case "":
+		return nil
+	case shipping.FakeTrackerName:
+		return shipping.NewFakeTracker(true)
+	}
+	log.Fatalln("unknown COURIER_TRACKER", config.COURIER_TRACKER)
+	return nil
+}
+
+// newTaxPolicy taxes by the rules saved as JSON at TAX_RULES, the default rules are used when none are configured.
+// The app doesn't start when the configured rules can't be read
+func newTaxPolicy() entity.TaxPolicy {
+	if config.TAX_RULES == "" {
+		return tax.NewRulePolicy(tax.DefaultRules())
+	}
+	rules, err := tax.LoadRules(config.TAX_RULES)
+	if err != nil {
+		log.Fatalln("tax rules error", err)
 	}
+	return tax.NewRulePolicy(rules)
 }
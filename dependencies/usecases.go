@@ -0,0 +1,112 @@
+package dependencies
+
+import (
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/config"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	analyticsrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/analytics_repository"
+	idempotencyrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/idempotency_repository"
+	invoicerepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/invoice_repository"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	orderrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/order_repository"
+	paymentrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/payment_repository"
+	paymentwebhookrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/payment_webhook_event_repository"
+	productrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/product_repository"
+	refundrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/refund_repository"
+	reportrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/report_repository"
+	returnrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/return_repository"
+	sellerrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/seller_repository"
+	shipmentrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/shipment_repository"
+	voucherrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/voucher_repository"
+	wishlistrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/wishlist_repository"
+	analyticsusecase "github.com/hieronimusbudi/komodo-backend/usecases/analytics_usecase"
+	idempotencyusecase "github.com/hieronimusbudi/komodo-backend/usecases/idempotency_usecase"
+	invoiceusecase "github.com/hieronimusbudi/komodo-backend/usecases/invoice_usecase"
+	orderusecase "github.com/hieronimusbudi/komodo-backend/usecases/order_usecase"
+	paymentusecase "github.com/hieronimusbudi/komodo-backend/usecases/payment_usecase"
+	productusecase "github.com/hieronimusbudi/komodo-backend/usecases/product_usecase"
+	reportusecase "github.com/hieronimusbudi/komodo-backend/usecases/report_usecase"
+	returnusecase "github.com/hieronimusbudi/komodo-backend/usecases/return_usecase"
+	shipmentusecase "github.com/hieronimusbudi/komodo-backend/usecases/shipment_usecase"
+	shippingusecase "github.com/hieronimusbudi/komodo-backend/usecases/shipping_usecase"
+	taxusecase "github.com/hieronimusbudi/komodo-backend/usecases/tax_usecase"
+	voucherusecase "github.com/hieronimusbudi/komodo-backend/usecases/voucher_usecase"
+	wishlistusecase "github.com/hieronimusbudi/komodo-backend/usecases/wishlist_usecase"
+)
+
+const (
+	defaultReportCacheTTL    = 5 * time.Minute
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+)
+
+// Usecases are built once and shared by the routes and the background workers
+type Usecases struct {
+	Product     entity.ProductUseCase
+	Wishlist    entity.WishlistUseCase
+	Shipping    entity.ShippingUseCase
+	Voucher     entity.VoucherUseCase
+	Payment     entity.PaymentUseCase
+	Order       entity.OrderUseCase
+	Return      entity.ReturnUseCase
+	Shipment    entity.ShipmentUseCase
+	Invoice     entity.InvoiceUseCase
+	Analytics   entity.AnalyticsUseCase
+	Report      entity.ReportUseCase
+	Locker      entity.Locker
+	Idempotency entity.IdempotencyUseCase
+}
+
+// NewUsecases wires the repositories of d into the usecases
+func NewUsecases(d *Dependencies) *Usecases {
+	u := &Usecases{
+		Locker: mysqlutils.NewMysqlLocker(d.Conn),
+	}
+	rSeller := sellerrepo.NewMysqlSellerRepository(d.Conn)
+
+	// idempotency keys of buyers, the response of a request is replayed to its retries until the key expires
+	u.Idempotency = idempotencyusecase.NewIdempotencyUsecase(idempotencyrepo.NewMysqlIdempotencyRepository(d.Conn),
+		helpers.ParseDuration(config.IDEMPOTENCY_KEY_TTL, defaultIdempotencyKeyTTL))
+
+	// wishlist and followed sellers of buyers, the buyers are told when a product in their wishlist gets cheaper
+	rP := productrepo.NewMysqlProductRepository(d.Conn)
+	u.Wishlist = wishlistusecase.NewWishlistUsecase(wishlistrepo.NewMysqlWishlistRepository(d.Conn), rP, rSeller, d.Publisher)
+	u.Product = productusecase.NewProductUsecase(rP, d.Publisher, u.Wishlist)
+
+	// shipping rates, quoted from the weight of the products and the pickup region of their seller
+	u.Shipping = shippingusecase.NewShippingUsecase(d.ShippingRates, rP, rSeller)
+
+	// voucher, its usage is released when the order is cancelled
+	u.Voucher = voucherusecase.NewVoucherUsecase(voucherrepo.NewMysqlVoucherRepository(d.Conn))
+
+	// tax of the items, its rate depends on the tax status of their seller
+	uT := taxusecase.NewTaxUsecase(d.TaxPolicy, rSeller)
+
+	// order and its payment, the payment is released or refunded, the voucher given back and the stock released
+	// when the order is cancelled
+	rO := orderrepo.NewMysqlOrderRepository(d.Conn)
+	uPay := paymentusecase.NewPaymentUsecase(paymentrepo.NewMysqlPaymentRepository(d.Conn),
+		paymentwebhookrepo.NewMysqlPaymentWebhookEventRepository(d.Conn), refundrepo.NewMysqlRefundRepository(d.Conn), rO,
+		d.PaymentGateway, u.Locker, d.PaymentCurrency)
+	u.Payment = uPay
+	u.Order = orderusecase.NewOrderUsecase(rO, rP, uPay, u.Voucher, uT, u.Shipping, d.Publisher, uPay, u.Voucher, u.Product)
+
+	// returns of accepted orders, refunded through the payment of the order once received
+	u.Return = returnusecase.NewReturnUsecase(returnrepo.NewMysqlReturnRepository(d.Conn), rO, uPay, d.Publisher, u.Locker)
+
+	// shipment of accepted orders, tracked with the courier until the order is delivered
+	u.Shipment = shipmentusecase.NewShipmentUsecase(shipmentrepo.NewMysqlShipmentRepository(d.Conn), rO, u.Order, d.CourierTracker)
+
+	// invoices of accepted orders, numbered per seller when they are first asked for
+	u.Invoice = invoiceusecase.NewInvoiceUsecase(invoicerepo.NewMysqlInvoiceRepository(d.Conn), u.Order, rSeller, d.InvoiceRenderer)
+
+	// sales analytics of sellers, the days before today are read from the daily sales when a worker keeps them
+	dailySales := helpers.ParseDuration(config.SALES_ROLLUP_INTERVAL, 0) > 0
+	u.Analytics = analyticsusecase.NewAnalyticsUsecase(analyticsrepo.NewMysqlAnalyticsRepository(d.Conn), dailySales)
+
+	// platform reports for admins, kept for a while as they sum every order
+	u.Report = reportusecase.NewReportUsecase(reportrepo.NewMysqlReportRepository(d.Conn),
+		helpers.ParseDuration(config.REPORT_CACHE_TTL, defaultReportCacheTTL))
+	return u
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"context"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// Admin runs the platform, admins aren't registered through the API, they are added to the admins table
+type Admin struct {
+	ID       int64
+	Email    string
+	Name     string
+	Password string
+}
+
+type AdminDTOLogin struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type AdminDTOResponse struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type AdminUseCase interface {
+	// Login returns the admin with the email and password of admin, an unknown email and a wrong password
+	// both fail with the same unauthorized error
+	Login(ctx context.Context, admin *Admin) (Admin, resterrors.RestErr)
+}
+
+type AdminRepository interface {
+	GetByEmail(ctx context.Context, admin *Admin) (Admin, resterrors.RestErr)
+}
@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// SalesGroupEnum is the length of the periods sales are summed over
+type SalesGroupEnum string
+
+const (
+	SALES_GROUP_DAY   SalesGroupEnum = "day"
+	SALES_GROUP_WEEK  SalesGroupEnum = "week"
+	SALES_GROUP_MONTH SalesGroupEnum = "month"
+)
+
+// SoldOrderStatuses are the statuses of the orders counted as sales, the seller accepted them
+var SoldOrderStatuses = []OrderStatusEnum{ACCEPTED, SHIPPED, DELIVERED}
+
+// PeriodStart is the start of the period of group t is in, weeks start on Monday
+func (g SalesGroupEnum) PeriodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch g {
+	case SALES_GROUP_WEEK:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case SALES_GROUP_MONTH:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Next is the start of the period after the one starting at start
+func (g SalesGroupEnum) Next(start time.Time) time.Time {
+	switch g {
+	case SALES_GROUP_WEEK:
+		return start.AddDate(0, 0, 7)
+	case SALES_GROUP_MONTH:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// SalesFilter picks the orders placed from From until before To, Top is how many products are ranked
+type SalesFilter struct {
+	From  time.Time
+	To    time.Time
+	Group SalesGroupEnum
+	Top   int
+}
+
+// Sales sums the orders sold in a period, it starts at Start. Revenue sums the subtotals of their line items
+// less the discount, without tax and shipping fees
+type Sales struct {
+	Start     time.Time
+	Orders    int64
+	Revenue   decimal.Decimal
+	UnitsSold int64
+}
+
+// AverageOrderValue is the revenue per order, zero without orders
+func (s Sales) AverageOrderValue() decimal.Decimal {
+	if s.Orders == 0 {
+		return decimal.Zero
+	}
+	return s.Revenue.DivRound(decimal.NewFromInt(s.Orders), 2)
+}
+
+// Add sums s and other, keeping the start of s
+func (s Sales) Add(other Sales) Sales {
+	s.Orders += other.Orders
+	s.Revenue = s.Revenue.Add(other.Revenue)
+	s.UnitsSold += other.UnitsSold
+	return s
+}
+
+// ProductSales is how much of a product was sold, Revenue is defined like the revenue of Sales
+type ProductSales struct {
+	Product   Product
+	UnitsSold int64
+	Revenue   decimal.Decimal
+}
+
+// SalesAnalytics are the sales of a seller over the range of a SalesFilter, Total sums Periods.
+// Periods has every period of the range in order, with zero sales when nothing was sold
+type SalesAnalytics struct {
+	Filter      SalesFilter
+	Total       Sales
+	Periods     []Sales
+	TopProducts []ProductSales
+}
+
+type SalesAnalyticsDTORequest struct {
+	From  string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Group string `query:"group" validate:"omitempty,oneof=day week month"`
+	Top   int    `query:"top" validate:"omitempty,gte=1,lte=50"`
+}
+
+type SalesDTOResponse struct {
+	Start             string  `json:"start,omitempty"`
+	Orders            int64   `json:"orders"`
+	Revenue           float64 `json:"revenue"`
+	AverageOrderValue float64 `json:"averageOrderValue"`
+	UnitsSold         int64   `json:"unitsSold"`
+}
+
+type ProductSalesDTOResponse struct {
+	ProductID int64   `json:"productId"`
+	SKU       string  `json:"sku,omitempty"`
+	Name      string  `json:"name"`
+	UnitsSold int64   `json:"unitsSold"`
+	Revenue   float64 `json:"revenue"`
+}
+
+type SalesAnalyticsDTOResponse struct {
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	Group       SalesGroupEnum            `json:"group"`
+	Total       SalesDTOResponse          `json:"total"`
+	Periods     []SalesDTOResponse        `json:"periods"`
+	TopProducts []ProductSalesDTOResponse `json:"topProducts"`
+}
+
+type AnalyticsUseCase interface {
+	// GetSellerSales returns the sales of the seller in the range of filter
+	GetSellerSales(ctx context.Context, sellerID int64, filter SalesFilter) (SalesAnalytics, resterrors.RestErr)
+	// RefreshDailySales computes the daily sales of every seller again for the last days, today included
+	RefreshDailySales(ctx context.Context, days int) resterrors.RestErr
+}
+
+type AnalyticsRepository interface {
+	// GetSales sums the orders of the seller sold from until before to by period of group
+	GetSales(ctx context.Context, sellerID int64, from, to time.Time, group SalesGroupEnum) ([]Sales, resterrors.RestErr)
+	// GetDailySales is GetSales read from the daily sales saved by RefreshDailySales, from and to are days
+	GetDailySales(ctx context.Context, sellerID int64, from, to time.Time, group SalesGroupEnum) ([]Sales, resterrors.RestErr)
+	// GetTopProducts returns the limit products of the seller sold the most from until before to, by revenue
+	GetTopProducts(ctx context.Context, sellerID int64, from, to time.Time, limit int) ([]ProductSales, resterrors.RestErr)
+	// RefreshDailySales replaces the daily sales of every seller from day from until before day to
+	RefreshDailySales(ctx context.Context, from, to time.Time) resterrors.RestErr
+}
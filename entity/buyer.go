@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"context"
+	"time"
+
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 )
 
@@ -10,6 +13,7 @@ type Buyer struct {
 	Name           string
 	Password       string
 	SendingAddress string
+	CreatedAt      time.Time
 }
 
 type BuyerDTORequest struct {
@@ -43,15 +47,15 @@ type BuyerDTOResponse struct {
 }
 
 type BuyerUseCase interface {
-	Register(buyer *Buyer) resterrors.RestErr
-	Login(buyer *Buyer) (Buyer, resterrors.RestErr)
+	Register(ctx context.Context, buyer *Buyer) resterrors.RestErr
+	Login(ctx context.Context, buyer *Buyer) (Buyer, resterrors.RestErr)
 }
 
 type BuyerRepository interface {
-	GetAll() ([]Buyer, resterrors.RestErr)
-	GetByID(buyer *Buyer) resterrors.RestErr
-	Update(buyer *Buyer) resterrors.RestErr
-	Store(buyer *Buyer) resterrors.RestErr
-	Delete(buyer *Buyer) resterrors.RestErr
-	GetByEmail(buyer *Buyer) (Buyer, resterrors.RestErr)
+	GetAll(ctx context.Context) ([]Buyer, resterrors.RestErr)
+	GetByID(ctx context.Context, buyer *Buyer) resterrors.RestErr
+	Update(ctx context.Context, buyer *Buyer) resterrors.RestErr
+	Store(ctx context.Context, buyer *Buyer) resterrors.RestErr
+	Delete(ctx context.Context, buyer *Buyer) resterrors.RestErr
+	GetByEmail(ctx context.Context, buyer *Buyer) (Buyer, resterrors.RestErr)
 }
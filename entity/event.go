@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ORDER_EXPIRED_EVENT   = "order.expired"
+	ORDER_DELIVERED_EVENT = "order.delivered"
+	RETURN_RECEIVED_EVENT = "return.received"
+
+	STOCK_RELEASED_EVENT = "stock.released"
+
+	WISHLIST_PRICE_DROPPED_EVENT = "wishlist.price_dropped"
+	SELLER_FOLLOWED_EVENT        = "seller.followed"
+)
+
+// Event is something that happened to the domain, published for listeners like notification senders
+type Event struct {
+	Name       string      `json:"name"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Payload    interface{} `json:"payload"`
+}
+
+// OrderEventPayload is the payload of order events
+type OrderEventPayload struct {
+	OrderID    int64               `json:"orderId"`
+	BuyerID    int64               `json:"buyerId"`
+	SellerID   int64               `json:"sellerId"`
+	Status     OrderStatusEnum     `json:"status"`
+	ReasonCode OrderReasonCodeEnum `json:"reasonCode,omitempty"`
+}
+
+// ReturnEventPayload is the payload of return events, Items are the products sent back.
+// Restock tells listeners keeping stock to add the items back, the products themselves don't keep stock
+type ReturnEventPayload struct {
+	ReturnID int64                    `json:"returnId"`
+	OrderID  int64                    `json:"orderId"`
+	BuyerID  int64                    `json:"buyerId"`
+	SellerID int64                    `json:"sellerId"`
+	Status   ReturnStatusEnum         `json:"status"`
+	Restock  bool                     `json:"restock"`
+	Items    []ReturnEventItemPayload `json:"items"`
+}
+
+type ReturnEventItemPayload struct {
+	ProductID int64 `json:"productId"`
+	Quantity  int64 `json:"quantity"`
+}
+
+// StockEventPayload is the payload of stock events, Items are the products an order held before it was cancelled
+// or rejected. Products don't keep stock, listeners keeping it add the items back
+type StockEventPayload struct {
+	OrderID  int64                   `json:"orderId"`
+	SellerID int64                   `json:"sellerId"`
+	Status   OrderStatusEnum         `json:"status"`
+	Items    []StockEventItemPayload `json:"items"`
+}
+
+type StockEventItemPayload struct {
+	ProductID int64 `json:"productId"`
+	Quantity  int64 `json:"quantity"`
+}
+
+// PriceDropEventPayload is the payload of a price drop of a product in the wishlist of the buyer
+type PriceDropEventPayload struct {
+	BuyerID   int64           `json:"buyerId"`
+	ProductID int64           `json:"productId"`
+	SellerID  int64           `json:"sellerId"`
+	Name      string          `json:"name"`
+	OldPrice  decimal.Decimal `json:"oldPrice"`
+	NewPrice  decimal.Decimal `json:"newPrice"`
+}
+
+// FollowEventPayload is the payload of a buyer following a seller
+type FollowEventPayload struct {
+	BuyerID  int64 `json:"buyerId"`
+	SellerID int64 `json:"sellerId"`
+}
+
+// EventPublisher delivers events to their listeners
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) resterrors.RestErr
+}
@@ -0,0 +1,9 @@
+package entity
+
+// SheetWriter writes the rows of a spreadsheet as it gets them. Cells are string, int64, bool, decimal.Decimal
+// or time.Time values, decimals are amounts and are written with two decimals
+type SheetWriter interface {
+	WriteRow(cells ...interface{}) error
+	// Close writes what is left of the sheet, rows can't be written after
+	Close() error
+}
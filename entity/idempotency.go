@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// IdempotencyKey is a key sent by a buyer with a request that must not be made twice, like placing an order.
+// RequestHash identifies the request the key was first used with and the response is kept to be replayed
+// to retries until ExpiresAt, ResponseStatus is 0 while the first request is still running
+type IdempotencyKey struct {
+	Key                 string
+	BuyerID             int64
+	RequestHash         string
+	ResponseStatus      int
+	ResponseContentType string
+	ResponseBody        []byte
+	CreatedAt           time.Time
+	ExpiresAt           time.Time
+}
+
+// Completed reports whether the response of the request made with the key is saved
+func (k IdempotencyKey) Completed() bool {
+	return k.ResponseStatus != 0
+}
+
+type IdempotencyUseCase interface {
+	// Reserve saves key of the buyer for the request identified by requestHash until the keys expire. When a request with
+	// the same key and hash was completed already its saved key is returned with replay set, so its response is sent
+	// again. The key used with another request fails with 422 and while a request made with it still runs with 409
+	Reserve(ctx context.Context, buyerID int64, key string, requestHash string) (saved IdempotencyKey, replay bool, err resterrors.RestErr)
+	// Complete saves the response of the request made with key. A key whose response can't be saved is released,
+	// so retries are not rejected until it expires
+	Complete(ctx context.Context, key *IdempotencyKey) resterrors.RestErr
+	// Release removes key so the request can be made again with it
+	Release(ctx context.Context, key *IdempotencyKey) resterrors.RestErr
+	// DeleteExpired removes the expired keys and returns how many were removed
+	DeleteExpired(ctx context.Context) (int64, resterrors.RestErr)
+}
+
+type IdempotencyRepository interface {
+	// Reserve saves key unless the buyer already used the same key and it hasn't expired,
+	// then ok is false and the saved key is returned
+	Reserve(ctx context.Context, key *IdempotencyKey) (existing IdempotencyKey, ok bool, err resterrors.RestErr)
+	// Complete saves the response of the request made with key
+	Complete(ctx context.Context, key *IdempotencyKey) resterrors.RestErr
+	// Delete removes key so the request can be made again with it
+	Delete(ctx context.Context, key *IdempotencyKey) resterrors.RestErr
+	// DeleteExpired removes keys that expired before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, resterrors.RestErr)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// Invoice is the invoice of an order, an order has at most one. Number counts the invoices of the seller from 1
+// in the order they were issued. Order is only filled by InvoiceUseCase.GetByOrderID, with the pickup address of its seller
+type Invoice struct {
+	ID       int64
+	OrderID  int64
+	SellerID int64
+	Number   int64
+	IssuedAt time.Time
+	Order    Order
+}
+
+// Code is the invoice number printed on the invoice, it is unique across sellers
+func (i Invoice) Code() string {
+	return fmt.Sprintf("INV/%d/%06d", i.SellerID, i.Number)
+}
+
+// FileName is the name of the invoice document without its extension
+func (i Invoice) FileName() string {
+	return strings.ReplaceAll(i.Code(), "/", "-")
+}
+
+// InvoiceRenderer writes an invoice as a document
+type InvoiceRenderer interface {
+	Render(w io.Writer, invoice Invoice) error
+}
+
+type InvoiceUseCase interface {
+	// GetByOrderID returns the invoice of an accepted order with the order, it is issued the first time it is asked for
+	GetByOrderID(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Invoice, resterrors.RestErr)
+	// Render writes invoice to w
+	Render(ctx context.Context, invoice Invoice, w io.Writer) resterrors.RestErr
+}
+
+type InvoiceRepository interface {
+	GetByOrderID(ctx context.Context, orderID int64) (Invoice, resterrors.RestErr)
+	// Store numbers invoice after the last invoice of its seller and saves it, storing a second invoice of an order fails
+	Store(ctx context.Context, invoice *Invoice) resterrors.RestErr
+}
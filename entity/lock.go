@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"context"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// Locker hands out named locks shared by every instance of the app. TryLock doesn't wait,
+// ok is false when another instance holds the lock, otherwise unlock must be called to release it
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err resterrors.RestErr)
+}
@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,13 +16,13 @@ type BuyerRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: buyer
-func (_m *BuyerRepository) Delete(buyer *entity.Buyer) resterrors.RestErr {
-	ret := _m.Called(buyer)
+// Delete provides a mock function with given fields: ctx, buyer
+func (_m *BuyerRepository) Delete(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -30,13 +32,13 @@ func (_m *BuyerRepository) Delete(buyer *entity.Buyer) resterrors.RestErr {
 	return r0
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *BuyerRepository) GetAll() ([]entity.Buyer, resterrors.RestErr) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *BuyerRepository) GetAll(ctx context.Context) ([]entity.Buyer, resterrors.RestErr) {
+	ret := _m.Called(ctx)
 
 	var r0 []entity.Buyer
-	if rf, ok := ret.Get(0).(func() []entity.Buyer); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Buyer); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Buyer)
@@ -44,8 +46,8 @@ func (_m *BuyerRepository) GetAll() ([]entity.Buyer, resterrors.RestErr) {
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func() resterrors.RestErr); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) resterrors.RestErr); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -55,20 +57,20 @@ func (_m *BuyerRepository) GetAll() ([]entity.Buyer, resterrors.RestErr) {
 	return r0, r1
 }
 
-// GetByEmail provides a mock function with given fields: buyer
-func (_m *BuyerRepository) GetByEmail(buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
-	ret := _m.Called(buyer)
+// GetByEmail provides a mock function with given fields: ctx, buyer
+func (_m *BuyerRepository) GetByEmail(ctx context.Context, buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 entity.Buyer
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) entity.Buyer); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) entity.Buyer); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		r0 = ret.Get(0).(entity.Buyer)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r1 = rf(buyer)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r1 = rf(ctx, buyer)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -78,13 +80,13 @@ func (_m *BuyerRepository) GetByEmail(buyer *entity.Buyer) (entity.Buyer, rester
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: buyer
-func (_m *BuyerRepository) GetByID(buyer *entity.Buyer) resterrors.RestErr {
-	ret := _m.Called(buyer)
+// GetByID provides a mock function with given fields: ctx, buyer
+func (_m *BuyerRepository) GetByID(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -94,13 +96,13 @@ func (_m *BuyerRepository) GetByID(buyer *entity.Buyer) resterrors.RestErr {
 	return r0
 }
 
-// Store provides a mock function with given fields: buyer
-func (_m *BuyerRepository) Store(buyer *entity.Buyer) resterrors.RestErr {
-	ret := _m.Called(buyer)
+// Store provides a mock function with given fields: ctx, buyer
+func (_m *BuyerRepository) Store(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -110,13 +112,13 @@ func (_m *BuyerRepository) Store(buyer *entity.Buyer) resterrors.RestErr {
 	return r0
 }
 
-// Update provides a mock function with given fields: buyer
-func (_m *BuyerRepository) Update(buyer *entity.Buyer) resterrors.RestErr {
-	ret := _m.Called(buyer)
+// Update provides a mock function with given fields: ctx, buyer
+func (_m *BuyerRepository) Update(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
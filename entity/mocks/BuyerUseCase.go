@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,20 +16,20 @@ type BuyerUseCase struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: buyer
-func (_m *BuyerUseCase) Login(buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
-	ret := _m.Called(buyer)
+// Login provides a mock function with given fields: ctx, buyer
+func (_m *BuyerUseCase) Login(ctx context.Context, buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 entity.Buyer
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) entity.Buyer); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) entity.Buyer); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		r0 = ret.Get(0).(entity.Buyer)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r1 = rf(buyer)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r1 = rf(ctx, buyer)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -37,13 +39,13 @@ func (_m *BuyerUseCase) Login(buyer *entity.Buyer) (entity.Buyer, resterrors.Res
 	return r0, r1
 }
 
-// Register provides a mock function with given fields: buyer
-func (_m *BuyerUseCase) Register(buyer *entity.Buyer) resterrors.RestErr {
-	ret := _m.Called(buyer)
+// Register provides a mock function with given fields: ctx, buyer
+func (_m *BuyerUseCase) Register(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	ret := _m.Called(ctx, buyer)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Buyer) resterrors.RestErr); ok {
-		r0 = rf(buyer)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Buyer) resterrors.RestErr); ok {
+		r0 = rf(ctx, buyer)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
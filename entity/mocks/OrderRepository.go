@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+	time "time"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,13 +17,13 @@ type OrderRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: order
-func (_m *OrderRepository) Delete(order *entity.Order) resterrors.RestErr {
-	ret := _m.Called(order)
+// Delete provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) Delete(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	ret := _m.Called(ctx, order)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Order) resterrors.RestErr); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r0 = rf(ctx, order)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -30,13 +33,13 @@ func (_m *OrderRepository) Delete(order *entity.Order) resterrors.RestErr {
 	return r0
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *OrderRepository) GetAll() ([]entity.Order, resterrors.RestErr) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *OrderRepository) GetAll(ctx context.Context) ([]entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx)
 
 	var r0 []entity.Order
-	if rf, ok := ret.Get(0).(func() []entity.Order); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Order); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Order)
@@ -44,8 +47,8 @@ func (_m *OrderRepository) GetAll() ([]entity.Order, resterrors.RestErr) {
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func() resterrors.RestErr); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) resterrors.RestErr); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -55,22 +58,20 @@ func (_m *OrderRepository) GetAll() ([]entity.Order, resterrors.RestErr) {
 	return r0, r1
 }
 
-// GetByBuyerID provides a mock function with given fields: buyerID
-func (_m *OrderRepository) GetByBuyerID(buyerID int64) ([]entity.Order, resterrors.RestErr) {
-	ret := _m.Called(buyerID)
+// GetByBuyerID provides a mock function with given fields: ctx, buyerID, filter
+func (_m *OrderRepository) GetByBuyerID(ctx context.Context, buyerID int64, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	ret := _m.Called(ctx, buyerID, filter)
 
-	var r0 []entity.Order
-	if rf, ok := ret.Get(0).(func(int64) []entity.Order); ok {
-		r0 = rf(buyerID)
+	var r0 entity.OrderPage
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.OrderFilter) entity.OrderPage); ok {
+		r0 = rf(ctx, buyerID, filter)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.Order)
-		}
+		r0 = ret.Get(0).(entity.OrderPage)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(int64) resterrors.RestErr); ok {
-		r1 = rf(buyerID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.OrderFilter) resterrors.RestErr); ok {
+		r1 = rf(ctx, buyerID, filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -80,20 +81,43 @@ func (_m *OrderRepository) GetByBuyerID(buyerID int64) ([]entity.Order, resterro
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: order
-func (_m *OrderRepository) GetByID(order *entity.Order) (entity.Order, resterrors.RestErr) {
-	ret := _m.Called(order)
+// GetByID provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) GetByID(ctx context.Context, order *entity.Order) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order)
 
 	var r0 entity.Order
-	if rf, ok := ret.Get(0).(func(*entity.Order) entity.Order); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) entity.Order); ok {
+		r0 = rf(ctx, order)
 	} else {
 		r0 = ret.Get(0).(entity.Order)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Order) resterrors.RestErr); ok {
-		r1 = rf(order)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r1 = rf(ctx, order)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetBySellerID provides a mock function with given fields: ctx, sellerID, filter
+func (_m *OrderRepository) GetBySellerID(ctx context.Context, sellerID int64, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	ret := _m.Called(ctx, sellerID, filter)
+
+	var r0 entity.OrderPage
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.OrderFilter) entity.OrderPage); ok {
+		r0 = rf(ctx, sellerID, filter)
+	} else {
+		r0 = ret.Get(0).(entity.OrderPage)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.OrderFilter) resterrors.RestErr); ok {
+		r1 = rf(ctx, sellerID, filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -103,13 +127,13 @@ func (_m *OrderRepository) GetByID(order *entity.Order) (entity.Order, resterror
 	return r0, r1
 }
 
-// GetBySellerID provides a mock function with given fields: buyerID
-func (_m *OrderRepository) GetBySellerID(buyerID int64) ([]entity.Order, resterrors.RestErr) {
-	ret := _m.Called(buyerID)
+// GetPendingBefore provides a mock function with given fields: ctx, placedBefore, limit
+func (_m *OrderRepository) GetPendingBefore(ctx context.Context, placedBefore time.Time, limit int) ([]entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, placedBefore, limit)
 
 	var r0 []entity.Order
-	if rf, ok := ret.Get(0).(func(int64) []entity.Order); ok {
-		r0 = rf(buyerID)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []entity.Order); ok {
+		r0 = rf(ctx, placedBefore, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Order)
@@ -117,8 +141,8 @@ func (_m *OrderRepository) GetBySellerID(buyerID int64) ([]entity.Order, resterr
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(int64) resterrors.RestErr); ok {
-		r1 = rf(buyerID)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) resterrors.RestErr); ok {
+		r1 = rf(ctx, placedBefore, limit)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -128,13 +152,29 @@ func (_m *OrderRepository) GetBySellerID(buyerID int64) ([]entity.Order, resterr
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: order
-func (_m *OrderRepository) Store(order *entity.Order) resterrors.RestErr {
-	ret := _m.Called(order)
+// Store provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) Store(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	ret := _m.Called(ctx, order)
+
+	var r0 resterrors.RestErr
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r0 = rf(ctx, order)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(resterrors.RestErr)
+		}
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) Update(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	ret := _m.Called(ctx, order)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Order) resterrors.RestErr); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r0 = rf(ctx, order)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -144,13 +184,13 @@ func (_m *OrderRepository) Store(order *entity.Order) resterrors.RestErr {
 	return r0
 }
 
-// Update provides a mock function with given fields: order
-func (_m *OrderRepository) Update(order *entity.Order) resterrors.RestErr {
-	ret := _m.Called(order)
+// UpdateStatus provides a mock function with given fields: ctx, order, change
+func (_m *OrderRepository) UpdateStatus(ctx context.Context, order *entity.Order, change entity.OrderStatusHistory) resterrors.RestErr {
+	ret := _m.Called(ctx, order, change)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Order) resterrors.RestErr); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, entity.OrderStatusHistory) resterrors.RestErr); ok {
+		r0 = rf(ctx, order, change)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -3,8 +3,10 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
 	helpers "github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -16,20 +18,20 @@ type OrderUseCase struct {
 	mock.Mock
 }
 
-// AcceptOrder provides a mock function with given fields: order
-func (_m *OrderUseCase) AcceptOrder(order *entity.Order) (entity.Order, resterrors.RestErr) {
-	ret := _m.Called(order)
+// AcceptOrder provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) AcceptOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order, user)
 
 	var r0 entity.Order
-	if rf, ok := ret.Get(0).(func(*entity.Order) entity.Order); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) entity.Order); ok {
+		r0 = rf(ctx, order, user)
 	} else {
 		r0 = ret.Get(0).(entity.Order)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Order) resterrors.RestErr); ok {
-		r1 = rf(order)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r1 = rf(ctx, order, user)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -39,13 +41,36 @@ func (_m *OrderUseCase) AcceptOrder(order *entity.Order) (entity.Order, resterro
 	return r0, r1
 }
 
-// GetByUserID provides a mock function with given fields: userID, userType
-func (_m *OrderUseCase) GetByUserID(userID int64, userType helpers.UserTypeEnum) ([]entity.Order, resterrors.RestErr) {
-	ret := _m.Called(userID, userType)
+// CancelOrder provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) CancelOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order, user)
+
+	var r0 entity.Order
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) entity.Order); ok {
+		r0 = rf(ctx, order, user)
+	} else {
+		r0 = ret.Get(0).(entity.Order)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r1 = rf(ctx, order, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// ExpirePendingOrders provides a mock function with given fields: ctx, placedBefore, limit
+func (_m *OrderUseCase) ExpirePendingOrders(ctx context.Context, placedBefore time.Time, limit int) ([]entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, placedBefore, limit)
 
 	var r0 []entity.Order
-	if rf, ok := ret.Get(0).(func(int64, helpers.UserTypeEnum) []entity.Order); ok {
-		r0 = rf(userID, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []entity.Order); ok {
+		r0 = rf(ctx, placedBefore, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Order)
@@ -53,8 +78,148 @@ func (_m *OrderUseCase) GetByUserID(userID int64, userType helpers.UserTypeEnum)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(int64, helpers.UserTypeEnum) resterrors.RestErr); ok {
-		r1 = rf(userID, userType)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) resterrors.RestErr); ok {
+		r1 = rf(ctx, placedBefore, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// ExportBySellerID provides a mock function with given fields: ctx, sellerID, filter
+func (_m *OrderUseCase) ExportBySellerID(ctx context.Context, sellerID int64, filter entity.OrderFilter) (entity.OrderExport, resterrors.RestErr) {
+	ret := _m.Called(ctx, sellerID, filter)
+
+	var r0 entity.OrderExport
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.OrderFilter) entity.OrderExport); ok {
+		r0 = rf(ctx, sellerID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.OrderExport)
+		}
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.OrderFilter) resterrors.RestErr); ok {
+		r1 = rf(ctx, sellerID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) GetByID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order, user)
+
+	var r0 entity.Order
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) entity.Order); ok {
+		r0 = rf(ctx, order, user)
+	} else {
+		r0 = ret.Get(0).(entity.Order)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r1 = rf(ctx, order, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID, userType, filter
+func (_m *OrderUseCase) GetByUserID(ctx context.Context, userID int64, userType helpers.UserTypeEnum, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	ret := _m.Called(ctx, userID, userType, filter)
+
+	var r0 entity.OrderPage
+	if rf, ok := ret.Get(0).(func(context.Context, int64, helpers.UserTypeEnum, entity.OrderFilter) entity.OrderPage); ok {
+		r0 = rf(ctx, userID, userType, filter)
+	} else {
+		r0 = ret.Get(0).(entity.OrderPage)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64, helpers.UserTypeEnum, entity.OrderFilter) resterrors.RestErr); ok {
+		r1 = rf(ctx, userID, userType, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkDelivered provides a mock function with given fields: ctx, order
+func (_m *OrderUseCase) MarkDelivered(ctx context.Context, order *entity.Order) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order)
+
+	var r0 entity.Order
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) entity.Order); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(entity.Order)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r1 = rf(ctx, order)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkShipped provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) MarkShipped(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order, user)
+
+	var r0 entity.Order
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) entity.Order); ok {
+		r0 = rf(ctx, order, user)
+	} else {
+		r0 = ret.Get(0).(entity.Order)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r1 = rf(ctx, order, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// RejectOrder provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) RejectOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	ret := _m.Called(ctx, order, user)
+
+	var r0 entity.Order
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) entity.Order); ok {
+		r0 = rf(ctx, order, user)
+	} else {
+		r0 = ret.Get(0).(entity.Order)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r1 = rf(ctx, order, user)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -64,13 +229,13 @@ func (_m *OrderUseCase) GetByUserID(userID int64, userType helpers.UserTypeEnum)
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: order
-func (_m *OrderUseCase) Store(order *entity.Order) resterrors.RestErr {
-	ret := _m.Called(order)
+// Store provides a mock function with given fields: ctx, order, user
+func (_m *OrderUseCase) Store(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) resterrors.RestErr {
+	ret := _m.Called(ctx, order, user)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Order) resterrors.RestErr); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order, helpers.UserJWTPayload) resterrors.RestErr); ok {
+		r0 = rf(ctx, order, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+	decimal "github.com/shopspring/decimal"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,13 +17,13 @@ type ProductRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: product
-func (_m *ProductRepository) Delete(product *entity.Product) resterrors.RestErr {
-	ret := _m.Called(product)
+// Delete provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) Delete(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	ret := _m.Called(ctx, product)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Product) resterrors.RestErr); ok {
-		r0 = rf(product)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) resterrors.RestErr); ok {
+		r0 = rf(ctx, product)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -30,13 +33,13 @@ func (_m *ProductRepository) Delete(product *entity.Product) resterrors.RestErr
 	return r0
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *ProductRepository) GetAll() ([]entity.Product, resterrors.RestErr) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *ProductRepository) GetAll(ctx context.Context) ([]entity.Product, resterrors.RestErr) {
+	ret := _m.Called(ctx)
 
 	var r0 []entity.Product
-	if rf, ok := ret.Get(0).(func() []entity.Product); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Product); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Product)
@@ -44,8 +47,8 @@ func (_m *ProductRepository) GetAll() ([]entity.Product, resterrors.RestErr) {
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func() resterrors.RestErr); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) resterrors.RestErr); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -55,20 +58,20 @@ func (_m *ProductRepository) GetAll() ([]entity.Product, resterrors.RestErr) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: product
-func (_m *ProductRepository) GetByID(product *entity.Product) (entity.Product, resterrors.RestErr) {
-	ret := _m.Called(product)
+// GetByID provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) GetByID(ctx context.Context, product *entity.Product) (entity.Product, resterrors.RestErr) {
+	ret := _m.Called(ctx, product)
 
 	var r0 entity.Product
-	if rf, ok := ret.Get(0).(func(*entity.Product) entity.Product); ok {
-		r0 = rf(product)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) entity.Product); ok {
+		r0 = rf(ctx, product)
 	} else {
 		r0 = ret.Get(0).(entity.Product)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Product) resterrors.RestErr); ok {
-		r1 = rf(product)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Product) resterrors.RestErr); ok {
+		r1 = rf(ctx, product)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -78,29 +81,38 @@ func (_m *ProductRepository) GetByID(product *entity.Product) (entity.Product, r
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: product
-func (_m *ProductRepository) Store(product *entity.Product) resterrors.RestErr {
-	ret := _m.Called(product)
+// GetBySellerID provides a mock function with given fields: ctx, sellerID
+func (_m *ProductRepository) GetBySellerID(ctx context.Context, sellerID int64) ([]entity.Product, resterrors.RestErr) {
+	ret := _m.Called(ctx, sellerID)
 
-	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Product) resterrors.RestErr); ok {
-		r0 = rf(product)
+	var r0 []entity.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.Product); ok {
+		r0 = rf(ctx, sellerID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(resterrors.RestErr)
+			r0 = ret.Get(0).([]entity.Product)
 		}
 	}
 
-	return r0
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64) resterrors.RestErr); ok {
+		r1 = rf(ctx, sellerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
 }
 
-// Update provides a mock function with given fields: product
-func (_m *ProductRepository) Update(product *entity.Product) resterrors.RestErr {
-	ret := _m.Called(product)
+// Store provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) Store(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	ret := _m.Called(ctx, product)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Product) resterrors.RestErr); ok {
-		r0 = rf(product)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) resterrors.RestErr); ok {
+		r0 = rf(ctx, product)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -109,3 +121,51 @@ func (_m *ProductRepository) Update(product *entity.Product) resterrors.RestErr
 
 	return r0
 }
+
+// Update provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) Update(ctx context.Context, product *entity.Product) (decimal.Decimal, resterrors.RestErr) {
+	ret := _m.Called(ctx, product)
+
+	var r0 decimal.Decimal
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) decimal.Decimal); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Product) resterrors.RestErr); ok {
+		r1 = rf(ctx, product)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpsertBySKU provides a mock function with given fields: ctx, sellerID, products, dryRun
+func (_m *ProductRepository) UpsertBySKU(ctx context.Context, sellerID int64, products []entity.Product, dryRun bool) (map[int64]decimal.Decimal, resterrors.RestErr) {
+	ret := _m.Called(ctx, sellerID, products, dryRun)
+
+	var r0 map[int64]decimal.Decimal
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []entity.Product, bool) map[int64]decimal.Decimal); ok {
+		r0 = rf(ctx, sellerID, products, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]decimal.Decimal)
+		}
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []entity.Product, bool) resterrors.RestErr); ok {
+		r1 = rf(ctx, sellerID, products, dryRun)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
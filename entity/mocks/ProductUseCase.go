@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,13 +16,29 @@ type ProductUseCase struct {
 	mock.Mock
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *ProductUseCase) GetAll() ([]entity.Product, resterrors.RestErr) {
-	ret := _m.Called()
+// ExportBySellerID provides a mock function with given fields: ctx, sellerID, w
+func (_m *ProductUseCase) ExportBySellerID(ctx context.Context, sellerID int64, w entity.SheetWriter) resterrors.RestErr {
+	ret := _m.Called(ctx, sellerID, w)
+
+	var r0 resterrors.RestErr
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.SheetWriter) resterrors.RestErr); ok {
+		r0 = rf(ctx, sellerID, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(resterrors.RestErr)
+		}
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *ProductUseCase) GetAll(ctx context.Context) ([]entity.Product, resterrors.RestErr) {
+	ret := _m.Called(ctx)
 
 	var r0 []entity.Product
-	if rf, ok := ret.Get(0).(func() []entity.Product); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Product); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Product)
@@ -28,8 +46,31 @@ func (_m *ProductUseCase) GetAll() ([]entity.Product, resterrors.RestErr) {
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func() resterrors.RestErr); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) resterrors.RestErr); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(resterrors.RestErr)
+		}
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, sellerID, products, dryRun
+func (_m *ProductUseCase) Import(ctx context.Context, sellerID int64, products []entity.Product, dryRun bool) (entity.ProductImport, resterrors.RestErr) {
+	ret := _m.Called(ctx, sellerID, products, dryRun)
+
+	var r0 entity.ProductImport
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []entity.Product, bool) entity.ProductImport); ok {
+		r0 = rf(ctx, sellerID, products, dryRun)
+	} else {
+		r0 = ret.Get(0).(entity.ProductImport)
+	}
+
+	var r1 resterrors.RestErr
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []entity.Product, bool) resterrors.RestErr); ok {
+		r1 = rf(ctx, sellerID, products, dryRun)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -39,13 +80,45 @@ func (_m *ProductUseCase) GetAll() ([]entity.Product, resterrors.RestErr) {
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: product
-func (_m *ProductUseCase) Store(product *entity.Product) resterrors.RestErr {
-	ret := _m.Called(product)
+// OrderCancelled provides a mock function with given fields: ctx, order
+func (_m *ProductUseCase) OrderCancelled(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	ret := _m.Called(ctx, order)
+
+	var r0 resterrors.RestErr
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) resterrors.RestErr); ok {
+		r0 = rf(ctx, order)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(resterrors.RestErr)
+		}
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, product
+func (_m *ProductUseCase) Store(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	ret := _m.Called(ctx, product)
+
+	var r0 resterrors.RestErr
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) resterrors.RestErr); ok {
+		r0 = rf(ctx, product)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(resterrors.RestErr)
+		}
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, product, sellerID
+func (_m *ProductUseCase) Update(ctx context.Context, product *entity.Product, sellerID int64) resterrors.RestErr {
+	ret := _m.Called(ctx, product, sellerID)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Product) resterrors.RestErr); ok {
-		r0 = rf(product)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product, int64) resterrors.RestErr); ok {
+		r0 = rf(ctx, product, sellerID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
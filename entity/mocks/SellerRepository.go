@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,13 +16,13 @@ type SellerRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: seller
-func (_m *SellerRepository) Delete(seller *entity.Seller) resterrors.RestErr {
-	ret := _m.Called(seller)
+// Delete provides a mock function with given fields: ctx, seller
+func (_m *SellerRepository) Delete(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	ret := _m.Called(ctx, seller)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Seller) resterrors.RestErr); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -30,13 +32,13 @@ func (_m *SellerRepository) Delete(seller *entity.Seller) resterrors.RestErr {
 	return r0
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *SellerRepository) GetAll() ([]entity.Seller, resterrors.RestErr) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *SellerRepository) GetAll(ctx context.Context) ([]entity.Seller, resterrors.RestErr) {
+	ret := _m.Called(ctx)
 
 	var r0 []entity.Seller
-	if rf, ok := ret.Get(0).(func() []entity.Seller); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Seller); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Seller)
@@ -44,8 +46,8 @@ func (_m *SellerRepository) GetAll() ([]entity.Seller, resterrors.RestErr) {
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func() resterrors.RestErr); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) resterrors.RestErr); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -55,20 +57,20 @@ func (_m *SellerRepository) GetAll() ([]entity.Seller, resterrors.RestErr) {
 	return r0, r1
 }
 
-// GetByEmail provides a mock function with given fields: seller
-func (_m *SellerRepository) GetByEmail(seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
-	ret := _m.Called(seller)
+// GetByEmail provides a mock function with given fields: ctx, seller
+func (_m *SellerRepository) GetByEmail(ctx context.Context, seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
+	ret := _m.Called(ctx, seller)
 
 	var r0 entity.Seller
-	if rf, ok := ret.Get(0).(func(*entity.Seller) entity.Seller); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) entity.Seller); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		r0 = ret.Get(0).(entity.Seller)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Seller) resterrors.RestErr); ok {
-		r1 = rf(seller)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r1 = rf(ctx, seller)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -78,13 +80,13 @@ func (_m *SellerRepository) GetByEmail(seller *entity.Seller) (entity.Seller, re
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: seller
-func (_m *SellerRepository) GetByID(seller *entity.Seller) resterrors.RestErr {
-	ret := _m.Called(seller)
+// GetByID provides a mock function with given fields: ctx, seller
+func (_m *SellerRepository) GetByID(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	ret := _m.Called(ctx, seller)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Seller) resterrors.RestErr); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -94,13 +96,13 @@ func (_m *SellerRepository) GetByID(seller *entity.Seller) resterrors.RestErr {
 	return r0
 }
 
-// Store provides a mock function with given fields: seller
-func (_m *SellerRepository) Store(seller *entity.Seller) resterrors.RestErr {
-	ret := _m.Called(seller)
+// Store provides a mock function with given fields: ctx, seller
+func (_m *SellerRepository) Store(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	ret := _m.Called(ctx, seller)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Seller) resterrors.RestErr); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
@@ -110,13 +112,13 @@ func (_m *SellerRepository) Store(seller *entity.Seller) resterrors.RestErr {
 	return r0
 }
 
-// Update provides a mock function with given fields: seller
-func (_m *SellerRepository) Update(seller *entity.Seller) resterrors.RestErr {
-	ret := _m.Called(seller)
+// Update provides a mock function with given fields: ctx, seller
+func (_m *SellerRepository) Update(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	ret := _m.Called(ctx, seller)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Seller) resterrors.RestErr); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
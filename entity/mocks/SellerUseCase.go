@@ -3,7 +3,9 @@
 package mocks
 
 import (
+	context "context"
 	entity "github.com/hieronimusbudi/komodo-backend/entity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
@@ -14,20 +16,20 @@ type SellerUseCase struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: seller
-func (_m *SellerUseCase) Login(seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
-	ret := _m.Called(seller)
+// Login provides a mock function with given fields: ctx, seller
+func (_m *SellerUseCase) Login(ctx context.Context, seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
+	ret := _m.Called(ctx, seller)
 
 	var r0 entity.Seller
-	if rf, ok := ret.Get(0).(func(*entity.Seller) entity.Seller); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) entity.Seller); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		r0 = ret.Get(0).(entity.Seller)
 	}
 
 	var r1 resterrors.RestErr
-	if rf, ok := ret.Get(1).(func(*entity.Seller) resterrors.RestErr); ok {
-		r1 = rf(seller)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r1 = rf(ctx, seller)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(resterrors.RestErr)
@@ -37,13 +39,13 @@ func (_m *SellerUseCase) Login(seller *entity.Seller) (entity.Seller, resterrors
 	return r0, r1
 }
 
-// Register provides a mock function with given fields: seller
-func (_m *SellerUseCase) Register(seller *entity.Seller) resterrors.RestErr {
-	ret := _m.Called(seller)
+// Register provides a mock function with given fields: ctx, seller
+func (_m *SellerUseCase) Register(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	ret := _m.Called(ctx, seller)
 
 	var r0 resterrors.RestErr
-	if rf, ok := ret.Get(0).(func(*entity.Seller) resterrors.RestErr); ok {
-		r0 = rf(seller)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Seller) resterrors.RestErr); ok {
+		r0 = rf(ctx, seller)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(resterrors.RestErr)
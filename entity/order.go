@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"context"
 	"time"
 
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
@@ -13,8 +14,56 @@ type OrderStatusEnum int
 const (
 	PENDING OrderStatusEnum = iota
 	ACCEPTED
+	CANCELLED
+	REJECTED
+	SHIPPED
+	DELIVERED
 )
 
+var orderStatusNames = [...]string{"PENDING", "ACCEPTED", "CANCELLED", "REJECTED", "SHIPPED", "DELIVERED"}
+
+// String returns the name of the status, PENDING for PENDING
+func (s OrderStatusEnum) String() string {
+	if s < 0 || int(s) >= len(orderStatusNames) {
+		return "UNKNOWN"
+	}
+	return orderStatusNames[s]
+}
+
+// OrderReasonCodeEnum is why an order was cancelled by its buyer or rejected by its seller
+type OrderReasonCodeEnum string
+
+const (
+	// buyer cancellation reasons
+	REASON_CHANGED_MIND       OrderReasonCodeEnum = "CHANGED_MIND"
+	REASON_ORDERED_BY_MISTAKE OrderReasonCodeEnum = "ORDERED_BY_MISTAKE"
+	REASON_FOUND_CHEAPER      OrderReasonCodeEnum = "FOUND_CHEAPER"
+	// seller rejection reasons
+	REASON_OUT_OF_STOCK   OrderReasonCodeEnum = "OUT_OF_STOCK"
+	REASON_CANNOT_DELIVER OrderReasonCodeEnum = "CANNOT_DELIVER"
+	REASON_PRICE_ERROR    OrderReasonCodeEnum = "PRICE_ERROR"
+	// a note explains the reason
+	REASON_OTHER OrderReasonCodeEnum = "OTHER"
+	// set when the seller didn't accept the order in time
+	REASON_EXPIRED OrderReasonCodeEnum = "EXPIRED"
+)
+
+type OrderSortEnum string
+
+const (
+	SORT_ORDER_DATE_DESC  OrderSortEnum = "-order_date"
+	SORT_ORDER_DATE_ASC   OrderSortEnum = "order_date"
+	SORT_TOTAL_PRICE_DESC OrderSortEnum = "-total_price"
+	SORT_TOTAL_PRICE_ASC  OrderSortEnum = "total_price"
+)
+
+// Order is placed by a buyer to a seller. Version is incremented on every update,
+// an update made with an outdated Version fails with a conflict. Paid is what was taken from the buyer
+// for the order and Refunded what was given back of it, both are only filled by OrderUseCase.GetByID.
+// Discount is what the voucher VoucherCode took off the items, it is already taken off TotalPrice.
+// ShippingFee is what the carrier service ShippingService charges, it is already added to TotalPrice.
+// ShippingOrigin (the pickup region of the seller) and ShippingDestination are the regions the fee was quoted between.
+// Tax is the sum of the Tax of the items, it is added to TotalPrice unless TaxInclusive when the prices already include it
 type Order struct {
 	ID                         int64
 	Buyer                      Buyer
@@ -25,21 +74,99 @@ type Order struct {
 	TotalPrice                 decimal.Decimal
 	Status                     OrderStatusEnum
 	OrderDate                  time.Time
+	ReasonCode                 OrderReasonCodeEnum
+	ReasonNote                 string
+	Version                    int64
+	VoucherID                  int64
+	VoucherCode                string
+	Discount                   decimal.Decimal
+	ShippingService            string
+	ShippingFee                decimal.Decimal
+	ShippingOrigin             ShippingAddress
+	ShippingDestination        ShippingAddress
+	Tax                        decimal.Decimal
+	TaxInclusive               bool
+	Paid                       decimal.Decimal
+	Refunded                   decimal.Decimal
 	Items                      []OrderDetail
+	StatusHistory              []OrderStatusHistory
+}
+
+// Subtotal returns the price of the items before the discount and without shipping or the tax added to their price
+func (o Order) Subtotal() decimal.Decimal {
+	subtotal := o.TotalPrice.Add(o.Discount).Sub(o.ShippingFee)
+	if !o.TaxInclusive {
+		subtotal = subtotal.Sub(o.Tax)
+	}
+	return subtotal
+}
+
+// OrderStatusHistory is a status change of an order, PreviousStatus is nil for the status the order was created with
+// and ActorID is 0 when the change wasn't made by a user
+type OrderStatusHistory struct {
+	ID             int64
+	OrderID        int64
+	PreviousStatus *OrderStatusEnum
+	Status         OrderStatusEnum
+	ActorID        int64
+	ActorType      helpers.UserTypeEnum
+	Reason         string
+	CreatedAt      time.Time
 }
 
+// OrderDetail is a line item of an order, Price is the unit price the buyer was charged
+// and doesn't change with the price of Product. Discount is the share of the voucher discount of the order
+// taken off the whole line. Tax is the tax of the whole line at TaxRate, taken on its price after Discount
 type OrderDetail struct {
 	ID       int64
 	Product  Product
 	Quantity int64
+	Price    decimal.Decimal
+	Discount decimal.Decimal
+	TaxRate  decimal.Decimal
+	Tax      decimal.Decimal
+}
+
+// OrderFilter narrows down an order listing, zero values are not filtered on.
+// CounterpartID is the seller id when listing buyer orders and the buyer id when listing seller orders,
+// From is inclusive and To is exclusive. Cursor is the NextCursor of the previous page
+type OrderFilter struct {
+	Statuses      []OrderStatusEnum
+	From          time.Time
+	To            time.Time
+	CounterpartID int64
+	MinTotal      *decimal.Decimal
+	MaxTotal      *decimal.Decimal
+	Sort          OrderSortEnum
+	Limit         int
+	Cursor        string
+}
+
+// OrderExport writes the orders it was created for to w, see OrderUseCase.ExportBySellerID
+type OrderExport func(w SheetWriter) resterrors.RestErr
+
+// OrderPage is a page of an order listing, Limit is the page size used and NextCursor is empty on the last page
+type OrderPage struct {
+	Orders     []Order
+	Limit      int
+	NextCursor string
 }
 
+// OrderDTORequest is an order placed by the logged in buyer
 type OrderDTORequest struct {
-	BuyerID                    int64                   `json:"buyerId" validate:"required"`
-	SellerID                   int64                   `json:"sellerId" validate:"required"`
-	DeliverySourceAddress      string                  `json:"deliverySourceAddress" validate:"gte=0,lte=511"`
-	DeliveryDestinationAddress string                  `json:"deliveryDestinationAddress" validate:"gte=0,lte=511"`
-	Items                      []OrderDetailDTORequest `json:"items" validate:"required,dive"`
+	SellerID                   int64                    `json:"sellerId" validate:"required"`
+	DeliverySourceAddress      string                   `json:"deliverySourceAddress" validate:"gte=0,lte=511"`
+	DeliveryDestinationAddress string                   `json:"deliveryDestinationAddress" validate:"gte=0,lte=511"`
+	VoucherCode                string                   `json:"voucherCode" validate:"omitempty,alphanum,lte=32"`
+	Shipping                   *OrderShippingDTORequest `json:"shipping" validate:"required"`
+	Items                      []OrderDetailDTORequest  `json:"items" validate:"required,dive"`
+}
+
+// OrderShippingDTORequest is the carrier service an order is shipped with and the region it is sent to,
+// it is sent from the pickup region of the seller
+type OrderShippingDTORequest struct {
+	Service     string                    `json:"service" validate:"required,lte=32"`
+	Destination ShippingAddressDTORequest `json:"destination" validate:"required"`
 }
 
 type OrderDetailDTORequest struct {
@@ -47,6 +174,34 @@ type OrderDetailDTORequest struct {
 	Quantity  int64 `json:"quantity" validate:"required,gte=0"`
 }
 
+type OrderCancelDTORequest struct {
+	ReasonCode string `json:"reasonCode" validate:"omitempty,oneof=CHANGED_MIND ORDERED_BY_MISTAKE FOUND_CHEAPER OTHER"`
+	ReasonNote string `json:"reasonNote" validate:"required_if=ReasonCode OTHER,lte=511"`
+}
+
+type OrderRejectDTORequest struct {
+	ReasonCode string `json:"reasonCode" validate:"required,oneof=OUT_OF_STOCK CANNOT_DELIVER PRICE_ERROR OTHER"`
+	ReasonNote string `json:"reasonNote" validate:"required_if=ReasonCode OTHER,lte=511"`
+}
+
+type OrderListDTORequest struct {
+	Status        []int64 `query:"status" validate:"omitempty,dive,oneof=0 1 2 3 4 5"`
+	From          string  `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To            string  `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	CounterpartID int64   `query:"counterpartId" validate:"omitempty,gte=1"`
+	MinTotal      string  `query:"minTotal" validate:"omitempty,numeric"`
+	MaxTotal      string  `query:"maxTotal" validate:"omitempty,numeric"`
+	Sort          string  `query:"sort" validate:"omitempty,oneof=order_date -order_date total_price -total_price"`
+	Limit         int     `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Cursor        string  `query:"cursor"`
+}
+
+// OrderExportDTORequest takes the filters of an order listing, limit and cursor are ignored as every order is exported
+type OrderExportDTORequest struct {
+	OrderListDTORequest
+	Format string `query:"format" validate:"required,oneof=csv xlsx"`
+}
+
 type OrderDTOResponse struct {
 	ID                         int64                    `json:"id"`
 	BuyerID                    int64                    `json:"buyerId"`
@@ -54,28 +209,83 @@ type OrderDTOResponse struct {
 	DeliverySourceAddress      string                   `json:"deliverySourceAddress"`
 	DeliveryDestinationAddress string                   `json:"deliveryDestinationAddress"`
 	TotalQuantity              int64                    `json:"totalQuantity"`
+	Subtotal                   float64                  `json:"subtotal"`
+	Discount                   float64                  `json:"discount"`
+	VoucherCode                string                   `json:"voucherCode,omitempty"`
+	ShippingService            string                   `json:"shippingService,omitempty"`
+	ShippingFee                float64                  `json:"shippingFee"`
+	Tax                        float64                  `json:"tax"`
+	TaxInclusive               bool                     `json:"taxInclusive"`
 	TotalPrice                 float64                  `json:"totalPrice"`
 	Status                     OrderStatusEnum          `json:"status"`
 	OrderDate                  time.Time                `json:"orderDate"`
+	ReasonCode                 OrderReasonCodeEnum      `json:"reasonCode,omitempty"`
+	ReasonNote                 string                   `json:"reasonNote,omitempty"`
 	Items                      []OrderDetailDTOResponse `json:"items"`
 }
 
 type OrderDTOSimpleResponse struct {
-	ID                         int64           `json:"id"`
-	BuyerID                    int64           `json:"buyerId"`
-	SellerID                   int64           `json:"sellerId"`
-	DeliverySourceAddress      string          `json:"deliverySourceAddress"`
-	DeliveryDestinationAddress string          `json:"deliveryDestinationAddress"`
-	TotalQuantity              int64           `json:"totalQuantity"`
-	TotalPrice                 float64         `json:"totalPrice"`
-	Status                     OrderStatusEnum `json:"status"`
-	OrderDate                  time.Time       `json:"orderDate"`
+	ID                         int64               `json:"id"`
+	BuyerID                    int64               `json:"buyerId"`
+	SellerID                   int64               `json:"sellerId"`
+	DeliverySourceAddress      string              `json:"deliverySourceAddress"`
+	DeliveryDestinationAddress string              `json:"deliveryDestinationAddress"`
+	TotalQuantity              int64               `json:"totalQuantity"`
+	TotalPrice                 float64             `json:"totalPrice"`
+	Status                     OrderStatusEnum     `json:"status"`
+	OrderDate                  time.Time           `json:"orderDate"`
+	ReasonCode                 OrderReasonCodeEnum `json:"reasonCode,omitempty"`
+	ReasonNote                 string              `json:"reasonNote,omitempty"`
+}
+
+type OrderDTODetailResponse struct {
+	ID                         int64                           `json:"id"`
+	Buyer                      OrderParticipantDTOResponse     `json:"buyer"`
+	Seller                     OrderParticipantDTOResponse     `json:"seller"`
+	DeliverySourceAddress      string                          `json:"deliverySourceAddress"`
+	DeliveryDestinationAddress string                          `json:"deliveryDestinationAddress"`
+	TotalQuantity              int64                           `json:"totalQuantity"`
+	Subtotal                   float64                         `json:"subtotal"`
+	Discount                   float64                         `json:"discount"`
+	VoucherCode                string                          `json:"voucherCode,omitempty"`
+	ShippingService            string                          `json:"shippingService,omitempty"`
+	ShippingFee                float64                         `json:"shippingFee"`
+	Tax                        float64                         `json:"tax"`
+	TaxInclusive               bool                            `json:"taxInclusive"`
+	TotalPrice                 float64                         `json:"totalPrice"`
+	Status                     OrderStatusEnum                 `json:"status"`
+	OrderDate                  time.Time                       `json:"orderDate"`
+	ReasonCode                 OrderReasonCodeEnum             `json:"reasonCode,omitempty"`
+	ReasonNote                 string                          `json:"reasonNote,omitempty"`
+	Paid                       float64                         `json:"paid"`
+	Refunded                   float64                         `json:"refunded"`
+	Net                        float64                         `json:"net"`
+	Items                      []OrderDetailDTOResponse        `json:"items"`
+	StatusHistory              []OrderStatusHistoryDTOResponse `json:"statusHistory"`
+}
+
+type OrderParticipantDTOResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type OrderStatusHistoryDTOResponse struct {
+	PreviousStatus *OrderStatusEnum      `json:"previousStatus"`
+	Status         OrderStatusEnum       `json:"status"`
+	ActorID        int64                 `json:"actorId,omitempty"`
+	ActorType      *helpers.UserTypeEnum `json:"actorType,omitempty"`
+	Reason         string                `json:"reason,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
 }
 
 type OrderDetailDTOResponse struct {
+	ID       int64              `json:"id"`
 	Product  ProductDTOResponse `json:"product"`
 	Quantity int64              `json:"quantity"`
 	Price    float64            `json:"price"`
+	TaxRate  float64            `json:"taxRate"`
+	Tax      float64            `json:"tax"`
 }
 
 type OrderDetailSimpleDTOResponse struct {
@@ -83,17 +293,43 @@ type OrderDetailSimpleDTOResponse struct {
 }
 
 type OrderUseCase interface {
-	Store(order *Order) resterrors.RestErr
-	GetByUserID(userID int64, userType helpers.UserTypeEnum) ([]Order, resterrors.RestErr)
-	AcceptOrder(order *Order) (Order, resterrors.RestErr)
+	Store(ctx context.Context, order *Order, user helpers.UserJWTPayload) resterrors.RestErr
+	GetByUserID(ctx context.Context, userID int64, userType helpers.UserTypeEnum, filter OrderFilter) (OrderPage, resterrors.RestErr)
+	GetByID(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Order, resterrors.RestErr)
+	AcceptOrder(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Order, resterrors.RestErr)
+	CancelOrder(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Order, resterrors.RestErr)
+	RejectOrder(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Order, resterrors.RestErr)
+	ExpirePendingOrders(ctx context.Context, placedBefore time.Time, limit int) ([]Order, resterrors.RestErr)
+	// MarkShipped moves an accepted order of the seller user to SHIPPED
+	MarkShipped(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Order, resterrors.RestErr)
+	// MarkDelivered moves an accepted or shipped order to DELIVERED, nothing happens when it already is
+	MarkDelivered(ctx context.Context, order *Order) (Order, resterrors.RestErr)
+	// ExportBySellerID checks filter and returns an export of every order of the seller matching it with a row for each
+	// line item. The orders are read a page at a time with ctx when the export is written, so they don't all have to fit
+	// in memory, the export stops once ctx is done
+	ExportBySellerID(ctx context.Context, sellerID int64, filter OrderFilter) (OrderExport, resterrors.RestErr)
+}
+
+// OrderCancelHook is run by OrderUseCase after an order was cancelled or rejected,
+// implementations give back what the order held, like product stock or an applied voucher
+type OrderCancelHook interface {
+	OrderCancelled(ctx context.Context, order *Order) resterrors.RestErr
 }
 
 type OrderRepository interface {
-	GetAll() ([]Order, resterrors.RestErr)
-	GetByBuyerID(buyerID int64) ([]Order, resterrors.RestErr)
-	GetBySellerID(buyerID int64) ([]Order, resterrors.RestErr)
-	GetByID(order *Order) (Order, resterrors.RestErr)
-	Update(order *Order) resterrors.RestErr
-	Store(order *Order) resterrors.RestErr
-	Delete(order *Order) resterrors.RestErr
+	// GetAll returns every order with its line items, ordered by id
+	GetAll(ctx context.Context) ([]Order, resterrors.RestErr)
+	GetByBuyerID(ctx context.Context, buyerID int64, filter OrderFilter) (OrderPage, resterrors.RestErr)
+	GetBySellerID(ctx context.Context, sellerID int64, filter OrderFilter) (OrderPage, resterrors.RestErr)
+	GetByID(ctx context.Context, order *Order) (Order, resterrors.RestErr)
+	GetPendingBefore(ctx context.Context, placedBefore time.Time, limit int) ([]Order, resterrors.RestErr)
+	// Update saves order when its Version is still the stored one and increments it, otherwise nothing is saved
+	// and a conflict error is returned
+	Update(ctx context.Context, order *Order) resterrors.RestErr
+	// UpdateStatus saves the status and reason of order and appends change to its history in one transaction,
+	// with the same Version check as Update
+	UpdateStatus(ctx context.Context, order *Order, change OrderStatusHistory) resterrors.RestErr
+	// Store saves order with its items and its StatusHistory in one transaction
+	Store(ctx context.Context, order *Order) resterrors.RestErr
+	Delete(ctx context.Context, order *Order) resterrors.RestErr
 }
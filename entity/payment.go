@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentStatusEnum int
+
+const (
+	// waiting for the buyer to pay
+	PAYMENT_PENDING PaymentStatusEnum = iota
+	// paid by the buyer and held by the gateway until it is captured
+	PAYMENT_AUTHORIZED
+	PAYMENT_CAPTURED
+	PAYMENT_FAILED
+	PAYMENT_CANCELLED
+	PAYMENT_REFUNDED
+)
+
+// Active reports whether a payment in the status is still going on or done, an order has at most one active payment
+func (s PaymentStatusEnum) Active() bool {
+	return s == PAYMENT_PENDING || s == PAYMENT_AUTHORIZED || s == PAYMENT_CAPTURED
+}
+
+// Payment is the payment of an order made through a gateway, ProviderPaymentID is the id of the payment
+// at the gateway named Provider and ClientSecret is given to the buyer to pay with the gateway
+type Payment struct {
+	ID                int64
+	OrderID           int64
+	Provider          string
+	ProviderPaymentID string
+	ClientSecret      string
+	Amount            decimal.Decimal
+	Currency          string
+	Status            PaymentStatusEnum
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// PaymentIntent is a payment created at a gateway
+type PaymentIntent struct {
+	ProviderPaymentID string
+	ClientSecret      string
+	Status            PaymentStatusEnum
+}
+
+// PaymentRefund is a refund made at a gateway
+type PaymentRefund struct {
+	ProviderRefundID string
+	Amount           decimal.Decimal
+}
+
+// PaymentWebhookEvent is a change of a payment a gateway calls us back with, ProviderEventID is unique
+// for each event of a Provider. Payload is the body of the callback, kept to replay and debug the event.
+// ProcessedAt is nil until the event was handled and Error is why the last attempt to handle it failed
+type PaymentWebhookEvent struct {
+	ID                int64
+	Provider          string
+	ProviderEventID   string
+	Type              string
+	ProviderPaymentID string
+	Status            PaymentStatusEnum
+	Amount            decimal.Decimal
+	Currency          string
+	Payload           []byte
+	ReceivedAt        time.Time
+	ProcessedAt       *time.Time
+	Error             string
+}
+
+type PaymentWebhookEventDTOResponse struct {
+	ID                int64             `json:"id"`
+	Provider          string            `json:"provider"`
+	ProviderEventID   string            `json:"providerEventId"`
+	Type              string            `json:"type"`
+	ProviderPaymentID string            `json:"providerPaymentId"`
+	Status            PaymentStatusEnum `json:"status"`
+	ReceivedAt        time.Time         `json:"receivedAt"`
+	ProcessedAt       *time.Time        `json:"processedAt"`
+	Error             string            `json:"error,omitempty"`
+}
+
+type PaymentDTOResponse struct {
+	ID           int64             `json:"id"`
+	OrderID      int64             `json:"orderId"`
+	Provider     string            `json:"provider"`
+	ClientSecret string            `json:"clientSecret,omitempty"`
+	Amount       float64           `json:"amount"`
+	Currency     string            `json:"currency"`
+	Status       PaymentStatusEnum `json:"status"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+}
+
+// PaymentGateway is a payment provider, implementations talk to the provider API
+type PaymentGateway interface {
+	// Name is the provider name payments made through the gateway are saved with
+	Name() string
+	// CreateIntent creates a payment of payment.Amount at the gateway for the buyer to pay
+	CreateIntent(ctx context.Context, payment *Payment) (PaymentIntent, resterrors.RestErr)
+	// Capture takes the money of an authorized payment
+	Capture(ctx context.Context, payment *Payment) resterrors.RestErr
+	// Refund gives amount of a payment back to the buyer, an authorized payment is released instead
+	Refund(ctx context.Context, payment *Payment, amount decimal.Decimal) (PaymentRefund, resterrors.RestErr)
+	// ParseWebhook verifies the signature of a callback request of the gateway and reads its event
+	ParseWebhook(ctx context.Context, header http.Header, body []byte) (PaymentWebhookEvent, resterrors.RestErr)
+}
+
+type PaymentUseCase interface {
+	// Create starts the payment of order for its buyer
+	Create(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Payment, resterrors.RestErr)
+	// GetByOrderID returns the latest payment of order to one of its participants
+	GetByOrderID(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Payment, resterrors.RestErr)
+	// Capture takes the payment of order, it fails with a conflict unless the buyer paid it.
+	// Capturing a captured payment does nothing
+	Capture(ctx context.Context, order *Order) resterrors.RestErr
+	// OrderCancelled releases or refunds the payment of a cancelled order
+	OrderCancelled(ctx context.Context, order *Order) resterrors.RestErr
+	// HandleWebhook applies the event of a callback request of the gateway named provider,
+	// an event already handled is ignored
+	HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) (PaymentWebhookEvent, resterrors.RestErr)
+	// ReplayWebhookEvent applies a received event again
+	ReplayWebhookEvent(ctx context.Context, eventID int64) (PaymentWebhookEvent, resterrors.RestErr)
+	// Refund gives back the Items of request or, without items, what is left of the captured payment of order.
+	// Only the seller of the order or an admin can refund it
+	Refund(ctx context.Context, order *Order, request Refund, user helpers.UserJWTPayload) (Refund, resterrors.RestErr)
+	// GetRefunds returns the refunds of order to one of its participants
+	GetRefunds(ctx context.Context, order *Order, user helpers.UserJWTPayload) (RefundSummary, resterrors.RestErr)
+	// Summary returns what was paid and refunded for the order with orderID, it is empty for unpaid orders
+	Summary(ctx context.Context, orderID int64) (RefundSummary, resterrors.RestErr)
+}
+
+type PaymentRepository interface {
+	Store(ctx context.Context, payment *Payment) resterrors.RestErr
+	// GetByOrderID returns the latest payment of an order
+	GetByOrderID(ctx context.Context, orderID int64) (Payment, resterrors.RestErr)
+	GetByProviderPaymentID(ctx context.Context, provider string, providerPaymentID string) (Payment, resterrors.RestErr)
+	// UpdateStatus saves the status of payment when the stored one is still from, otherwise a conflict error is returned
+	UpdateStatus(ctx context.Context, payment *Payment, from PaymentStatusEnum) resterrors.RestErr
+}
+
+type PaymentWebhookEventRepository interface {
+	// Store saves event unless an event with the same provider and ProviderEventID was received before,
+	// then ok is false and the saved event is returned
+	Store(ctx context.Context, event *PaymentWebhookEvent) (existing PaymentWebhookEvent, ok bool, err resterrors.RestErr)
+	GetByID(ctx context.Context, eventID int64) (PaymentWebhookEvent, resterrors.RestErr)
+	// UpdateResult saves ProcessedAt and Error of event
+	UpdateResult(ctx context.Context, event *PaymentWebhookEvent) resterrors.RestErr
+}
@@ -1,42 +1,101 @@
 package entity
 
 import (
+	"context"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"github.com/shopspring/decimal"
 )
 
+// Product is sold by a seller, Weight is its shipping weight in grams and Category decides its tax rate.
+// SKU is the optional code the seller gives the product, it is unique among the products of the seller
 type Product struct {
 	ID          int64
+	SKU         string
 	Name        string
 	Description string
 	Price       decimal.Decimal
+	Weight      int64
+	Category    string
 	Seller      Seller
 }
 
 type ProductDTORequest struct {
+	SKU         string  `json:"sku" validate:"omitempty,lte=64"`
 	Name        string  `json:"name" validate:"required"`
 	Description string  `json:"description" validate:"required,gte=0"`
 	Price       float64 `json:"price" validate:"required"`
+	Weight      int64   `json:"weight" validate:"gte=0"`
+	Category    string  `json:"category" validate:"omitempty,lte=64"`
 	SellerID    int64   `json:"sellerId" validate:"required"`
 }
 
+type ProductUpdateDTORequest struct {
+	Name        string  `json:"name" validate:"required"`
+	Description string  `json:"description" validate:"required,gte=0"`
+	Price       float64 `json:"price" validate:"required"`
+	Weight      int64   `json:"weight" validate:"gte=0"`
+	Category    string  `json:"category" validate:"omitempty,lte=64"`
+}
+
 type ProductDTOResponse struct {
 	ID          int64   `json:"id"`
+	SKU         string  `json:"sku,omitempty"`
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
+	Weight      int64   `json:"weight"`
+	Category    string  `json:"category,omitempty"`
 	SellerID    int64   `json:"sellerId"`
 }
 
+// ProductColumns are the columns of a product export in their order, an import takes the same columns
+var ProductColumns = []string{"sku", "name", "description", "price", "weight", "category"}
+
+// ProductImport is the outcome of importing products, Created and Updated count the imported products
+// by what happened to them. Nothing was saved when DryRun
+type ProductImport struct {
+	DryRun  bool
+	Created int
+	Updated int
+}
+
+type ProductImportDTOResponse struct {
+	DryRun  bool `json:"dryRun"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+}
+
 type ProductUseCase interface {
-	Store(product *Product) resterrors.RestErr
-	GetAll() ([]Product, resterrors.RestErr)
+	Store(ctx context.Context, product *Product) resterrors.RestErr
+	GetAll(ctx context.Context) ([]Product, resterrors.RestErr)
+	// Update saves the name, description, price, weight and category of a product of the seller,
+	// the price hooks are run when its price was lowered
+	Update(ctx context.Context, product *Product, sellerID int64) resterrors.RestErr
+	// Import saves products for the seller, they all must have a SKU. A product updates the product of the seller
+	// with its SKU or is created, either all of them are saved or none. dryRun checks the import without saving it.
+	// Once saved the price hooks are run for the updated products whose price was lowered
+	Import(ctx context.Context, sellerID int64, products []Product, dryRun bool) (ProductImport, resterrors.RestErr)
+	// ExportBySellerID writes the products of the seller that have a SKU to w with the columns of an import,
+	// the others can't be imported again without being created twice
+	ExportBySellerID(ctx context.Context, sellerID int64, w SheetWriter) resterrors.RestErr
+	// OrderCancelled gives back the stock held by a cancelled or rejected order with a STOCK_RELEASED_EVENT,
+	// it is run as an OrderCancelHook
+	OrderCancelled(ctx context.Context, order *Order) resterrors.RestErr
 }
 
 type ProductRepository interface {
-	GetAll() ([]Product, resterrors.RestErr)
-	GetByID(product *Product) (Product, resterrors.RestErr)
-	Update(product *Product) resterrors.RestErr
-	Store(product *Product) resterrors.RestErr
-	Delete(product *Product) resterrors.RestErr
+	GetAll(ctx context.Context) ([]Product, resterrors.RestErr)
+	GetByID(ctx context.Context, product *Product) (Product, resterrors.RestErr)
+	// Update saves product when it is sold by product.Seller, the stored row is locked meanwhile. The SKU of product is
+	// set to the stored one, which isn't changed, and oldPrice is the price the product had before
+	Update(ctx context.Context, product *Product) (oldPrice decimal.Decimal, err resterrors.RestErr)
+	Store(ctx context.Context, product *Product) resterrors.RestErr
+	Delete(ctx context.Context, product *Product) resterrors.RestErr
+	// GetBySellerID returns the products of the seller ordered by SKU
+	GetBySellerID(ctx context.Context, sellerID int64) ([]Product, resterrors.RestErr)
+	// UpsertBySKU saves products of the seller in one transaction, a product with the SKU of a stored product of the seller
+	// updates it and the others are inserted. ID is set on every product and previous has the price each updated product
+	// had before, keyed by its ID, the products not in it were inserted. The transaction is rolled back instead of
+	// committed when dryRun
+	UpsertBySKU(ctx context.Context, sellerID int64, products []Product, dryRun bool) (previous map[int64]decimal.Decimal, err resterrors.RestErr)
 }
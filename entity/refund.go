@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// Refund gives back Amount of a captured payment to the buyer. A refund without Items is a full refund of
+// what was left of the payment, otherwise Amount is the sum of its Items. ActorID is 0 when the refund
+// wasn't made by a user, like the refund of an order cancelled after it was paid
+type Refund struct {
+	ID               int64
+	OrderID          int64
+	PaymentID        int64
+	ProviderRefundID string
+	Amount           decimal.Decimal
+	Reason           string
+	ActorID          int64
+	ActorType        helpers.UserTypeEnum
+	CreatedAt        time.Time
+	Items            []RefundItem
+}
+
+// RefundItem is Quantity of an order line item given back, Amount is Quantity times the price the buyer was charged
+type RefundItem struct {
+	ID            int64
+	RefundID      int64
+	OrderDetailID int64
+	Quantity      int64
+	Amount        decimal.Decimal
+}
+
+// RefundSummary is what the buyer paid for an order and what was refunded of it, oldest refund first
+type RefundSummary struct {
+	Paid     decimal.Decimal
+	Refunded decimal.Decimal
+	Refunds  []Refund
+}
+
+// Net is what the buyer paid and was not refunded
+func (s RefundSummary) Net() decimal.Decimal {
+	return s.Paid.Sub(s.Refunded)
+}
+
+type RefundDTORequest struct {
+	Reason string                 `json:"reason" validate:"lte=511"`
+	Items  []RefundItemDTORequest `json:"items" validate:"omitempty,dive"`
+}
+
+type RefundItemDTORequest struct {
+	OrderItemID int64 `json:"orderItemId" validate:"required"`
+	Quantity    int64 `json:"quantity" validate:"required,gte=1"`
+}
+
+type RefundDTOResponse struct {
+	ID        int64                   `json:"id"`
+	OrderID   int64                   `json:"orderId"`
+	PaymentID int64                   `json:"paymentId"`
+	Amount    float64                 `json:"amount"`
+	Reason    string                  `json:"reason,omitempty"`
+	ActorID   int64                   `json:"actorId,omitempty"`
+	ActorType *helpers.UserTypeEnum   `json:"actorType,omitempty"`
+	CreatedAt time.Time               `json:"createdAt"`
+	Items     []RefundItemDTOResponse `json:"items"`
+}
+
+type RefundItemDTOResponse struct {
+	OrderItemID int64   `json:"orderItemId"`
+	Quantity    int64   `json:"quantity"`
+	Amount      float64 `json:"amount"`
+}
+
+type RefundSummaryDTOResponse struct {
+	Paid     float64             `json:"paid"`
+	Refunded float64             `json:"refunded"`
+	Net      float64             `json:"net"`
+	Refunds  []RefundDTOResponse `json:"refunds"`
+}
+
+type RefundRepository interface {
+	// Store saves refund with its items in one transaction
+	Store(ctx context.Context, refund *Refund) resterrors.RestErr
+	// GetByPaymentID returns the refunds of a payment with their items, oldest first
+	GetByPaymentID(ctx context.Context, paymentID int64) ([]Refund, resterrors.RestErr)
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// ReportFilter picks what happened on the platform from From until before To, summed by period of Group
+type ReportFilter struct {
+	From  time.Time
+	To    time.Time
+	Group SalesGroupEnum
+}
+
+// OrderFunnel counts the orders placed in a period by how far they went. Only pending orders are cancelled
+// or rejected, so Accepted counts every order accepted since and Completed the ones delivered
+type OrderFunnel struct {
+	Placed    int64
+	Accepted  int64
+	Completed int64
+	Cancelled int64
+	Rejected  int64
+}
+
+// rate is count out of the placed orders with four decimals, zero without orders
+func (f OrderFunnel) rate(count int64) decimal.Decimal {
+	if f.Placed == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(count).DivRound(decimal.NewFromInt(f.Placed), 4)
+}
+
+// AcceptanceRate is the share of the placed orders their seller accepted
+func (f OrderFunnel) AcceptanceRate() decimal.Decimal {
+	return f.rate(f.Accepted)
+}
+
+// CompletionRate is the share of the placed orders delivered
+func (f OrderFunnel) CompletionRate() decimal.Decimal {
+	return f.rate(f.Completed)
+}
+
+// CancellationRate is the share of the placed orders cancelled by their buyer or expired
+func (f OrderFunnel) CancellationRate() decimal.Decimal {
+	return f.rate(f.Cancelled)
+}
+
+// RejectionRate is the share of the placed orders rejected by their seller
+func (f OrderFunnel) RejectionRate() decimal.Decimal {
+	return f.rate(f.Rejected)
+}
+
+// ReportPeriod is what happened on the platform in a period starting at Start. GMV is the total price of the
+// orders placed in the period that were sold, NewBuyers and NewSellers are the users registered in it
+type ReportPeriod struct {
+	Start      time.Time
+	GMV        decimal.Decimal
+	NewBuyers  int64
+	NewSellers int64
+	Funnel     OrderFunnel
+}
+
+// Add sums p and other, keeping the start of p
+func (p ReportPeriod) Add(other ReportPeriod) ReportPeriod {
+	p.GMV = p.GMV.Add(other.GMV)
+	p.NewBuyers += other.NewBuyers
+	p.NewSellers += other.NewSellers
+	p.Funnel.Placed += other.Funnel.Placed
+	p.Funnel.Accepted += other.Funnel.Accepted
+	p.Funnel.Completed += other.Funnel.Completed
+	p.Funnel.Cancelled += other.Funnel.Cancelled
+	p.Funnel.Rejected += other.Funnel.Rejected
+	return p
+}
+
+// PlatformReport is what happened on the platform over the range of Filter, computed at GeneratedAt.
+// Periods has every period of the range in order, Total sums them
+type PlatformReport struct {
+	Filter      ReportFilter
+	GeneratedAt time.Time
+	Total       ReportPeriod
+	Periods     []ReportPeriod
+}
+
+type ReportDTORequest struct {
+	From  string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Group string `query:"group" validate:"omitempty,oneof=day week month"`
+}
+
+type OrderFunnelDTOResponse struct {
+	Placed           int64   `json:"placed"`
+	Accepted         int64   `json:"accepted"`
+	Completed        int64   `json:"completed"`
+	Cancelled        int64   `json:"cancelled"`
+	Rejected         int64   `json:"rejected"`
+	AcceptanceRate   float64 `json:"acceptanceRate"`
+	CompletionRate   float64 `json:"completionRate"`
+	CancellationRate float64 `json:"cancellationRate"`
+	RejectionRate    float64 `json:"rejectionRate"`
+}
+
+type ReportPeriodDTOResponse struct {
+	Start      string                 `json:"start,omitempty"`
+	GMV        float64                `json:"gmv"`
+	NewBuyers  int64                  `json:"newBuyers"`
+	NewSellers int64                  `json:"newSellers"`
+	Orders     OrderFunnelDTOResponse `json:"orders"`
+}
+
+type PlatformReportDTOResponse struct {
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	Group       SalesGroupEnum            `json:"group"`
+	GeneratedAt time.Time                 `json:"generatedAt"`
+	Total       ReportPeriodDTOResponse   `json:"total"`
+	Periods     []ReportPeriodDTOResponse `json:"periods"`
+}
+
+type ReportUseCase interface {
+	// GetPlatformReport returns the report of the range of filter, a report is computed again once it is older than the TTL
+	GetPlatformReport(ctx context.Context, filter ReportFilter) (PlatformReport, resterrors.RestErr)
+	// ExportPlatformReport writes the report of the range of filter to w, a row for each period and one for the total
+	ExportPlatformReport(ctx context.Context, filter ReportFilter, w SheetWriter) resterrors.RestErr
+}
+
+type ReportRepository interface {
+	// GetOrderReport sums the orders placed from until before to by period of group, with the GMV and the funnel
+	GetOrderReport(ctx context.Context, from, to time.Time, group SalesGroupEnum) ([]ReportPeriod, resterrors.RestErr)
+	// GetSignups counts the buyers and sellers registered from until before to by period of group
+	GetSignups(ctx context.Context, from, to time.Time, group SalesGroupEnum) ([]ReportPeriod, resterrors.RestErr)
+}
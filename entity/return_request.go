@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type ReturnStatusEnum int
+
+const (
+	// asked by the buyer, waiting for the seller
+	RETURN_REQUESTED ReturnStatusEnum = iota
+	RETURN_APPROVED
+	RETURN_REJECTED
+	RETURN_CANCELLED
+	// sent back by the buyer
+	RETURN_SHIPPED
+	// received by the seller, waiting for the refund
+	RETURN_RECEIVED
+	RETURN_REFUNDED
+)
+
+// Active reports whether a return in the status holds its items, items of inactive returns can be returned again
+func (s ReturnStatusEnum) Active() bool {
+	return s != RETURN_REJECTED && s != RETURN_CANCELLED
+}
+
+// ReturnReasonEnum is why the buyer returns items
+type ReturnReasonEnum string
+
+const (
+	RETURN_REASON_DAMAGED          ReturnReasonEnum = "DAMAGED"
+	RETURN_REASON_WRONG_ITEM       ReturnReasonEnum = "WRONG_ITEM"
+	RETURN_REASON_NOT_AS_DESCRIBED ReturnReasonEnum = "NOT_AS_DESCRIBED"
+	RETURN_REASON_NO_LONGER_NEEDED ReturnReasonEnum = "NO_LONGER_NEEDED"
+	// a note explains the reason
+	RETURN_REASON_OTHER ReturnReasonEnum = "OTHER"
+)
+
+// ReturnRequest is a buyer sending items of a delivered order back for a refund. Photos are URLs of pictures
+// of the items, Carrier and TrackingNumber are of the return shipment and RefundID is 0 until the items were refunded.
+// Restock tells whether the seller puts the items back on sale, products don't keep stock so it is only passed on
+// with the RETURN_RECEIVED_EVENT
+type ReturnRequest struct {
+	ID             int64
+	OrderID        int64
+	BuyerID        int64
+	SellerID       int64
+	Status         ReturnStatusEnum
+	Reason         ReturnReasonEnum
+	Note           string
+	Photos         []string
+	RejectReason   string
+	Carrier        string
+	TrackingNumber string
+	Restock        bool
+	RefundID       int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ShippedAt      *time.Time
+	ReceivedAt     *time.Time
+	Items          []ReturnItem
+}
+
+// ReturnItem is Quantity of an order line item sent back
+type ReturnItem struct {
+	ID            int64
+	ReturnID      int64
+	OrderDetailID int64
+	Quantity      int64
+}
+
+type ReturnDTORequest struct {
+	Reason string                 `json:"reason" validate:"required,oneof=DAMAGED WRONG_ITEM NOT_AS_DESCRIBED NO_LONGER_NEEDED OTHER"`
+	Note   string                 `json:"note" validate:"required_if=Reason OTHER,lte=511"`
+	Photos []string               `json:"photos" validate:"lte=5,dive,url,lte=1023"`
+	Items  []ReturnItemDTORequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type ReturnItemDTORequest struct {
+	OrderItemID int64 `json:"orderItemId" validate:"required"`
+	Quantity    int64 `json:"quantity" validate:"required,gte=1"`
+}
+
+type ReturnRejectDTORequest struct {
+	Reason string `json:"reason" validate:"required,lte=511"`
+}
+
+type ReturnShipmentDTORequest struct {
+	Carrier        string `json:"carrier" validate:"required,lte=64"`
+	TrackingNumber string `json:"trackingNumber" validate:"required,lte=255"`
+}
+
+type ReturnReceiveDTORequest struct {
+	Restock bool `json:"restock"`
+}
+
+type ReturnDTOResponse struct {
+	ID             int64                   `json:"id"`
+	OrderID        int64                   `json:"orderId"`
+	BuyerID        int64                   `json:"buyerId"`
+	SellerID       int64                   `json:"sellerId"`
+	Status         ReturnStatusEnum        `json:"status"`
+	Reason         ReturnReasonEnum        `json:"reason"`
+	Note           string                  `json:"note,omitempty"`
+	Photos         []string                `json:"photos"`
+	RejectReason   string                  `json:"rejectReason,omitempty"`
+	Carrier        string                  `json:"carrier,omitempty"`
+	TrackingNumber string                  `json:"trackingNumber,omitempty"`
+	Restock        bool                    `json:"restock"`
+	RefundID       int64                   `json:"refundId,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+	ShippedAt      *time.Time              `json:"shippedAt,omitempty"`
+	ReceivedAt     *time.Time              `json:"receivedAt,omitempty"`
+	Items          []ReturnItemDTOResponse `json:"items"`
+}
+
+type ReturnItemDTOResponse struct {
+	OrderItemID int64 `json:"orderItemId"`
+	Quantity    int64 `json:"quantity"`
+}
+
+type ReturnUseCase interface {
+	// Request saves a return of items of a delivered order for its buyer
+	Request(ctx context.Context, returnRequest *ReturnRequest, user helpers.UserJWTPayload) resterrors.RestErr
+	// GetByOrderID returns the returns of order to one of its participants, oldest first
+	GetByOrderID(ctx context.Context, order *Order, user helpers.UserJWTPayload) ([]ReturnRequest, resterrors.RestErr)
+	GetByID(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+	// Approve and Reject are the answer of the seller of the order or an admin to a requested return
+	Approve(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+	Reject(ctx context.Context, returnID int64, reason string, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+	// Cancel withdraws a return the buyer didn't send yet
+	Cancel(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+	// Ship saves the shipment the buyer sent an approved return with
+	Ship(ctx context.Context, returnID int64, carrier string, trackingNumber string, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+	// Receive is called by the seller or an admin once the items arrived, it refunds them. A received return
+	// whose refund failed is refunded again by calling Receive again, a refund already made for it isn't repeated.
+	// restock is only published with the RETURN_RECEIVED_EVENT
+	Receive(ctx context.Context, returnID int64, restock bool, user helpers.UserJWTPayload) (ReturnRequest, resterrors.RestErr)
+}
+
+type ReturnRepository interface {
+	// Store saves returnRequest with its items and photos in one transaction
+	Store(ctx context.Context, returnRequest *ReturnRequest) resterrors.RestErr
+	GetByID(ctx context.Context, returnID int64) (ReturnRequest, resterrors.RestErr)
+	// GetByOrderID returns the returns of an order with their items and photos, oldest first
+	GetByOrderID(ctx context.Context, orderID int64) ([]ReturnRequest, resterrors.RestErr)
+	// Update saves the status and shipment of returnRequest when the stored status is still from, otherwise a conflict error is returned
+	Update(ctx context.Context, returnRequest *ReturnRequest, from ReturnStatusEnum) resterrors.RestErr
+}
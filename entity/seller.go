@@ -1,20 +1,39 @@
 package entity
 
-import resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+import (
+	"context"
+	"time"
 
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// Seller sells products, TaxStatus decides whether their sales are taxed.
+// PickUpProvince and PickUpPostalCode are the region of PickUpAddress, orders are shipped from there
 type Seller struct {
-	ID            int64
-	Email         string
-	Name          string
-	Password      string
-	PickUpAddress string
+	ID               int64
+	Email            string
+	Name             string
+	Password         string
+	PickUpAddress    string
+	PickUpProvince   string
+	PickUpPostalCode string
+	TaxStatus        SellerTaxStatusEnum
+	CreatedAt        time.Time
+}
+
+// PickUpRegion returns the region the orders of the seller are shipped from
+func (s Seller) PickUpRegion() ShippingAddress {
+	return ShippingAddress{Province: s.PickUpProvince, PostalCode: s.PickUpPostalCode}
 }
 
 type SellerDTORequest struct {
-	Email         string `json:"email" validate:"required,email"`
-	Name          string `json:"name" validate:"required"`
-	Password      string `json:"password" validate:"required"`
-	PickUpAddress string `json:"pickupAddress" validate:"gte=0,lte=511"`
+	Email            string `json:"email" validate:"required,email"`
+	Name             string `json:"name" validate:"required"`
+	Password         string `json:"password" validate:"required"`
+	PickUpAddress    string `json:"pickupAddress" validate:"gte=0,lte=511"`
+	PickUpProvince   string `json:"pickupProvince" validate:"lte=64"`
+	PickUpPostalCode string `json:"pickupPostalCode" validate:"omitempty,numeric,len=5"`
+	TaxStatus        string `json:"taxStatus" validate:"omitempty,oneof=REGISTERED UNREGISTERED"`
 }
 
 type SellerDTOLogin struct {
@@ -23,22 +42,25 @@ type SellerDTOLogin struct {
 }
 
 type SellerDTOResponse struct {
-	ID            int64  `json:"id"`
-	Email         string `json:"email"`
-	Name          string `json:"name"`
-	PickUpAddress string `json:"pickupAddress"`
+	ID               int64               `json:"id"`
+	Email            string              `json:"email"`
+	Name             string              `json:"name"`
+	PickUpAddress    string              `json:"pickupAddress"`
+	PickUpProvince   string              `json:"pickupProvince"`
+	PickUpPostalCode string              `json:"pickupPostalCode"`
+	TaxStatus        SellerTaxStatusEnum `json:"taxStatus"`
 }
 
 type SellerUseCase interface {
-	Register(seller *Seller) resterrors.RestErr
-	Login(seller *Seller) (Seller, resterrors.RestErr)
+	Register(ctx context.Context, seller *Seller) resterrors.RestErr
+	Login(ctx context.Context, seller *Seller) (Seller, resterrors.RestErr)
 }
 
 type SellerRepository interface {
-	GetAll() ([]Seller, resterrors.RestErr)
-	GetByID(seller *Seller) resterrors.RestErr
-	Update(seller *Seller) resterrors.RestErr
-	Store(seller *Seller) resterrors.RestErr
-	Delete(seller *Seller) resterrors.RestErr
-	GetByEmail(seller *Seller) (Seller, resterrors.RestErr)
+	GetAll(ctx context.Context) ([]Seller, resterrors.RestErr)
+	GetByID(ctx context.Context, seller *Seller) resterrors.RestErr
+	Update(ctx context.Context, seller *Seller) resterrors.RestErr
+	Store(ctx context.Context, seller *Seller) resterrors.RestErr
+	Delete(ctx context.Context, seller *Seller) resterrors.RestErr
+	GetByEmail(ctx context.Context, seller *Seller) (Seller, resterrors.RestErr)
 }
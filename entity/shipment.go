@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type ShipmentStatusEnum int
+
+const (
+	SHIPMENT_IN_TRANSIT ShipmentStatusEnum = iota
+	SHIPMENT_DELIVERED
+)
+
+// Shipment is the parcel the seller sent an order with, an order has at most one. Events are what the courier
+// reported about the parcel, oldest first. CheckedAt is when the courier was last asked, nil until it is asked once
+type Shipment struct {
+	ID             int64
+	OrderID        int64
+	Courier        string
+	TrackingNumber string
+	Status         ShipmentStatusEnum
+	ShippedAt      time.Time
+	DeliveredAt    *time.Time
+	CheckedAt      *time.Time
+	Events         []TrackingEvent
+}
+
+// TrackingEvent is a step of a parcel reported by its courier, Code is the status code of the courier
+type TrackingEvent struct {
+	ID          int64
+	ShipmentID  int64
+	Code        string
+	Description string
+	Location    string
+	OccurredAt  time.Time
+}
+
+// CourierTracking is what a courier knows about a parcel, Events holds every event reported so far
+type CourierTracking struct {
+	Events      []TrackingEvent
+	Delivered   bool
+	DeliveredAt time.Time
+}
+
+type ShipmentDTORequest struct {
+	Courier        string `json:"courier" validate:"required,lte=64"`
+	TrackingNumber string `json:"trackingNumber" validate:"required,alphanum,lte=64"`
+}
+
+type ShipmentDTOResponse struct {
+	OrderID        int64                      `json:"orderId"`
+	Courier        string                     `json:"courier"`
+	TrackingNumber string                     `json:"trackingNumber"`
+	Status         ShipmentStatusEnum         `json:"status"`
+	ShippedAt      time.Time                  `json:"shippedAt"`
+	DeliveredAt    *time.Time                 `json:"deliveredAt,omitempty"`
+	CheckedAt      *time.Time                 `json:"checkedAt,omitempty"`
+	Events         []TrackingEventDTOResponse `json:"events"`
+}
+
+type TrackingEventDTOResponse struct {
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+// CourierTracker asks a courier where a parcel is
+type CourierTracker interface {
+	Track(ctx context.Context, courier, trackingNumber string) (CourierTracking, resterrors.RestErr)
+}
+
+type ShipmentUseCase interface {
+	// Ship saves the shipment of an accepted order of the seller user and moves the order to SHIPPED
+	Ship(ctx context.Context, shipment *Shipment, user helpers.UserJWTPayload) resterrors.RestErr
+	// GetByOrderID returns the shipment of an order with its tracking events
+	GetByOrderID(ctx context.Context, order *Order, user helpers.UserJWTPayload) (Shipment, resterrors.RestErr)
+	// TrackInTransit asks the courier of up to limit shipments in transit that weren't checked since checkedBefore for new events,
+	// the order of a delivered shipment is moved to DELIVERED. It returns the shipments that were checked
+	TrackInTransit(ctx context.Context, checkedBefore time.Time, limit int) ([]Shipment, resterrors.RestErr)
+}
+
+type ShipmentRepository interface {
+	Store(ctx context.Context, shipment *Shipment) resterrors.RestErr
+	GetByOrderID(ctx context.Context, orderID int64) (Shipment, resterrors.RestErr)
+	GetInTransit(ctx context.Context, checkedBefore time.Time, limit int) ([]Shipment, resterrors.RestErr)
+	// SaveTracking saves the status, DeliveredAt and CheckedAt of shipment with the events it doesn't have yet in one transaction
+	SaveTracking(ctx context.Context, shipment *Shipment, events []TrackingEvent) resterrors.RestErr
+}
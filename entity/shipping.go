@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"context"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// ShippingAddress is the region a parcel is sent from or to, rates are looked up by the postal code
+// and by the province when the postal code isn't known to the provider
+type ShippingAddress struct {
+	Province   string
+	PostalCode string
+}
+
+// ShippingRate is what a carrier service charges to send a parcel of Weight grams between two regions.
+// Service identifies the service of the carrier, it is what buyers choose an order with
+type ShippingRate struct {
+	Service       string
+	Carrier       string
+	Name          string
+	Fee           decimal.Decimal
+	Weight        int64
+	EstimatedDays int
+}
+
+// ShippingQuote asks the rates to send Items to Destination from the pickup region of their seller,
+// the weight of the items is taken from their products
+type ShippingQuote struct {
+	Destination ShippingAddress
+	Items       []OrderDetail
+}
+
+type ShippingAddressDTORequest struct {
+	Province   string `json:"province" validate:"required_without=PostalCode,lte=64"`
+	PostalCode string `json:"postalCode" validate:"omitempty,numeric,len=5"`
+}
+
+type ShippingQuoteDTORequest struct {
+	Destination ShippingAddressDTORequest `json:"destination" validate:"required"`
+	Items       []OrderDetailDTORequest   `json:"items" validate:"required,gte=1,dive"`
+}
+
+type ShippingRateDTOResponse struct {
+	Service       string  `json:"service"`
+	Carrier       string  `json:"carrier"`
+	Name          string  `json:"name"`
+	Fee           float64 `json:"fee"`
+	Weight        int64   `json:"weight"`
+	EstimatedDays int     `json:"estimatedDays"`
+}
+
+// ShippingRateProvider gives the rates of the carrier services that send a parcel of weight grams
+// from origin to destination, cheapest first
+type ShippingRateProvider interface {
+	Rates(ctx context.Context, origin, destination ShippingAddress, weight int64) ([]ShippingRate, resterrors.RestErr)
+}
+
+type ShippingUseCase interface {
+	// Quote returns the rates of every service that ships the items of quote, the items must be sold by one seller
+	Quote(ctx context.Context, quote ShippingQuote) ([]ShippingRate, resterrors.RestErr)
+	// Apply sets order.ShippingOrigin to the pickup region of order.Seller, adds the fee of order.ShippingService
+	// from there to order.ShippingDestination to order.TotalPrice and sets ShippingFee.
+	// The service is required and the products of order.Items must be loaded
+	Apply(ctx context.Context, order *Order) resterrors.RestErr
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"context"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// SellerTaxStatusEnum is whether a seller is registered to collect tax on their sales
+type SellerTaxStatusEnum string
+
+const (
+	TAX_REGISTERED   SellerTaxStatusEnum = "REGISTERED"
+	TAX_UNREGISTERED SellerTaxStatusEnum = "UNREGISTERED"
+)
+
+// TaxPolicy decides how line items are taxed. Rate is a fraction of the price, 0.11 is 11%.
+// With Inclusive prices already include the tax, otherwise it is added to them
+type TaxPolicy interface {
+	Rate(seller Seller, product Product) decimal.Decimal
+	Inclusive() bool
+	// Round rounds an amount of tax to what is charged
+	Round(amount decimal.Decimal) decimal.Decimal
+}
+
+type TaxUseCase interface {
+	// Apply splits the discount of a new order between its items and sets the tax of each item and the tax of the order,
+	// it is run after the voucher is applied
+	Apply(ctx context.Context, order *Order) resterrors.RestErr
+}
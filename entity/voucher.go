@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type VoucherTypeEnum string
+
+const (
+	// Value is the percentage of the order subtotal taken off
+	VOUCHER_PERCENTAGE VoucherTypeEnum = "PERCENTAGE"
+	// Value is the amount taken off
+	VOUCHER_FIXED VoucherTypeEnum = "FIXED"
+)
+
+// Voucher is a promo code buyers apply to an order. A voucher of a seller (SellerID) only applies to orders of that seller,
+// one without a seller applies to every order. It can be used from StartsAt until EndsAt on orders whose subtotal is at least
+// MinSpend, UsageLimit caps how many orders use it and PerBuyerLimit how many orders of one buyer do, a zero limit
+// or MaxDiscount is no limit. UsedCount is the number of orders using it
+type Voucher struct {
+	ID            int64
+	Code          string
+	Type          VoucherTypeEnum
+	Value         decimal.Decimal
+	SellerID      int64
+	MinSpend      decimal.Decimal
+	MaxDiscount   decimal.Decimal
+	StartsAt      time.Time
+	EndsAt        time.Time
+	UsageLimit    int64
+	PerBuyerLimit int64
+	UsedCount     int64
+	CreatedAt     time.Time
+}
+
+// Discount returns what the voucher takes off subtotal, rounded to cents and never more than subtotal
+func (v Voucher) Discount(subtotal decimal.Decimal) decimal.Decimal {
+	discount := v.Value
+	if v.Type == VOUCHER_PERCENTAGE {
+		discount = subtotal.Mul(v.Value).Div(decimal.NewFromInt(100)).Round(2)
+	}
+	if v.MaxDiscount.IsPositive() && discount.GreaterThan(v.MaxDiscount) {
+		discount = v.MaxDiscount
+	}
+	if discount.GreaterThan(subtotal) {
+		discount = subtotal
+	}
+	return discount
+}
+
+type VoucherDTORequest struct {
+	Code          string    `json:"code" validate:"required,alphanum,gte=3,lte=32"`
+	Type          string    `json:"type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value         float64   `json:"value" validate:"required,gt=0"`
+	SellerID      int64     `json:"sellerId" validate:"omitempty,gte=1"`
+	MinSpend      float64   `json:"minSpend" validate:"gte=0"`
+	MaxDiscount   float64   `json:"maxDiscount" validate:"gte=0"`
+	StartsAt      time.Time `json:"startsAt" validate:"required"`
+	EndsAt        time.Time `json:"endsAt" validate:"required,gtfield=StartsAt"`
+	UsageLimit    int64     `json:"usageLimit" validate:"gte=0"`
+	PerBuyerLimit int64     `json:"perBuyerLimit" validate:"gte=0"`
+}
+
+type VoucherDTOResponse struct {
+	ID            int64           `json:"id"`
+	Code          string          `json:"code"`
+	Type          VoucherTypeEnum `json:"type"`
+	Value         float64         `json:"value"`
+	SellerID      int64           `json:"sellerId,omitempty"`
+	MinSpend      float64         `json:"minSpend"`
+	MaxDiscount   float64         `json:"maxDiscount"`
+	StartsAt      time.Time       `json:"startsAt"`
+	EndsAt        time.Time       `json:"endsAt"`
+	UsageLimit    int64           `json:"usageLimit"`
+	PerBuyerLimit int64           `json:"perBuyerLimit"`
+	UsedCount     int64           `json:"usedCount"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type VoucherUseCase interface {
+	// Store saves a voucher of the seller user, or of any seller or the platform when user is an admin
+	Store(ctx context.Context, voucher *Voucher, user helpers.UserJWTPayload) resterrors.RestErr
+	// Fetch returns the vouchers of the seller user, or every voucher to an admin
+	Fetch(ctx context.Context, user helpers.UserJWTPayload) ([]Voucher, resterrors.RestErr)
+	// Apply takes the discount of the voucher with order.VoucherCode off order.TotalPrice and sets VoucherID and Discount.
+	// The usage limits are enforced when the order is saved
+	Apply(ctx context.Context, order *Order) resterrors.RestErr
+	// OrderCancelled gives back the use of a voucher by a cancelled or rejected order, it is run as an OrderCancelHook
+	OrderCancelled(ctx context.Context, order *Order) resterrors.RestErr
+}
+
+type VoucherRepository interface {
+	Store(ctx context.Context, voucher *Voucher) resterrors.RestErr
+	GetByCode(ctx context.Context, code string) (Voucher, resterrors.RestErr)
+	GetBySellerID(ctx context.Context, sellerID int64) ([]Voucher, resterrors.RestErr)
+	GetAll(ctx context.Context) ([]Voucher, resterrors.RestErr)
+	// Release gives back the use of a voucher by an order in one transaction, nothing happens when the order didn't use one
+	Release(ctx context.Context, orderID int64) resterrors.RestErr
+}
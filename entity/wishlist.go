@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// WishlistItem is a product a buyer saved for later
+type WishlistItem struct {
+	BuyerID int64
+	Product Product
+	AddedAt time.Time
+}
+
+// FollowedSeller is a seller a buyer follows
+type FollowedSeller struct {
+	BuyerID    int64
+	Seller     Seller
+	FollowedAt time.Time
+}
+
+type WishlistItemDTORequest struct {
+	ProductID int64 `json:"productId" validate:"required,gt=0"`
+}
+
+type WishlistItemDTOResponse struct {
+	Product ProductDTOResponse `json:"product"`
+	AddedAt time.Time          `json:"addedAt"`
+}
+
+type FollowedSellerDTORequest struct {
+	SellerID int64 `json:"sellerId" validate:"required,gt=0"`
+}
+
+type FollowedSellerDTOResponse struct {
+	SellerID   int64     `json:"sellerId"`
+	Name       string    `json:"name"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// ProductPriceHook is run by ProductUseCase after the price of a product was lowered
+type ProductPriceHook interface {
+	PriceDropped(ctx context.Context, product Product, oldPrice decimal.Decimal) resterrors.RestErr
+}
+
+type WishlistUseCase interface {
+	// AddItem saves the product to the wishlist of the buyer, a product can only be saved once
+	AddItem(ctx context.Context, buyerID, productID int64) (WishlistItem, resterrors.RestErr)
+	// GetItems returns the wishlist of the buyer with the current product details, the latest saved first
+	GetItems(ctx context.Context, buyerID int64) ([]WishlistItem, resterrors.RestErr)
+	RemoveItem(ctx context.Context, buyerID, productID int64) resterrors.RestErr
+	// Follow makes the buyer follow the seller and tells the seller about it with a SELLER_FOLLOWED_EVENT
+	Follow(ctx context.Context, buyerID, sellerID int64) (FollowedSeller, resterrors.RestErr)
+	// GetFollowing returns the sellers the buyer follows, the latest followed first
+	GetFollowing(ctx context.Context, buyerID int64) ([]FollowedSeller, resterrors.RestErr)
+	Unfollow(ctx context.Context, buyerID, sellerID int64) resterrors.RestErr
+	// PriceDropped publishes a WISHLIST_PRICE_DROPPED_EVENT to each buyer with the product in their wishlist,
+	// it is run as a ProductPriceHook
+	PriceDropped(ctx context.Context, product Product, oldPrice decimal.Decimal) resterrors.RestErr
+}
+
+type WishlistRepository interface {
+	// StoreItem saves item, a conflict error is returned when the product already is in the wishlist
+	StoreItem(ctx context.Context, item *WishlistItem) resterrors.RestErr
+	GetItemsByBuyerID(ctx context.Context, buyerID int64) ([]WishlistItem, resterrors.RestErr)
+	// DeleteItem removes the product from the wishlist of the buyer, a not found error is returned when it isn't there
+	DeleteItem(ctx context.Context, buyerID, productID int64) resterrors.RestErr
+	// GetBuyerIDsByProductID returns the buyers with the product in their wishlist
+	GetBuyerIDsByProductID(ctx context.Context, productID int64) ([]int64, resterrors.RestErr)
+	// StoreFollowedSeller saves followed, a conflict error is returned when the buyer already follows the seller
+	StoreFollowedSeller(ctx context.Context, followed *FollowedSeller) resterrors.RestErr
+	GetFollowedSellersByBuyerID(ctx context.Context, buyerID int64) ([]FollowedSeller, resterrors.RestErr)
+	// DeleteFollowedSeller removes the seller from the sellers the buyer follows, a not found error is returned
+	// when the buyer doesn't follow them
+	DeleteFollowedSeller(ctx context.Context, buyerID, sellerID int64) resterrors.RestErr
+}
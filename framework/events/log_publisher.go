@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type logPublisher struct {
+	logger *log.Logger
+}
+
+// NewLogPublisher will create a object with entity.EventPublisher interface representation,
+// events are written as JSON lines to logger, the standard logger is used when logger is nil
+func NewLogPublisher(logger *log.Logger) entity.EventPublisher {
+	if logger == nil {
+		logger = log.New(log.Writer(), "", log.LstdFlags)
+	}
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event entity.Event) resterrors.RestErr {
+	j, err := json.Marshal(event)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to publish event", err)
+	}
+
+	p.logger.Println("event", string(j))
+	return nil
+}
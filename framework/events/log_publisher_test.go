@@ -0,0 +1,34 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := events.NewLogPublisher(log.New(&buf, "", 0))
+
+	pErr := publisher.Publish(context.Background(), entity.Event{
+		Name:       entity.ORDER_EXPIRED_EVENT,
+		OccurredAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Payload: entity.OrderEventPayload{
+			OrderID:    1,
+			BuyerID:    2,
+			SellerID:   3,
+			Status:     entity.CANCELLED,
+			ReasonCode: entity.REASON_EXPIRED,
+		},
+	})
+
+	assert.Nil(t, pErr)
+	assert.Equal(t, `event {"name":"order.expired","occurredAt":"2021-05-01T10:00:00Z",`+
+		`"payload":{"orderId":1,"buyerId":2,"sellerId":3,"status":2,"reasonCode":"EXPIRED"}}`+"\n", buf.String())
+}
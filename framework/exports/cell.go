@@ -0,0 +1,50 @@
+package exports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// text formats a cell of an entity.SheetWriter row, amounts keep two decimals and no exponent
+func text(cell interface{}) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case decimal.Decimal:
+		return v.StringFixed(2)
+	case time.Time:
+		return v.Format(dateTimeLayout)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(cell)
+}
+
+// safeText keeps spreadsheet applications from running text as a formula, text starting like one is quoted
+func safeText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// UnquoteText undoes the quote safeText puts before text starting like a formula, files written by a SheetWriter
+// are read back with the values they were given
+func UnquoteText(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,33 @@
+package exports
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVWriter is an entity.SheetWriter writing comma separated values, rows are buffered a few kilobytes at a time
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter creates a CSVWriter writing to w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *CSVWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		if s, ok := cell.(string); ok {
+			record[i] = safeText(s)
+			continue
+		}
+		record[i] = text(cell)
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
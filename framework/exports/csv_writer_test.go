@@ -0,0 +1,27 @@
+package exports_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := exports.NewCSVWriter(&buf)
+
+	assert.NoError(t, w.WriteRow("Order ID", "Order date", "Total price", "Tax inclusive", "Address"))
+	assert.NoError(t, w.WriteRow(int64(1), time.Date(2021, 5, 1, 10, 30, 0, 0, time.UTC),
+		decimal.RequireFromString("1234567.5"), true, "Jl. Sudirman No. 1, Jakarta"))
+	assert.NoError(t, w.WriteRow(int64(2), time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC),
+		decimal.NewFromFloat(0.1).Add(decimal.NewFromFloat(0.2)), false, "=HYPERLINK(\"http://x\")"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "Order ID,Order date,Total price,Tax inclusive,Address\n"+
+		"1,2021-05-01 10:30:00,1234567.50,true,\"Jl. Sudirman No. 1, Jakarta\"\n"+
+		"2,2021-05-02 00:00:00,0.30,false,\"'=HYPERLINK(\"\"http://x\"\")\"\n", buf.String())
+}
@@ -0,0 +1,126 @@
+package exports_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/exports"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// sheet is the part of a worksheet read back by the tests
+type sheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			S      string `xml:"s,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readParts(t *testing.T, b []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc.Close()
+		parts[f.Name] = content
+	}
+	return parts
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := exports.NewXLSXWriter(&buf, "Orders & more")
+
+	assert.NoError(t, w.WriteRow("Order ID", "Order date", "Total price", "Tax inclusive", "Voucher code", "Product name"))
+	assert.NoError(t, w.WriteRow(int64(1), time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC),
+		decimal.RequireFromString("1234567.5"), true, "", "Kopi <Toraja> & Gayo"))
+	assert.NoError(t, w.Close())
+
+	parts := readParts(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, name)
+	}
+	assert.Contains(t, string(parts["xl/workbook.xml"]), `name="Orders &amp; more"`)
+
+	var s sheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &s); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, s.Rows, 2) {
+		return
+	}
+	assert.Equal(t, 1, s.Rows[0].R)
+	assert.Equal(t, "Order ID", s.Rows[0].Cells[0].Inline)
+	assert.Equal(t, "F1", s.Rows[0].Cells[5].R)
+
+	row := s.Rows[1].Cells
+	// the empty voucher code has no cell
+	if !assert.Len(t, row, 5) {
+		return
+	}
+	assert.Equal(t, "A2", row[0].R)
+	assert.Equal(t, "1", row[0].V)
+	assert.Equal(t, "2", row[1].S)
+	assert.Equal(t, "44317.5", row[1].V)
+	assert.Equal(t, "1", row[2].S)
+	assert.Equal(t, "1234567.50", row[2].V)
+	assert.Equal(t, "b", row[3].T)
+	assert.Equal(t, "1", row[3].V)
+	assert.Equal(t, "F2", row[4].R)
+	assert.Equal(t, "inlineStr", row[4].T)
+	assert.Equal(t, "Kopi <Toraja> & Gayo", row[4].Inline)
+}
+
+func TestXLSXWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w := exports.NewXLSXWriter(&buf, "Orders")
+	assert.NoError(t, w.Close())
+
+	var s sheet
+	if err := xml.Unmarshal(readParts(t, buf.Bytes())["xl/worksheets/sheet1.xml"], &s); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, s.Rows)
+}
+
+func TestXLSXWriterColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w := exports.NewXLSXWriter(&buf, "Wide")
+	cells := make([]interface{}, 28)
+	for i := range cells {
+		cells[i] = int64(i)
+	}
+	assert.NoError(t, w.WriteRow(cells...))
+	assert.NoError(t, w.Close())
+
+	var s sheet
+	if err := xml.Unmarshal(readParts(t, buf.Bytes())["xl/worksheets/sheet1.xml"], &s); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Z1", s.Rows[0].Cells[25].R)
+	assert.Equal(t, "AA1", s.Rows[0].Cells[26].R)
+	assert.Equal(t, "AB1", s.Rows[0].Cells[27].R)
+}
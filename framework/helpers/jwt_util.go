@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 )
 
 type UserTypeEnum int
@@ -12,6 +14,7 @@ type UserTypeEnum int
 const (
 	BUYER_TYPE UserTypeEnum = iota
 	SELLER_TYPE
+	ADMIN_TYPE
 )
 
 type UserJWTPayload struct {
@@ -44,6 +47,38 @@ func GenerateToken(payload *UserJWTPayload, secret []byte) (string, error) {
 	return token, nil
 }
 
+// GetJWTPayload reads the logged in user from token claims set by GenerateToken
+func GetJWTPayload(claims jwt.MapClaims) (UserJWTPayload, error) {
+	id, idOk := claims["id"].(float64)
+	userType, typeOk := claims["type"].(float64)
+	if !idOk || !typeOk {
+		return UserJWTPayload{}, errors.New("invalid token claims")
+	}
+
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	return UserJWTPayload{
+		ID:    int64(id),
+		Email: email,
+		Name:  name,
+		Type:  UserTypeEnum(userType),
+	}, nil
+}
+
+// LoggedInUser reads the user of the request from the token claims set by the ValidateRequest middleware
+func LoggedInUser(c *fiber.Ctx) (UserJWTPayload, resterrors.RestErr) {
+	tokenClaims, ok := c.Context().UserValue("tokenClaims").(jwt.MapClaims)
+	if !ok {
+		return UserJWTPayload{}, resterrors.NewUnauthorizedError("token claims not exists")
+	}
+
+	user, err := GetJWTPayload(tokenClaims)
+	if err != nil {
+		return UserJWTPayload{}, resterrors.NewUnauthorizedError(err.Error())
+	}
+	return user, nil
+}
+
 func VerifyToken(tokenString string, secret []byte) (*jwt.Token, error) {
 	parsedToken, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
 		if jwt.GetSigningMethod("HS256") != t.Method {
@@ -1,5 +1,29 @@
 package helpers
 
+import (
+	"github.com/gofiber/fiber/v2"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
 type SuccessResponse struct {
 	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// CursorMeta is the meta of a cursor paginated response, NextCursor is empty on the last page
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ErrorResponse writes rErr as an application/problem+json response
+func ErrorResponse(c *fiber.Ctx, rErr resterrors.RestErr) error {
+	res := rErr.ErrorResponse()
+	res.ErrInstance = c.Path()
+
+	if err := c.Status(rErr.Status()).JSON(res); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, resterrors.ProblemContentType)
+	return nil
 }
@@ -1,24 +1,93 @@
 package resterrors
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 )
 
+// ProblemContentType is the media type of RFC 7807 problem details responses
+const ProblemContentType = "application/problem+json"
+
+// Code is a stable, machine-readable identifier of an error
+type Code string
+
+const (
+	CodeBadRequest          Code = "bad_request"
+	CodeValidationFailed    Code = "validation_failed"
+	CodeUnauthorized        Code = "unauthorized"
+	CodeForbidden           Code = "forbidden"
+	CodeNotFound            Code = "not_found"
+	CodeConflict            Code = "conflict"
+	CodePreconditionFailed  Code = "precondition_failed"
+	CodeUnprocessableEntity Code = "unprocessable_entity"
+	CodeTimeout             Code = "timeout"
+	CodeBadGateway          Code = "bad_gateway"
+	CodeInternalServerError Code = "internal_server_error"
+)
+
+// Sentinel errors, every RestErr matches the sentinel of its code with errors.Is
+var (
+	ErrBadRequest          = errors.New("bad request")
+	ErrValidationFailed    = errors.New("validation failed")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrUnprocessableEntity = errors.New("unprocessable entity")
+	ErrTimeout             = errors.New("timeout")
+	ErrBadGateway          = errors.New("bad gateway")
+	ErrInternalServerError = errors.New("internal server error")
+)
+
+var sentinels = map[Code]error{
+	CodeBadRequest:          ErrBadRequest,
+	CodeValidationFailed:    ErrValidationFailed,
+	CodeUnauthorized:        ErrUnauthorized,
+	CodeForbidden:           ErrForbidden,
+	CodeNotFound:            ErrNotFound,
+	CodeConflict:            ErrConflict,
+	CodePreconditionFailed:  ErrPreconditionFailed,
+	CodeUnprocessableEntity: ErrUnprocessableEntity,
+	CodeTimeout:             ErrTimeout,
+	CodeBadGateway:          ErrBadGateway,
+	CodeInternalServerError: ErrInternalServerError,
+}
+
 //RestErr interface
 type RestErr interface {
 	Message() string
 	Status() int
+	Code() Code
 	Error() string
 	Causes() string
+	Fields() []FieldError
+	Unwrap() error
 	ErrorResponse() restErr
 }
 
+// FieldError describes a single failed validation rule of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// restErr is serialized as an RFC 7807 problem details document,
+// code, errors and causes are extension members
 type restErr struct {
-	ErrMessage string `json:"message"`
-	ErrStatus  int    `json:"status"`
-	ErrError   string `json:"error"`
-	ErrCauses  string `json:"causes"`
+	ErrType     string       `json:"type"`
+	ErrTitle    string       `json:"title"`
+	ErrStatus   int          `json:"status"`
+	ErrMessage  string       `json:"detail"`
+	ErrInstance string       `json:"instance,omitempty"`
+	ErrCode     Code         `json:"code"`
+	ErrFields   []FieldError `json:"errors,omitempty"`
+	ErrCauses   string       `json:"causes,omitempty"`
+	err         error
 }
 
 func (e restErr) ErrorResponse() restErr {
@@ -26,7 +95,7 @@ func (e restErr) ErrorResponse() restErr {
 }
 
 func (e restErr) Error() string {
-	return fmt.Sprintf("message: %s - status: %d - error: %s - causes: %v", e.ErrMessage, e.ErrStatus, e.ErrMessage, e.ErrCauses)
+	return fmt.Sprintf("message: %s - status: %d - error: %s - causes: %v", e.ErrMessage, e.ErrStatus, e.ErrCode, e.ErrCauses)
 }
 
 func (e restErr) Message() string {
@@ -37,55 +106,108 @@ func (e restErr) Status() int {
 	return e.ErrStatus
 }
 
+func (e restErr) Code() Code {
+	return e.ErrCode
+}
+
 func (e restErr) Causes() string {
 	return e.ErrCauses
 }
 
-//NewRestError func
-func NewRestError(message string, status int, err string) RestErr {
-	return restErr{
-		ErrMessage: message,
+func (e restErr) Fields() []FieldError {
+	return e.ErrFields
+}
+
+// Unwrap returns the underlying error, if any
+func (e restErr) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the sentinel error of e's code
+func (e restErr) Is(target error) bool {
+	sentinel, ok := sentinels[e.ErrCode]
+	return ok && sentinel == target
+}
+
+func newRestErr(message string, status int, code Code, err error) restErr {
+	result := restErr{
+		ErrType:    fmt.Sprintf("/problems/%s", code),
+		ErrTitle:   http.StatusText(status),
 		ErrStatus:  status,
-		ErrError:   err,
+		ErrMessage: message,
+		ErrCode:    code,
+		err:        err,
+	}
+	if err != nil {
+		result.ErrCauses = err.Error()
 	}
+	return result
+}
+
+//NewRestError func
+func NewRestError(message string, status int, code Code) RestErr {
+	return newRestErr(message, status, code, nil)
 }
 
 //NewBadRequestError func
 func NewBadRequestError(message string) RestErr {
-	return restErr{
-		ErrMessage: message,
-		ErrStatus:  http.StatusBadRequest,
-		ErrError:   "bad_request",
-	}
+	return newRestErr(message, http.StatusBadRequest, CodeBadRequest, nil)
+}
+
+//NewValidationError func
+func NewValidationError(message string, fields []FieldError) RestErr {
+	result := newRestErr(message, http.StatusBadRequest, CodeValidationFailed, nil)
+	result.ErrFields = fields
+	return result
 }
 
 //NewNotFoundError func
 func NewNotFoundError(message string) RestErr {
-	return restErr{
-		ErrMessage: message,
-		ErrStatus:  http.StatusNotFound,
-		ErrError:   "not_found",
-	}
+	return newRestErr(message, http.StatusNotFound, CodeNotFound, nil)
+}
+
+//NewConflictError func
+func NewConflictError(message string) RestErr {
+	return newRestErr(message, http.StatusConflict, CodeConflict, nil)
+}
+
+//NewPreconditionFailedError func
+func NewPreconditionFailedError(message string) RestErr {
+	return newRestErr(message, http.StatusPreconditionFailed, CodePreconditionFailed, nil)
 }
 
 //NewUnauthorizedError func
 func NewUnauthorizedError(message string) RestErr {
-	return restErr{
-		ErrMessage: message,
-		ErrStatus:  http.StatusUnauthorized,
-		ErrError:   "unauthorized",
+	return newRestErr(message, http.StatusUnauthorized, CodeUnauthorized, nil)
+}
+
+//NewForbiddenError func
+func NewForbiddenError(message string) RestErr {
+	return newRestErr(message, http.StatusForbidden, CodeForbidden, nil)
+}
+
+//NewUnprocessableEntityError func
+func NewUnprocessableEntityError(message string, err error) RestErr {
+	return newRestErr(message, http.StatusUnprocessableEntity, CodeUnprocessableEntity, err)
+}
+
+//NewTimeoutError func
+func NewTimeoutError(message string, err error) RestErr {
+	return newRestErr(message, http.StatusGatewayTimeout, CodeTimeout, err)
+}
+
+//NewBadGatewayError func, for failures of the services we depend on like a payment gateway
+func NewBadGatewayError(message string, err error) RestErr {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return NewTimeoutError(message, err)
 	}
+	return newRestErr(message, http.StatusBadGateway, CodeBadGateway, err)
 }
 
-//NewInternalServerError func
+//NewInternalServerError func, errors caused by a cancelled or expired context are reported as timeouts
 func NewInternalServerError(message string, err error) RestErr {
-	result := restErr{
-		ErrMessage: message,
-		ErrStatus:  http.StatusInternalServerError,
-		ErrError:   "internal_server_error",
-	}
-	if err != nil {
-		result.ErrCauses = err.Error()
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return NewTimeoutError(message, err)
 	}
-	return result
+	return newRestErr(message, http.StatusInternalServerError, CodeInternalServerError, err)
 }
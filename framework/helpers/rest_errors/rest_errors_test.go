@@ -0,0 +1,66 @@
+package resterrors_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	rErr := resterrors.NewNotFoundError("order not found")
+
+	assert.Equal(t, "message: order not found - status: 404 - error: not_found - causes: ", rErr.Error())
+}
+
+func TestIs(t *testing.T) {
+	t.Run("matches sentinel of its code", func(t *testing.T) {
+		rErr := resterrors.NewNotFoundError("order not found")
+
+		assert.True(t, errors.Is(rErr, resterrors.ErrNotFound))
+		assert.False(t, errors.Is(rErr, resterrors.ErrInternalServerError))
+	})
+
+	t.Run("unwraps underlying error", func(t *testing.T) {
+		rErr := resterrors.NewInternalServerError("error when trying to get data", sql.ErrNoRows)
+
+		assert.True(t, errors.Is(rErr, sql.ErrNoRows))
+		assert.True(t, errors.Is(rErr, resterrors.ErrInternalServerError))
+		assert.Equal(t, sql.ErrNoRows.Error(), rErr.Causes())
+	})
+}
+
+func TestInternalServerErrorTimeout(t *testing.T) {
+	rErr := resterrors.NewInternalServerError("error when trying to get data", context.DeadlineExceeded)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rErr.Status())
+	assert.True(t, errors.Is(rErr, resterrors.ErrTimeout))
+	assert.True(t, errors.Is(rErr, context.DeadlineExceeded))
+}
+
+func TestAs(t *testing.T) {
+	var err error = resterrors.NewUnauthorizedError("token claims not exists")
+
+	var rErr resterrors.RestErr
+	assert.True(t, errors.As(err, &rErr))
+	assert.Equal(t, resterrors.CodeUnauthorized, rErr.Code())
+	assert.Equal(t, http.StatusUnauthorized, rErr.Status())
+}
+
+func TestErrorResponse(t *testing.T) {
+	fields := []resterrors.FieldError{
+		{Field: "Email", Tag: "email", Message: "Field validation for 'Email' failed on the 'email' tag"},
+	}
+	res := resterrors.NewValidationError("request validation failed", fields).ErrorResponse()
+
+	assert.Equal(t, "/problems/validation_failed", res.ErrType)
+	assert.Equal(t, http.StatusText(http.StatusBadRequest), res.ErrTitle)
+	assert.Equal(t, http.StatusBadRequest, res.ErrStatus)
+	assert.Equal(t, "request validation failed", res.ErrMessage)
+	assert.Equal(t, resterrors.CodeValidationFailed, res.ErrCode)
+	assert.Equal(t, fields, res.ErrFields)
+}
@@ -0,0 +1,37 @@
+// Package testhelpers has the stand-ins shared by the tests of controllers and usecases
+package testhelpers
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+)
+
+// WithClaims stands in for the ValidateRequest middleware, the request is made by user
+func WithClaims(user helpers.UserJWTPayload) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(user.ID), "type": float64(user.Type)})
+		return c.Next()
+	}
+}
+
+// SheetRows is an entity.SheetWriter keeping the rows written to it
+type SheetRows struct {
+	Rows   [][]interface{}
+	Closed bool
+	// AfterWrite is called with the number of rows after each row, when set
+	AfterWrite func(rows int)
+}
+
+func (s *SheetRows) WriteRow(cells ...interface{}) error {
+	s.Rows = append(s.Rows, cells)
+	if s.AfterWrite != nil {
+		s.AfterWrite(len(s.Rows))
+	}
+	return nil
+}
+
+func (s *SheetRows) Close() error {
+	s.Closed = true
+	return nil
+}
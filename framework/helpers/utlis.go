@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 )
 
 func JwtFromHeader(auth string, authScheme string) (string, error) {
@@ -17,6 +18,15 @@ func JwtFromHeader(auth string, authScheme string) (string, error) {
 	return "", errors.New("missing or malformed JWT")
 }
 
+// ParseDuration parses value like "5s" or "1m30s", fallback is returned when value is empty or invalid
+func ParseDuration(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
 func GetTimeFromUint8(t []uint8) (time.Time, error) {
 	pt, err := time.Parse("2006-01-02 15:04:05", string(t))
 	return pt, err
@@ -31,19 +41,35 @@ func GetTimeNow() (time.Time, error) {
 	return tn, err
 }
 
-func CreateValidationMessage(err error) (string, error) {
-	if _, ok := err.(*validator.InvalidValidationError); ok {
-		return "", err
+// CreateValidationError transforms validator errors into a RestErr with field level details
+func CreateValidationError(err error) resterrors.RestErr {
+	fields, ok := ValidationFieldErrors(err)
+	if !ok {
+		return resterrors.NewInternalServerError("error when trying to validate request", err)
 	}
 
-	var message string
-	for _, err := range err.(validator.ValidationErrors) {
-		message = message + fmt.Sprintf(
-			"Field validation for '%s' failed on the '%s' tag\n",
-			err.Field(),
-			err.ActualTag(),
-		)
+	return resterrors.NewValidationError("request validation failed", fields)
+}
+
+// ValidationFieldErrors returns a FieldError for each failed rule of validator errors, ok is false for other errors
+func ValidationFieldErrors(err error) ([]resterrors.FieldError, bool) {
+	var vErrs validator.ValidationErrors
+	if !errors.As(err, &vErrs) {
+		return nil, false
 	}
 
-	return message, err
+	fields := []resterrors.FieldError{}
+	for _, err := range vErrs {
+		fields = append(fields, resterrors.FieldError{
+			Field: err.Field(),
+			Tag:   err.ActualTag(),
+			Param: err.Param(),
+			Message: fmt.Sprintf(
+				"Field validation for '%s' failed on the '%s' tag",
+				err.Field(),
+				err.ActualTag(),
+			),
+		})
+	}
+	return fields, true
 }
@@ -0,0 +1,224 @@
+package invoices
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateLayout = "02 Jan 2006"
+
+	lineHeight = 6.0
+	// widths of the columns of the items table, they add up to the width of the page between its margins
+	colNo       = 8.0
+	colItem     = 62.0
+	colQuantity = 12.0
+	colPrice    = 28.0
+	colTaxRate  = 16.0
+	colTax      = 24.0
+	colAmount   = 30.0
+	pageWidth   = colNo + colItem + colQuantity + colPrice + colTaxRate + colTax + colAmount
+)
+
+// PDFRenderer is an entity.InvoiceRenderer writing A4 PDF invoices with the core fonts, it needs no font files
+type PDFRenderer struct {
+	issuer   string
+	currency string
+}
+
+// NewPDFRenderer creates a PDFRenderer for invoices issued through issuer with amounts in currency
+func NewPDFRenderer(issuer, currency string) *PDFRenderer {
+	return &PDFRenderer{issuer: issuer, currency: currency}
+}
+
+// Render writes the invoice with its order. The document only depends on the invoice, it is left uncompressed and
+// dated when the invoice was issued so rendering an invoice again gives the same bytes
+func (r *PDFRenderer) Render(w io.Writer, invoice entity.Invoice) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(invoice.IssuedAt)
+	pdf.SetModificationDate(invoice.IssuedAt)
+	pdf.SetTitle(fmt.Sprintf("Invoice %s", invoice.Code()), true)
+	pdf.SetAuthor(r.issuer, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AliasNbPages("")
+	// the core fonts are encoded in cp1252, text is translated from UTF-8 before it is written
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(pageWidth/2, 5, tr(invoice.Code()), "", 0, "L", false, 0, "")
+		pdf.CellFormat(pageWidth/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	order := invoice.Order
+	r.header(pdf, tr, invoice)
+	r.parties(pdf, tr, order)
+	r.items(pdf, tr, order)
+	r.totals(pdf, tr, order)
+
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.MultiCell(pageWidth, 5, tr(fmt.Sprintf("Amounts are in %s. This invoice was issued by %s for order #%d.",
+		r.currency, r.issuer, order.ID)), "", "L", false)
+
+	return pdf.Output(w)
+}
+
+func (r *PDFRenderer) header(pdf *gofpdf.Fpdf, tr func(string) string, invoice entity.Invoice) {
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(pageWidth/2, 10, "INVOICE", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(pageWidth/2, 10, tr(r.issuer), "", 1, "R", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, row := range [][2]string{
+		{"Invoice number", invoice.Code()},
+		{"Issued", invoice.IssuedAt.Format(dateLayout)},
+		{"Order", "#" + strconv.FormatInt(invoice.Order.ID, 10)},
+		{"Order date", invoice.Order.OrderDate.Format(dateLayout)},
+	} {
+		pdf.CellFormat(35, 5, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(pageWidth-35, 5, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+}
+
+// parties writes the seller with their pickup address and the buyer with the address the order was shipped to side by side
+func (r *PDFRenderer) parties(pdf *gofpdf.Fpdf, tr func(string) string, order entity.Order) {
+	left, _, _, _ := pdf.GetMargins()
+	top := pdf.GetY()
+	bottom := top
+	for i, party := range []struct {
+		title, name, email, address string
+	}{
+		{"Sold by", order.Seller.Name, order.Seller.Email, order.Seller.PickUpAddress},
+		{"Billed to", order.Buyer.Name, order.Buyer.Email, order.DeliveryDestinationAddress},
+	} {
+		x := left + float64(i)*pageWidth/2
+		pdf.SetXY(x, top)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(pageWidth/2, 5, party.title, "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(pageWidth/2, 5, tr(party.name), "", 2, "L", false, 0, "")
+		pdf.CellFormat(pageWidth/2, 5, tr(party.email), "", 2, "L", false, 0, "")
+		pdf.SetX(x)
+		pdf.MultiCell(pageWidth/2-5, 5, tr(party.address), "", "L", false)
+		if pdf.GetY() > bottom {
+			bottom = pdf.GetY()
+		}
+	}
+	pdf.SetXY(left, bottom)
+	pdf.Ln(8)
+}
+
+// items writes a row for each line item, the header is written again on every page the table goes on
+func (r *PDFRenderer) items(pdf *gofpdf.Fpdf, tr func(string) string, order entity.Order) {
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+
+	itemsHeader(pdf)
+	pdf.SetFont("Helvetica", "", 9)
+	for i, od := range order.Items {
+		lines := pdf.SplitText(tr(od.Product.Name), colItem-2)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		height := lineHeight * float64(len(lines))
+		if pdf.GetY()+height > pageHeight-bottomMargin {
+			pdf.AddPage()
+			itemsHeader(pdf)
+			pdf.SetFont("Helvetica", "", 9)
+		}
+
+		x, y := pdf.GetXY()
+		pdf.CellFormat(colNo, height, strconv.Itoa(i+1), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(colItem, height, "", "B", 0, "L", false, 0, "")
+		pdf.CellFormat(colQuantity, height, strconv.FormatInt(od.Quantity, 10), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colPrice, height, money(od.Price), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colTaxRate, height, percent(od.TaxRate), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colTax, height, money(od.Tax), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colAmount, height, money(od.Price.Mul(decimal.NewFromInt(od.Quantity))), "B", 1, "R", false, 0, "")
+
+		// the name is written over its empty cell so it can wrap
+		for l, line := range lines {
+			pdf.SetXY(x+colNo, y+float64(l)*lineHeight)
+			pdf.CellFormat(colItem, lineHeight, line, "", 0, "L", false, 0, "")
+		}
+		pdf.SetXY(x, y+height)
+	}
+	pdf.Ln(4)
+}
+
+func itemsHeader(pdf *gofpdf.Fpdf) {
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(colNo, 7, "#", "", 0, "L", true, 0, "")
+	pdf.CellFormat(colItem, 7, "Item", "", 0, "L", true, 0, "")
+	pdf.CellFormat(colQuantity, 7, "Qty", "", 0, "R", true, 0, "")
+	pdf.CellFormat(colPrice, 7, "Unit price", "", 0, "R", true, 0, "")
+	pdf.CellFormat(colTaxRate, 7, "Tax rate", "", 0, "R", true, 0, "")
+	pdf.CellFormat(colTax, 7, "Tax", "", 0, "R", true, 0, "")
+	pdf.CellFormat(colAmount, 7, "Amount", "", 1, "R", true, 0, "")
+}
+
+// totals writes the breakdown of the total price of the order, lines the order doesn't have are left out
+func (r *PDFRenderer) totals(pdf *gofpdf.Fpdf, tr func(string) string, order entity.Order) {
+	rows := [][2]string{{"Subtotal", money(order.Subtotal())}}
+	if !order.Discount.IsZero() {
+		rows = append(rows, [2]string{fmt.Sprintf("Discount (%s)", order.VoucherCode), "-" + money(order.Discount)})
+	}
+	if order.TaxInclusive {
+		rows = append(rows, [2]string{"Tax (included in prices)", money(order.Tax)})
+	} else {
+		rows = append(rows, [2]string{"Tax", money(order.Tax)})
+	}
+	if order.ShippingService != "" {
+		rows = append(rows, [2]string{fmt.Sprintf("Shipping (%s)", order.ShippingService), money(order.ShippingFee)})
+	}
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, row := range rows {
+		pdf.CellFormat(pageWidth-colAmount-colTax, lineHeight, tr(row[0]), "", 0, "R", false, 0, "")
+		pdf.CellFormat(colAmount+colTax, lineHeight, row[1], "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(pageWidth-colAmount-colTax, 8, "Total", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(colAmount+colTax, 8, r.currency+" "+money(order.TotalPrice), "T", 1, "R", false, 0, "")
+}
+
+// money formats an amount with two decimals and its thousands separated, 1234567.5 is 1,234,567.50
+func money(amount decimal.Decimal) string {
+	s := amount.Abs().StringFixed(2)
+	whole, cents := s[:len(s)-3], s[len(s)-3:]
+
+	var b strings.Builder
+	if amount.IsNegative() {
+		b.WriteString("-")
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(",")
+		}
+		b.WriteRune(d)
+	}
+	b.WriteString(cents)
+	return b.String()
+}
+
+// percent formats a rate as a percentage, 0.11 is 11%
+func percent(rate decimal.Decimal) string {
+	return rate.Mul(decimal.NewFromInt(100)).String() + "%"
+}
@@ -0,0 +1,102 @@
+package invoices_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/invoices"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// run go test ./framework/invoices -update to write the golden files again after changing the layout
+var update = flag.Bool("update", false, "update golden files")
+
+func taxedInvoice() entity.Invoice {
+	issuedAt := time.Date(2021, 5, 3, 9, 30, 0, 0, time.UTC)
+	return entity.Invoice{
+		ID:       5,
+		OrderID:  42,
+		SellerID: 2,
+		Number:   8,
+		IssuedAt: issuedAt,
+		Order: entity.Order{
+			ID:                         42,
+			Buyer:                      entity.Buyer{ID: 1, Name: "Budi Santoso", Email: "buyer@mail.com"},
+			Seller:                     entity.Seller{ID: 2, Name: "Toko Komodo", Email: "seller@mail.com", PickUpAddress: "Jl. Sudirman No. 1, Jakarta Selatan 12190"},
+			DeliverySourceAddress:      "Jl. Sudirman No. 1, Jakarta Selatan 12190",
+			DeliveryDestinationAddress: "Jl. Asia Afrika No. 8, Bandung 40111",
+			TotalQuantity:              4,
+			VoucherCode:                "HEMAT10",
+			Discount:                   decimal.NewFromInt(10000),
+			Tax:                        decimal.NewFromInt(9900),
+			ShippingService:            "JNE_REG",
+			ShippingFee:                decimal.NewFromInt(20000),
+			TotalPrice:                 decimal.NewFromInt(119900),
+			Status:                     entity.ACCEPTED,
+			OrderDate:                  issuedAt.Add(-48 * time.Hour),
+			Items: []entity.OrderDetail{
+				{ID: 1, Product: entity.Product{Name: "Kopi Toraja 250g"}, Quantity: 3, Price: decimal.NewFromInt(20000),
+					TaxRate: decimal.RequireFromString("0.11"), Tax: decimal.NewFromInt(5940)},
+				{ID: 2, Product: entity.Product{Name: "French press with a name long enough to wrap over two lines of the table"},
+					Quantity: 1, Price: decimal.NewFromInt(40000), TaxRate: decimal.RequireFromString("0.11"), Tax: decimal.NewFromInt(3960)},
+			},
+		},
+	}
+}
+
+// inclusiveInvoice has enough items to go on a second page
+func inclusiveInvoice() entity.Invoice {
+	invoice := taxedInvoice()
+	invoice.Number = 9
+	invoice.Order.VoucherCode = ""
+	invoice.Order.Discount = decimal.Zero
+	invoice.Order.ShippingService = ""
+	invoice.Order.ShippingFee = decimal.Zero
+	invoice.Order.TaxInclusive = true
+	invoice.Order.Items = nil
+	invoice.Order.Tax = decimal.Zero
+	invoice.Order.TotalPrice = decimal.Zero
+	for i := 1; i <= 40; i++ {
+		od := entity.OrderDetail{ID: int64(i), Product: entity.Product{Name: fmt.Sprintf("Batik tulis motif %d", i)},
+			Quantity: 1, Price: decimal.NewFromInt(111000), TaxRate: decimal.RequireFromString("0.11"), Tax: decimal.NewFromInt(11000)}
+		invoice.Order.Items = append(invoice.Order.Items, od)
+		invoice.Order.Tax = invoice.Order.Tax.Add(od.Tax)
+		invoice.Order.TotalPrice = invoice.Order.TotalPrice.Add(od.Price)
+	}
+	return invoice
+}
+
+func TestPDFRenderer(t *testing.T) {
+	renderer := invoices.NewPDFRenderer("Komodo", "IDR")
+
+	for name, invoice := range map[string]entity.Invoice{
+		"exclusive_tax": taxedInvoice(),
+		"inclusive_tax": inclusiveInvoice(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			assert.NoError(t, renderer.Render(buf, invoice))
+			assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+			golden := filepath.Join("testdata", name+".golden.pdf")
+			if *update {
+				assert.NoError(t, ioutil.WriteFile(golden, buf.Bytes(), 0644))
+			}
+			want, err := ioutil.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(want, buf.Bytes()), "%s differs from the rendered invoice", golden)
+
+			// rendering the same invoice again gives the same document
+			again := new(bytes.Buffer)
+			assert.NoError(t, renderer.Render(again, invoice))
+			assert.True(t, bytes.Equal(buf.Bytes(), again.Bytes()))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package middlerwares
+
+import "net"
+
+// clientGone can't tell a closed connection on this platform, requests run until they are done or time out
+func clientGone(conn net.Conn) bool {
+	return false
+}
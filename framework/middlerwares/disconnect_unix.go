@@ -0,0 +1,40 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package middlerwares
+
+import (
+	"net"
+	"syscall"
+)
+
+// clientGone tells whether the peer of conn closed it, by peeking at the socket without taking anything from it,
+// so a request pipelined after the current one is still read by the server. Connections that don't expose their
+// socket, like TLS ones, are never reported gone
+func clientGone(conn net.Conn) bool {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	gone := false
+	var buf [1]byte
+	err = raw.Read(func(fd uintptr) bool {
+		n, _, rErr := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch {
+		case rErr == syscall.EAGAIN || rErr == syscall.EWOULDBLOCK || rErr == syscall.EINTR:
+			// nothing was sent, the client is still waiting
+		case rErr != nil:
+			gone = true
+		case n == 0:
+			// an orderly shutdown, the client won't read the response
+			gone = true
+		}
+		return true
+	})
+	return err == nil && gone
+}
@@ -0,0 +1,85 @@
+package middlerwares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyCleanupTimeout = 5 * time.Second
+)
+
+// NewIdempotency returns a middleware that makes a request sent with an Idempotency-Key header run only once per buyer.
+// Retries with the same key and body get the saved response replayed until the key expires, the same key with another
+// body is rejected with 422 and a retry made while the first request still runs with 409. Responses with a 5xx status
+// are not saved so the request can be retried. It must run after ValidateRequest
+func NewIdempotency(idempotency entity.IdempotencyUseCase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		idemKey := c.Get(HeaderIdempotencyKey)
+		if idemKey == "" {
+			return c.Next()
+		}
+		if len(idemKey) > maxIdempotencyKeyLength {
+			return helpers.ErrorResponse(c, resterrors.NewBadRequestError("Idempotency-Key must not be longer than 255 characters"))
+		}
+
+		user, rErr := helpers.LoggedInUser(c)
+		if rErr != nil {
+			return helpers.ErrorResponse(c, rErr)
+		}
+
+		key, replay, rErr := idempotency.Reserve(c.UserContext(), user.ID, idemKey, requestHash(c))
+		if rErr != nil {
+			return helpers.ErrorResponse(c, rErr)
+		}
+		if replay {
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, key.ResponseContentType)
+			return c.Status(key.ResponseStatus).Send(key.ResponseBody)
+		}
+
+		handlerErr := c.Next()
+
+		// the request context may be done already, the key must still be completed or released
+		ctx, cancel := context.WithTimeout(context.Background(), idempotencyCleanupTimeout)
+		defer cancel()
+
+		status := c.Response().StatusCode()
+		if handlerErr != nil || status >= fiber.StatusInternalServerError {
+			if rErr := idempotency.Release(ctx, &key); rErr != nil {
+				log.Println("idempotency key delete error", key.Key, rErr)
+			}
+			return handlerErr
+		}
+
+		key.ResponseStatus = status
+		key.ResponseContentType = string(c.Response().Header.ContentType())
+		key.ResponseBody = append([]byte(nil), c.Response().Body()...)
+		if rErr := idempotency.Complete(ctx, &key); rErr != nil {
+			log.Println("idempotency key complete error", key.Key, rErr)
+		}
+		return nil
+	}
+}
+
+// requestHash identifies a request by its method, path and body
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte(" "))
+	h.Write([]byte(c.Path()))
+	h.Write([]byte("\n"))
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,157 @@
+package middlerwares_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+	"github.com/stretchr/testify/mock"
+)
+
+const orderBody = `{"sellerId":1,"items":[{"productId":1,"quantity":2}]}`
+
+// idempotentApp serves POST /orders behind the idempotency middleware, handler counts its calls
+func (suite *TestSuite) idempotentApp(idempotency entity.IdempotencyUseCase, status int, calls *int) {
+	suite.app.Post("/orders",
+		func(c *fiber.Ctx) error {
+			c.Context().SetUserValue("tokenClaims", jwt.MapClaims{"id": float64(7), "type": float64(helpers.BUYER_TYPE)})
+			return c.Next()
+		},
+		middlerwares.NewIdempotency(idempotency),
+		func(c *fiber.Ctx) error {
+			*calls++
+			return c.Status(status).JSON(helpers.SuccessResponse{Data: struct {
+				ID int64 `json:"id"`
+			}{ID: 1}})
+		},
+	)
+}
+
+func newOrderRequest(idemKey, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if idemKey != "" {
+		req.Header.Set(middlerwares.HeaderIdempotencyKey, idemKey)
+	}
+	return req
+}
+
+func (suite *TestSuite) TestIdempotencyFirstRequest() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.AnythingOfType("string")).
+		Return(entity.IdempotencyKey{Key: "key-1", BuyerID: 7}, false, nil).Once()
+	idempotency.On("Complete", mock.Anything, mock.MatchedBy(func(k *entity.IdempotencyKey) bool {
+		return k.Key == "key-1" && k.ResponseStatus == http.StatusCreated && string(k.ResponseBody) == `{"data":{"id":1}}` &&
+			k.ResponseContentType == fiber.MIMEApplicationJSON
+	})).Return(nil).Once()
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("key-1", orderBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal(1, calls)
+	idempotency.AssertExpectations(suite.T())
+}
+
+func (suite *TestSuite) TestIdempotencySameRequestHash() {
+	hashes := []string{}
+	idempotency := new(mocks.IdempotencyUseCase)
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.MatchedBy(func(hash string) bool {
+		hashes = append(hashes, hash)
+		return true
+	})).Return(entity.IdempotencyKey{}, false, resterrors.NewConflictError("a request with the same Idempotency-Key is in progress"))
+	calls := 0
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	for _, body := range []string{orderBody, orderBody, `{"sellerId":2}`} {
+		_, err := suite.app.Test(newOrderRequest("key-1", body))
+		suite.NoError(err)
+	}
+	// a request is identified by its body
+	suite.Len(hashes, 3)
+	suite.Equal(hashes[0], hashes[1])
+	suite.NotEqual(hashes[0], hashes[2])
+}
+
+func (suite *TestSuite) TestIdempotencyReplay() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	// the key was completed by a request with the same body
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.AnythingOfType("string")).
+		Return(entity.IdempotencyKey{Key: "key-1", BuyerID: 7, ResponseStatus: http.StatusCreated,
+			ResponseContentType: fiber.MIMEApplicationJSON, ResponseBody: []byte(`{"data":{"id":1}}`)}, true, nil).Once()
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("key-1", orderBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal("true", resp.Header.Get(middlerwares.HeaderIdempotentReplayed))
+	suite.Equal(fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	body, err := ioutil.ReadAll(resp.Body)
+	suite.NoError(err)
+	suite.Equal(`{"data":{"id":1}}`, string(body))
+	suite.Equal(0, calls)
+	idempotency.AssertNotCalled(suite.T(), "Complete", mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestIdempotencyKeyRejected() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.AnythingOfType("string")).
+		Return(entity.IdempotencyKey{}, false, resterrors.NewUnprocessableEntityError("Idempotency-Key was already used with another request", nil)).Once()
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("key-1", `{"sellerId":2}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	suite.Equal(0, calls)
+}
+
+func (suite *TestSuite) TestIdempotencyServerErrorReleasesKey() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.AnythingOfType("string")).
+		Return(entity.IdempotencyKey{Key: "key-1", BuyerID: 7}, false, nil).Once()
+	idempotency.On("Release", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).Return(nil).Once()
+	suite.idempotentApp(idempotency, http.StatusInternalServerError, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("key-1", orderBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+	idempotency.AssertExpectations(suite.T())
+	idempotency.AssertNotCalled(suite.T(), "Complete", mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestIdempotencyWithoutKey() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("", orderBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal(1, calls)
+	idempotency.AssertNotCalled(suite.T(), "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TestSuite) TestIdempotencyReserveError() {
+	calls := 0
+	idempotency := new(mocks.IdempotencyUseCase)
+	idempotency.On("Reserve", mock.Anything, int64(7), "key-1", mock.AnythingOfType("string")).
+		Return(entity.IdempotencyKey{}, false, resterrors.NewInternalServerError("error when trying to save data", nil)).Once()
+	suite.idempotentApp(idempotency, http.StatusCreated, &calls)
+
+	resp, err := suite.app.Test(newOrderRequest("key-1", orderBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+	suite.Equal(0, calls)
+}
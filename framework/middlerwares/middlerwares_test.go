@@ -174,3 +174,20 @@ func (suite *TestSuite) TestValidateRequestAndSellerTypeCheckerError() {
 	suite.NoError(err)
 	suite.Equal(fiber.StatusUnauthorized, resp.StatusCode)
 }
+
+func (suite *TestSuite) TestTimeout() {
+	suite.app.Get("/test",
+		middlerwares.Timeout,
+		func(c *fiber.Ctx) error {
+			_, ok := c.UserContext().Deadline()
+
+			// request context has a deadline, set by middlerwares.Timeout
+			suite.Equal(true, ok)
+			return nil
+		},
+	)
+
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "http://example.com/test", nil))
+	suite.NoError(err)
+	suite.Equal(fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,52 @@
+package middlerwares
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/config"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+)
+
+const (
+	defaultRequestTimeout = 10 * time.Second
+	// disconnectCheckInterval is how often the connection of a running request is checked for a client that went away
+	disconnectCheckInterval = 200 * time.Millisecond
+)
+
+var requestTimeout = helpers.ParseDuration(config.REQUEST_TIMEOUT, defaultRequestTimeout)
+
+// Timeout puts a deadline on the request context passed to usecases and repositories,
+// queries still running when it fires, when the server shuts down or when the client disconnects are cancelled
+func Timeout(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	// fasthttp doesn't tell a handler its client went away, the connection is watched while the request runs
+	done := make(chan struct{})
+	defer close(done)
+	go watchConnection(c.Context().Conn(), cancel, done)
+
+	c.SetUserContext(ctx)
+	return c.Next()
+}
+
+// watchConnection calls cancel once the client of conn disconnected, until done is closed
+func watchConnection(conn net.Conn, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(disconnectCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if clientGone(conn) {
+				cancel()
+				return
+			}
+		}
+	}
+}
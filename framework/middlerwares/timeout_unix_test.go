@@ -0,0 +1,51 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package middlerwares_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+func (suite *TestSuite) TestTimeoutClientDisconnect() {
+	cancelled := make(chan error, 1)
+	suite.app.Get("/test",
+		middlerwares.Timeout,
+		func(c *fiber.Ctx) error {
+			// stands in for a slow query, it stops once the request context is done
+			select {
+			case <-c.UserContext().Done():
+				cancelled <- c.UserContext().Err()
+			case <-time.After(5 * time.Second):
+				cancelled <- nil
+			}
+			return nil
+		},
+	)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.NoError(err)
+	go suite.app.Listener(ln)
+	defer suite.app.Shutdown()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	suite.NoError(err)
+	_, err = fmt.Fprintf(conn, "GET /test HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	suite.NoError(err)
+	// the client gives up before the response is sent
+	time.Sleep(100 * time.Millisecond)
+	suite.NoError(conn.Close())
+
+	select {
+	case err := <-cancelled:
+		suite.Equal(context.Canceled, err)
+	case <-time.After(10 * time.Second):
+		suite.Fail("the handler didn't return")
+	}
+}
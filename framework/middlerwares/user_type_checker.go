@@ -30,7 +30,7 @@ func userTypeChecker(c *fiber.Ctx, as helpers.UserTypeEnum) resterrors.RestErr {
 func BuyerTypeChecker(c *fiber.Ctx) error {
 	err := userTypeChecker(c, helpers.BUYER_TYPE)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
 	}
 
 	return c.Next()
@@ -39,7 +39,16 @@ func BuyerTypeChecker(c *fiber.Ctx) error {
 func SellerTypeChecker(c *fiber.Ctx) error {
 	err := userTypeChecker(c, helpers.SELLER_TYPE)
 	if err != nil {
-		return c.Status(err.Status()).JSON(err.ErrorResponse())
+		return helpers.ErrorResponse(c, err)
+	}
+
+	return c.Next()
+}
+
+func AdminTypeChecker(c *fiber.Ctx) error {
+	err := userTypeChecker(c, helpers.ADMIN_TYPE)
+	if err != nil {
+		return helpers.ErrorResponse(c, err)
 	}
 
 	return c.Next()
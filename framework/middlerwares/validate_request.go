@@ -13,14 +13,14 @@ func ValidateRequest(c *fiber.Ctx) error {
 	token, err := helpers.JwtFromHeader(auth, "Bearer")
 	if err != nil {
 		rErr := resterrors.NewUnauthorizedError(err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	// Validate token
 	tokenClaims, err := helpers.ValidateToken(token, []byte(config.JWT_SECRET))
 	if err != nil {
 		rErr := resterrors.NewUnauthorizedError(err.Error())
-		return c.Status(rErr.Status()).JSON(rErr.ErrorResponse())
+		return helpers.ErrorResponse(c, rErr)
 	}
 
 	c.Context().SetUserValue("tokenClaims", tokenClaims)
@@ -0,0 +1,120 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const FakeGatewayName = "fake"
+
+// FakeGateway is an in memory entity.PaymentGateway for tests and local development, nothing is charged.
+// Its webhooks are signed and use the same body as the HTTP gateway
+type FakeGateway struct {
+	mu            sync.Mutex
+	autoAuthorize bool
+	webhookSecret string
+	seq           int
+	intents       map[string]entity.PaymentStatusEnum
+}
+
+// NewFakeGateway creates a FakeGateway, with autoAuthorize payments are authorized as soon as they are created
+// as if the buyer paid right away, otherwise they stay pending until Authorize is called.
+// Webhooks must be signed with webhookSecret
+func NewFakeGateway(autoAuthorize bool, webhookSecret string) *FakeGateway {
+	return &FakeGateway{
+		autoAuthorize: autoAuthorize,
+		webhookSecret: webhookSecret,
+		intents:       map[string]entity.PaymentStatusEnum{},
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeGatewayName
+}
+
+func (g *FakeGateway) CreateIntent(ctx context.Context, payment *entity.Payment) (entity.PaymentIntent, resterrors.RestErr) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.seq++
+	id := fmt.Sprintf("fake_pi_%d", g.seq)
+	status := entity.PAYMENT_PENDING
+	if g.autoAuthorize {
+		status = entity.PAYMENT_AUTHORIZED
+	}
+	g.intents[id] = status
+
+	return entity.PaymentIntent{
+		ProviderPaymentID: id,
+		ClientSecret:      id + "_secret",
+		Status:            status,
+	}, nil
+}
+
+// Authorize marks a pending payment as paid by the buyer
+func (g *FakeGateway) Authorize(providerPaymentID string) resterrors.RestErr {
+	return g.transition(providerPaymentID, entity.PAYMENT_PENDING, entity.PAYMENT_AUTHORIZED)
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, payment *entity.Payment) resterrors.RestErr {
+	return g.transition(payment.ProviderPaymentID, entity.PAYMENT_AUTHORIZED, entity.PAYMENT_CAPTURED)
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, payment *entity.Payment, amount decimal.Decimal) (entity.PaymentRefund, resterrors.RestErr) {
+	g.mu.Lock()
+	status, ok := g.intents[payment.ProviderPaymentID]
+	g.mu.Unlock()
+	if !ok {
+		return entity.PaymentRefund{}, resterrors.NewBadGatewayError(fmt.Sprintf("payment %s not found", payment.ProviderPaymentID), nil)
+	}
+
+	to := entity.PAYMENT_REFUNDED
+	if status == entity.PAYMENT_AUTHORIZED {
+		to = entity.PAYMENT_CANCELLED
+	}
+	if err := g.transition(payment.ProviderPaymentID, status, to); err != nil {
+		return entity.PaymentRefund{}, err
+	}
+
+	return entity.PaymentRefund{
+		ProviderRefundID: "fake_re_" + payment.ProviderPaymentID,
+		Amount:           amount,
+	}, nil
+}
+
+func (g *FakeGateway) ParseWebhook(ctx context.Context, header http.Header, body []byte) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	if err := verifyWebhook(g.webhookSecret, header, body, time.Now()); err != nil {
+		return entity.PaymentWebhookEvent{}, err
+	}
+	return parseWebhookEvent(body)
+}
+
+// Status returns the status of a payment at the gateway
+func (g *FakeGateway) Status(providerPaymentID string) (entity.PaymentStatusEnum, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status, ok := g.intents[providerPaymentID]
+	return status, ok
+}
+
+func (g *FakeGateway) transition(providerPaymentID string, from entity.PaymentStatusEnum, to entity.PaymentStatusEnum) resterrors.RestErr {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status, ok := g.intents[providerPaymentID]
+	if !ok {
+		return resterrors.NewBadGatewayError(fmt.Sprintf("payment %s not found", providerPaymentID), nil)
+	}
+	if status != from {
+		return resterrors.NewBadGatewayError(fmt.Sprintf("payment %s can't be changed from status %d", providerPaymentID, status), nil)
+	}
+	g.intents[providerPaymentID] = to
+	return nil
+}
@@ -0,0 +1,57 @@
+package payments_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/payments"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		gateway := payments.NewFakeGateway(false, "whsec")
+		intent, gErr := gateway.CreateIntent(ctx, &entity.Payment{Amount: decimal.NewFromInt(100)})
+		assert.Nil(t, gErr)
+		assert.Equal(t, entity.PAYMENT_PENDING, intent.Status)
+
+		payment := entity.Payment{ProviderPaymentID: intent.ProviderPaymentID}
+		assert.NotNil(t, gateway.Capture(ctx, &payment))
+
+		assert.Nil(t, gateway.Authorize(intent.ProviderPaymentID))
+		assert.Nil(t, gateway.Capture(ctx, &payment))
+		status, _ := gateway.Status(intent.ProviderPaymentID)
+		assert.Equal(t, entity.PAYMENT_CAPTURED, status)
+
+		_, gErr = gateway.Refund(ctx, &payment, decimal.NewFromInt(100))
+		assert.Nil(t, gErr)
+		status, _ = gateway.Status(intent.ProviderPaymentID)
+		assert.Equal(t, entity.PAYMENT_REFUNDED, status)
+	})
+
+	t.Run("success-auto-authorize", func(t *testing.T) {
+		gateway := payments.NewFakeGateway(true, "whsec")
+		intent, gErr := gateway.CreateIntent(ctx, &entity.Payment{Amount: decimal.NewFromInt(100)})
+		assert.Nil(t, gErr)
+		assert.Equal(t, entity.PAYMENT_AUTHORIZED, intent.Status)
+
+		// an authorized payment is released
+		_, gErr = gateway.Refund(ctx, &entity.Payment{ProviderPaymentID: intent.ProviderPaymentID}, decimal.NewFromInt(100))
+		assert.Nil(t, gErr)
+		status, _ := gateway.Status(intent.ProviderPaymentID)
+		assert.Equal(t, entity.PAYMENT_CANCELLED, status)
+	})
+
+	t.Run("error-unknown-payment", func(t *testing.T) {
+		gateway := payments.NewFakeGateway(true, "whsec")
+		gErr := gateway.Capture(ctx, &entity.Payment{ProviderPaymentID: "pi_unknown"})
+
+		assert.NotNil(t, gErr)
+		assert.Equal(t, http.StatusBadGateway, gErr.Status())
+	})
+}
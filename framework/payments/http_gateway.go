@@ -0,0 +1,177 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+type httpGateway struct {
+	name          string
+	baseURL       string
+	apiKey        string
+	webhookSecret string
+	client        *http.Client
+}
+
+type intentRequest struct {
+	Amount    string `json:"amount"`
+	Currency  string `json:"currency"`
+	Reference string `json:"reference"`
+}
+
+type intentResponse struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+}
+
+type refundRequest struct {
+	PaymentIntent string `json:"payment_intent"`
+	Amount        string `json:"amount"`
+}
+
+type refundResponse struct {
+	ID     string          `json:"id"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewHTTPGateway will create a object with entity.PaymentGateway interface representation that calls the
+// JSON API of a payment provider at baseURL with apiKey, client defaults to one with a 10 seconds timeout.
+// Webhooks of the provider are signed with webhookSecret
+func NewHTTPGateway(name string, baseURL string, apiKey string, webhookSecret string, client *http.Client) entity.PaymentGateway {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return &httpGateway{
+		name:          name,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		apiKey:        apiKey,
+		webhookSecret: webhookSecret,
+		client:        client,
+	}
+}
+
+func (g *httpGateway) Name() string {
+	return g.name
+}
+
+func (g *httpGateway) CreateIntent(ctx context.Context, payment *entity.Payment) (entity.PaymentIntent, resterrors.RestErr) {
+	req := intentRequest{
+		Amount:    payment.Amount.StringFixed(2),
+		Currency:  payment.Currency,
+		Reference: fmt.Sprintf("order_%d", payment.OrderID),
+	}
+
+	var res intentResponse
+	if err := g.post(ctx, "/payment_intents", req, &res); err != nil {
+		return entity.PaymentIntent{}, err
+	}
+
+	status, err := toPaymentStatus(res.Status)
+	if err != nil {
+		return entity.PaymentIntent{}, resterrors.NewBadGatewayError("payment gateway error", err)
+	}
+	return entity.PaymentIntent{
+		ProviderPaymentID: res.ID,
+		ClientSecret:      res.ClientSecret,
+		Status:            status,
+	}, nil
+}
+
+func (g *httpGateway) Capture(ctx context.Context, payment *entity.Payment) resterrors.RestErr {
+	var res intentResponse
+	return g.post(ctx, fmt.Sprintf("/payment_intents/%s/capture", payment.ProviderPaymentID), nil, &res)
+}
+
+func (g *httpGateway) Refund(ctx context.Context, payment *entity.Payment, amount decimal.Decimal) (entity.PaymentRefund, resterrors.RestErr) {
+	// money of an authorized payment was never taken, the intent is cancelled to release it
+	if payment.Status == entity.PAYMENT_AUTHORIZED {
+		var res intentResponse
+		if err := g.post(ctx, fmt.Sprintf("/payment_intents/%s/cancel", payment.ProviderPaymentID), nil, &res); err != nil {
+			return entity.PaymentRefund{}, err
+		}
+		return entity.PaymentRefund{Amount: amount}, nil
+	}
+
+	req := refundRequest{
+		PaymentIntent: payment.ProviderPaymentID,
+		Amount:        amount.StringFixed(2),
+	}
+	var res refundResponse
+	if err := g.post(ctx, "/refunds", req, &res); err != nil {
+		return entity.PaymentRefund{}, err
+	}
+	return entity.PaymentRefund{
+		ProviderRefundID: res.ID,
+		Amount:           res.Amount,
+	}, nil
+}
+
+func (g *httpGateway) ParseWebhook(ctx context.Context, header http.Header, body []byte) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	if err := verifyWebhook(g.webhookSecret, header, body, time.Now()); err != nil {
+		return entity.PaymentWebhookEvent{}, err
+	}
+	return parseWebhookEvent(body)
+}
+
+// post sends body as JSON to path and decodes the response into res, any response other than 2xx is an error
+func (g *httpGateway) post(ctx context.Context, path string, body interface{}, res interface{}) resterrors.RestErr {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return resterrors.NewInternalServerError("error when trying to call payment gateway", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to call payment gateway", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return resterrors.NewBadGatewayError("error when trying to call payment gateway", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resterrors.NewBadGatewayError("error when trying to call payment gateway", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errRes errorResponse
+		message := fmt.Sprintf("payment gateway responded with status %d", resp.StatusCode)
+		if json.Unmarshal(respBody, &errRes) == nil && errRes.Error.Message != "" {
+			message = "payment gateway error: " + errRes.Error.Message
+		}
+		return resterrors.NewBadGatewayError(message, nil)
+	}
+
+	if err := json.Unmarshal(respBody, res); err != nil {
+		return resterrors.NewBadGatewayError("invalid payment gateway response", err)
+	}
+	return nil
+}
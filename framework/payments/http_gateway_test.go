@@ -0,0 +1,211 @@
+package payments_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/payments"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// gatewayStandIn serves handler as the payment provider API and checks every request is authenticated
+func gatewayStandIn(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		handler(w, r)
+	}))
+}
+
+func TestHTTPGatewayCreateIntent(t *testing.T) {
+	payment := entity.Payment{OrderID: 12, Amount: decimal.NewFromFloat(150000), Currency: "IDR"}
+
+	t.Run("success", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/payment_intents", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"amount":"150000.00","currency":"IDR","reference":"order_12"}`, string(body))
+			w.Write([]byte(`{"id":"pi_1","client_secret":"pi_1_secret","status":"requires_payment_method"}`))
+		})
+		defer server.Close()
+
+		gateway := payments.NewHTTPGateway("acme", server.URL+"/", "sk_test", "whsec", nil)
+		intent, gErr := gateway.CreateIntent(context.Background(), &payment)
+
+		assert.Nil(t, gErr)
+		assert.Equal(t, entity.PaymentIntent{
+			ProviderPaymentID: "pi_1",
+			ClientSecret:      "pi_1_secret",
+			Status:            entity.PAYMENT_PENDING,
+		}, intent)
+		assert.Equal(t, "acme", gateway.Name())
+	})
+
+	t.Run("error-response", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"currency not supported"}}`))
+		})
+		defer server.Close()
+
+		gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+		_, gErr := gateway.CreateIntent(context.Background(), &payment)
+
+		assert.NotNil(t, gErr)
+		assert.Equal(t, http.StatusBadGateway, gErr.Status())
+		assert.Equal(t, "payment gateway error: currency not supported", gErr.Message())
+	})
+
+	t.Run("error-unknown-status", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id":"pi_1","status":"on_hold"}`))
+		})
+		defer server.Close()
+
+		gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+		_, gErr := gateway.CreateIntent(context.Background(), &payment)
+
+		assert.NotNil(t, gErr)
+		assert.Equal(t, http.StatusBadGateway, gErr.Status())
+	})
+
+	t.Run("error-timeout", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		})
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+		_, gErr := gateway.CreateIntent(ctx, &payment)
+
+		assert.NotNil(t, gErr)
+		assert.ErrorIs(t, gErr, resterrors.ErrTimeout)
+	})
+}
+
+func TestHTTPGatewayCapture(t *testing.T) {
+	server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/payment_intents/pi_1/capture", r.URL.Path)
+		w.Write([]byte(`{"id":"pi_1","status":"succeeded"}`))
+	})
+	defer server.Close()
+
+	gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+	gErr := gateway.Capture(context.Background(), &entity.Payment{ProviderPaymentID: "pi_1"})
+
+	assert.Nil(t, gErr)
+}
+
+func TestHTTPGatewayRefund(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/refunds", r.URL.Path)
+			var req map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, map[string]string{"payment_intent": "pi_1", "amount": "5000.50"}, req)
+			w.Write([]byte(`{"id":"re_1","amount":"5000.50"}`))
+		})
+		defer server.Close()
+
+		gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+		refund, gErr := gateway.Refund(context.Background(),
+			&entity.Payment{ProviderPaymentID: "pi_1", Status: entity.PAYMENT_CAPTURED}, decimal.RequireFromString("5000.50"))
+
+		assert.Nil(t, gErr)
+		assert.Equal(t, "re_1", refund.ProviderRefundID)
+		assert.Equal(t, "5000.5", refund.Amount.String())
+	})
+
+	t.Run("success-authorized-is-cancelled", func(t *testing.T) {
+		server := gatewayStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/payment_intents/pi_1/cancel", r.URL.Path)
+			w.Write([]byte(`{"id":"pi_1","status":"canceled"}`))
+		})
+		defer server.Close()
+
+		gateway := payments.NewHTTPGateway("acme", server.URL, "sk_test", "whsec", nil)
+		_, gErr := gateway.Refund(context.Background(),
+			&entity.Payment{ProviderPaymentID: "pi_1", Status: entity.PAYMENT_AUTHORIZED}, decimal.NewFromInt(100))
+
+		assert.Nil(t, gErr)
+	})
+}
+
+// signedHeader is the header of a callback signed with secret at timestamp
+func signedHeader(secret string, timestamp time.Time, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(payments.HeaderWebhookSignature, payments.SignWebhook(secret, timestamp, body))
+	return header
+}
+
+func TestHTTPGatewayParseWebhook(t *testing.T) {
+	gateway := payments.NewHTTPGateway("acme", "http://localhost", "sk_test", "whsec", nil)
+	body := []byte(`{"id":"evt_1","type":"payment_intent.requires_capture","data":{"id":"pi_1","status":"requires_capture","amount":"150000.00","currency":"IDR"}}`)
+
+	t.Run("success", func(t *testing.T) {
+		event, gErr := gateway.ParseWebhook(context.Background(), signedHeader("whsec", time.Now(), body), body)
+
+		assert.Nil(t, gErr)
+		assert.Equal(t, "evt_1", event.ProviderEventID)
+		assert.Equal(t, "payment_intent.requires_capture", event.Type)
+		assert.Equal(t, "pi_1", event.ProviderPaymentID)
+		assert.Equal(t, entity.PAYMENT_AUTHORIZED, event.Status)
+		assert.True(t, decimal.NewFromInt(150000).Equal(event.Amount))
+		assert.Equal(t, "IDR", event.Currency)
+		assert.Equal(t, body, event.Payload)
+	})
+
+	t.Run("success-rotated-secret", func(t *testing.T) {
+		header := http.Header{}
+		now := time.Now()
+		header.Set(payments.HeaderWebhookSignature,
+			payments.SignWebhook("whsec", now, body)+","+strings.Split(payments.SignWebhook("old", now, body), ",")[1])
+
+		_, gErr := gateway.ParseWebhook(context.Background(), header, body)
+
+		assert.Nil(t, gErr)
+	})
+
+	t.Run("error-signature", func(t *testing.T) {
+		for name, header := range map[string]http.Header{
+			"missing":       {},
+			"malformed":     {payments.HeaderWebhookSignature: []string{"v1=abc"}},
+			"wrong secret":  signedHeader("other", time.Now(), body),
+			"too old":       signedHeader("whsec", time.Now().Add(-10*time.Minute), body),
+			"in the future": signedHeader("whsec", time.Now().Add(10*time.Minute), body),
+			"other body":    signedHeader("whsec", time.Now(), []byte(`{}`)),
+		} {
+			_, gErr := gateway.ParseWebhook(context.Background(), header, body)
+
+			assert.NotNil(t, gErr, name)
+			assert.Equal(t, http.StatusUnauthorized, gErr.Status(), name)
+		}
+	})
+
+	t.Run("error-no-secret", func(t *testing.T) {
+		gateway := payments.NewHTTPGateway("acme", "http://localhost", "sk_test", "", nil)
+		_, gErr := gateway.ParseWebhook(context.Background(), signedHeader("", time.Now(), body), body)
+
+		assert.NotNil(t, gErr)
+		assert.Equal(t, http.StatusUnauthorized, gErr.Status())
+	})
+
+	t.Run("error-invalid-body", func(t *testing.T) {
+		body := []byte(`{"id":"evt_1"}`)
+		_, gErr := gateway.ParseWebhook(context.Background(), signedHeader("whsec", time.Now(), body), body)
+
+		assert.NotNil(t, gErr)
+		assert.Equal(t, http.StatusBadRequest, gErr.Status())
+	})
+}
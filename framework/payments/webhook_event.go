@@ -0,0 +1,79 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// intent statuses of the gateway API
+const (
+	intentRequiresPaymentMethod = "requires_payment_method"
+	intentRequiresAction        = "requires_action"
+	intentProcessing            = "processing"
+	intentRequiresCapture       = "requires_capture"
+	intentSucceeded             = "succeeded"
+	intentCanceled              = "canceled"
+	intentFailed                = "failed"
+	intentRefunded              = "refunded"
+)
+
+// webhookEvent is the body of a gateway callback, like
+// {"id": "evt_1", "type": "payment_intent.requires_capture", "data": {"id": "pi_1", "status": "requires_capture", "amount": "150000.00", "currency": "IDR"}}
+type webhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		ID       string          `json:"id"`
+		Status   string          `json:"status"`
+		Amount   decimal.Decimal `json:"amount"`
+		Currency string          `json:"currency"`
+	} `json:"data"`
+}
+
+func parseWebhookEvent(body []byte) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return entity.PaymentWebhookEvent{}, resterrors.NewBadRequestError("invalid webhook body")
+	}
+	if event.ID == "" || event.Data.ID == "" {
+		return entity.PaymentWebhookEvent{}, resterrors.NewBadRequestError("webhook event id and payment id are required")
+	}
+
+	status, err := toPaymentStatus(event.Data.Status)
+	if err != nil {
+		return entity.PaymentWebhookEvent{}, resterrors.NewBadRequestError(err.Error())
+	}
+
+	return entity.PaymentWebhookEvent{
+		ProviderEventID:   event.ID,
+		Payload:           body,
+		Type:              event.Type,
+		ProviderPaymentID: event.Data.ID,
+		Status:            status,
+		Amount:            event.Data.Amount,
+		Currency:          event.Data.Currency,
+	}, nil
+}
+
+// toPaymentStatus maps an intent status of the gateway API to a payment status
+func toPaymentStatus(status string) (entity.PaymentStatusEnum, error) {
+	switch status {
+	case intentRequiresPaymentMethod, intentRequiresAction, intentProcessing:
+		return entity.PAYMENT_PENDING, nil
+	case intentRequiresCapture:
+		return entity.PAYMENT_AUTHORIZED, nil
+	case intentSucceeded:
+		return entity.PAYMENT_CAPTURED, nil
+	case intentCanceled:
+		return entity.PAYMENT_CANCELLED, nil
+	case intentFailed:
+		return entity.PAYMENT_FAILED, nil
+	case intentRefunded:
+		return entity.PAYMENT_REFUNDED, nil
+	}
+	return 0, fmt.Errorf("unknown payment status %q", status)
+}
@@ -0,0 +1,81 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const (
+	// HeaderWebhookSignature signs a gateway callback, like "t=1620000000,v1=5257a869..."
+	HeaderWebhookSignature = "Webhook-Signature"
+	// a signature older or newer than this is refused so a captured callback can't be sent again later
+	webhookTolerance = 5 * time.Minute
+)
+
+// SignWebhook returns the HeaderWebhookSignature value of body sent at timestamp, it is the hex HMAC-SHA256
+// with secret of the unix timestamp, a dot and body
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(webhookMAC(secret, t, body)))
+}
+
+// verifyWebhook checks the HeaderWebhookSignature of a callback was made with secret within webhookTolerance of now
+func verifyWebhook(secret string, header http.Header, body []byte, now time.Time) resterrors.RestErr {
+	if secret == "" {
+		return resterrors.NewUnauthorizedError("webhook secret is not configured")
+	}
+
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header.Get(HeaderWebhookSignature), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			// several signatures are sent while the secret is rotated
+			if sig, err := hex.DecodeString(kv[1]); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if t == "" || len(signatures) == 0 {
+		return resterrors.NewUnauthorizedError("missing or malformed webhook signature")
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return resterrors.NewUnauthorizedError("missing or malformed webhook signature")
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return resterrors.NewUnauthorizedError("webhook timestamp is outside the tolerance")
+	}
+
+	expected := webhookMAC(secret, t, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return resterrors.NewUnauthorizedError("invalid webhook signature")
+}
+
+func webhookMAC(secret string, t string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,41 @@
+package adminrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const (
+	queryFindByEmail = "SELECT id, email, name, password FROM admins WHERE email=?;"
+)
+
+type mysqlAdminRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlAdminRepository will create a object with entity.AdminRepository interface representation
+func NewMysqlAdminRepository(Conn *sql.DB) entity.AdminRepository {
+	return &mysqlAdminRepository{Conn: Conn}
+}
+
+func (m *mysqlAdminRepository) GetByEmail(ctx context.Context, admin *entity.Admin) (entity.Admin, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryFindByEmail)
+	if err != nil {
+		return *admin, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer stmt.Close()
+
+	dbRes := stmt.QueryRowContext(ctx, admin.Email)
+	if err := dbRes.Scan(&admin.ID, &admin.Email, &admin.Name, &admin.Password); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *admin, resterrors.NewNotFoundError(fmt.Sprintf("admin with email %s not found", admin.Email))
+		}
+		return *admin, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return *admin, nil
+}
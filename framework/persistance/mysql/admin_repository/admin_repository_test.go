@@ -0,0 +1,51 @@
+package adminrepo_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	adminrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/admin_repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const queryFindByEmail = "SELECT id, email, name, password FROM admins WHERE email=?;"
+
+func TestGetByEmail(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "email", "name", "password"}).
+			AddRow(1, "admin@mail.com", "john admin", "$2a$10$e2kYEecnGMVYJmlbINDWfeLgu5Y9Q5.EJAVjHtUPOahCQkYbpWUyW")
+		mock.ExpectPrepare(regexp.QuoteMeta(queryFindByEmail)).ExpectQuery().WithArgs("admin@mail.com").WillReturnRows(rows)
+
+		repo := adminrepo.NewMysqlAdminRepository(db)
+		res, rErr := repo.GetByEmail(context.Background(), &entity.Admin{Email: "admin@mail.com"})
+
+		assert.Nil(t, rErr)
+		assert.Equal(t, int64(1), res.ID)
+		assert.Equal(t, "john admin", res.Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "email", "name", "password"})
+		mock.ExpectPrepare(regexp.QuoteMeta(queryFindByEmail)).ExpectQuery().WithArgs("nobody@mail.com").WillReturnRows(rows)
+
+		repo := adminrepo.NewMysqlAdminRepository(db)
+		_, rErr := repo.GetByEmail(context.Background(), &entity.Admin{Email: "nobody@mail.com"})
+
+		assert.True(t, errors.Is(rErr, resterrors.ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
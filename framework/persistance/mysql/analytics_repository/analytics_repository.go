@@ -0,0 +1,158 @@
+package analyticsrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	// lineRevenue is the one definition of revenue, summed by every sales query: the subtotal of an order line item
+	// less its share of the discount, without tax (taken back out of tax inclusive prices) and without the shipping fee
+	lineRevenue = "d.price*d.quantity - d.discount - CASE WHEN o.tax_inclusive THEN d.tax ELSE 0 END"
+
+	// the sales queries are completed with the period of a date column, then with a placeholder for each sold status
+	queryGetSales = `SELECT %s AS period, COUNT(DISTINCT o.id), COALESCE(SUM(` + lineRevenue + `), 0), COALESCE(SUM(d.quantity), 0)
+	FROM orders o JOIN order_details d ON d.order_id=o.id
+	WHERE o.seller_id=? AND o.status IN (%s) AND o.order_date>=? AND o.order_date<? GROUP BY period ORDER BY period;`
+	queryGetDailySales = `SELECT %s AS period, SUM(orders), SUM(revenue), SUM(units_sold)
+	FROM seller_daily_sales WHERE seller_id=? AND sales_date>=? AND sales_date<? GROUP BY period ORDER BY period;`
+	queryGetTopProducts = `SELECT d.product_id, COALESCE(p.sku, ''), p.name, SUM(d.quantity), SUM(` + lineRevenue + `) AS revenue
+	FROM order_details d JOIN orders o ON o.id=d.order_id JOIN products p ON p.id=d.product_id
+	WHERE o.seller_id=? AND o.status IN (%s) AND o.order_date>=? AND o.order_date<?
+	GROUP BY d.product_id, p.sku, p.name ORDER BY revenue DESC, d.product_id LIMIT ?;`
+
+	queryDeleteDailySales = "DELETE FROM seller_daily_sales WHERE sales_date>=? AND sales_date<?;"
+	queryInsertDailySales = `INSERT INTO seller_daily_sales(seller_id, sales_date, orders, revenue, units_sold)
+	SELECT o.seller_id, DATE(o.order_date), COUNT(DISTINCT o.id), SUM(` + lineRevenue + `), SUM(d.quantity)
+	FROM orders o JOIN order_details d ON d.order_id=o.id
+	WHERE o.status IN (%s) AND o.order_date>=? AND o.order_date<? GROUP BY o.seller_id, DATE(o.order_date);`
+)
+
+// soldStatuses are the arguments of the placeholders for entity.SoldOrderStatuses
+func soldStatuses() []interface{} {
+	args := make([]interface{}, 0, len(entity.SoldOrderStatuses))
+	for _, status := range entity.SoldOrderStatuses {
+		args = append(args, status)
+	}
+	return args
+}
+
+type mysqlAnalyticsRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlAnalyticsRepository will create a object with entity.AnalyticsRepository interface representation
+func NewMysqlAnalyticsRepository(Conn *sql.DB) entity.AnalyticsRepository {
+	return &mysqlAnalyticsRepository{Conn}
+}
+
+func (m *mysqlAnalyticsRepository) GetSales(ctx context.Context, sellerID int64, from, to time.Time, group entity.SalesGroupEnum) ([]entity.Sales, resterrors.RestErr) {
+	query := fmt.Sprintf(queryGetSales, mysqlutils.PeriodStart(group, "o.order_date"), mysqlutils.Placeholders(len(entity.SoldOrderStatuses)))
+	args := append([]interface{}{sellerID}, soldStatuses()...)
+	args = append(args, from.Format(dateTimeLayout), to.Format(dateTimeLayout))
+	return m.getSales(ctx, query, args...)
+}
+
+func (m *mysqlAnalyticsRepository) GetDailySales(ctx context.Context, sellerID int64, from, to time.Time, group entity.SalesGroupEnum) ([]entity.Sales, resterrors.RestErr) {
+	query := fmt.Sprintf(queryGetDailySales, mysqlutils.PeriodStart(group, "sales_date"))
+	return m.getSales(ctx, query, sellerID, from.Format(dateLayout), to.Format(dateLayout))
+}
+
+func (m *mysqlAnalyticsRepository) getSales(ctx context.Context, query string, args ...interface{}) ([]entity.Sales, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.Sales{}
+	for dbRes.Next() {
+		var period string
+		var revenue []uint8
+		sales := entity.Sales{}
+		if err := dbRes.Scan(&period, &sales.Orders, &revenue, &sales.UnitsSold); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if sales.Start, err = time.Parse(dateLayout, period); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if sales.Revenue, err = decimal.NewFromString(string(revenue)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, sales)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlAnalyticsRepository) GetTopProducts(ctx context.Context, sellerID int64, from, to time.Time, limit int) ([]entity.ProductSales, resterrors.RestErr) {
+	query := fmt.Sprintf(queryGetTopProducts, mysqlutils.Placeholders(len(entity.SoldOrderStatuses)))
+	args := append([]interface{}{sellerID}, soldStatuses()...)
+	args = append(args, from.Format(dateTimeLayout), to.Format(dateTimeLayout), limit)
+
+	dbRes, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.ProductSales{}
+	for dbRes.Next() {
+		var revenue []uint8
+		product := entity.ProductSales{}
+		err := dbRes.Scan(&product.Product.ID, &product.Product.SKU, &product.Product.Name, &product.UnitsSold, &revenue)
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if product.Revenue, err = decimal.NewFromString(string(revenue)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		product.Product.Seller.ID = sellerID
+		res = append(res, product)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlAnalyticsRepository) RefreshDailySales(ctx context.Context, from, to time.Time) resterrors.RestErr {
+	// start transaction, the days are replaced at once so they are never read half computed
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, queryDeleteDailySales, from.Format(dateLayout), to.Format(dateLayout)); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	query := fmt.Sprintf(queryInsertDailySales, mysqlutils.Placeholders(len(entity.SoldOrderStatuses)))
+	args := append(soldStatuses(), from.Format(dateTimeLayout), to.Format(dateTimeLayout))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
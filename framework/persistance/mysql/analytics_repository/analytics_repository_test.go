@@ -0,0 +1,145 @@
+package analyticsrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	analyticsrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/analytics_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryGetSalesByWeek = `SELECT DATE_FORMAT(DATE_SUB(o.order_date, INTERVAL WEEKDAY(o.order_date) DAY), '%Y-%m-%d') AS period, COUNT(DISTINCT o.id), COALESCE(SUM(d.price*d.quantity - d.discount - CASE WHEN o.tax_inclusive THEN d.tax ELSE 0 END), 0), COALESCE(SUM(d.quantity), 0)
+	FROM orders o JOIN order_details d ON d.order_id=o.id
+	WHERE o.seller_id=? AND o.status IN (?, ?, ?) AND o.order_date>=? AND o.order_date<? GROUP BY period ORDER BY period;`
+	queryGetDailySalesByMonth = `SELECT DATE_FORMAT(sales_date, '%Y-%m-01') AS period, SUM(orders), SUM(revenue), SUM(units_sold)
+	FROM seller_daily_sales WHERE seller_id=? AND sales_date>=? AND sales_date<? GROUP BY period ORDER BY period;`
+	queryGetTopProducts = `SELECT d.product_id, COALESCE(p.sku, ''), p.name, SUM(d.quantity), SUM(d.price*d.quantity - d.discount - CASE WHEN o.tax_inclusive THEN d.tax ELSE 0 END) AS revenue
+	FROM order_details d JOIN orders o ON o.id=d.order_id JOIN products p ON p.id=d.product_id
+	WHERE o.seller_id=? AND o.status IN (?, ?, ?) AND o.order_date>=? AND o.order_date<?
+	GROUP BY d.product_id, p.sku, p.name ORDER BY revenue DESC, d.product_id LIMIT ?;`
+	queryDeleteDailySales = "DELETE FROM seller_daily_sales WHERE sales_date>=? AND sales_date<?;"
+	queryInsertDailySales = `INSERT INTO seller_daily_sales(seller_id, sales_date, orders, revenue, units_sold)
+	SELECT o.seller_id, DATE(o.order_date), COUNT(DISTINCT o.id), SUM(d.price*d.quantity - d.discount - CASE WHEN o.tax_inclusive THEN d.tax ELSE 0 END), SUM(d.quantity)
+	FROM orders o JOIN order_details d ON d.order_id=o.id
+	WHERE o.status IN (?, ?, ?) AND o.order_date>=? AND o.order_date<? GROUP BY o.seller_id, DATE(o.order_date);`
+)
+
+type TestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo entity.AnalyticsRepository
+	from time.Time
+	to   time.Time
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = analyticsrepo.NewMysqlAnalyticsRepository(suite.db)
+	suite.from = time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	suite.to = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAnalyticsRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestGetSales() {
+	rows := sqlmock.NewRows([]string{"period", "count", "revenue", "units"}).
+		AddRow("2021-04-26", 2, []uint8("150000.50"), 3).
+		AddRow("2021-05-03", 1, []uint8("50000.00"), 1)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetSalesByWeek)).
+		WithArgs(int64(2), entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED, "2021-05-01 00:00:00", "2021-06-01 00:00:00").
+		WillReturnRows(rows)
+
+	res, err := suite.repo.GetSales(context.Background(), 2, suite.from, suite.to, entity.SALES_GROUP_WEEK)
+	suite.NoError(err)
+	suite.Len(res, 2)
+	suite.Equal(time.Date(2021, 4, 26, 0, 0, 0, 0, time.UTC), res[0].Start)
+	suite.Equal(int64(2), res[0].Orders)
+	suite.True(decimal.RequireFromString("150000.5").Equal(res[0].Revenue))
+	suite.Equal(int64(3), res[0].UnitsSold)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetSalesError() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetSalesByWeek)).WillReturnError(errors.New("connection refused"))
+
+	_, err := suite.repo.GetSales(context.Background(), 2, suite.from, suite.to, entity.SALES_GROUP_WEEK)
+	suite.Error(err)
+	suite.Equal(http.StatusInternalServerError, err.Status())
+}
+
+func (suite *TestSuite) TestGetDailySales() {
+	rows := sqlmock.NewRows([]string{"period", "orders", "revenue", "units_sold"}).
+		AddRow("2021-05-01", []uint8("12"), []uint8("1200000.00"), []uint8("30"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetDailySalesByMonth)).
+		WithArgs(int64(2), "2021-05-01", "2021-06-01").
+		WillReturnRows(rows)
+
+	res, err := suite.repo.GetDailySales(context.Background(), 2, suite.from, suite.to, entity.SALES_GROUP_MONTH)
+	suite.NoError(err)
+	suite.Len(res, 1)
+	suite.Equal(int64(12), res[0].Orders)
+	suite.Equal(int64(30), res[0].UnitsSold)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetTopProducts() {
+	rows := sqlmock.NewRows([]string{"product_id", "sku", "name", "quantity", "revenue"}).
+		AddRow(7, "KOPI-1", "Kopi Toraja", 10, []uint8("500000.00")).
+		AddRow(8, "", "Kopi Gayo", 4, []uint8("180000.00"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetTopProducts)).
+		WithArgs(int64(2), entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED, "2021-05-01 00:00:00", "2021-06-01 00:00:00", 5).
+		WillReturnRows(rows)
+
+	res, err := suite.repo.GetTopProducts(context.Background(), 2, suite.from, suite.to, 5)
+	suite.NoError(err)
+	suite.Len(res, 2)
+	suite.Equal(int64(7), res[0].Product.ID)
+	suite.Equal("KOPI-1", res[0].Product.SKU)
+	suite.Equal(int64(2), res[0].Product.Seller.ID)
+	suite.Equal(int64(10), res[0].UnitsSold)
+	suite.True(decimal.NewFromInt(500000).Equal(res[0].Revenue))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestRefreshDailySales() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteDailySales)).WithArgs("2021-05-01", "2021-06-01").
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertDailySales)).
+		WithArgs(entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED, "2021-05-01 00:00:00", "2021-06-01 00:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 42))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.RefreshDailySales(context.Background(), suite.from, suite.to)
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestRefreshDailySalesError() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteDailySales)).WillReturnResult(sqlmock.NewResult(0, 40))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertDailySales)).WillReturnError(errors.New("lock wait timeout exceeded"))
+	// the deleted days are kept
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.RefreshDailySales(context.Background(), suite.from, suite.to)
+	suite.Error(err)
+	suite.Equal(http.StatusInternalServerError, err.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
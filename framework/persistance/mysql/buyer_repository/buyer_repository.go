@@ -1,15 +1,21 @@
 package buyerrepo
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
 )
 
 const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
 	queryGetAll  = "SELECT id, email, name, sending_address FROM buyers;"
-	queryInsert  = "INSERT INTO buyers(email, name, password, sending_address) VALUES(?, ?, ?, ?);"
+	queryInsert  = "INSERT INTO buyers(email, name, password, sending_address, created_at) VALUES(?, ?, ?, ?, ?);"
 	queryGetById = "SELECT id, email, name, sending_address FROM buyers WHERE id=?;"
 	queryUpdate  = "UPDATE buyers SET email=?, name=?, sending_address=? WHERE id=?;"
 	queryDelete  = "DELETE FROM buyers WHERE id=?;"
@@ -26,12 +32,12 @@ func NewMysqlBuyerRepository(Conn *sql.DB) entity.BuyerRepository {
 	return &mysqlBuyerRepository{Conn: Conn}
 }
 
-func (m *mysqlBuyerRepository) GetAll() ([]entity.Buyer, resterrors.RestErr) {
-	dbRes, err := m.Conn.Query(queryGetAll)
+func (m *mysqlBuyerRepository) GetAll(ctx context.Context) ([]entity.Buyer, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, queryGetAll)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
-	defer m.Conn.Close()
+	defer dbRes.Close()
 
 	buyer := entity.Buyer{}
 	res := []entity.Buyer{}
@@ -49,35 +55,44 @@ func (m *mysqlBuyerRepository) GetAll() ([]entity.Buyer, resterrors.RestErr) {
 
 		res = append(res, buyer)
 	}
+
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
 	return res, nil
 }
 
-func (m *mysqlBuyerRepository) GetByID(buyer *entity.Buyer) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryGetById)
+func (m *mysqlBuyerRepository) GetByID(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetById)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes := stmt.QueryRow(buyer.ID)
+	dbRes := stmt.QueryRowContext(ctx, buyer.ID)
 	if err := dbRes.Scan(&buyer.ID, &buyer.Email, &buyer.Name, &buyer.SendingAddress); err != nil {
-
+		if errors.Is(err, sql.ErrNoRows) {
+			return resterrors.NewNotFoundError(fmt.Sprintf("buyer with id %d not found", buyer.ID))
+		}
 		return resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 
 	return nil
 }
 
-func (m *mysqlBuyerRepository) Store(buyer *entity.Buyer) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryInsert)
+func (m *mysqlBuyerRepository) Store(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryInsert)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 	defer stmt.Close()
 
-	// email, name, password, sending_address
-	dbRes, err := stmt.Exec(buyer.Email, buyer.Name, buyer.Password, buyer.SendingAddress)
+	// email, name, password, sending_address, created_at
+	dbRes, err := stmt.ExecContext(ctx, buyer.Email, buyer.Name, buyer.Password, buyer.SendingAddress, buyer.CreatedAt.Format(dateTimeLayout))
 	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("user with email %s is already exist", buyer.Email))
+		}
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 
@@ -91,42 +106,50 @@ func (m *mysqlBuyerRepository) Store(buyer *entity.Buyer) resterrors.RestErr {
 	return nil
 }
 
-func (m *mysqlBuyerRepository) Update(buyer *entity.Buyer) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryUpdate)
+func (m *mysqlBuyerRepository) Update(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryUpdate)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(buyer.Email, buyer.Name, buyer.SendingAddress, buyer.ID)
+	_, err = stmt.ExecContext(ctx, buyer.Email, buyer.Name, buyer.SendingAddress, buyer.ID)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
 	return nil
 }
 
-func (m *mysqlBuyerRepository) Delete(buyer *entity.Buyer) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryDelete)
+func (m *mysqlBuyerRepository) Delete(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryDelete)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
 	defer stmt.Close()
 
-	if _, err = stmt.Exec(buyer.ID); err != nil {
+	dbRes, err := stmt.ExecContext(ctx, buyer.ID)
+	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("buyer with id %d not found", buyer.ID))
+	}
 	return nil
 }
 
-func (m *mysqlBuyerRepository) GetByEmail(buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryFindByEmail)
+func (m *mysqlBuyerRepository) GetByEmail(ctx context.Context, buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryFindByEmail)
 	if err != nil {
 		return *buyer, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes := stmt.QueryRow(buyer.Email)
+	dbRes := stmt.QueryRowContext(ctx, buyer.Email)
 	if err := dbRes.Scan(&buyer.ID, &buyer.Email, &buyer.Name, &buyer.Password, &buyer.SendingAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *buyer, resterrors.NewNotFoundError(fmt.Sprintf("buyer with email %s not found", buyer.Email))
+		}
 		return *buyer, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	return *buyer, nil
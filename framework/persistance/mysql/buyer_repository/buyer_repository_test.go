@@ -1,11 +1,16 @@
 package buyerrepo_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	buyerrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/buyer_repository"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
@@ -79,7 +84,7 @@ func (suite *TestSuite) TestGetAll() {
 	prep.WillReturnRows(rows...)
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	res, repoErr := repo.GetAll()
+	res, repoErr := repo.GetAll(context.Background())
 
 	suite.NoError(repoErr)
 	suite.NotNil(res)
@@ -97,18 +102,18 @@ func (suite *TestSuite) TestGetByID() {
 	buyer.ID = suite.expectedBuyer1.ID
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	repoErr := repo.GetByID(buyer)
+	repoErr := repo.GetByID(context.Background(), buyer)
 
 	suite.NoError(repoErr)
 	suite.NotNil(buyer)
 }
 
 func (suite *TestSuite) TestStore() {
-	queryInsert := "INSERT INTO buyers(email, name, password, sending_address) VALUES(?, ?, ?, ?);"
+	queryInsert := "INSERT INTO buyers(email, name, password, sending_address, created_at) VALUES(?, ?, ?, ?, ?);"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
 
 	prep.ExpectExec().
-		WithArgs(suite.expectedBuyer1.Email, suite.expectedBuyer1.Name, suite.expectedBuyer1.Password, suite.expectedBuyer1.SendingAddress).
+		WithArgs(suite.expectedBuyer1.Email, suite.expectedBuyer1.Name, suite.expectedBuyer1.Password, suite.expectedBuyer1.SendingAddress, "2021-05-01 08:30:00").
 		WillReturnResult(sqlmock.NewResult(suite.expectedBuyer1.ID, 1))
 
 	buyer := new(entity.Buyer)
@@ -117,14 +122,50 @@ func (suite *TestSuite) TestStore() {
 	buyer.Name = suite.expectedBuyer1.Name
 	buyer.Password = suite.expectedBuyer1.Password
 	buyer.SendingAddress = suite.expectedBuyer1.SendingAddress
+	buyer.CreatedAt = time.Date(2021, 5, 1, 8, 30, 0, 0, time.UTC)
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	repoErr := repo.Store(buyer)
+	repoErr := repo.Store(context.Background(), buyer)
 
 	suite.NoError(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
 	suite.NotNil(buyer)
 }
 
+func (suite *TestSuite) TestGetByIDNotFound() {
+	queryGetById := "SELECT id, email, name, sending_address FROM buyers WHERE id=?;"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
+	prep.ExpectQuery().WithArgs(suite.expectedBuyer1.ID).WillReturnError(sql.ErrNoRows)
+
+	buyer := new(entity.Buyer)
+	buyer.ID = suite.expectedBuyer1.ID
+
+	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
+	repoErr := repo.GetByID(context.Background(), buyer)
+
+	suite.True(errors.Is(repoErr, resterrors.ErrNotFound))
+}
+
+func (suite *TestSuite) TestStoreDuplicateEmail() {
+	queryInsert := "INSERT INTO buyers(email, name, password, sending_address, created_at) VALUES(?, ?, ?, ?, ?);"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
+
+	prep.ExpectExec().
+		WithArgs(suite.expectedBuyer1.Email, suite.expectedBuyer1.Name, suite.expectedBuyer1.Password, suite.expectedBuyer1.SendingAddress, sqlmock.AnyArg()).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'buyer1@mail.com' for key 'email_UNIQUE'"})
+
+	buyer := new(entity.Buyer)
+	buyer.Email = suite.expectedBuyer1.Email
+	buyer.Name = suite.expectedBuyer1.Name
+	buyer.Password = suite.expectedBuyer1.Password
+	buyer.SendingAddress = suite.expectedBuyer1.SendingAddress
+
+	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
+	repoErr := repo.Store(context.Background(), buyer)
+
+	suite.True(errors.Is(repoErr, resterrors.ErrConflict))
+}
+
 func (suite *TestSuite) TestUpdate() {
 	queryUpdate := "UPDATE buyers SET email=?, name=?, sending_address=? WHERE id=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdate))
@@ -141,7 +182,7 @@ func (suite *TestSuite) TestUpdate() {
 	buyer.SendingAddress = suite.expectedBuyer1.SendingAddress
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	repoErr := repo.Update(buyer)
+	repoErr := repo.Update(context.Background(), buyer)
 
 	suite.NoError(repoErr)
 	suite.NotNil(buyer)
@@ -159,12 +200,29 @@ func (suite *TestSuite) TestDelete() {
 	buyer.ID = suite.expectedBuyer1.ID
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	repoErr := repo.Delete(buyer)
+	repoErr := repo.Delete(context.Background(), buyer)
 
 	suite.NoError(repoErr)
 	suite.NotNil(buyer)
 }
 
+func (suite *TestSuite) TestDeleteNotFound() {
+	queryDelete := "DELETE FROM buyers WHERE id=?;"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryDelete))
+
+	prep.ExpectExec().
+		WithArgs(suite.expectedBuyer1.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	buyer := new(entity.Buyer)
+	buyer.ID = suite.expectedBuyer1.ID
+
+	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
+	repoErr := repo.Delete(context.Background(), buyer)
+
+	suite.True(errors.Is(repoErr, resterrors.ErrNotFound))
+}
+
 func (suite *TestSuite) TestGetByEmail() {
 	queryFindByEmail := "SELECT id, email, name, password, sending_address FROM buyers WHERE email=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryFindByEmail))
@@ -177,7 +235,7 @@ func (suite *TestSuite) TestGetByEmail() {
 	buyer.Email = suite.expectedBuyer1.Email
 
 	repo := buyerrepo.NewMysqlBuyerRepository(suite.db)
-	repoRes, repoErr := repo.GetByEmail(buyer)
+	repoRes, repoErr := repo.GetByEmail(context.Background(), buyer)
 
 	suite.NoError(repoErr)
 	suite.NotNil(repoRes)
@@ -0,0 +1,112 @@
+package idempotencyrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryDeleteExpiredKey = "DELETE FROM idempotency_keys WHERE buyer_id=? AND idem_key=? AND expires_at<=?;"
+	queryInsert           = `INSERT INTO idempotency_keys(idem_key, buyer_id, request_hash, created_at, expires_at) 
+	VALUES(?, ?, ?, ?, ?);`
+	queryGetByKey = `SELECT idem_key, buyer_id, request_hash, COALESCE(response_status, 0), COALESCE(response_content_type, ''), 
+	response_body, created_at, expires_at FROM idempotency_keys WHERE buyer_id=? AND idem_key=?;`
+	queryComplete = `UPDATE idempotency_keys SET response_status=?, response_content_type=?, response_body=? 
+	WHERE buyer_id=? AND idem_key=?;`
+	queryDelete        = "DELETE FROM idempotency_keys WHERE buyer_id=? AND idem_key=?;"
+	queryDeleteExpired = "DELETE FROM idempotency_keys WHERE expires_at<=?;"
+)
+
+type mysqlIdempotencyRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlIdempotencyRepository will create a object with entity.IdempotencyRepository interface representation
+func NewMysqlIdempotencyRepository(Conn *sql.DB) entity.IdempotencyRepository {
+	return &mysqlIdempotencyRepository{Conn}
+}
+
+func (m *mysqlIdempotencyRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey) (entity.IdempotencyKey, bool, resterrors.RestErr) {
+	// an expired key can be used again
+	_, err := m.Conn.ExecContext(ctx, queryDeleteExpiredKey, key.BuyerID, key.Key, key.CreatedAt.Format(dateTimeLayout))
+	if err != nil {
+		return entity.IdempotencyKey{}, false, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	// the unique key on buyer_id and idem_key lets only one request reserve the key
+	_, err = m.Conn.ExecContext(ctx, queryInsert, key.Key, key.BuyerID, key.RequestHash,
+		key.CreatedAt.Format(dateTimeLayout), key.ExpiresAt.Format(dateTimeLayout))
+	if err == nil {
+		return entity.IdempotencyKey{}, true, nil
+	}
+	if !mysqlutils.IsDuplicateEntry(err) {
+		return entity.IdempotencyKey{}, false, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	existing, rErr := m.getByKey(ctx, key.BuyerID, key.Key)
+	if rErr != nil {
+		return entity.IdempotencyKey{}, false, rErr
+	}
+	return existing, false, nil
+}
+
+func (m *mysqlIdempotencyRepository) getByKey(ctx context.Context, buyerID int64, idemKey string) (entity.IdempotencyKey, resterrors.RestErr) {
+	var createdAt, expiresAt []uint8
+	res := entity.IdempotencyKey{}
+	err := m.Conn.QueryRowContext(ctx, queryGetByKey, buyerID, idemKey).Scan(&res.Key, &res.BuyerID, &res.RequestHash,
+		&res.ResponseStatus, &res.ResponseContentType, &res.ResponseBody, &createdAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// removed by the request that reserved it since the insert failed
+			return res, resterrors.NewConflictError("a request with the same idempotency key has just failed, try again")
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if res.CreatedAt, err = helpers.GetTimeFromUint8(createdAt); err != nil {
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	if res.ExpiresAt, err = helpers.GetTimeFromUint8(expiresAt); err != nil {
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlIdempotencyRepository) Complete(ctx context.Context, key *entity.IdempotencyKey) resterrors.RestErr {
+	_, err := m.Conn.ExecContext(ctx, queryComplete, key.ResponseStatus, key.ResponseContentType, key.ResponseBody,
+		key.BuyerID, key.Key)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	return nil
+}
+
+func (m *mysqlIdempotencyRepository) Delete(ctx context.Context, key *entity.IdempotencyKey) resterrors.RestErr {
+	_, err := m.Conn.ExecContext(ctx, queryDelete, key.BuyerID, key.Key)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+	return nil
+}
+
+func (m *mysqlIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, resterrors.RestErr) {
+	dbRes, err := m.Conn.ExecContext(ctx, queryDeleteExpired, now.Format(dateTimeLayout))
+	if err != nil {
+		return 0, resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+
+	affected, err := dbRes.RowsAffected()
+	if err != nil {
+		return 0, resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+	return affected, nil
+}
@@ -0,0 +1,130 @@
+package idempotencyrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	idempotencyrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/idempotency_repository"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryDeleteExpiredKey = "DELETE FROM idempotency_keys WHERE buyer_id=? AND idem_key=? AND expires_at<=?;"
+	queryInsert           = `INSERT INTO idempotency_keys(idem_key, buyer_id, request_hash, created_at, expires_at) 
+	VALUES(?, ?, ?, ?, ?);`
+	queryGetByKey = `SELECT idem_key, buyer_id, request_hash, COALESCE(response_status, 0), COALESCE(response_content_type, ''), 
+	response_body, created_at, expires_at FROM idempotency_keys WHERE buyer_id=? AND idem_key=?;`
+)
+
+type TestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo entity.IdempotencyRepository
+	key  entity.IdempotencyKey
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = idempotencyrepo.NewMysqlIdempotencyRepository(suite.db)
+	suite.key = entity.IdempotencyKey{
+		Key:         "5b1f0c1e-6d3a-4a7e-9d55-0b7a1c3e2f10",
+		BuyerID:     1,
+		RequestHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		CreatedAt:   time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		ExpiresAt:   time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestIdempotencyRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestReserve() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteExpiredKey)).
+		WithArgs(suite.key.BuyerID, suite.key.Key, "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(suite.key.Key, suite.key.BuyerID, suite.key.RequestHash, "2021-05-01 10:00:00", "2021-05-02 10:00:00").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	_, ok, repoErr := suite.repo.Reserve(context.Background(), &suite.key)
+
+	suite.NoError(repoErr)
+	suite.True(ok)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestReserveUsedKey() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteExpiredKey)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByKey)).
+		WithArgs(suite.key.BuyerID, suite.key.Key).
+		WillReturnRows(sqlmock.NewRows([]string{"idem_key", "buyer_id", "request_hash", "response_status",
+			"response_content_type", "response_body", "created_at", "expires_at"}).
+			AddRow(suite.key.Key, suite.key.BuyerID, suite.key.RequestHash, http.StatusCreated, "application/json",
+				[]byte(`{"data":{"id":1}}`), []uint8("2021-05-01 09:59:00"), []uint8("2021-05-02 09:59:00")))
+
+	existing, ok, repoErr := suite.repo.Reserve(context.Background(), &suite.key)
+
+	suite.NoError(repoErr)
+	suite.False(ok)
+	suite.True(existing.Completed())
+	suite.Equal(http.StatusCreated, existing.ResponseStatus)
+	suite.Equal(`{"data":{"id":1}}`, string(existing.ResponseBody))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestReserveKeyJustDeleted() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteExpiredKey)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByKey)).WillReturnError(sql.ErrNoRows)
+
+	_, ok, repoErr := suite.repo.Reserve(context.Background(), &suite.key)
+
+	suite.Error(repoErr)
+	suite.False(ok)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+}
+
+func (suite *TestSuite) TestComplete() {
+	queryComplete := `UPDATE idempotency_keys SET response_status=?, response_content_type=?, response_body=? 
+	WHERE buyer_id=? AND idem_key=?;`
+	suite.key.ResponseStatus = http.StatusCreated
+	suite.key.ResponseContentType = "application/json"
+	suite.key.ResponseBody = []byte(`{"data":{"id":1}}`)
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryComplete)).
+		WithArgs(http.StatusCreated, "application/json", suite.key.ResponseBody, suite.key.BuyerID, suite.key.Key).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repoErr := suite.repo.Complete(context.Background(), &suite.key)
+
+	suite.NoError(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestDeleteExpired() {
+	queryDeleteExpired := "DELETE FROM idempotency_keys WHERE expires_at<=?;"
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteExpired)).
+		WithArgs("2021-05-03 00:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+
+	deleted, repoErr := suite.repo.DeleteExpired(context.Background(), time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC))
+
+	suite.NoError(repoErr)
+	suite.Equal(int64(12), deleted)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
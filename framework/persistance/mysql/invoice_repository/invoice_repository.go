@@ -0,0 +1,93 @@
+package invoicerepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryGetByOrderId = "SELECT id, order_id, seller_id, number, issued_at FROM invoices WHERE order_id=?;"
+	queryInsert       = "INSERT INTO invoices(order_id, seller_id, number, issued_at) VALUES(?, ?, ?, ?);"
+
+	// the sequence row of the seller stays locked until the invoice is committed, so the invoices of a seller
+	// are numbered one at a time and a rolled back invoice gives its number back
+	seqNext = `INSERT INTO invoice_sequences(seller_id, last_number) VALUES(?, 1) 
+	ON DUPLICATE KEY UPDATE last_number=last_number+1;`
+	seqGet = "SELECT last_number FROM invoice_sequences WHERE seller_id=?;"
+)
+
+type mysqlInvoiceRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlInvoiceRepository will create a object with entity.InvoiceRepository interface representation
+func NewMysqlInvoiceRepository(Conn *sql.DB) entity.InvoiceRepository {
+	return &mysqlInvoiceRepository{Conn}
+}
+
+func (m *mysqlInvoiceRepository) GetByOrderID(ctx context.Context, orderID int64) (entity.Invoice, resterrors.RestErr) {
+	invoice := entity.Invoice{}
+	var issuedAt []uint8
+	err := m.Conn.QueryRowContext(ctx, queryGetByOrderId, orderID).
+		Scan(&invoice.ID, &invoice.OrderID, &invoice.SellerID, &invoice.Number, &issuedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return invoice, resterrors.NewNotFoundError(fmt.Sprintf("order with id %d has no invoice", orderID))
+		}
+		return invoice, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if invoice.IssuedAt, err = helpers.GetTimeFromUint8(issuedAt); err != nil {
+		return entity.Invoice{}, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return invoice, nil
+}
+
+func (m *mysqlInvoiceRepository) Store(ctx context.Context, invoice *entity.Invoice) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, seqNext, invoice.SellerID); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	var number int64
+	if err = tx.QueryRowContext(ctx, seqGet, invoice.SellerID).Scan(&number); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	dbRes, err := tx.ExecContext(ctx, queryInsert, invoice.OrderID, invoice.SellerID, number, invoice.IssuedAt.Format(dateTimeLayout))
+	if err != nil {
+		tx.Rollback()
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("order with id %d already has an invoice", invoice.OrderID))
+		}
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	invoiceID, err := dbRes.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	// commit the change if all queries ran successfully
+	if err = tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	invoice.ID = invoiceID
+	invoice.Number = number
+	return nil
+}
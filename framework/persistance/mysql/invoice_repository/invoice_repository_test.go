@@ -0,0 +1,109 @@
+package invoicerepo_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	invoicerepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/invoice_repository"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryGetByOrderId = "SELECT id, order_id, seller_id, number, issued_at FROM invoices WHERE order_id=?;"
+	queryInsert       = "INSERT INTO invoices(order_id, seller_id, number, issued_at) VALUES(?, ?, ?, ?);"
+	seqNext           = `INSERT INTO invoice_sequences(seller_id, last_number) VALUES(?, 1) 
+	ON DUPLICATE KEY UPDATE last_number=last_number+1;`
+	seqGet = "SELECT last_number FROM invoice_sequences WHERE seller_id=?;"
+)
+
+type TestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	repo    entity.InvoiceRepository
+	invoice entity.Invoice
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = invoicerepo.NewMysqlInvoiceRepository(suite.db)
+	suite.invoice = entity.Invoice{
+		OrderID:  1,
+		SellerID: 2,
+		IssuedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestInvoiceRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(seqNext)).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(seqGet)).WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(8))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(int64(1), int64(2), int64(8), "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.invoice)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(5), suite.invoice.ID)
+	suite.Equal(int64(8), suite.invoice.Number)
+	suite.Equal("INV/2/000008", suite.invoice.Code())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreAlreadyIssued() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(seqNext)).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(seqGet)).WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(9))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'order_id'"})
+	// the number is given back to the seller
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.invoice)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.Zero(suite.invoice.Number)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "seller_id", "number", "issued_at"}).
+			AddRow(5, 1, 2, 8, []uint8("2021-05-01 10:00:00")))
+
+	invoice, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(8), invoice.Number)
+	suite.Equal(time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC), invoice.IssuedAt)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderIDNotIssued() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+
+	_, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+}
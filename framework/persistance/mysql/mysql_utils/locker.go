@@ -0,0 +1,53 @@
+package mysqlutils
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const (
+	queryGetLock     = "SELECT GET_LOCK(?, 0);"
+	queryReleaseLock = "DO RELEASE_LOCK(?);"
+)
+
+type mysqlLocker struct {
+	Conn *sql.DB
+}
+
+// NewMysqlLocker will create a object with entity.Locker interface representation. Locks are MySQL named locks,
+// held by a database session, so they are released by the server as well when an instance dies while holding one
+func NewMysqlLocker(Conn *sql.DB) entity.Locker {
+	return &mysqlLocker{Conn: Conn}
+}
+
+func (l *mysqlLocker) TryLock(ctx context.Context, name string) (func(), bool, resterrors.RestErr) {
+	// the lock belongs to the session, so the same connection must be used to release it
+	conn, err := l.Conn.Conn(ctx)
+	if err != nil {
+		return nil, false, resterrors.NewInternalServerError("error when trying to acquire lock", err)
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, queryGetLock, name).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, resterrors.NewInternalServerError("error when trying to acquire lock", err)
+	}
+
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// released even when ctx is already done
+		if _, err := conn.ExecContext(context.Background(), queryReleaseLock, name); err != nil {
+			log.Println("release lock error", name, err)
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,40 @@
+package mysqlutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+)
+
+// ER_DUP_ENTRY, returned when a unique constraint is violated
+const errDuplicateEntry = 1062
+
+// IsDuplicateEntry reports whether err is caused by a unique constraint violation
+func IsDuplicateEntry(err error) bool {
+	var mErr *mysql.MySQLError
+	return errors.As(err, &mErr) && mErr.Number == errDuplicateEntry
+}
+
+// Placeholders returns n comma separated bind placeholders, to be used inside IN (...)
+func Placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// PeriodStart returns an expression of the start of the period of group the date in column is in,
+// formatted as a 2006-01-02 date. Weeks start on Monday like entity.SalesGroupEnum.PeriodStart
+func PeriodStart(group entity.SalesGroupEnum, column string) string {
+	switch group {
+	case entity.SALES_GROUP_WEEK:
+		return fmt.Sprintf("DATE_FORMAT(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY), '%%Y-%%m-%%d')", column, column)
+	case entity.SALES_GROUP_MONTH:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", column)
+	default:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+	}
+}
@@ -0,0 +1,85 @@
+package mysqlutils_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestIsDuplicateEntry(t *testing.T) {
+	assert.True(t, mysqlutils.IsDuplicateEntry(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, mysqlutils.IsDuplicateEntry(fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062})))
+	assert.False(t, mysqlutils.IsDuplicateEntry(&mysql.MySQLError{Number: 1452}))
+	assert.False(t, mysqlutils.IsDuplicateEntry(errors.New("duplicate")))
+}
+
+func TestPlaceholders(t *testing.T) {
+	assert.Equal(t, "", mysqlutils.Placeholders(0))
+	assert.Equal(t, "?", mysqlutils.Placeholders(1))
+	assert.Equal(t, "?, ?, ?", mysqlutils.Placeholders(3))
+}
+
+func TestPeriodStart(t *testing.T) {
+	assert.Equal(t, "DATE_FORMAT(order_date, '%Y-%m-%d')", mysqlutils.PeriodStart(entity.SALES_GROUP_DAY, "order_date"))
+	assert.Equal(t, "DATE_FORMAT(DATE_SUB(order_date, INTERVAL WEEKDAY(order_date) DAY), '%Y-%m-%d')",
+		mysqlutils.PeriodStart(entity.SALES_GROUP_WEEK, "order_date"))
+	assert.Equal(t, "DATE_FORMAT(created_at, '%Y-%m-01')", mysqlutils.PeriodStart(entity.SALES_GROUP_MONTH, "created_at"))
+}
+
+func TestTryLock(t *testing.T) {
+	t.Run("acquired", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, 0);")).WithArgs("order_expiry").
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("DO RELEASE_LOCK(?);")).WithArgs("order_expiry").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		locker := mysqlutils.NewMysqlLocker(db)
+		unlock, ok, lErr := locker.TryLock(context.Background(), "order_expiry")
+		assert.Nil(t, lErr)
+		assert.True(t, ok)
+
+		unlock()
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("held by another session", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, 0);")).WithArgs("order_expiry").
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+		locker := mysqlutils.NewMysqlLocker(db)
+		unlock, ok, lErr := locker.TryLock(context.Background(), "order_expiry")
+		assert.Nil(t, lErr)
+		assert.False(t, ok)
+		assert.Nil(t, unlock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, 0);")).WillReturnError(errors.New("connection lost"))
+
+		locker := mysqlutils.NewMysqlLocker(db)
+		_, ok, lErr := locker.TryLock(context.Background(), "order_expiry")
+		assert.Error(t, lErr)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,162 @@
+package orderrepo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// sortColumn describes how an order listing is sorted, id breaks ties so the sort is stable
+type sortColumn struct {
+	column string
+	desc   bool
+	// placeholder used when comparing the column with a cursor value
+	placeholder string
+	// value of the column for order, stored in the cursor
+	value func(order entity.Order) string
+	// validates a cursor value
+	parse func(value string) error
+}
+
+var (
+	orderDateColumn = sortColumn{
+		column:      "order_date",
+		placeholder: "?",
+		value:       func(order entity.Order) string { return order.OrderDate.Format(dateTimeLayout) },
+		parse: func(value string) error {
+			_, err := time.Parse(dateTimeLayout, value)
+			return err
+		},
+	}
+	totalPriceColumn = sortColumn{
+		column:      "total_price",
+		placeholder: "CAST(? AS DECIMAL(15,2))",
+		value:       func(order entity.Order) string { return order.TotalPrice.String() },
+		parse: func(value string) error {
+			_, err := decimal.NewFromString(value)
+			return err
+		},
+	}
+)
+
+func sortColumnOf(sort entity.OrderSortEnum) (sortColumn, bool) {
+	switch sort {
+	case entity.SORT_ORDER_DATE_DESC, "":
+		sc := orderDateColumn
+		sc.desc = true
+		return sc, true
+	case entity.SORT_ORDER_DATE_ASC:
+		return orderDateColumn, true
+	case entity.SORT_TOTAL_PRICE_DESC:
+		sc := totalPriceColumn
+		sc.desc = true
+		return sc, true
+	case entity.SORT_TOTAL_PRICE_ASC:
+		return totalPriceColumn, true
+	}
+	return sortColumn{}, false
+}
+
+// orderCursor points at the last order of a page, the next page starts right after it
+type orderCursor struct {
+	Sort  entity.OrderSortEnum `json:"s"`
+	Value string               `json:"v"`
+	ID    int64                `json:"id"`
+}
+
+func encodeCursor(sort entity.OrderSortEnum, last entity.Order) string {
+	sc, _ := sortColumnOf(sort)
+	j, _ := json.Marshal(orderCursor{Sort: sort, Value: sc.value(last), ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(j)
+}
+
+func decodeCursor(cursor string, sort entity.OrderSortEnum) (orderCursor, resterrors.RestErr) {
+	res := orderCursor{}
+	invalidErr := resterrors.NewBadRequestError("invalid cursor")
+
+	j, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return res, invalidErr
+	}
+	if err := json.Unmarshal(j, &res); err != nil {
+		return res, invalidErr
+	}
+
+	// a cursor is only valid for the sort it was created with
+	sc, _ := sortColumnOf(sort)
+	if res.Sort != sort || sc.parse(res.Value) != nil {
+		return res, invalidErr
+	}
+	return res, nil
+}
+
+// buildListQuery returns queryList completed with the conditions, sort and limit of filter
+func buildListQuery(userColumn, counterpartColumn string, userID int64, filter entity.OrderFilter) (string, []interface{}, resterrors.RestErr) {
+	sc, ok := sortColumnOf(filter.Sort)
+	if !ok {
+		return "", nil, resterrors.NewBadRequestError(fmt.Sprintf("unknown sort %s", filter.Sort))
+	}
+	if filter.Limit <= 0 {
+		return "", nil, resterrors.NewBadRequestError("limit must be greater than 0")
+	}
+
+	conds := []string{userColumn + "=?"}
+	args := []interface{}{userID}
+
+	if len(filter.Statuses) > 0 {
+		conds = append(conds, fmt.Sprintf("status IN (%s)", mysqlutils.Placeholders(len(filter.Statuses))))
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	if !filter.From.IsZero() {
+		conds = append(conds, "order_date>=?")
+		args = append(args, filter.From.Format(dateTimeLayout))
+	}
+	if !filter.To.IsZero() {
+		conds = append(conds, "order_date<?")
+		args = append(args, filter.To.Format(dateTimeLayout))
+	}
+	if filter.CounterpartID != 0 {
+		conds = append(conds, counterpartColumn+"=?")
+		args = append(args, filter.CounterpartID)
+	}
+	if filter.MinTotal != nil {
+		conds = append(conds, "total_price>=?")
+		args = append(args, filter.MinTotal.String())
+	}
+	if filter.MaxTotal != nil {
+		conds = append(conds, "total_price<=?")
+		args = append(args, filter.MaxTotal.String())
+	}
+
+	op, dir := ">", "ASC"
+	if sc.desc {
+		op, dir = "<", "DESC"
+	}
+
+	// keyset pagination, continue after the (sort column, id) of the cursor
+	if filter.Cursor != "" {
+		cursor, rErr := decodeCursor(filter.Cursor, filter.Sort)
+		if rErr != nil {
+			return "", nil, rErr
+		}
+
+		conds = append(conds, fmt.Sprintf("(%[1]s%[2]s%[3]s OR (%[1]s=%[3]s AND id%[2]s?))", sc.column, op, sc.placeholder))
+		args = append(args, cursor.Value, cursor.Value, cursor.ID)
+	}
+
+	orderBy := fmt.Sprintf("%[1]s %[2]s, id %[2]s", sc.column, dir)
+	args = append(args, filter.Limit+1)
+
+	return fmt.Sprintf(queryList, strings.Join(conds, " AND "), orderBy), args, nil
+}
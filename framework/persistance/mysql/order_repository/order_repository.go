@@ -3,32 +3,67 @@ package orderrepo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
 	"github.com/shopspring/decimal"
 )
 
 const (
 	queryGetAll = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-	total_quantity, total_price, status, order_date 
-	FROM orders;`
-	queryGetById = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-	total_quantity, total_price, status, order_date FROM orders WHERE id=?;`
-	queryGetByBuyerID = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-	total_quantity, total_price, status, order_date FROM orders WHERE buyer_id=?;`
-	queryGetBySellerID = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-	total_quantity, total_price, status, order_date FROM orders WHERE seller_id=?;`
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders ORDER BY id;`
+	queryGetById = `SELECT o.id, o.buyer_id, o.seller_id, o.delivery_source_address, o.delivery_destination_address, 
+	o.total_quantity, o.total_price, o.status, o.order_date, COALESCE(o.reason_code, ''), COALESCE(o.reason_note, ''), 
+	o.version, COALESCE(o.voucher_id, 0), COALESCE(o.voucher_code, ''), o.discount, 
+	COALESCE(o.shipping_service, ''), o.shipping_fee, o.tax, o.tax_inclusive, o.shipping_origin_province, 
+	o.shipping_origin_postal_code, o.shipping_destination_province, o.shipping_destination_postal_code, b.name, b.email, s.name, s.email 
+	FROM orders o JOIN buyers b ON b.id = o.buyer_id JOIN sellers s ON s.id = o.seller_id WHERE o.id=?;`
+	// queryList is completed with the conditions and the sort of an entity.OrderFilter
+	queryList = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE %s ORDER BY %s LIMIT ?;`
+	queryGetPendingBefore = `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE status=? AND order_date<? ORDER BY order_date, id LIMIT ?;`
 
 	queryInsert = `INSERT INTO orders(buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-		total_quantity, total_price, status, order_date) VALUES(?, ?, ?, ?, ?, ?, ?, ?);`
+		total_quantity, total_price, status, order_date, voucher_id, voucher_code, discount, shipping_service, shipping_fee, 
+		tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+		shipping_destination_postal_code) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?);`
 	queryUpdate = `UPDATE orders SET buyer_id=?, seller_id=?, delivery_source_address=?, delivery_destination_address=?, 
-	total_quantity=?, total_price=?, status=?, order_date=? WHERE id=?;`
+	total_quantity=?, total_price=?, status=?, order_date=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), 
+	version=version+1 WHERE id=? AND version=?;`
+	queryUpdateStatus = `UPDATE orders SET status=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), version=version+1 
+	WHERE id=? AND version=?;`
 	queryDelete = "DELETE FROM orders WHERE id=?;"
 
-	odInsert       = `INSERT INTO order_details(order_id, product_id, quantity) VALUES(?, ?, ?);`
-	odGetByOrderId = `SELECT id, product_id, quantity FROM order_details WHERE order_id=?;`
+	odInsert        = `INSERT INTO order_details(order_id, product_id, quantity, price, discount, tax_rate, tax) VALUES(?, ?, ?, ?, ?, ?, ?);`
+	odGetByOrderIds = `SELECT od.id, od.order_id, od.quantity, od.price, od.discount, od.tax_rate, od.tax, p.id, p.name, COALESCE(p.description, ''), p.price, p.seller_id 
+	FROM order_details od JOIN products p ON p.id = od.product_id WHERE od.order_id IN (%s) ORDER BY od.id;`
+
+	// a voucher is claimed by incrementing its use count while uses are left, the row stays locked until the order
+	// is committed so the uses of one voucher are counted one order at a time
+	vClaim         = "UPDATE vouchers SET used_count=used_count+1 WHERE id=? AND (usage_limit=0 OR used_count<usage_limit);"
+	vuCountByBuyer = `SELECT v.per_buyer_limit, (SELECT COUNT(*) FROM voucher_usages vu WHERE vu.voucher_id=v.id AND vu.buyer_id=?) 
+	FROM vouchers v WHERE v.id=?;`
+	vuInsert = "INSERT INTO voucher_usages(voucher_id, order_id, buyer_id, discount, created_at) VALUES(?, ?, ?, ?, ?);"
+
+	shInsert = `INSERT INTO order_status_history(order_id, previous_status, status, actor_id, actor_type, reason, created_at) 
+	VALUES(?, ?, ?, ?, ?, NULLIF(?, ''), ?);`
+	shGetByOrderId = `SELECT id, order_id, previous_status, status, actor_id, actor_type, COALESCE(reason, ''), created_at
+	FROM order_status_history WHERE order_id=? ORDER BY created_at, id;`
 )
 
 type mysqlOrderRepository struct {
@@ -40,205 +75,329 @@ func NewMysqlOrderRepository(Conn *sql.DB) entity.OrderRepository {
 	return &mysqlOrderRepository{Conn: Conn}
 }
 
-func (m *mysqlOrderRepository) GetAll() ([]entity.Order, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetAll)
+func (m *mysqlOrderRepository) GetAll(ctx context.Context) ([]entity.Order, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetAll)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes, err := stmt.Query()
+	dbRes, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
+	defer dbRes.Close()
 
-	order := entity.Order{}
 	res := []entity.Order{}
 	for dbRes.Next() {
-		var totalPrice []uint8
-
-		err = dbRes.Scan(
-			&order.ID, &order.Buyer.ID, &order.Seller.ID, &order.DeliverySourceAddress,
-			&order.DeliveryDestinationAddress, &order.TotalQuantity, totalPrice, &order.Status, &order.OrderDate)
-		if err != nil {
-			panic(err.Error())
+		orderRow := entity.Order{}
+		if err := scanOrder(dbRes, &orderRow); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 		}
 
-		dP, err := decimal.NewFromString(string(totalPrice))
-		if err != nil {
-			panic(err.Error())
-		}
-		order.TotalPrice = dP
+		res = append(res, orderRow)
+	}
 
-		res = append(res, order)
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if rErr := m.loadItems(ctx, res); rErr != nil {
+		return nil, rErr
 	}
 	return res, nil
 }
 
-func (m *mysqlOrderRepository) GetByBuyerID(buyerID int64) ([]entity.Order, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetByBuyerID)
+func (m *mysqlOrderRepository) GetByBuyerID(ctx context.Context, buyerID int64, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	return m.list(ctx, "buyer_id", "seller_id", buyerID, filter)
+}
+
+func (m *mysqlOrderRepository) GetBySellerID(ctx context.Context, sellerID int64, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	return m.list(ctx, "seller_id", "buyer_id", sellerID, filter)
+}
+
+// list returns a page of the orders where userColumn is userID, the line items of every order are loaded
+// with one batched query, so a page costs two queries no matter how many orders it contains
+func (m *mysqlOrderRepository) list(ctx context.Context, userColumn, counterpartColumn string, userID int64, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	page := entity.OrderPage{Orders: []entity.Order{}, Limit: filter.Limit}
+
+	query, args, rErr := buildListQuery(userColumn, counterpartColumn, userID, filter)
+	if rErr != nil {
+		return page, rErr
+	}
+
+	stmt, err := m.Conn.PrepareContext(ctx, query)
 	if err != nil {
-		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		return page, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes, err := stmt.Query(buyerID)
+	dbRes, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
-		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		return page, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
+	defer dbRes.Close()
 
-	orderRow := entity.Order{}
-	res := []entity.Order{}
 	for dbRes.Next() {
-		var totalPrice, orderDate []uint8
-
-		// get oder for each row
-		err = dbRes.Scan(
-			&orderRow.ID, &orderRow.Buyer.ID, &orderRow.Seller.ID, &orderRow.DeliverySourceAddress,
-			&orderRow.DeliveryDestinationAddress, &orderRow.TotalQuantity, &totalPrice, &orderRow.Status, &orderDate)
-		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
-		}
-
-		dP, err := decimal.NewFromString(string(totalPrice))
-		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		orderRow := entity.Order{}
+		if err := scanOrder(dbRes, &orderRow); err != nil {
+			return page, resterrors.NewInternalServerError("error when trying to get data", err)
 		}
-		orderRow.TotalPrice = dP
 
-		vT, err := helpers.GetTimeFromUint8(orderDate)
-		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
-		}
-		orderRow.OrderDate = vT
+		page.Orders = append(page.Orders, orderRow)
+	}
 
-		// find order detail for each order
-		odRes, err := m.Conn.Query(odGetByOrderId, orderRow.ID)
-		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
-		}
+	if err = dbRes.Err(); err != nil {
+		return page, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
 
-		// scan order details result
-		odRow := entity.OrderDetail{}
-		orderRow.Items = []entity.OrderDetail{}
-		for odRes.Next() {
-			// id, order_id, product_id, quantity
-			err = odRes.Scan(&odRow.ID, &odRow.Product.ID, &odRow.Quantity)
-			if err != nil {
-				return nil, resterrors.NewInternalServerError("error when trying to get data", err)
-			}
-
-			orderRow.Items = append(orderRow.Items, odRow)
-		}
+	// one more row than the limit is selected to know whether there is a next page
+	if len(page.Orders) > filter.Limit {
+		page.Orders = page.Orders[:filter.Limit]
+		page.NextCursor = encodeCursor(filter.Sort, page.Orders[filter.Limit-1])
+	}
 
-		res = append(res, orderRow)
+	if rErr := m.loadItems(ctx, page.Orders); rErr != nil {
+		return entity.OrderPage{Orders: []entity.Order{}, Limit: filter.Limit}, rErr
 	}
-	return res, nil
+	return page, nil
 }
 
-func (m *mysqlOrderRepository) GetBySellerID(sellerID int64) ([]entity.Order, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetBySellerID)
+// GetPendingBefore returns up to limit pending orders placed before placedBefore, oldest first
+func (m *mysqlOrderRepository) GetPendingBefore(ctx context.Context, placedBefore time.Time, limit int) ([]entity.Order, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetPendingBefore)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes, err := stmt.Query(sellerID)
+	dbRes, err := stmt.QueryContext(ctx, entity.PENDING, placedBefore.Format(dateTimeLayout), limit)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
+	defer dbRes.Close()
 
-	orderRow := entity.Order{}
 	res := []entity.Order{}
 	for dbRes.Next() {
-		var totalPrice, orderDate []uint8
-
-		// get oder for each row
-		err = dbRes.Scan(
-			&orderRow.ID, &orderRow.Buyer.ID, &orderRow.Seller.ID, &orderRow.DeliverySourceAddress,
-			&orderRow.DeliveryDestinationAddress, &orderRow.TotalQuantity, &totalPrice, &orderRow.Status, &orderDate)
-		if err != nil {
+		orderRow := entity.Order{}
+		if err := scanOrder(dbRes, &orderRow); err != nil {
 			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 		}
 
-		dP, err := decimal.NewFromString(string(totalPrice))
+		res = append(res, orderRow)
+	}
+
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if rErr := m.loadItems(ctx, res); rErr != nil {
+		return nil, rErr
+	}
+	return res, nil
+}
+
+// loadItems fills Items of orders, line items come with their product
+func (m *mysqlOrderRepository) loadItems(ctx context.Context, orders []entity.Order) resterrors.RestErr {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]interface{}, len(orders))
+	orderIdx := make(map[int64]int, len(orders))
+	for idx := range orders {
+		orderIDs[idx] = orders[idx].ID
+		orderIdx[orders[idx].ID] = idx
+		orders[idx].Items = []entity.OrderDetail{}
+	}
+
+	odRes, err := m.Conn.QueryContext(ctx, fmt.Sprintf(odGetByOrderIds, mysqlutils.Placeholders(len(orderIDs))), orderIDs...)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer odRes.Close()
+
+	for odRes.Next() {
+		var orderID int64
+		var odPrice, discount, taxRate, tax, price []uint8
+		odRow := entity.OrderDetail{}
+
+		// id, order_id, quantity, order detail price, discount, tax rate, tax, product id, name, description, price, seller_id
+		err = odRes.Scan(&odRow.ID, &orderID, &odRow.Quantity, &odPrice, &discount, &taxRate, &tax, &odRow.Product.ID, &odRow.Product.Name,
+			&odRow.Product.Description, &price, &odRow.Product.Seller.ID)
 		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+			return resterrors.NewInternalServerError("error when trying to get data", err)
 		}
-		orderRow.TotalPrice = dP
 
-		vT, err := helpers.GetTimeFromUint8(orderDate)
+		dOdP, err := decimal.NewFromString(string(odPrice))
 		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+			return resterrors.NewInternalServerError("error when trying to get data", err)
 		}
-		orderRow.OrderDate = vT
+		odRow.Price = dOdP
 
-		// find order detail for each order
-		odRes, err := m.Conn.Query(odGetByOrderId, orderRow.ID)
-		if err != nil {
-			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		if odRow.Discount, err = decimal.NewFromString(string(discount)); err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if odRow.TaxRate, err = decimal.NewFromString(string(taxRate)); err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if odRow.Tax, err = decimal.NewFromString(string(tax)); err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
 		}
 
-		// scan order details result
-		odRow := entity.OrderDetail{}
-		orderRow.Items = []entity.OrderDetail{}
-		for odRes.Next() {
-			// id, order_id, product_id, quantity
-			err = odRes.Scan(&odRow.ID, &odRow.Product.ID, &odRow.Quantity)
-			if err != nil {
-				return nil, resterrors.NewInternalServerError("error when trying to get data", err)
-			}
-
-			orderRow.Items = append(orderRow.Items, odRow)
+		dP, err := decimal.NewFromString(string(price))
+		if err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
 		}
+		odRow.Product.Price = dP
 
-		res = append(res, orderRow)
+		idx := orderIdx[orderID]
+		orders[idx].Items = append(orders[idx].Items, odRow)
 	}
-	return res, nil
+
+	if err = odRes.Err(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return nil
 }
 
-func (m *mysqlOrderRepository) GetByID(order *entity.Order) (entity.Order, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetById)
+// loadStatusHistory fills StatusHistory of order, oldest change first
+func (m *mysqlOrderRepository) loadStatusHistory(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	shRes, err := m.Conn.QueryContext(ctx, shGetByOrderId, order.ID)
 	if err != nil {
-		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+		return resterrors.NewInternalServerError("error when trying to get data", err)
 	}
-	defer stmt.Close()
+	defer shRes.Close()
 
-	var totalPrice, orderDate []uint8
-	dbRes := stmt.QueryRow(order.ID)
-	if err := dbRes.Scan(&order.ID, &order.Buyer.ID, &order.Seller.ID, &order.DeliverySourceAddress,
-		&order.DeliveryDestinationAddress, &order.TotalQuantity, &totalPrice, &order.Status, &orderDate); err != nil {
-		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+	order.StatusHistory = []entity.OrderStatusHistory{}
+	for shRes.Next() {
+		var previousStatus, actorID, actorType sql.NullInt64
+		var createdAt []uint8
+		shRow := entity.OrderStatusHistory{}
+
+		err = shRes.Scan(&shRow.ID, &shRow.OrderID, &previousStatus, &shRow.Status, &actorID, &actorType, &shRow.Reason, &createdAt)
+		if err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if previousStatus.Valid {
+			ps := entity.OrderStatusEnum(previousStatus.Int64)
+			shRow.PreviousStatus = &ps
+		}
+		shRow.ActorID = actorID.Int64
+		shRow.ActorType = helpers.UserTypeEnum(actorType.Int64)
+
+		vT, err := helpers.GetTimeFromUint8(createdAt)
+		if err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		shRow.CreatedAt = vT
+
+		order.StatusHistory = append(order.StatusHistory, shRow)
+	}
+
+	if err = shRes.Err(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return nil
+}
+
+// scanOrder scans a row selected with the order columns of queryList into order,
+// extra is scanned from the columns following them
+func scanOrder(row interface{ Scan(...interface{}) error }, order *entity.Order, extra ...interface{}) error {
+	var totalPrice, orderDate, discount, shippingFee, tax []uint8
+	dest := []interface{}{&order.ID, &order.Buyer.ID, &order.Seller.ID, &order.DeliverySourceAddress,
+		&order.DeliveryDestinationAddress, &order.TotalQuantity, &totalPrice, &order.Status, &orderDate,
+		&order.ReasonCode, &order.ReasonNote, &order.Version, &order.VoucherID, &order.VoucherCode, &discount,
+		&order.ShippingService, &shippingFee, &tax, &order.TaxInclusive, &order.ShippingOrigin.Province,
+		&order.ShippingOrigin.PostalCode, &order.ShippingDestination.Province, &order.ShippingDestination.PostalCode}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
 	}
 
 	dP, err := decimal.NewFromString(string(totalPrice))
 	if err != nil {
-		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+		return err
 	}
 	order.TotalPrice = dP
 
+	dD, err := decimal.NewFromString(string(discount))
+	if err != nil {
+		return err
+	}
+	order.Discount = dD
+
+	dS, err := decimal.NewFromString(string(shippingFee))
+	if err != nil {
+		return err
+	}
+	order.ShippingFee = dS
+
+	dT, err := decimal.NewFromString(string(tax))
+	if err != nil {
+		return err
+	}
+	order.Tax = dT
+
 	vT, err := helpers.GetTimeFromUint8(orderDate)
 	if err != nil {
-		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+		return err
 	}
 	order.OrderDate = vT
 
+	return nil
+}
+
+func (m *mysqlOrderRepository) GetByID(ctx context.Context, order *entity.Order) (entity.Order, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetById)
+	if err != nil {
+		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer stmt.Close()
+
+	// buyer and seller summaries come with the order
+	dbRes := stmt.QueryRowContext(ctx, order.ID)
+	err = scanOrder(dbRes, order, &order.Buyer.Name, &order.Buyer.Email, &order.Seller.Name, &order.Seller.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *order, resterrors.NewNotFoundError(fmt.Sprintf("order with id %d not found", order.ID))
+		}
+		return *order, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	orders := []entity.Order{*order}
+	if rErr := m.loadItems(ctx, orders); rErr != nil {
+		return *order, rErr
+	}
+	order.Items = orders[0].Items
+
+	if rErr := m.loadStatusHistory(ctx, order); rErr != nil {
+		return *order, rErr
+	}
+
 	return *order, nil
 }
 
-func (m *mysqlOrderRepository) Store(order *entity.Order) resterrors.RestErr {
+func (m *mysqlOrderRepository) Store(ctx context.Context, order *entity.Order) resterrors.RestErr {
 	// start transaction sequence
-	ctx := context.Background()
 	tx, err := m.Conn.BeginTx(ctx, nil)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 
+	var voucherID interface{}
+	if order.VoucherID != 0 {
+		voucherID = order.VoucherID
+	}
+
 	// insert order
 	dbRes, err := tx.ExecContext(
 		ctx, queryInsert,
 		order.Buyer.ID, order.Seller.ID, order.DeliverySourceAddress, order.DeliveryDestinationAddress,
-		order.TotalQuantity, []uint8(order.TotalPrice.String()), order.Status, []uint8(order.OrderDate.Format("2006-01-02 15:04:05")))
+		order.TotalQuantity, []uint8(order.TotalPrice.String()), order.Status, []uint8(order.OrderDate.Format("2006-01-02 15:04:05")),
+		voucherID, order.VoucherCode, order.Discount, order.ShippingService, order.ShippingFee, order.Tax, order.TaxInclusive,
+		order.ShippingOrigin.Province, order.ShippingOrigin.PostalCode, order.ShippingDestination.Province,
+		order.ShippingDestination.PostalCode)
 	if err != nil {
 		tx.Rollback()
 		return resterrors.NewInternalServerError("error when trying to save data", err)
@@ -250,12 +409,13 @@ func (m *mysqlOrderRepository) Store(order *entity.Order) resterrors.RestErr {
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 	order.ID = orderID
+	order.Version = 1
 
 	// insert order details
 	for idx, od := range order.Items {
 		odRes, err := tx.ExecContext(
 			ctx, odInsert,
-			orderID, od.Product.ID, od.Quantity)
+			orderID, od.Product.ID, od.Quantity, od.Price, od.Discount, od.TaxRate, od.Tax)
 		if err != nil {
 			tx.Rollback()
 			return resterrors.NewInternalServerError("error when trying to save data", err)
@@ -269,6 +429,23 @@ func (m *mysqlOrderRepository) Store(order *entity.Order) resterrors.RestErr {
 		order.Items[idx].ID = odID
 	}
 
+	// claim the voucher
+	if order.VoucherID != 0 {
+		if rErr := claimVoucher(ctx, tx, order); rErr != nil {
+			tx.Rollback()
+			return rErr
+		}
+	}
+
+	// insert status history
+	for idx := range order.StatusHistory {
+		order.StatusHistory[idx].OrderID = orderID
+		if rErr := insertStatusHistory(ctx, tx, &order.StatusHistory[idx]); rErr != nil {
+			tx.Rollback()
+			return rErr
+		}
+	}
+
 	// commit the change if all queries ran successfully
 	err = tx.Commit()
 	if err != nil {
@@ -277,31 +454,148 @@ func (m *mysqlOrderRepository) Store(order *entity.Order) resterrors.RestErr {
 	return nil
 }
 
-func (m *mysqlOrderRepository) Update(order *entity.Order) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryUpdate)
+func (m *mysqlOrderRepository) Update(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryUpdate)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
 	defer stmt.Close()
 
-	//buyer_id, seller_id, delivery_source_address, delivery_destination_address,total_quantity, total_price, status, order_date
-	_, err = stmt.Exec(order.Buyer.ID, order.Seller.ID, order.DeliverySourceAddress,
-		order.DeliveryDestinationAddress, order.TotalQuantity, order.TotalPrice, order.Status, order.OrderDate, order.ID)
+	//buyer_id, seller_id, delivery_source_address, delivery_destination_address,total_quantity, total_price, status, order_date,
+	//reason_code, reason_note
+	dbRes, err := stmt.ExecContext(ctx, order.Buyer.ID, order.Seller.ID, order.DeliverySourceAddress,
+		order.DeliveryDestinationAddress, order.TotalQuantity, order.TotalPrice, order.Status, order.OrderDate,
+		order.ReasonCode, order.ReasonNote, order.ID, order.Version)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	if rErr := checkVersionUpdated(dbRes, order); rErr != nil {
+		return rErr
+	}
+	order.Version++
+	return nil
+}
+
+// checkVersionUpdated reports a conflict when an update matched no row, the order was changed
+// or deleted since it was read
+func checkVersionUpdated(dbRes sql.Result, order *entity.Order) resterrors.RestErr {
+	affected, err := dbRes.RowsAffected()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	if affected == 0 {
+		return resterrors.NewConflictError(fmt.Sprintf("order with id %d was changed by another request", order.ID))
+	}
+	return nil
+}
+
+func (m *mysqlOrderRepository) UpdateStatus(ctx context.Context, order *entity.Order, change entity.OrderStatusHistory) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	dbRes, err := tx.ExecContext(ctx, queryUpdateStatus, order.Status, order.ReasonCode, order.ReasonNote, order.ID, order.Version)
 	if err != nil {
+		tx.Rollback()
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
+
+	if rErr := checkVersionUpdated(dbRes, order); rErr != nil {
+		tx.Rollback()
+		return rErr
+	}
+
+	change.OrderID = order.ID
+	if rErr := insertStatusHistory(ctx, tx, &change); rErr != nil {
+		tx.Rollback()
+		return rErr
+	}
+
+	// commit the change if all queries ran successfully
+	err = tx.Commit()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	order.Version++
+	order.StatusHistory = append(order.StatusHistory, change)
+	return nil
+}
+
+// claimVoucher uses the voucher of order within tx, it fails with a conflict when the voucher has no uses left
+// or the buyer used it as often as allowed
+func claimVoucher(ctx context.Context, tx *sql.Tx, order *entity.Order) resterrors.RestErr {
+	vRes, err := tx.ExecContext(ctx, vClaim, order.VoucherID)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	affected, err := vRes.RowsAffected()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	if affected == 0 {
+		return resterrors.NewConflictError(fmt.Sprintf("voucher %s has been used up", order.VoucherCode))
+	}
+
+	var perBuyerLimit, used int64
+	if err := tx.QueryRowContext(ctx, vuCountByBuyer, order.Buyer.ID, order.VoucherID).Scan(&perBuyerLimit, &used); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	if perBuyerLimit > 0 && used >= perBuyerLimit {
+		return resterrors.NewConflictError(fmt.Sprintf("voucher %s can't be used again", order.VoucherCode))
+	}
+
+	_, err = tx.ExecContext(ctx, vuInsert, order.VoucherID, order.ID, order.Buyer.ID, order.Discount,
+		[]uint8(order.OrderDate.Format(dateTimeLayout)))
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
 	return nil
 }
 
-func (m *mysqlOrderRepository) Delete(order *entity.Order) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryDelete)
+// insertStatusHistory inserts change within tx and sets its ID, actor columns are left NULL when ActorID is 0
+func insertStatusHistory(ctx context.Context, tx *sql.Tx, change *entity.OrderStatusHistory) resterrors.RestErr {
+	var previousStatus, actorID, actorType interface{}
+	if change.PreviousStatus != nil {
+		previousStatus = *change.PreviousStatus
+	}
+	if change.ActorID != 0 {
+		actorID = change.ActorID
+		actorType = change.ActorType
+	}
+
+	shRes, err := tx.ExecContext(ctx, shInsert, change.OrderID, previousStatus, change.Status, actorID, actorType,
+		change.Reason, []uint8(change.CreatedAt.Format(dateTimeLayout)))
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	shID, err := shRes.LastInsertId()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	change.ID = shID
+	return nil
+}
+
+func (m *mysqlOrderRepository) Delete(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryDelete)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
 	defer stmt.Close()
 
-	if _, err = stmt.Exec(order.ID); err != nil {
+	dbRes, err := stmt.ExecContext(ctx, order.ID)
+	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("order with id %d not found", order.ID))
+	}
 	return nil
 }
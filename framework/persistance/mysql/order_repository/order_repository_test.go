@@ -1,12 +1,19 @@
 package orderrepo_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	orderrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/order_repository"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
@@ -14,6 +21,39 @@ import (
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
+const odGetByOrderIds = `SELECT od.id, od.order_id, od.quantity, od.price, od.discount, od.tax_rate, od.tax, p.id, p.name, COALESCE(p.description, ''), p.price, p.seller_id 
+	FROM order_details od JOIN products p ON p.id = od.product_id WHERE od.order_id IN (%s) ORDER BY od.id;`
+
+const shInsert = `INSERT INTO order_status_history(order_id, previous_status, status, actor_id, actor_type, reason, created_at) 
+	VALUES(?, ?, ?, ?, ?, NULLIF(?, ''), ?);`
+
+const queryInsert = `INSERT INTO orders(buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+		total_quantity, total_price, status, order_date, voucher_id, voucher_code, discount, shipping_service, shipping_fee, 
+		tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+		shipping_destination_postal_code) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?);`
+
+const (
+	vClaim         = "UPDATE vouchers SET used_count=used_count+1 WHERE id=? AND (usage_limit=0 OR used_count<usage_limit);"
+	vuCountByBuyer = `SELECT v.per_buyer_limit, (SELECT COUNT(*) FROM voucher_usages vu WHERE vu.voucher_id=v.id AND vu.buyer_id=?) 
+	FROM vouchers v WHERE v.id=?;`
+	vuInsert = "INSERT INTO voucher_usages(voucher_id, order_id, buyer_id, discount, created_at) VALUES(?, ?, ?, ?, ?);"
+)
+
+const queryGetById = `SELECT o.id, o.buyer_id, o.seller_id, o.delivery_source_address, o.delivery_destination_address, 
+	o.total_quantity, o.total_price, o.status, o.order_date, COALESCE(o.reason_code, ''), COALESCE(o.reason_note, ''), 
+	o.version, COALESCE(o.voucher_id, 0), COALESCE(o.voucher_code, ''), o.discount, 
+	COALESCE(o.shipping_service, ''), o.shipping_fee, o.tax, o.tax_inclusive, o.shipping_origin_province, 
+	o.shipping_origin_postal_code, o.shipping_destination_province, o.shipping_destination_postal_code, b.name, b.email, s.name, s.email 
+	FROM orders o JOIN buyers b ON b.id = o.buyer_id JOIN sellers s ON s.id = o.seller_id WHERE o.id=?;`
+
+var (
+	orderColumns = []string{"id", "buyer_id", "seller_id", "delivery_source_address",
+		"delivery_destination_address", "total_quantity", "total_price", "status", "order_date", "reason_code", "reason_note", "version",
+		"voucher_id", "voucher_code", "discount", "shipping_service", "shipping_fee", "tax", "tax_inclusive",
+		"shipping_origin_province", "shipping_origin_postal_code", "shipping_destination_province", "shipping_destination_postal_code"}
+	orderDetailColumns = []string{"id", "order_id", "quantity", "order_detail_price", "discount", "tax_rate", "tax", "product_id", "name", "description", "price", "seller_id"}
+)
+
 type TestSuite struct {
 	suite.Suite
 	db                   *sql.DB
@@ -28,6 +68,7 @@ type TestSuite struct {
 	expectedBuyer1       entity.Buyer
 	price                []uint8
 	time                 []uint8
+	filter               entity.OrderFilter
 }
 
 // before each test
@@ -70,6 +111,14 @@ func (suite *TestSuite) SetupTest() {
 
 	t, err := helpers.GetTimeNow()
 	suite.NoError(err)
+	suite.expectedOrderDetail1 = entity.OrderDetail{
+		ID:       1,
+		Product:  suite.expectedProduct1,
+		Quantity: 10,
+		Price:    decimal.NewFromFloat(181818.11),
+		TaxRate:  decimal.RequireFromString("0.11"),
+		Tax:      decimal.RequireFromString("199999.92"),
+	}
 	suite.expectedOrder1 = entity.Order{
 		ID:                         1,
 		Buyer:                      suite.expectedBuyer1,
@@ -85,13 +134,9 @@ func (suite *TestSuite) SetupTest() {
 		},
 	}
 
-	suite.expectedOrderDetail1 = entity.OrderDetail{
-		ID:       1,
-		Product:  suite.expectedProduct1,
-		Quantity: 10,
-	}
 	suite.price = []uint8("181818.11")
 	suite.time = []uint8(helpers.GetStringTimeNow())
+	suite.filter = entity.OrderFilter{Sort: entity.SORT_ORDER_DATE_DESC, Limit: 20}
 }
 
 func TestOrderRepo(t *testing.T) {
@@ -100,43 +145,326 @@ func TestOrderRepo(t *testing.T) {
 
 func (suite *TestSuite) TestGetByBuyerID() {
 	queryGetByBuyerID := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-	total_quantity, total_price, status, order_date FROM orders WHERE buyer_id=?;`
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE buyer_id=? ORDER BY order_date DESC, id DESC LIMIT ?;`
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetByBuyerID))
 
-	row1 := sqlmock.NewRows([]string{"id", "buyer_id", "seller_id", "delivery_source_address",
-		"delivery_destination_address", "total_quantity", "total_price", "status", "order_date"}).
+	row1 := sqlmock.NewRows(orderColumns).
+		AddRow(suite.expectedOrder1.ID, suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID,
+			suite.expectedOrder1.DeliverySourceAddress, suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
+			suite.price, suite.expectedOrder1.Status, suite.time, "", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "",
+		)
+	prep.ExpectQuery().WithArgs(suite.expectedOrder1.Buyer.ID, 21).WillReturnRows(row1)
+
+	expect := suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?")))
+	row2 := sqlmock.NewRows(orderDetailColumns).
+		AddRow(suite.expectedOrderDetail1.ID, suite.expectedOrder1.ID, suite.expectedOrderDetail1.Quantity, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"),
+			suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID)
+	expect.WithArgs(suite.expectedOrder1.ID).WillReturnRows(row2)
+
+	res, repoErr := suite.repo.GetByBuyerID(context.Background(), suite.expectedOrder1.Buyer.ID, suite.filter)
+	suite.NoError(repoErr)
+	suite.Len(res.Orders, 1)
+	suite.Empty(res.NextCursor)
+	suite.Len(res.Orders[0].Items, 1)
+	suite.Equal(suite.expectedProduct1.Name, res.Orders[0].Items[0].Product.Name)
+	suite.True(suite.expectedProduct1.Price.Equal(res.Orders[0].Items[0].Product.Price))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetBySellerID() {
+	queryGetBySellerID := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE seller_id=? ORDER BY order_date DESC, id DESC LIMIT ?;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetBySellerID))
+
+	rows := sqlmock.NewRows(orderColumns)
+	for id := int64(1); id <= 2; id++ {
+		rows.AddRow(id, suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID,
+			suite.expectedOrder1.DeliverySourceAddress, suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
+			suite.price, suite.expectedOrder1.Status, suite.time, "", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "",
+		)
+	}
+	prep.ExpectQuery().WithArgs(suite.expectedOrder1.Seller.ID, 21).WillReturnRows(rows)
+
+	// line items of both orders are loaded with a single query
+	expect := suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?, ?")))
+	odRows := sqlmock.NewRows(orderDetailColumns).
+		AddRow(1, 1, 10, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"), suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID).
+		AddRow(2, 2, 5, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"), suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID).
+		AddRow(3, 2, 1, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"), suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID)
+	expect.WithArgs(1, 2).WillReturnRows(odRows)
+
+	res, repoErr := suite.repo.GetBySellerID(context.Background(), suite.expectedOrder1.Seller.ID, suite.filter)
+	suite.NoError(repoErr)
+	suite.Len(res.Orders, 2)
+	suite.Len(res.Orders[0].Items, 1)
+	suite.Len(res.Orders[1].Items, 2)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByBuyerIDWithFilters() {
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	minTotal := decimal.NewFromInt(100)
+	maxTotal := decimal.NewFromInt(200000)
+	suite.filter.Statuses = []entity.OrderStatusEnum{entity.PENDING, entity.ACCEPTED}
+	suite.filter.From = from
+	suite.filter.To = to
+	suite.filter.CounterpartID = suite.expectedSeller1.ID
+	suite.filter.MinTotal = &minTotal
+	suite.filter.MaxTotal = &maxTotal
+	suite.filter.Sort = entity.SORT_TOTAL_PRICE_ASC
+
+	queryGetByBuyerID := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE buyer_id=? AND status IN (?, ?) 
+	AND order_date>=? AND order_date<? AND seller_id=? AND total_price>=? AND total_price<=? 
+	ORDER BY total_price ASC, id ASC LIMIT ?;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(strings.Join(strings.Fields(queryGetByBuyerID), " ")))
+	prep.ExpectQuery().
+		WithArgs(suite.expectedBuyer1.ID, entity.PENDING, entity.ACCEPTED, "2021-05-01 00:00:00", "2021-06-01 00:00:00",
+			suite.expectedSeller1.ID, "100", "200000", 21).
+		WillReturnRows(sqlmock.NewRows(orderColumns))
+
+	res, repoErr := suite.repo.GetByBuyerID(context.Background(), suite.expectedBuyer1.ID, suite.filter)
+	suite.NoError(repoErr)
+	suite.Empty(res.Orders)
+	suite.Empty(res.NextCursor)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByBuyerIDCursor() {
+	suite.filter.Limit = 1
+
+	// the first page selects one extra order to know there is a next page
+	queryFirstPage := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE buyer_id=? ORDER BY order_date DESC, id DESC LIMIT ?;`
+	rows := sqlmock.NewRows(orderColumns)
+	for id := int64(2); id >= 1; id-- {
+		rows.AddRow(id, suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID,
+			suite.expectedOrder1.DeliverySourceAddress, suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
+			suite.price, suite.expectedOrder1.Status, []uint8("2021-05-02 10:00:00"), "", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "",
+		)
+	}
+	suite.mock.ExpectPrepare(regexp.QuoteMeta(queryFirstPage)).
+		ExpectQuery().WithArgs(suite.expectedBuyer1.ID, 2).WillReturnRows(rows)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?"))).
+		WithArgs(2).WillReturnRows(sqlmock.NewRows(orderDetailColumns))
+
+	res, repoErr := suite.repo.GetByBuyerID(context.Background(), suite.expectedBuyer1.ID, suite.filter)
+	suite.NoError(repoErr)
+	suite.Len(res.Orders, 1)
+	suite.Equal(int64(2), res.Orders[0].ID)
+	suite.NotEmpty(res.NextCursor)
+
+	// the next page continues after the last order of the first page
+	queryNextPage := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE buyer_id=? 
+	AND (order_date<? OR (order_date=? AND id<?)) ORDER BY order_date DESC, id DESC LIMIT ?;`
+	suite.mock.ExpectPrepare(regexp.QuoteMeta(strings.Join(strings.Fields(queryNextPage), " "))).
+		ExpectQuery().WithArgs(suite.expectedBuyer1.ID, "2021-05-02 10:00:00", "2021-05-02 10:00:00", 2, 2).
+		WillReturnRows(sqlmock.NewRows(orderColumns))
+
+	suite.filter.Cursor = res.NextCursor
+	res, repoErr = suite.repo.GetByBuyerID(context.Background(), suite.expectedBuyer1.ID, suite.filter)
+	suite.NoError(repoErr)
+	suite.Empty(res.Orders)
+	suite.Empty(res.NextCursor)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByBuyerIDInvalidCursor() {
+	suite.Run("malformed cursor", func() {
+		suite.filter.Cursor = "not a cursor"
+
+		_, repoErr := suite.repo.GetByBuyerID(context.Background(), suite.expectedBuyer1.ID, suite.filter)
+		suite.Error(repoErr)
+		suite.Equal(http.StatusBadRequest, repoErr.Status())
+	})
+
+	suite.Run("cursor of another sort", func() {
+		// {"s":"total_price","v":"100","id":1}
+		suite.filter.Cursor = "eyJzIjoidG90YWxfcHJpY2UiLCJ2IjoiMTAwIiwiaWQiOjF9"
+
+		_, repoErr := suite.repo.GetByBuyerID(context.Background(), suite.expectedBuyer1.ID, suite.filter)
+		suite.Error(repoErr)
+		suite.Equal(http.StatusBadRequest, repoErr.Status())
+	})
+
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByID() {
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
+	row := sqlmock.NewRows(append(orderColumns, "buyer_name", "buyer_email", "seller_name", "seller_email")).
 		AddRow(suite.expectedOrder1.ID, suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID,
 			suite.expectedOrder1.DeliverySourceAddress, suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
-			suite.price, suite.expectedOrder1.Status, suite.time,
+			suite.price, entity.ACCEPTED, suite.time, "", "", 1, 0, "", []uint8("0.00"), "JNE_REG", []uint8("18000.00"), []uint8("199999.92"), false, "DKI Jakarta", "12190", "Jawa Barat", "",
+			suite.expectedBuyer1.Name, suite.expectedBuyer1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.Email,
 		)
-	prep.ExpectQuery().WillReturnRows(row1)
+	prep.ExpectQuery().WithArgs(suite.expectedOrder1.ID).WillReturnRows(row)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?"))).
+		WithArgs(suite.expectedOrder1.ID).
+		WillReturnRows(sqlmock.NewRows(orderDetailColumns).
+			AddRow(suite.expectedOrderDetail1.ID, suite.expectedOrder1.ID, suite.expectedOrderDetail1.Quantity, suite.price, []uint8("18181.81"), []uint8("0.1100"), []uint8("199999.92"),
+				suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID))
+
+	shGetByOrderId := `SELECT id, order_id, previous_status, status, actor_id, actor_type, COALESCE(reason, ''), created_at
+	FROM order_status_history WHERE order_id=? ORDER BY created_at, id;`
+	suite.mock.ExpectQuery(regexp.QuoteMeta(shGetByOrderId)).
+		WithArgs(suite.expectedOrder1.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "previous_status", "status", "actor_id", "actor_type", "reason", "created_at"}).
+			AddRow(1, suite.expectedOrder1.ID, nil, entity.PENDING, suite.expectedBuyer1.ID, helpers.BUYER_TYPE, "", suite.time).
+			AddRow(2, suite.expectedOrder1.ID, entity.PENDING, entity.ACCEPTED, suite.expectedSeller1.ID, helpers.SELLER_TYPE, "", suite.time))
 
-	odGetByOrderId := `SELECT id, product_id, quantity FROM order_details WHERE order_id=?;`
-	expect := suite.mock.ExpectQuery(regexp.QuoteMeta(odGetByOrderId))
-	row2 := sqlmock.NewRows([]string{"id", "product_id", "quantity"}).
-		AddRow(suite.expectedOrderDetail1.ID, suite.expectedOrderDetail1.Product.ID, suite.expectedOrderDetail1.Quantity)
-	expect.WillReturnRows(row2)
+	order := new(entity.Order)
+	order.ID = suite.expectedOrder1.ID
 
-	res, repoErr := suite.repo.GetByBuyerID(suite.expectedOrder1.Buyer.ID)
+	res, repoErr := suite.repo.GetByID(context.Background(), order)
 	suite.NoError(repoErr)
-	suite.NotNil(res)
+	suite.Equal(entity.ACCEPTED, res.Status)
+	suite.Equal("JNE_REG", res.ShippingService)
+	suite.True(decimal.NewFromInt(18000).Equal(res.ShippingFee))
+	suite.Equal(entity.ShippingAddress{Province: "DKI Jakarta", PostalCode: "12190"}, res.ShippingOrigin)
+	suite.Equal(entity.ShippingAddress{Province: "Jawa Barat"}, res.ShippingDestination)
+	suite.True(suite.expectedOrderDetail1.Tax.Equal(res.Tax))
+	suite.False(res.TaxInclusive)
+	suite.Equal(suite.expectedBuyer1.Email, res.Buyer.Email)
+	suite.Equal(suite.expectedSeller1.Name, res.Seller.Name)
+	suite.Equal(int64(1), res.Version)
+	suite.Len(res.Items, 1)
+	suite.Equal(suite.expectedProduct1.Name, res.Items[0].Product.Name)
+	suite.True(decimal.RequireFromString("18181.81").Equal(res.Items[0].Discount))
+	suite.True(suite.expectedOrderDetail1.TaxRate.Equal(res.Items[0].TaxRate))
+	suite.True(suite.expectedOrderDetail1.Tax.Equal(res.Items[0].Tax))
+
+	suite.Len(res.StatusHistory, 2)
+	suite.Nil(res.StatusHistory[0].PreviousStatus)
+	suite.Equal(entity.PENDING, *res.StatusHistory[1].PreviousStatus)
+	suite.Equal(suite.expectedSeller1.ID, res.StatusHistory[1].ActorID)
+	suite.Equal(helpers.SELLER_TYPE, res.StatusHistory[1].ActorType)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetAll() {
+	queryGetAll := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders ORDER BY id;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetAll))
+
+	rows := sqlmock.NewRows(orderColumns).
+		AddRow(1, 1, 1, "pickup address", "sending address", 10, suite.price, entity.PENDING, suite.time,
+			"", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "").
+		AddRow(2, 1, 1, "pickup address", "sending address", 1, []uint8("50000.50"), entity.CANCELLED, suite.time,
+			"CHANGED_MIND", "", 2, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "")
+	prep.ExpectQuery().WillReturnRows(rows)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?, ?"))).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows(orderDetailColumns).
+			AddRow(1, 1, 10, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"), 1, "product1", "desc", suite.price, 1).
+			AddRow(2, 2, 1, []uint8("50000.50"), []uint8("0.00"), []uint8("0.1100"), []uint8("5500.06"), 2, "product2", "desc", []uint8("50000.50"), 1))
+
+	res, repoErr := suite.repo.GetAll(context.Background())
+	suite.NoError(repoErr)
+	suite.Len(res, 2)
+	suite.True(decimal.RequireFromString("181818.11").Equal(res[0].TotalPrice))
+	suite.True(decimal.RequireFromString("50000.50").Equal(res[1].TotalPrice))
+	suite.Equal(entity.REASON_CHANGED_MIND, res[1].ReasonCode)
+	suite.Len(res[0].Items, 1)
+	suite.Len(res[1].Items, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetAllInvalidRow() {
+	prep := suite.mock.ExpectPrepare("SELECT (.+) FROM orders ORDER BY id;")
+	rows := sqlmock.NewRows(orderColumns).
+		AddRow(1, 1, 1, "pickup address", "sending address", 10, []uint8("not a price"), entity.PENDING, suite.time,
+			"", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "")
+	prep.ExpectQuery().WillReturnRows(rows)
+
+	res, repoErr := suite.repo.GetAll(context.Background())
+	suite.Nil(res)
+	suite.Error(repoErr)
+	suite.Equal(http.StatusInternalServerError, repoErr.Status())
+}
+
+func (suite *TestSuite) TestGetPendingBefore() {
+	queryGetPendingBefore := `SELECT id, buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
+	total_quantity, total_price, status, order_date, COALESCE(reason_code, ''), COALESCE(reason_note, ''), version, 
+	COALESCE(voucher_id, 0), COALESCE(voucher_code, ''), discount, COALESCE(shipping_service, ''), shipping_fee, 
+	tax, tax_inclusive, shipping_origin_province, shipping_origin_postal_code, shipping_destination_province, 
+	shipping_destination_postal_code FROM orders WHERE status=? AND order_date<? ORDER BY order_date, id LIMIT ?;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetPendingBefore))
+
+	rows := sqlmock.NewRows(orderColumns).
+		AddRow(suite.expectedOrder1.ID, suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID,
+			suite.expectedOrder1.DeliverySourceAddress, suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
+			suite.price, entity.PENDING, []uint8("2021-05-01 09:00:00"), "", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "",
+		)
+	prep.ExpectQuery().WithArgs(entity.PENDING, "2021-05-01 10:00:00", 100).WillReturnRows(rows)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, "?"))).
+		WithArgs(suite.expectedOrder1.ID).
+		WillReturnRows(sqlmock.NewRows(orderDetailColumns).
+			AddRow(suite.expectedOrderDetail1.ID, suite.expectedOrder1.ID, suite.expectedOrderDetail1.Quantity, suite.price, []uint8("0.00"), []uint8("0.1100"), []uint8("199999.92"),
+				suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID))
+
+	placedBefore := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	res, repoErr := suite.repo.GetPendingBefore(context.Background(), placedBefore, 100)
+	suite.NoError(repoErr)
+	suite.Len(res, 1)
+	suite.Len(res[0].Items, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByIDNotFound() {
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
+	prep.ExpectQuery().WithArgs(suite.expectedOrder1.ID).WillReturnError(sql.ErrNoRows)
+
+	order := new(entity.Order)
+	order.ID = suite.expectedOrder1.ID
+
+	_, repoErr := suite.repo.GetByID(context.Background(), order)
+	suite.True(errors.Is(repoErr, resterrors.ErrNotFound))
+	suite.Equal(http.StatusNotFound, repoErr.Status())
 }
 
 func (suite *TestSuite) TestStore() {
-	queryInsert := `INSERT INTO orders(buyer_id, seller_id, delivery_source_address, delivery_destination_address, 
-		total_quantity, total_price, status, order_date) VALUES(?, ?, ?, ?, ?, ?, ?, ?);`
-	odInsert := `INSERT INTO order_details(order_id, product_id, quantity) VALUES(?, ?, ?);`
+	odInsert := `INSERT INTO order_details(order_id, product_id, quantity, price, discount, tax_rate, tax) VALUES(?, ?, ?, ?, ?, ?, ?);`
 
 	suite.mock.ExpectBegin()
 	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
 		WithArgs(suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID, suite.expectedOrder1.DeliverySourceAddress,
 			suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
-			suite.price, suite.expectedOrder1.Status, suite.time).
+			suite.price, suite.expectedOrder1.Status, suite.time, nil, "", decimal.Decimal{}, "", decimal.Decimal{},
+			suite.expectedOrderDetail1.Tax, false, "DKI Jakarta", "12190", "Jawa Barat", "").
 		WillReturnResult(sqlmock.NewResult(suite.expectedOrder1.ID, 1))
 
 	suite.mock.ExpectExec(regexp.QuoteMeta(odInsert)).
-		WithArgs(suite.expectedOrder1.ID, suite.expectedOrderDetail1.Product.ID, 10).
+		WithArgs(suite.expectedOrder1.ID, suite.expectedOrderDetail1.Product.ID, 10, suite.expectedOrderDetail1.Price,
+			suite.expectedOrderDetail1.Discount, suite.expectedOrderDetail1.TaxRate, suite.expectedOrderDetail1.Tax).
 		WillReturnResult(sqlmock.NewResult(suite.expectedOrderDetail1.ID, 1))
+
+	suite.mock.ExpectExec(regexp.QuoteMeta(shInsert)).
+		WithArgs(suite.expectedOrder1.ID, nil, entity.PENDING, suite.expectedBuyer1.ID, helpers.BUYER_TYPE, "", suite.time).
+		WillReturnResult(sqlmock.NewResult(7, 1))
 	suite.mock.ExpectCommit()
 
 	order := new(entity.Order)
@@ -149,22 +477,104 @@ func (suite *TestSuite) TestStore() {
 	order.Status = suite.expectedOrder1.Status
 	order.OrderDate = suite.expectedOrder1.OrderDate
 	order.Items = []entity.OrderDetail{suite.expectedOrderDetail1}
+	order.Tax = suite.expectedOrderDetail1.Tax
+	order.ShippingOrigin = entity.ShippingAddress{Province: "DKI Jakarta", PostalCode: "12190"}
+	order.ShippingDestination = entity.ShippingAddress{Province: "Jawa Barat"}
+	order.StatusHistory = []entity.OrderStatusHistory{{
+		Status:    entity.PENDING,
+		ActorID:   suite.expectedBuyer1.ID,
+		ActorType: helpers.BUYER_TYPE,
+		CreatedAt: suite.expectedOrder1.OrderDate,
+	}}
 
-	repoErr := suite.repo.Store(order)
+	repoErr := suite.repo.Store(context.Background(), order)
 
 	suite.NoError(repoErr)
-	suite.NotNil(order)
+	suite.Equal(int64(1), order.Version)
+	suite.Equal(suite.expectedOrder1.ID, order.StatusHistory[0].OrderID)
+	suite.Equal(int64(7), order.StatusHistory[0].ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+// voucherOrder returns the order of TestStore with a voucher taking 10% off
+func (suite *TestSuite) voucherOrder() *entity.Order {
+	order := new(entity.Order)
+	order.Buyer = suite.expectedBuyer1
+	order.Seller = suite.expectedSeller1
+	order.TotalQuantity = suite.expectedOrder1.TotalQuantity
+	order.TotalPrice = decimal.RequireFromString("163636.30")
+	order.Status = entity.PENDING
+	order.OrderDate = suite.expectedOrder1.OrderDate
+	order.VoucherID = 3
+	order.VoucherCode = "HEMAT10"
+	order.Discount = decimal.RequireFromString("18181.81")
+	return order
+}
+
+func (suite *TestSuite) TestStoreWithVoucher() {
+	order := suite.voucherOrder()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(suite.expectedBuyer1.ID, suite.expectedSeller1.ID, "", "", suite.expectedOrder1.TotalQuantity,
+			[]uint8("163636.3"), entity.PENDING, suite.time, int64(3), "HEMAT10", order.Discount, "", decimal.Decimal{}, decimal.Decimal{}, false,
+			"", "", "", "").
+		WillReturnResult(sqlmock.NewResult(suite.expectedOrder1.ID, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(vClaim)).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(vuCountByBuyer)).
+		WithArgs(suite.expectedBuyer1.ID, int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"per_buyer_limit", "used"}).AddRow(2, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(vuInsert)).
+		WithArgs(int64(3), suite.expectedOrder1.ID, suite.expectedBuyer1.ID, order.Discount, suite.time).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Store(context.Background(), order)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreVoucherUsedUp() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).WillReturnResult(sqlmock.NewResult(suite.expectedOrder1.ID, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(vClaim)).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Store(context.Background(), suite.voucherOrder())
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreVoucherBuyerLimitReached() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).WillReturnResult(sqlmock.NewResult(suite.expectedOrder1.ID, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(vClaim)).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(vuCountByBuyer)).
+		WithArgs(suite.expectedBuyer1.ID, int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"per_buyer_limit", "used"}).AddRow(1, 1))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Store(context.Background(), suite.voucherOrder())
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
 }
 
 func (suite *TestSuite) TestUpdate() {
 	queryUpdate := `UPDATE orders SET buyer_id=?, seller_id=?, delivery_source_address=?, delivery_destination_address=?, 
-	total_quantity=?, total_price=?, status=?, order_date=? WHERE id=?;`
+	total_quantity=?, total_price=?, status=?, order_date=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), 
+	version=version+1 WHERE id=? AND version=?;`
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdate))
 
 	prep.ExpectExec().
 		WithArgs(suite.expectedOrder1.Buyer.ID, suite.expectedOrder1.Seller.ID, suite.expectedOrder1.DeliverySourceAddress,
 			suite.expectedOrder1.DeliveryDestinationAddress, suite.expectedOrder1.TotalQuantity,
-			suite.expectedOrder1.TotalPrice, suite.expectedOrder1.Status, suite.expectedOrder1.OrderDate, suite.expectedOrder1.ID).
+			suite.expectedOrder1.TotalPrice, entity.REJECTED, suite.expectedOrder1.OrderDate,
+			"OUT_OF_STOCK", "", suite.expectedOrder1.ID, 2).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	order := new(entity.Order)
@@ -175,11 +585,190 @@ func (suite *TestSuite) TestUpdate() {
 	order.DeliveryDestinationAddress = suite.expectedOrder1.DeliveryDestinationAddress
 	order.TotalQuantity = suite.expectedOrder1.TotalQuantity
 	order.TotalPrice = suite.expectedOrder1.TotalPrice
-	order.Status = suite.expectedOrder1.Status
+	order.Status = entity.REJECTED
 	order.OrderDate = suite.expectedOrder1.OrderDate
+	order.ReasonCode = entity.REASON_OUT_OF_STOCK
+	order.Version = 2
 	order.Items = []entity.OrderDetail{suite.expectedOrderDetail1}
 
-	repoErr := suite.repo.Update(order)
+	repoErr := suite.repo.Update(context.Background(), order)
 	suite.NoError(repoErr)
-	suite.NotNil(order)
+	suite.Equal(int64(3), order.Version)
+}
+
+func (suite *TestSuite) TestUpdateVersionConflict() {
+	queryUpdate := `UPDATE orders SET buyer_id=?, seller_id=?, delivery_source_address=?, delivery_destination_address=?, 
+	total_quantity=?, total_price=?, status=?, order_date=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), 
+	version=version+1 WHERE id=? AND version=?;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdate))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+
+	order := suite.expectedOrder1
+	order.Version = 1
+	repoErr := suite.repo.Update(context.Background(), &order)
+
+	suite.Error(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.Equal(int64(1), order.Version)
+}
+
+func (suite *TestSuite) TestUpdateStatus() {
+	queryUpdateStatus := `UPDATE orders SET status=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), version=version+1 
+	WHERE id=? AND version=?;`
+	previousStatus := entity.PENDING
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WithArgs(entity.REJECTED, "OUT_OF_STOCK", "", suite.expectedOrder1.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(shInsert)).
+		WithArgs(suite.expectedOrder1.ID, entity.PENDING, entity.REJECTED, suite.expectedSeller1.ID, helpers.SELLER_TYPE,
+			"OUT_OF_STOCK", suite.time).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	suite.mock.ExpectCommit()
+
+	order := suite.expectedOrder1
+	order.Status = entity.REJECTED
+	order.ReasonCode = entity.REASON_OUT_OF_STOCK
+	order.Version = 1
+	repoErr := suite.repo.UpdateStatus(context.Background(), &order, entity.OrderStatusHistory{
+		PreviousStatus: &previousStatus,
+		Status:         entity.REJECTED,
+		ActorID:        suite.expectedSeller1.ID,
+		ActorType:      helpers.SELLER_TYPE,
+		Reason:         "OUT_OF_STOCK",
+		CreatedAt:      suite.expectedOrder1.OrderDate,
+	})
+
+	suite.NoError(repoErr)
+	suite.Equal(int64(2), order.Version)
+	suite.Len(order.StatusHistory, 1)
+	suite.Equal(int64(3), order.StatusHistory[0].ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateStatusWithoutActor() {
+	queryUpdateStatus := `UPDATE orders SET status=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), version=version+1 
+	WHERE id=? AND version=?;`
+	previousStatus := entity.PENDING
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WithArgs(entity.CANCELLED, "EXPIRED", "", suite.expectedOrder1.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(shInsert)).
+		WithArgs(suite.expectedOrder1.ID, entity.PENDING, entity.CANCELLED, nil, nil, "EXPIRED", suite.time).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	suite.mock.ExpectCommit()
+
+	order := suite.expectedOrder1
+	order.Status = entity.CANCELLED
+	order.ReasonCode = entity.REASON_EXPIRED
+	order.Version = 1
+	repoErr := suite.repo.UpdateStatus(context.Background(), &order, entity.OrderStatusHistory{
+		PreviousStatus: &previousStatus,
+		Status:         entity.CANCELLED,
+		Reason:         "EXPIRED",
+		CreatedAt:      suite.expectedOrder1.OrderDate,
+	})
+
+	suite.NoError(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateStatusRollback() {
+	queryUpdateStatus := `UPDATE orders SET status=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), version=version+1 
+	WHERE id=? AND version=?;`
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WithArgs(entity.ACCEPTED, "", "", suite.expectedOrder1.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(shInsert)).
+		WillReturnError(errors.New("insert failed"))
+	suite.mock.ExpectRollback()
+
+	order := suite.expectedOrder1
+	order.Status = entity.ACCEPTED
+	order.Version = 1
+	repoErr := suite.repo.UpdateStatus(context.Background(), &order, entity.OrderStatusHistory{
+		Status:    entity.ACCEPTED,
+		CreatedAt: suite.expectedOrder1.OrderDate,
+	})
+
+	suite.Error(repoErr)
+	suite.Empty(order.StatusHistory)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateStatusVersionConflict() {
+	queryUpdateStatus := `UPDATE orders SET status=?, reason_code=NULLIF(?, ''), reason_note=NULLIF(?, ''), version=version+1 
+	WHERE id=? AND version=?;`
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WithArgs(entity.ACCEPTED, "", "", suite.expectedOrder1.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	order := suite.expectedOrder1
+	order.Status = entity.ACCEPTED
+	order.Version = 1
+	repoErr := suite.repo.UpdateStatus(context.Background(), &order, entity.OrderStatusHistory{
+		Status:    entity.ACCEPTED,
+		CreatedAt: suite.expectedOrder1.OrderDate,
+	})
+
+	suite.Error(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.Empty(order.StatusHistory)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+// BenchmarkGetByBuyerID lists 100 orders of 3 line items each. Loading details per order and
+// then every product one by one took 1 + 100 + 300 queries, the batched listing takes 2.
+func BenchmarkGetByBuyerID(b *testing.B) {
+	const orders, itemsPerOrder = 100, 3
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+	repo := orderrepo.NewMysqlOrderRepository(db)
+
+	price := []uint8("181818.11")
+	orderDate := []uint8(helpers.GetStringTimeNow())
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", orders), ", ")
+	filter := entity.OrderFilter{Sort: entity.SORT_ORDER_DATE_DESC, Limit: orders}
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rows := sqlmock.NewRows(orderColumns)
+		odRows := sqlmock.NewRows(orderDetailColumns)
+		for id := int64(1); id <= orders; id++ {
+			rows.AddRow(id, 1, 1, "pickup address", "sending address", itemsPerOrder, price, entity.PENDING, orderDate, "", "", 1, 0, "", []uint8("0.00"), "", []uint8("0.00"), []uint8("0.00"), false, "", "", "", "")
+			for item := int64(0); item < itemsPerOrder; item++ {
+				odRows.AddRow(id*itemsPerOrder+item, id, 1, price, []uint8("0.00"), []uint8("0.1100"), []uint8("20000.00"), item+1, "product", "desc", price, 1)
+			}
+		}
+
+		// the orders and their items take two queries, sqlmock fails on any other statement
+		mock.ExpectPrepare("SELECT (.+) FROM orders WHERE buyer_id=\\? ORDER BY (.+)").ExpectQuery().WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(odGetByOrderIds, placeholders))).WillReturnRows(odRows)
+		b.StartTimer()
+
+		res, repoErr := repo.GetByBuyerID(context.Background(), 1, filter)
+		if repoErr != nil {
+			b.Fatal(repoErr)
+		}
+		if len(res.Orders) != orders {
+			b.Fatalf("expected %d orders, got %d", orders, len(res.Orders))
+		}
+	}
+
+	b.StopTimer()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		b.Fatal(err)
+	}
 }
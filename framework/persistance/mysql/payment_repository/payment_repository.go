@@ -0,0 +1,112 @@
+package paymentrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert = `INSERT INTO payments(order_id, provider, provider_payment_id, client_secret, amount, currency, status,
+	created_at, updated_at) VALUES(?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?);`
+	queryGetByOrderId = `SELECT id, order_id, provider, provider_payment_id, COALESCE(client_secret, ''), amount, currency, status,
+	created_at, updated_at FROM payments WHERE order_id=? ORDER BY id DESC LIMIT 1;`
+	queryGetByProviderPaymentId = `SELECT id, order_id, provider, provider_payment_id, COALESCE(client_secret, ''), amount, currency,
+	status, created_at, updated_at FROM payments WHERE provider=? AND provider_payment_id=?;`
+	queryUpdateStatus = "UPDATE payments SET status=?, updated_at=? WHERE id=? AND status=?;"
+)
+
+type mysqlPaymentRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlPaymentRepository will create a object with entity.PaymentRepository interface representation
+func NewMysqlPaymentRepository(Conn *sql.DB) entity.PaymentRepository {
+	return &mysqlPaymentRepository{Conn}
+}
+
+func (m *mysqlPaymentRepository) Store(ctx context.Context, payment *entity.Payment) resterrors.RestErr {
+	dbRes, err := m.Conn.ExecContext(ctx, queryInsert, payment.OrderID, payment.Provider, payment.ProviderPaymentID,
+		payment.ClientSecret, payment.Amount, payment.Currency, payment.Status,
+		payment.CreatedAt.Format(dateTimeLayout), payment.UpdatedAt.Format(dateTimeLayout))
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	paymentID, err := dbRes.LastInsertId()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	payment.ID = paymentID
+	return nil
+}
+
+func (m *mysqlPaymentRepository) GetByOrderID(ctx context.Context, orderID int64) (entity.Payment, resterrors.RestErr) {
+	res := entity.Payment{}
+	err := scanPayment(m.Conn.QueryRowContext(ctx, queryGetByOrderId, orderID), &res)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, resterrors.NewNotFoundError(fmt.Sprintf("payment of order with id %d not found", orderID))
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlPaymentRepository) GetByProviderPaymentID(ctx context.Context, provider string, providerPaymentID string) (entity.Payment, resterrors.RestErr) {
+	res := entity.Payment{}
+	err := scanPayment(m.Conn.QueryRowContext(ctx, queryGetByProviderPaymentId, provider, providerPaymentID), &res)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, resterrors.NewNotFoundError(fmt.Sprintf("payment %s of %s not found", providerPaymentID, provider))
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlPaymentRepository) UpdateStatus(ctx context.Context, payment *entity.Payment, from entity.PaymentStatusEnum) resterrors.RestErr {
+	// the status is only changed from the one it was read with, so concurrent changes can't overwrite each other
+	dbRes, err := m.Conn.ExecContext(ctx, queryUpdateStatus, payment.Status, payment.UpdatedAt.Format(dateTimeLayout),
+		payment.ID, from)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	affected, err := dbRes.RowsAffected()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	if affected == 0 {
+		return resterrors.NewConflictError(fmt.Sprintf("payment with id %d was changed by another request", payment.ID))
+	}
+	return nil
+}
+
+func scanPayment(row *sql.Row, payment *entity.Payment) error {
+	var amount, createdAt, updatedAt []uint8
+	err := row.Scan(&payment.ID, &payment.OrderID, &payment.Provider, &payment.ProviderPaymentID, &payment.ClientSecret,
+		&amount, &payment.Currency, &payment.Status, &createdAt, &updatedAt)
+	if err != nil {
+		return err
+	}
+
+	if payment.Amount, err = decimal.NewFromString(string(amount)); err != nil {
+		return err
+	}
+	if payment.CreatedAt, err = helpers.GetTimeFromUint8(createdAt); err != nil {
+		return err
+	}
+	if payment.UpdatedAt, err = helpers.GetTimeFromUint8(updatedAt); err != nil {
+		return err
+	}
+	return nil
+}
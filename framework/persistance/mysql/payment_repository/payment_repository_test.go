@@ -0,0 +1,122 @@
+package paymentrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	paymentrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/payment_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert = `INSERT INTO payments(order_id, provider, provider_payment_id, client_secret, amount, currency, status,
+	created_at, updated_at) VALUES(?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?);`
+	queryGetByOrderId = `SELECT id, order_id, provider, provider_payment_id, COALESCE(client_secret, ''), amount, currency, status,
+	created_at, updated_at FROM payments WHERE order_id=? ORDER BY id DESC LIMIT 1;`
+	queryUpdateStatus = "UPDATE payments SET status=?, updated_at=? WHERE id=? AND status=?;"
+)
+
+type TestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	repo    entity.PaymentRepository
+	payment entity.Payment
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = paymentrepo.NewMysqlPaymentRepository(suite.db)
+	suite.payment = entity.Payment{
+		OrderID:           1,
+		Provider:          "fake",
+		ProviderPaymentID: "fake_pi_1",
+		ClientSecret:      "fake_pi_1_secret",
+		Amount:            decimal.NewFromFloat(181818.11),
+		Currency:          "IDR",
+		Status:            entity.PAYMENT_PENDING,
+		CreatedAt:         time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:         time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPaymentRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(suite.payment.OrderID, suite.payment.Provider, suite.payment.ProviderPaymentID, suite.payment.ClientSecret,
+			suite.payment.Amount, suite.payment.Currency, suite.payment.Status, "2021-05-01 10:00:00", "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	repoErr := suite.repo.Store(context.Background(), &suite.payment)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(7), suite.payment.ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(suite.payment.OrderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "provider", "provider_payment_id", "client_secret",
+			"amount", "currency", "status", "created_at", "updated_at"}).
+			AddRow(7, suite.payment.OrderID, "fake", "fake_pi_1", "fake_pi_1_secret", []uint8("181818.11"), "IDR",
+				entity.PAYMENT_AUTHORIZED, []uint8("2021-05-01 10:00:00"), []uint8("2021-05-01 10:05:00")))
+
+	res, repoErr := suite.repo.GetByOrderID(context.Background(), suite.payment.OrderID)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(7), res.ID)
+	suite.Equal(entity.PAYMENT_AUTHORIZED, res.Status)
+	suite.True(suite.payment.Amount.Equal(res.Amount))
+	suite.Equal(time.Date(2021, 5, 1, 10, 5, 0, 0, time.UTC), res.UpdatedAt)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderIDNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(suite.payment.OrderID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, repoErr := suite.repo.GetByOrderID(context.Background(), suite.payment.OrderID)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+}
+
+func (suite *TestSuite) TestUpdateStatus() {
+	suite.payment.ID = 7
+	suite.payment.Status = entity.PAYMENT_CAPTURED
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WithArgs(entity.PAYMENT_CAPTURED, "2021-05-01 10:00:00", suite.payment.ID, entity.PAYMENT_AUTHORIZED).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repoErr := suite.repo.UpdateStatus(context.Background(), &suite.payment, entity.PAYMENT_AUTHORIZED)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateStatusConflict() {
+	suite.payment.ID = 7
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateStatus)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repoErr := suite.repo.UpdateStatus(context.Background(), &suite.payment, entity.PAYMENT_AUTHORIZED)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+}
@@ -0,0 +1,108 @@
+package paymentwebhookrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert = `INSERT INTO payment_webhook_events(provider, provider_event_id, event_type, provider_payment_id, status,
+	amount, currency, payload, received_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	querySelect = `SELECT id, provider, provider_event_id, event_type, provider_payment_id, status, amount, currency, payload,
+	received_at, processed_at, COALESCE(error, '') FROM payment_webhook_events `
+	queryGetById              = querySelect + "WHERE id=?;"
+	queryGetByProviderEventId = querySelect + "WHERE provider=? AND provider_event_id=?;"
+	queryUpdateResult         = "UPDATE payment_webhook_events SET processed_at=?, error=NULLIF(?, '') WHERE id=?;"
+)
+
+type mysqlPaymentWebhookEventRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlPaymentWebhookEventRepository will create a object with entity.PaymentWebhookEventRepository interface representation
+func NewMysqlPaymentWebhookEventRepository(Conn *sql.DB) entity.PaymentWebhookEventRepository {
+	return &mysqlPaymentWebhookEventRepository{Conn}
+}
+
+func (m *mysqlPaymentWebhookEventRepository) Store(ctx context.Context, event *entity.PaymentWebhookEvent) (entity.PaymentWebhookEvent, bool, resterrors.RestErr) {
+	// the unique key on provider and provider_event_id keeps an event from being saved twice
+	dbRes, err := m.Conn.ExecContext(ctx, queryInsert, event.Provider, event.ProviderEventID, event.Type,
+		event.ProviderPaymentID, event.Status, event.Amount, event.Currency, event.Payload, event.ReceivedAt.Format(dateTimeLayout))
+	if err != nil {
+		if !mysqlutils.IsDuplicateEntry(err) {
+			return entity.PaymentWebhookEvent{}, false, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+
+		existing, err := scanEvent(m.Conn.QueryRowContext(ctx, queryGetByProviderEventId, event.Provider, event.ProviderEventID))
+		if err != nil {
+			return entity.PaymentWebhookEvent{}, false, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		return existing, false, nil
+	}
+
+	eventID, err := dbRes.LastInsertId()
+	if err != nil {
+		return entity.PaymentWebhookEvent{}, false, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	event.ID = eventID
+	return entity.PaymentWebhookEvent{}, true, nil
+}
+
+func (m *mysqlPaymentWebhookEventRepository) GetByID(ctx context.Context, eventID int64) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	res, err := scanEvent(m.Conn.QueryRowContext(ctx, queryGetById, eventID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, resterrors.NewNotFoundError(fmt.Sprintf("payment webhook event with id %d not found", eventID))
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func scanEvent(row *sql.Row) (entity.PaymentWebhookEvent, error) {
+	var amount, receivedAt, processedAt []uint8
+	res := entity.PaymentWebhookEvent{}
+	err := row.Scan(&res.ID, &res.Provider, &res.ProviderEventID, &res.Type, &res.ProviderPaymentID, &res.Status,
+		&amount, &res.Currency, &res.Payload, &receivedAt, &processedAt, &res.Error)
+	if err != nil {
+		return res, err
+	}
+
+	if res.Amount, err = decimal.NewFromString(string(amount)); err != nil {
+		return res, err
+	}
+	if res.ReceivedAt, err = helpers.GetTimeFromUint8(receivedAt); err != nil {
+		return res, err
+	}
+	if processedAt != nil {
+		pT, err := helpers.GetTimeFromUint8(processedAt)
+		if err != nil {
+			return res, err
+		}
+		res.ProcessedAt = &pT
+	}
+	return res, nil
+}
+
+func (m *mysqlPaymentWebhookEventRepository) UpdateResult(ctx context.Context, event *entity.PaymentWebhookEvent) resterrors.RestErr {
+	var processedAt interface{}
+	if event.ProcessedAt != nil {
+		processedAt = event.ProcessedAt.Format(dateTimeLayout)
+	}
+
+	_, err := m.Conn.ExecContext(ctx, queryUpdateResult, processedAt, event.Error, event.ID)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	return nil
+}
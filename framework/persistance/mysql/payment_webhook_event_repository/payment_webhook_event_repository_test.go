@@ -0,0 +1,152 @@
+package paymentwebhookrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	paymentwebhookrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/payment_webhook_event_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert = `INSERT INTO payment_webhook_events(provider, provider_event_id, event_type, provider_payment_id, status,
+	amount, currency, payload, received_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	querySelect = `SELECT id, provider, provider_event_id, event_type, provider_payment_id, status, amount, currency, payload,
+	received_at, processed_at, COALESCE(error, '') FROM payment_webhook_events `
+	queryGetById              = querySelect + "WHERE id=?;"
+	queryGetByProviderEventId = querySelect + "WHERE provider=? AND provider_event_id=?;"
+	queryUpdateResult         = "UPDATE payment_webhook_events SET processed_at=?, error=NULLIF(?, '') WHERE id=?;"
+)
+
+var eventColumns = []string{"id", "provider", "provider_event_id", "event_type", "provider_payment_id", "status", "amount",
+	"currency", "payload", "received_at", "processed_at", "error"}
+
+type TestSuite struct {
+	suite.Suite
+	db    *sql.DB
+	mock  sqlmock.Sqlmock
+	repo  entity.PaymentWebhookEventRepository
+	event entity.PaymentWebhookEvent
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = paymentwebhookrepo.NewMysqlPaymentWebhookEventRepository(suite.db)
+	suite.event = entity.PaymentWebhookEvent{
+		Provider:          "fake",
+		ProviderEventID:   "evt_1",
+		Type:              "payment_intent.requires_capture",
+		ProviderPaymentID: "fake_pi_1",
+		Status:            entity.PAYMENT_AUTHORIZED,
+		Amount:            decimal.NewFromFloat(181818.11),
+		Currency:          "IDR",
+		Payload:           []byte(`{"id":"evt_1"}`),
+		ReceivedAt:        time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPaymentWebhookEventRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs("fake", "evt_1", suite.event.Type, "fake_pi_1", entity.PAYMENT_AUTHORIZED, suite.event.Amount,
+			"IDR", suite.event.Payload, "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	_, ok, repoErr := suite.repo.Store(context.Background(), &suite.event)
+
+	suite.Nil(repoErr)
+	suite.True(ok)
+	suite.Equal(int64(3), suite.event.ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreDuplicate() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByProviderEventId)).
+		WithArgs("fake", "evt_1").
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(3, "fake", "evt_1", suite.event.Type, "fake_pi_1", entity.PAYMENT_AUTHORIZED, []uint8("181818.11"),
+				"IDR", suite.event.Payload, []uint8("2021-05-01 09:59:00"), []uint8("2021-05-01 09:59:01"), ""))
+
+	existing, ok, repoErr := suite.repo.Store(context.Background(), &suite.event)
+
+	suite.Nil(repoErr)
+	suite.False(ok)
+	suite.Equal(int64(3), existing.ID)
+	suite.NotNil(existing.ProcessedAt)
+	suite.Equal(time.Date(2021, 5, 1, 9, 59, 1, 0, time.UTC), existing.ProcessedAt.UTC())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(3, "fake", "evt_1", suite.event.Type, "fake_pi_1", entity.PAYMENT_AUTHORIZED, []uint8("181818.11"),
+				"IDR", suite.event.Payload, []uint8("2021-05-01 10:00:00"), nil, "payment fake_pi_1 of fake not found"))
+
+	res, repoErr := suite.repo.GetByID(context.Background(), 3)
+
+	suite.Nil(repoErr)
+	suite.Equal("evt_1", res.ProviderEventID)
+	suite.Equal("IDR", res.Currency)
+	suite.Equal(suite.event.Payload, res.Payload)
+	suite.Nil(res.ProcessedAt)
+	suite.Equal("payment fake_pi_1 of fake not found", res.Error)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByIDNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(eventColumns))
+
+	_, repoErr := suite.repo.GetByID(context.Background(), 3)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateResult() {
+	processedAt := time.Date(2021, 5, 1, 10, 0, 1, 0, time.UTC)
+	suite.event.ID = 3
+	suite.event.ProcessedAt = &processedAt
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateResult)).
+		WithArgs("2021-05-01 10:00:01", "", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repoErr := suite.repo.UpdateResult(context.Background(), &suite.event)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateResultError() {
+	suite.event.ID = 3
+	suite.event.Error = "timeout"
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateResult)).
+		WithArgs(nil, "timeout", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repoErr := suite.repo.UpdateResult(context.Background(), &suite.event)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
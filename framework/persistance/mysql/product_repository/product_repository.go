@@ -1,19 +1,29 @@
 package productrepo
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
 	"github.com/shopspring/decimal"
 )
 
 const (
-	queryGetAll  = "SELECT id, name, description, price, seller_id FROM products;"
-	queryInsert  = "INSERT INTO products(name, description, price, seller_id) VALUES(?, ?, ?, ?);"
-	queryGetById = "SELECT id, name, description, price, seller_id FROM products WHERE id=?;"
-	queryUpdate  = "UPDATE products SET name=?, description=?, price=?, seller_id=? WHERE id=?;"
+	queryGetAll  = "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products;"
+	queryInsert  = "INSERT INTO products(sku, name, description, price, weight, category, seller_id) VALUES(NULLIF(?, ''), ?, ?, ?, ?, NULLIF(?, ''), ?);"
+	queryGetById = "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products WHERE id=?;"
+	queryUpdate  = "UPDATE products SET name=?, description=?, price=?, weight=?, category=NULLIF(?, '') WHERE id=?;"
 	queryDelete  = "DELETE FROM products WHERE id=?;"
+
+	queryGetBySellerId = `SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id
+	FROM products WHERE seller_id=? ORDER BY sku, id;`
+	// queryLockBySKU is completed with a placeholder for each SKU
+	queryLockBySKU = "SELECT id, sku, price FROM products WHERE seller_id=? AND sku IN (%s) FOR UPDATE;"
+	queryLockById  = "SELECT COALESCE(sku, ''), price, seller_id FROM products WHERE id=? FOR UPDATE;"
 )
 
 type mysqlProductRepository struct {
@@ -25,32 +35,36 @@ func NewMysqlProductRepository(Conn *sql.DB) entity.ProductRepository {
 	return &mysqlProductRepository{Conn: Conn}
 }
 
-func (m *mysqlProductRepository) GetAll() ([]entity.Product, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetAll)
+func (m *mysqlProductRepository) GetAll(ctx context.Context) ([]entity.Product, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetAll)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes, err := stmt.Query()
+	dbRes, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
+	defer dbRes.Close()
 
 	product := entity.Product{}
 	res := []entity.Product{}
 	for dbRes.Next() {
-		var id, seller_id int64
+		var id, weight, seller_id int64
 		var price []uint8
-		var name, description string
-		err = dbRes.Scan(&id, &name, &description, &price, &seller_id)
+		var sku, name, description, category string
+		err = dbRes.Scan(&id, &sku, &name, &description, &price, &weight, &category, &seller_id)
 		if err != nil {
 			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 		}
 
 		product.ID = id
+		product.SKU = sku
 		product.Name = name
 		product.Description = description
+		product.Weight = weight
+		product.Category = category
 		product.Seller.ID = seller_id
 
 		dP, err := decimal.NewFromString(string(price))
@@ -61,19 +75,26 @@ func (m *mysqlProductRepository) GetAll() ([]entity.Product, resterrors.RestErr)
 
 		res = append(res, product)
 	}
+
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
 	return res, nil
 }
 
-func (m *mysqlProductRepository) GetByID(product *entity.Product) (entity.Product, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryGetById)
+func (m *mysqlProductRepository) GetByID(ctx context.Context, product *entity.Product) (entity.Product, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetById)
 	if err != nil {
 		return *product, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
 	var price []uint8
-	dbRes := stmt.QueryRow(product.ID)
-	if err := dbRes.Scan(&product.ID, &product.Name, &product.Description, &price, &product.Seller.ID); err != nil {
+	dbRes := stmt.QueryRowContext(ctx, product.ID)
+	if err := dbRes.Scan(&product.ID, &product.SKU, &product.Name, &product.Description, &price, &product.Weight, &product.Category, &product.Seller.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *product, resterrors.NewNotFoundError(fmt.Sprintf("product with id %d not found", product.ID))
+		}
 		return *product, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 
@@ -86,16 +107,19 @@ func (m *mysqlProductRepository) GetByID(product *entity.Product) (entity.Produc
 	return *product, nil
 }
 
-func (m *mysqlProductRepository) Store(product *entity.Product) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryInsert)
+func (m *mysqlProductRepository) Store(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryInsert)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 	defer stmt.Close()
 
-	// name, description, price, seller_id
-	dbRes, err := stmt.Exec(product.Name, product.Description, product.Price, product.Seller.ID)
+	// sku, name, description, price, weight, category, seller_id
+	dbRes, err := stmt.ExecContext(ctx, product.SKU, product.Name, product.Description, product.Price, product.Weight, product.Category, product.Seller.ID)
 	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("product with sku %s already exists", product.SKU))
+		}
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 
@@ -108,30 +132,205 @@ func (m *mysqlProductRepository) Store(product *entity.Product) resterrors.RestE
 	return nil
 }
 
-func (m *mysqlProductRepository) Update(product *entity.Product) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryUpdate)
+func (m *mysqlProductRepository) Update(ctx context.Context, product *entity.Product) (decimal.Decimal, resterrors.RestErr) {
+	tx, err := m.Conn.BeginTx(ctx, nil)
 	if err != nil {
-		return resterrors.NewInternalServerError("error when trying to update data", err)
+		return decimal.Decimal{}, resterrors.NewInternalServerError("error when trying to update data", err)
 	}
-	defer stmt.Close()
 
-	// name, description, price, seller_id
-	_, err = stmt.Exec(product.Name, product.Description, product.Price, product.Seller.ID)
+	// the stored product is locked so its price can't change between reading and updating it
+	var sku string
+	var price []uint8
+	var sellerID int64
+	err = tx.QueryRowContext(ctx, queryLockById, product.ID).Scan(&sku, &price, &sellerID)
 	if err != nil {
-		return resterrors.NewInternalServerError("error when trying to update data", err)
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Decimal{}, resterrors.NewNotFoundError(fmt.Sprintf("product with id %d not found", product.ID))
+		}
+		return decimal.Decimal{}, resterrors.NewInternalServerError("error when trying to update data", err)
 	}
-	return nil
+	if sellerID != product.Seller.ID {
+		tx.Rollback()
+		return decimal.Decimal{}, resterrors.NewForbiddenError(fmt.Sprintf("product with id %d is not sold by the seller", product.ID))
+	}
+	oldPrice, err := decimal.NewFromString(string(price))
+	if err != nil {
+		tx.Rollback()
+		return decimal.Decimal{}, resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	// name, description, price, weight, category, id
+	product.SKU = sku
+	_, err = tx.ExecContext(ctx, queryUpdate, product.Name, product.Description, product.Price, product.Weight, product.Category, product.ID)
+	if err != nil {
+		tx.Rollback()
+		return decimal.Decimal{}, resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return decimal.Decimal{}, resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	return oldPrice, nil
 }
 
-func (m *mysqlProductRepository) Delete(product *entity.Product) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryDelete)
+func (m *mysqlProductRepository) Delete(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryDelete)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
 	defer stmt.Close()
 
-	if _, err = stmt.Exec(product.ID); err != nil {
+	dbRes, err := stmt.ExecContext(ctx, product.ID)
+	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("product with id %d not found", product.ID))
+	}
 	return nil
 }
+
+func (m *mysqlProductRepository) GetBySellerID(ctx context.Context, sellerID int64) ([]entity.Product, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetBySellerId)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer stmt.Close()
+
+	dbRes, err := stmt.QueryContext(ctx, sellerID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.Product{}
+	for dbRes.Next() {
+		var price []uint8
+		product := entity.Product{}
+		err = dbRes.Scan(&product.ID, &product.SKU, &product.Name, &product.Description, &price, &product.Weight,
+			&product.Category, &product.Seller.ID)
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		dP, err := decimal.NewFromString(string(price))
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		product.Price = dP
+
+		res = append(res, product)
+	}
+
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlProductRepository) UpsertBySKU(ctx context.Context, sellerID int64, products []entity.Product, dryRun bool) (map[int64]decimal.Decimal, resterrors.RestErr) {
+	if len(products) == 0 {
+		return map[int64]decimal.Decimal{}, nil
+	}
+
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	// the stored products with the imported SKUs are locked, so are the gaps where the new ones go
+	skus := make([]interface{}, 0, len(products)+1)
+	skus = append(skus, sellerID)
+	for _, product := range products {
+		skus = append(skus, product.SKU)
+	}
+	lockRes, err := tx.QueryContext(ctx, fmt.Sprintf(queryLockBySKU, mysqlutils.Placeholders(len(products))), skus...)
+	if err != nil {
+		tx.Rollback()
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	stored := map[string]int64{}
+	previous := map[int64]decimal.Decimal{}
+	for lockRes.Next() {
+		var id int64
+		var sku string
+		var price []uint8
+		if err := lockRes.Scan(&id, &sku, &price); err != nil {
+			lockRes.Close()
+			tx.Rollback()
+			return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		dP, err := decimal.NewFromString(string(price))
+		if err != nil {
+			lockRes.Close()
+			tx.Rollback()
+			return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		stored[sku] = id
+		previous[id] = dP
+	}
+	lockRes.Close()
+	if err := lockRes.Err(); err != nil {
+		tx.Rollback()
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	insertStmt, err := tx.PrepareContext(ctx, queryInsert)
+	if err != nil {
+		tx.Rollback()
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	defer insertStmt.Close()
+	updateStmt, err := tx.PrepareContext(ctx, queryUpdate)
+	if err != nil {
+		tx.Rollback()
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	defer updateStmt.Close()
+
+	for idx := range products {
+		product := &products[idx]
+		product.Seller.ID = sellerID
+
+		if id, ok := stored[product.SKU]; ok {
+			// name, description, price, weight, category, id
+			_, err := updateStmt.ExecContext(ctx, product.Name, product.Description, product.Price, product.Weight, product.Category, id)
+			if err != nil {
+				tx.Rollback()
+				return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+			}
+			product.ID = id
+			continue
+		}
+
+		// sku, name, description, price, weight, category, seller_id
+		dbRes, err := insertStmt.ExecContext(ctx, product.SKU, product.Name, product.Description, product.Price, product.Weight,
+			product.Category, sellerID)
+		if err != nil {
+			tx.Rollback()
+			if mysqlutils.IsDuplicateEntry(err) {
+				return nil, resterrors.NewConflictError(fmt.Sprintf("product with sku %s already exists", product.SKU))
+			}
+			return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		productID, err := dbRes.LastInsertId()
+		if err != nil {
+			tx.Rollback()
+			return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		product.ID = productID
+	}
+
+	if dryRun {
+		if err := tx.Rollback(); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		return previous, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return previous, nil
+}
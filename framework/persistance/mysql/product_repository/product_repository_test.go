@@ -1,11 +1,17 @@
 package productrepo_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"net/http"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	productrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/product_repository"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
@@ -49,17 +55,23 @@ func (suite *TestSuite) SetupTest() {
 
 	suite.expectedProduct1 = entity.Product{
 		ID:          1,
+		SKU:         "SKU-1",
 		Name:        "product1",
 		Description: "desc",
 		Price:       decimal.NewFromFloat(181818.11),
+		Weight:      1200,
+		Category:    "electronics",
 		Seller:      suite.expectedSeller1,
 	}
 
 	suite.expectedProduct2 = entity.Product{
 		ID:          2,
+		SKU:         "SKU-2",
 		Name:        "product1",
 		Description: "desc",
 		Price:       decimal.NewFromFloat(181818.11),
+		Weight:      1200,
+		Category:    "electronics",
 		Seller:      suite.expectedSeller1,
 	}
 
@@ -71,78 +83,170 @@ func TestProductRepo(t *testing.T) {
 }
 
 func (suite *TestSuite) TestGetAll() {
-	queryGetAll := "SELECT id, name, description, price, seller_id FROM products;"
+	queryGetAll := "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetAll))
 
-	row1 := sqlmock.NewRows([]string{"id", "name", "description", "price", "seller_id"}).
-		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID)
-	row2 := sqlmock.NewRows([]string{"id", "name", "description", "price", "seller_id"}).
-		AddRow(suite.expectedProduct2.ID, suite.expectedProduct2.Name, suite.expectedProduct2.Description, suite.price, suite.expectedProduct2.Seller.ID)
+	row1 := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id"}).
+		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.SKU, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.Seller.ID)
+	row2 := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id"}).
+		AddRow(suite.expectedProduct2.ID, suite.expectedProduct2.SKU, suite.expectedProduct2.Name, suite.expectedProduct2.Description, suite.price, suite.expectedProduct2.Weight, suite.expectedProduct2.Category, suite.expectedProduct2.Seller.ID)
 
 	var rows = []*sqlmock.Rows{}
 	rows = append(rows, row1, row2)
 	prep.ExpectQuery().WillReturnRows(rows...)
 
-	res, repoErr := suite.repo.GetAll()
+	res, repoErr := suite.repo.GetAll(context.Background())
 	suite.NoError(repoErr)
 	suite.NotNil(res)
 }
 
 func (suite *TestSuite) TestGetByID() {
-	queryGetById := "SELECT id, name, description, price, seller_id FROM products WHERE id=?;"
+	queryGetById := "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products WHERE id=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
 
-	row1 := sqlmock.NewRows([]string{"id", "name", "description", "price", "seller_id"}).
-		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Seller.ID)
+	row1 := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id"}).
+		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.SKU, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.Seller.ID)
 	prep.ExpectQuery().WithArgs(suite.expectedProduct1.ID).WillReturnRows(row1)
 
 	product := new(entity.Product)
 	product.ID = suite.expectedProduct1.ID
 
-	_, repoErr := suite.repo.GetByID(product)
+	_, repoErr := suite.repo.GetByID(context.Background(), product)
 	suite.NoError(repoErr)
 	suite.NotNil(product)
+	suite.Equal(suite.expectedProduct1.Weight, product.Weight)
+	suite.Equal(suite.expectedProduct1.Category, product.Category)
+	suite.Equal(suite.expectedProduct1.SKU, product.SKU)
+}
+
+func (suite *TestSuite) TestGetByIDNotFound() {
+	queryGetById := "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products WHERE id=?;"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
+	prep.ExpectQuery().WithArgs(suite.expectedProduct1.ID).WillReturnError(sql.ErrNoRows)
+
+	product := new(entity.Product)
+	product.ID = suite.expectedProduct1.ID
+
+	_, repoErr := suite.repo.GetByID(context.Background(), product)
+	suite.True(errors.Is(repoErr, resterrors.ErrNotFound))
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+}
+
+func (suite *TestSuite) TestGetByIDTimeout() {
+	queryGetById := "SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id FROM products WHERE id=?;"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
+
+	row1 := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id"}).
+		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.SKU, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.Seller.ID)
+	prep.ExpectQuery().WithArgs(suite.expectedProduct1.ID).WillDelayFor(time.Second).WillReturnRows(row1)
+
+	product := new(entity.Product)
+	product.ID = suite.expectedProduct1.ID
+
+	// slow query is cancelled once the deadline fires
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, repoErr := suite.repo.GetByID(ctx, product)
+	suite.Error(repoErr)
+	suite.ErrorIs(ctx.Err(), context.DeadlineExceeded)
 }
 
 func (suite *TestSuite) TestStore() {
-	queryInsert := "INSERT INTO products(name, description, price, seller_id) VALUES(?, ?, ?, ?);"
+	queryInsert := "INSERT INTO products(sku, name, description, price, weight, category, seller_id) VALUES(NULLIF(?, ''), ?, ?, ?, ?, NULLIF(?, ''), ?);"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
 
 	prep.ExpectExec().
-		WithArgs(suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.expectedProduct1.Price, suite.expectedProduct1.Seller.ID).
+		WithArgs(suite.expectedProduct1.SKU, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.expectedProduct1.Price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.Seller.ID).
 		WillReturnResult(sqlmock.NewResult(suite.expectedProduct1.ID, 1))
 
 	product := new(entity.Product)
 	product.ID = suite.expectedProduct1.ID
+	product.SKU = suite.expectedProduct1.SKU
 	product.Name = suite.expectedProduct1.Name
 	product.Description = suite.expectedProduct1.Description
 	product.Price = suite.expectedProduct1.Price
+	product.Weight = suite.expectedProduct1.Weight
+	product.Category = suite.expectedProduct1.Category
 	product.Seller = suite.expectedProduct1.Seller
 
-	repoErr := suite.repo.Store(product)
+	repoErr := suite.repo.Store(context.Background(), product)
 
 	suite.NoError(repoErr)
 	suite.NotNil(product)
 }
 
+func (suite *TestSuite) TestStoreDuplicateSKU() {
+	queryInsert := "INSERT INTO products(sku, name, description, price, weight, category, seller_id) VALUES(NULLIF(?, ''), ?, ?, ?, ?, NULLIF(?, ''), ?);"
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
+	prep.ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-SKU-1' for key 'seller_id_sku_UNIQUE'"})
+
+	product := suite.expectedProduct1
+	repoErr := suite.repo.Store(context.Background(), &product)
+	suite.Error(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+}
+
 func (suite *TestSuite) TestUpdate() {
-	queryUpdate := "UPDATE products SET name=?, description=?, price=?, seller_id=? WHERE id=?;"
-	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdate))
+	queryLockById := "SELECT COALESCE(sku, ''), price, seller_id FROM products WHERE id=? FOR UPDATE;"
+	queryUpdate := "UPDATE products SET name=?, description=?, price=?, weight=?, category=NULLIF(?, '') WHERE id=?;"
 
-	prep.ExpectExec().
-		WithArgs(suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.expectedProduct1.Price, suite.expectedProduct1.Seller.ID).
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryLockById)).
+		WithArgs(suite.expectedProduct1.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"sku", "price", "seller_id"}).
+			AddRow(suite.expectedProduct1.SKU, []uint8("200000.00"), suite.expectedSeller1.ID))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).
+		WithArgs(suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.expectedProduct1.Price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
 
 	product := new(entity.Product)
 	product.ID = suite.expectedProduct1.ID
 	product.Name = suite.expectedProduct1.Name
 	product.Description = suite.expectedProduct1.Description
 	product.Price = suite.expectedProduct1.Price
+	product.Weight = suite.expectedProduct1.Weight
+	product.Category = suite.expectedProduct1.Category
 	product.Seller = suite.expectedProduct1.Seller
 
-	repoErr := suite.repo.Update(product)
+	oldPrice, repoErr := suite.repo.Update(context.Background(), product)
 	suite.NoError(repoErr)
-	suite.NotNil(product)
+	suite.True(decimal.NewFromInt(200000).Equal(oldPrice))
+	suite.Equal(suite.expectedProduct1.SKU, product.SKU)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateNotFound() {
+	queryLockById := "SELECT COALESCE(sku, ''), price, seller_id FROM products WHERE id=? FOR UPDATE;"
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryLockById)).
+		WithArgs(suite.expectedProduct1.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"sku", "price", "seller_id"}))
+	suite.mock.ExpectRollback()
+
+	product := suite.expectedProduct1
+	_, repoErr := suite.repo.Update(context.Background(), &product)
+	suite.Error(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateOtherSeller() {
+	queryLockById := "SELECT COALESCE(sku, ''), price, seller_id FROM products WHERE id=? FOR UPDATE;"
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryLockById)).
+		WithArgs(suite.expectedProduct1.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"sku", "price", "seller_id"}).
+			AddRow(suite.expectedProduct1.SKU, suite.price, suite.expectedSeller1.ID+1))
+	// nothing is updated
+	suite.mock.ExpectRollback()
+
+	product := suite.expectedProduct1
+	_, repoErr := suite.repo.Update(context.Background(), &product)
+	suite.Error(repoErr)
+	suite.Equal(http.StatusForbidden, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
 }
 
 func (suite *TestSuite) TestDelete() {
@@ -156,7 +260,97 @@ func (suite *TestSuite) TestDelete() {
 	product := new(entity.Product)
 	product.ID = suite.expectedProduct1.ID
 
-	repoErr := suite.repo.Delete(product)
+	repoErr := suite.repo.Delete(context.Background(), product)
 	suite.NoError(repoErr)
 	suite.NotNil(product)
 }
+
+func (suite *TestSuite) TestGetBySellerID() {
+	queryGetBySellerId := `SELECT id, COALESCE(sku, ''), name, description, price, weight, COALESCE(category, ''), seller_id
+	FROM products WHERE seller_id=? ORDER BY sku, id;`
+	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetBySellerId))
+
+	rows := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id"}).
+		AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.SKU, suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedSeller1.ID).
+		AddRow(suite.expectedProduct2.ID, suite.expectedProduct2.SKU, suite.expectedProduct2.Name, suite.expectedProduct2.Description, suite.price, suite.expectedProduct2.Weight, suite.expectedProduct2.Category, suite.expectedSeller1.ID)
+	prep.ExpectQuery().WithArgs(suite.expectedSeller1.ID).WillReturnRows(rows)
+
+	res, repoErr := suite.repo.GetBySellerID(context.Background(), suite.expectedSeller1.ID)
+	suite.NoError(repoErr)
+	suite.Len(res, 2)
+	suite.Equal(suite.expectedProduct2.SKU, res[1].SKU)
+	suite.True(suite.expectedProduct1.Price.Equal(res[0].Price))
+	suite.Equal(suite.expectedSeller1.ID, res[0].Seller.ID)
+}
+
+func (suite *TestSuite) expectUpsertBySKU() {
+	queryLockBySKU := "SELECT id, sku, price FROM products WHERE seller_id=? AND sku IN (?, ?) FOR UPDATE;"
+	queryInsert := "INSERT INTO products(sku, name, description, price, weight, category, seller_id) VALUES(NULLIF(?, ''), ?, ?, ?, ?, NULLIF(?, ''), ?);"
+	queryUpdateBySKU := "UPDATE products SET name=?, description=?, price=?, weight=?, category=NULLIF(?, '') WHERE id=?;"
+
+	suite.mock.ExpectBegin()
+	// only the first product is stored already
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryLockBySKU)).
+		WithArgs(suite.expectedSeller1.ID, suite.expectedProduct1.SKU, suite.expectedProduct2.SKU).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "price"}).AddRow(suite.expectedProduct1.ID, suite.expectedProduct1.SKU, "200000.00"))
+	insertPrep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
+	updatePrep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdateBySKU))
+	updatePrep.ExpectExec().
+		WithArgs(suite.expectedProduct1.Name, suite.expectedProduct1.Description, suite.expectedProduct1.Price, suite.expectedProduct1.Weight, suite.expectedProduct1.Category, suite.expectedProduct1.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	insertPrep.ExpectExec().
+		WithArgs(suite.expectedProduct2.SKU, suite.expectedProduct2.Name, suite.expectedProduct2.Description, suite.expectedProduct2.Price, suite.expectedProduct2.Weight, suite.expectedProduct2.Category, suite.expectedSeller1.ID).
+		WillReturnResult(sqlmock.NewResult(suite.expectedProduct2.ID, 1))
+}
+
+func (suite *TestSuite) TestUpsertBySKU() {
+	suite.expectUpsertBySKU()
+	suite.mock.ExpectCommit()
+
+	products := []entity.Product{suite.expectedProduct1, suite.expectedProduct2}
+	products[0].ID, products[1].ID = 0, 0
+
+	previous, repoErr := suite.repo.UpsertBySKU(context.Background(), suite.expectedSeller1.ID, products, false)
+	suite.NoError(repoErr)
+	suite.Len(previous, 1)
+	suite.True(decimal.NewFromInt(200000).Equal(previous[suite.expectedProduct1.ID]))
+	suite.Equal(suite.expectedProduct1.ID, products[0].ID)
+	suite.Equal(suite.expectedProduct2.ID, products[1].ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpsertBySKUDryRun() {
+	suite.expectUpsertBySKU()
+	// nothing of a dry run is saved
+	suite.mock.ExpectRollback()
+
+	products := []entity.Product{suite.expectedProduct1, suite.expectedProduct2}
+
+	previous, repoErr := suite.repo.UpsertBySKU(context.Background(), suite.expectedSeller1.ID, products, true)
+	suite.NoError(repoErr)
+	suite.Len(previous, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpsertBySKUDuplicate() {
+	queryLockBySKU := "SELECT id, sku, price FROM products WHERE seller_id=? AND sku IN (?) FOR UPDATE;"
+	queryInsert := "INSERT INTO products(sku, name, description, price, weight, category, seller_id) VALUES(NULLIF(?, ''), ?, ?, ?, ?, NULLIF(?, ''), ?);"
+	queryUpdateBySKU := "UPDATE products SET name=?, description=?, price=?, weight=?, category=NULLIF(?, '') WHERE id=?;"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryLockBySKU)).
+		WithArgs(suite.expectedSeller1.ID, suite.expectedProduct1.SKU).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "price"}))
+	insertPrep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
+	suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdateBySKU))
+	// another import stored the same SKU in the meantime
+	insertPrep.ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-SKU-1' for key 'seller_id_sku_UNIQUE'"})
+	suite.mock.ExpectRollback()
+
+	products := []entity.Product{suite.expectedProduct1}
+
+	_, repoErr := suite.repo.UpsertBySKU(context.Background(), suite.expectedSeller1.ID, products, false)
+	suite.Error(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,149 @@
+package refundrepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert = `INSERT INTO refunds(order_id, payment_id, provider_refund_id, amount, reason, actor_id, actor_type, created_at) 
+	VALUES(?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?);`
+	queryGetByPaymentId = `SELECT id, order_id, payment_id, provider_refund_id, amount, COALESCE(reason, ''), actor_id, actor_type,
+	created_at FROM refunds WHERE payment_id=? ORDER BY id;`
+
+	riInsert         = "INSERT INTO refund_items(refund_id, order_detail_id, quantity, amount) VALUES(?, ?, ?, ?);"
+	riGetByPaymentId = `SELECT ri.id, ri.refund_id, ri.order_detail_id, ri.quantity, ri.amount FROM refund_items ri 
+	JOIN refunds r ON r.id = ri.refund_id WHERE r.payment_id=? ORDER BY ri.id;`
+)
+
+type mysqlRefundRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlRefundRepository will create a object with entity.RefundRepository interface representation
+func NewMysqlRefundRepository(Conn *sql.DB) entity.RefundRepository {
+	return &mysqlRefundRepository{Conn}
+}
+
+func (m *mysqlRefundRepository) Store(ctx context.Context, refund *entity.Refund) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	var actorID, actorType interface{}
+	if refund.ActorID != 0 {
+		actorID = refund.ActorID
+		actorType = refund.ActorType
+	}
+
+	dbRes, err := tx.ExecContext(ctx, queryInsert, refund.OrderID, refund.PaymentID, refund.ProviderRefundID, refund.Amount,
+		refund.Reason, actorID, actorType, refund.CreatedAt.Format(dateTimeLayout))
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	refundID, err := dbRes.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	refund.ID = refundID
+
+	// insert refund items
+	for idx, ri := range refund.Items {
+		riRes, err := tx.ExecContext(ctx, riInsert, refundID, ri.OrderDetailID, ri.Quantity, ri.Amount)
+		if err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+
+		riID, err := riRes.LastInsertId()
+		if err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		refund.Items[idx].ID = riID
+		refund.Items[idx].RefundID = refundID
+	}
+
+	// commit the change if all queries ran successfully
+	if err = tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
+
+func (m *mysqlRefundRepository) GetByPaymentID(ctx context.Context, paymentID int64) ([]entity.Refund, resterrors.RestErr) {
+	rRes, err := m.Conn.QueryContext(ctx, queryGetByPaymentId, paymentID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer rRes.Close()
+
+	refunds := []entity.Refund{}
+	refundIdx := map[int64]int{}
+	for rRes.Next() {
+		var actorID, actorType sql.NullInt64
+		var amount, createdAt []uint8
+		refund := entity.Refund{Items: []entity.RefundItem{}}
+
+		err = rRes.Scan(&refund.ID, &refund.OrderID, &refund.PaymentID, &refund.ProviderRefundID, &amount, &refund.Reason,
+			&actorID, &actorType, &createdAt)
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if refund.Amount, err = decimal.NewFromString(string(amount)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if refund.CreatedAt, err = helpers.GetTimeFromUint8(createdAt); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		refund.ActorID = actorID.Int64
+		refund.ActorType = helpers.UserTypeEnum(actorType.Int64)
+
+		refundIdx[refund.ID] = len(refunds)
+		refunds = append(refunds, refund)
+	}
+	if err = rRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	riRes, err := m.Conn.QueryContext(ctx, riGetByPaymentId, paymentID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer riRes.Close()
+
+	for riRes.Next() {
+		var amount []uint8
+		ri := entity.RefundItem{}
+		if err = riRes.Scan(&ri.ID, &ri.RefundID, &ri.OrderDetailID, &ri.Quantity, &amount); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if ri.Amount, err = decimal.NewFromString(string(amount)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		idx := refundIdx[ri.RefundID]
+		refunds[idx].Items = append(refunds[idx].Items, ri)
+	}
+	if err = riRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return refunds, nil
+}
@@ -0,0 +1,153 @@
+package refundrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	refundrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/refund_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert = `INSERT INTO refunds(order_id, payment_id, provider_refund_id, amount, reason, actor_id, actor_type, created_at) 
+	VALUES(?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?);`
+	queryGetByPaymentId = `SELECT id, order_id, payment_id, provider_refund_id, amount, COALESCE(reason, ''), actor_id, actor_type,
+	created_at FROM refunds WHERE payment_id=? ORDER BY id;`
+
+	riInsert         = "INSERT INTO refund_items(refund_id, order_detail_id, quantity, amount) VALUES(?, ?, ?, ?);"
+	riGetByPaymentId = `SELECT ri.id, ri.refund_id, ri.order_detail_id, ri.quantity, ri.amount FROM refund_items ri 
+	JOIN refunds r ON r.id = ri.refund_id WHERE r.payment_id=? ORDER BY ri.id;`
+)
+
+type TestSuite struct {
+	suite.Suite
+	db     *sql.DB
+	mock   sqlmock.Sqlmock
+	repo   entity.RefundRepository
+	refund entity.Refund
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = refundrepo.NewMysqlRefundRepository(suite.db)
+	suite.refund = entity.Refund{
+		OrderID:          1,
+		PaymentID:        7,
+		ProviderRefundID: "fake_re_1",
+		Amount:           decimal.RequireFromString("131817.60"),
+		Reason:           "damaged",
+		ActorID:          2,
+		ActorType:        helpers.SELLER_TYPE,
+		CreatedAt:        time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Items: []entity.RefundItem{
+			{OrderDetailID: 11, Quantity: 1, Amount: decimal.RequireFromString("50000.50")},
+			{OrderDetailID: 12, Quantity: 1, Amount: decimal.RequireFromString("81817.10")},
+		},
+	}
+}
+
+func TestRefundRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(int64(1), int64(7), "fake_re_1", suite.refund.Amount, "damaged", int64(2), helpers.SELLER_TYPE, "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(riInsert)).
+		WithArgs(int64(3), int64(11), int64(1), suite.refund.Items[0].Amount).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(riInsert)).
+		WithArgs(int64(3), int64(12), int64(1), suite.refund.Items[1].Amount).
+		WillReturnResult(sqlmock.NewResult(6, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.refund)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(3), suite.refund.ID)
+	suite.Equal(int64(6), suite.refund.Items[1].ID)
+	suite.Equal(int64(3), suite.refund.Items[1].RefundID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreWithoutActor() {
+	suite.refund.ActorID = 0
+	suite.refund.Items = nil
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(int64(1), int64(7), "fake_re_1", suite.refund.Amount, "damaged", nil, nil, "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.refund)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreItemError() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).WillReturnResult(sqlmock.NewResult(3, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(riInsert)).WillReturnError(errors.New("foreign key"))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.refund)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusInternalServerError, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByPaymentID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByPaymentId)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_id", "provider_refund_id", "amount", "reason",
+			"actor_id", "actor_type", "created_at"}).
+			AddRow(3, 1, 7, "fake_re_1", []uint8("50000.50"), "damaged", 2, helpers.SELLER_TYPE, []uint8("2021-05-01 10:00:00")).
+			AddRow(4, 1, 7, "fake_re_2", []uint8("131817.60"), "order cancelled", nil, nil, []uint8("2021-05-02 10:00:00")))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(riGetByPaymentId)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "refund_id", "order_detail_id", "quantity", "amount"}).
+			AddRow(5, 3, 11, 1, []uint8("50000.50")))
+
+	res, repoErr := suite.repo.GetByPaymentID(context.Background(), 7)
+
+	suite.Nil(repoErr)
+	suite.Len(res, 2)
+	suite.Equal("50000.5", res[0].Amount.String())
+	suite.Equal(int64(2), res[0].ActorID)
+	suite.Equal(helpers.SELLER_TYPE, res[0].ActorType)
+	suite.Len(res[0].Items, 1)
+	suite.Equal(int64(11), res[0].Items[0].OrderDetailID)
+	suite.Zero(res[1].ActorID)
+	suite.Empty(res[1].Items)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByPaymentIDNoRefunds() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByPaymentId)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_id", "provider_refund_id", "amount", "reason",
+			"actor_id", "actor_type", "created_at"}))
+
+	res, repoErr := suite.repo.GetByPaymentID(context.Background(), 7)
+
+	suite.Nil(repoErr)
+	suite.Empty(res)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
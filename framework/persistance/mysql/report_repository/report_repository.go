@@ -0,0 +1,116 @@
+package reportrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	// queryGetOrderReport is completed with the period of order_date, then with a placeholder for each sold status twice.
+	// Its arguments are the sold statuses, DELIVERED, CANCELLED, REJECTED, the sold statuses again and the range
+	queryGetOrderReport = `SELECT %s AS period, COUNT(*),
+	SUM(CASE WHEN status IN (%s) THEN 1 ELSE 0 END), SUM(CASE WHEN status=? THEN 1 ELSE 0 END),
+	SUM(CASE WHEN status=? THEN 1 ELSE 0 END), SUM(CASE WHEN status=? THEN 1 ELSE 0 END),
+	COALESCE(SUM(CASE WHEN status IN (%s) THEN total_price ELSE 0 END), 0)
+	FROM orders WHERE order_date>=? AND order_date<? GROUP BY period ORDER BY period;`
+	// querySignups is completed with the period of created_at of buyers, then of sellers
+	querySignups = `SELECT period, SUM(buyers), SUM(sellers) FROM (
+	SELECT %s AS period, 1 AS buyers, 0 AS sellers FROM buyers WHERE created_at>=? AND created_at<?
+	UNION ALL SELECT %s AS period, 0 AS buyers, 1 AS sellers FROM sellers WHERE created_at>=? AND created_at<?
+	) signups GROUP BY period ORDER BY period;`
+)
+
+type mysqlReportRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlReportRepository will create a object with entity.ReportRepository interface representation
+func NewMysqlReportRepository(Conn *sql.DB) entity.ReportRepository {
+	return &mysqlReportRepository{Conn}
+}
+
+func (m *mysqlReportRepository) GetOrderReport(ctx context.Context, from, to time.Time, group entity.SalesGroupEnum) ([]entity.ReportPeriod, resterrors.RestErr) {
+	sold := mysqlutils.Placeholders(len(entity.SoldOrderStatuses))
+	query := fmt.Sprintf(queryGetOrderReport, mysqlutils.PeriodStart(group, "order_date"), sold, sold)
+
+	args := []interface{}{}
+	for _, status := range entity.SoldOrderStatuses {
+		args = append(args, status)
+	}
+	args = append(args, entity.DELIVERED, entity.CANCELLED, entity.REJECTED)
+	for _, status := range entity.SoldOrderStatuses {
+		args = append(args, status)
+	}
+	args = append(args, from.Format(dateTimeLayout), to.Format(dateTimeLayout))
+
+	dbRes, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.ReportPeriod{}
+	for dbRes.Next() {
+		var period string
+		var gmv []uint8
+		p := entity.ReportPeriod{}
+		err := dbRes.Scan(&period, &p.Funnel.Placed, &p.Funnel.Accepted, &p.Funnel.Completed, &p.Funnel.Cancelled,
+			&p.Funnel.Rejected, &gmv)
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if p.Start, err = time.Parse(dateLayout, period); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if p.GMV, err = decimal.NewFromString(string(gmv)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, p)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlReportRepository) GetSignups(ctx context.Context, from, to time.Time, group entity.SalesGroupEnum) ([]entity.ReportPeriod, resterrors.RestErr) {
+	query := fmt.Sprintf(querySignups, mysqlutils.PeriodStart(group, "created_at"), mysqlutils.PeriodStart(group, "created_at"))
+	fromArg, toArg := from.Format(dateTimeLayout), to.Format(dateTimeLayout)
+
+	dbRes, err := m.Conn.QueryContext(ctx, query, fromArg, toArg, fromArg, toArg)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.ReportPeriod{}
+	for dbRes.Next() {
+		var period string
+		p := entity.ReportPeriod{GMV: decimal.Zero}
+		if err := dbRes.Scan(&period, &p.NewBuyers, &p.NewSellers); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if p.Start, err = time.Parse(dateLayout, period); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, p)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
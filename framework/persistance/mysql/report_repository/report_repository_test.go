@@ -0,0 +1,93 @@
+package reportrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	reportrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/report_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryGetOrderReportByDay = `SELECT DATE_FORMAT(order_date, '%Y-%m-%d') AS period, COUNT(*),
+	SUM(CASE WHEN status IN (?, ?, ?) THEN 1 ELSE 0 END), SUM(CASE WHEN status=? THEN 1 ELSE 0 END),
+	SUM(CASE WHEN status=? THEN 1 ELSE 0 END), SUM(CASE WHEN status=? THEN 1 ELSE 0 END),
+	COALESCE(SUM(CASE WHEN status IN (?, ?, ?) THEN total_price ELSE 0 END), 0)
+	FROM orders WHERE order_date>=? AND order_date<? GROUP BY period ORDER BY period;`
+	querySignupsByMonth = `SELECT period, SUM(buyers), SUM(sellers) FROM (
+	SELECT DATE_FORMAT(created_at, '%Y-%m-01') AS period, 1 AS buyers, 0 AS sellers FROM buyers WHERE created_at>=? AND created_at<?
+	UNION ALL SELECT DATE_FORMAT(created_at, '%Y-%m-01') AS period, 0 AS buyers, 1 AS sellers FROM sellers WHERE created_at>=? AND created_at<?
+	) signups GROUP BY period ORDER BY period;`
+)
+
+type TestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo entity.ReportRepository
+	from time.Time
+	to   time.Time
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = reportrepo.NewMysqlReportRepository(suite.db)
+	suite.from = time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	suite.to = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestReportRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestGetOrderReport() {
+	rows := sqlmock.NewRows([]string{"period", "placed", "accepted", "completed", "cancelled", "rejected", "gmv"}).
+		AddRow("2021-05-01", 10, []uint8("6"), []uint8("4"), []uint8("3"), []uint8("1"), []uint8("600000.00"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetOrderReportByDay)).
+		WithArgs(entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED, entity.DELIVERED, entity.CANCELLED, entity.REJECTED,
+			entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED, "2021-05-01 00:00:00", "2021-06-01 00:00:00").
+		WillReturnRows(rows)
+
+	res, err := suite.repo.GetOrderReport(context.Background(), suite.from, suite.to, entity.SALES_GROUP_DAY)
+	suite.NoError(err)
+	suite.Len(res, 1)
+	suite.Equal(suite.from, res[0].Start)
+	suite.Equal(entity.OrderFunnel{Placed: 10, Accepted: 6, Completed: 4, Cancelled: 3, Rejected: 1}, res[0].Funnel)
+	suite.True(decimal.NewFromInt(600000).Equal(res[0].GMV))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetOrderReportError() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetOrderReportByDay)).WillReturnError(errors.New("connection refused"))
+
+	_, err := suite.repo.GetOrderReport(context.Background(), suite.from, suite.to, entity.SALES_GROUP_DAY)
+	suite.Error(err)
+	suite.Equal(http.StatusInternalServerError, err.Status())
+}
+
+func (suite *TestSuite) TestGetSignups() {
+	rows := sqlmock.NewRows([]string{"period", "buyers", "sellers"}).
+		AddRow("2021-05-01", []uint8("12"), []uint8("2"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(querySignupsByMonth)).
+		WithArgs("2021-05-01 00:00:00", "2021-06-01 00:00:00", "2021-05-01 00:00:00", "2021-06-01 00:00:00").
+		WillReturnRows(rows)
+
+	res, err := suite.repo.GetSignups(context.Background(), suite.from, suite.to, entity.SALES_GROUP_MONTH)
+	suite.NoError(err)
+	suite.Len(res, 1)
+	suite.Equal(int64(12), res[0].NewBuyers)
+	suite.Equal(int64(2), res[0].NewSellers)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
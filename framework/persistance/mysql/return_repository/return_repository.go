@@ -0,0 +1,264 @@
+package returnrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert = `INSERT INTO return_requests(order_id, buyer_id, seller_id, status, reason, note, restock, created_at, updated_at) 
+	VALUES(?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?);`
+	queryGetById = `SELECT id, order_id, buyer_id, seller_id, status, reason, COALESCE(note, ''), COALESCE(reject_reason, ''),
+	COALESCE(carrier, ''), COALESCE(tracking_number, ''), restock, refund_id, created_at, updated_at, shipped_at, received_at
+	FROM return_requests WHERE id=?;`
+	queryGetByOrderId = `SELECT id, order_id, buyer_id, seller_id, status, reason, COALESCE(note, ''), COALESCE(reject_reason, ''),
+	COALESCE(carrier, ''), COALESCE(tracking_number, ''), restock, refund_id, created_at, updated_at, shipped_at, received_at
+	FROM return_requests WHERE order_id=? ORDER BY id;`
+	queryUpdate = `UPDATE return_requests SET status=?, reject_reason=NULLIF(?, ''), carrier=NULLIF(?, ''), tracking_number=NULLIF(?, ''),
+	restock=?, refund_id=?, updated_at=?, shipped_at=?, received_at=? WHERE id=? AND status=?;`
+
+	riInsert         = "INSERT INTO return_request_items(return_request_id, order_detail_id, quantity) VALUES(?, ?, ?);"
+	riGetByReturnIds = "SELECT id, return_request_id, order_detail_id, quantity FROM return_request_items WHERE return_request_id IN (%s) ORDER BY id;"
+	rpInsert         = "INSERT INTO return_request_photos(return_request_id, url) VALUES(?, ?);"
+	rpGetByReturnIds = "SELECT return_request_id, url FROM return_request_photos WHERE return_request_id IN (%s) ORDER BY id;"
+)
+
+type mysqlReturnRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlReturnRepository will create a object with entity.ReturnRepository interface representation
+func NewMysqlReturnRepository(Conn *sql.DB) entity.ReturnRepository {
+	return &mysqlReturnRepository{Conn}
+}
+
+func (m *mysqlReturnRepository) Store(ctx context.Context, ret *entity.ReturnRequest) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	dbRes, err := tx.ExecContext(ctx, queryInsert, ret.OrderID, ret.BuyerID, ret.SellerID, ret.Status, ret.Reason, ret.Note,
+		ret.Restock, ret.CreatedAt.Format(dateTimeLayout), ret.UpdatedAt.Format(dateTimeLayout))
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	returnID, err := dbRes.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	ret.ID = returnID
+
+	// insert return items
+	for idx, ri := range ret.Items {
+		riRes, err := tx.ExecContext(ctx, riInsert, returnID, ri.OrderDetailID, ri.Quantity)
+		if err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+
+		riID, err := riRes.LastInsertId()
+		if err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+		ret.Items[idx].ID = riID
+		ret.Items[idx].ReturnID = returnID
+	}
+
+	// insert photos
+	for _, url := range ret.Photos {
+		if _, err := tx.ExecContext(ctx, rpInsert, returnID, url); err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+	}
+
+	// commit the change if all queries ran successfully
+	if err = tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
+
+func (m *mysqlReturnRepository) GetByID(ctx context.Context, returnID int64) (entity.ReturnRequest, resterrors.RestErr) {
+	res := entity.ReturnRequest{}
+	err := scanReturn(m.Conn.QueryRowContext(ctx, queryGetById, returnID), &res)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, resterrors.NewNotFoundError(fmt.Sprintf("return with id %d not found", returnID))
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	returns := []entity.ReturnRequest{res}
+	if restErr := m.getDetails(ctx, returns); restErr != nil {
+		return entity.ReturnRequest{}, restErr
+	}
+	return returns[0], nil
+}
+
+func (m *mysqlReturnRepository) GetByOrderID(ctx context.Context, orderID int64) ([]entity.ReturnRequest, resterrors.RestErr) {
+	rRes, err := m.Conn.QueryContext(ctx, queryGetByOrderId, orderID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer rRes.Close()
+
+	returns := []entity.ReturnRequest{}
+	for rRes.Next() {
+		ret := entity.ReturnRequest{}
+		if err = scanReturn(rRes, &ret); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		returns = append(returns, ret)
+	}
+	if err = rRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	if len(returns) == 0 {
+		return returns, nil
+	}
+	if restErr := m.getDetails(ctx, returns); restErr != nil {
+		return nil, restErr
+	}
+	return returns, nil
+}
+
+func (m *mysqlReturnRepository) Update(ctx context.Context, ret *entity.ReturnRequest, from entity.ReturnStatusEnum) resterrors.RestErr {
+	var refundID interface{}
+	if ret.RefundID != 0 {
+		refundID = ret.RefundID
+	}
+
+	// the return is only changed from the status it was read with, so concurrent changes can't overwrite each other
+	dbRes, err := m.Conn.ExecContext(ctx, queryUpdate, ret.Status, ret.RejectReason, ret.Carrier, ret.TrackingNumber, ret.Restock,
+		refundID, ret.UpdatedAt.Format(dateTimeLayout), formatNullableTime(ret.ShippedAt), formatNullableTime(ret.ReceivedAt),
+		ret.ID, from)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	affected, err := dbRes.RowsAffected()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	if affected == 0 {
+		return resterrors.NewConflictError(fmt.Sprintf("return with id %d was changed by another request", ret.ID))
+	}
+	return nil
+}
+
+// getDetails fills the items and photos of returns
+func (m *mysqlReturnRepository) getDetails(ctx context.Context, returns []entity.ReturnRequest) resterrors.RestErr {
+	returnIDs := make([]interface{}, 0, len(returns))
+	returnIdx := map[int64]int{}
+	for idx := range returns {
+		returns[idx].Items = []entity.ReturnItem{}
+		returns[idx].Photos = []string{}
+		returnIDs = append(returnIDs, returns[idx].ID)
+		returnIdx[returns[idx].ID] = idx
+	}
+
+	riRes, err := m.Conn.QueryContext(ctx, fmt.Sprintf(riGetByReturnIds, mysqlutils.Placeholders(len(returnIDs))), returnIDs...)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer riRes.Close()
+
+	for riRes.Next() {
+		ri := entity.ReturnItem{}
+		if err = riRes.Scan(&ri.ID, &ri.ReturnID, &ri.OrderDetailID, &ri.Quantity); err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		idx := returnIdx[ri.ReturnID]
+		returns[idx].Items = append(returns[idx].Items, ri)
+	}
+	if err = riRes.Err(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	rpRes, err := m.Conn.QueryContext(ctx, fmt.Sprintf(rpGetByReturnIds, mysqlutils.Placeholders(len(returnIDs))), returnIDs...)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer rpRes.Close()
+
+	for rpRes.Next() {
+		var returnID int64
+		var url string
+		if err = rpRes.Scan(&returnID, &url); err != nil {
+			return resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		idx := returnIdx[returnID]
+		returns[idx].Photos = append(returns[idx].Photos, url)
+	}
+	if err = rpRes.Err(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReturn(row scanner, ret *entity.ReturnRequest) error {
+	var refundID sql.NullInt64
+	var createdAt, updatedAt, shippedAt, receivedAt []uint8
+	err := row.Scan(&ret.ID, &ret.OrderID, &ret.BuyerID, &ret.SellerID, &ret.Status, &ret.Reason, &ret.Note, &ret.RejectReason,
+		&ret.Carrier, &ret.TrackingNumber, &ret.Restock, &refundID, &createdAt, &updatedAt, &shippedAt, &receivedAt)
+	if err != nil {
+		return err
+	}
+	ret.RefundID = refundID.Int64
+
+	if ret.CreatedAt, err = helpers.GetTimeFromUint8(createdAt); err != nil {
+		return err
+	}
+	if ret.UpdatedAt, err = helpers.GetTimeFromUint8(updatedAt); err != nil {
+		return err
+	}
+	if ret.ShippedAt, err = getNullableTime(shippedAt); err != nil {
+		return err
+	}
+	if ret.ReceivedAt, err = getNullableTime(receivedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func getNullableTime(value []uint8) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := helpers.GetTimeFromUint8(value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func formatNullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(dateTimeLayout)
+}
@@ -0,0 +1,219 @@
+package returnrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	returnrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/return_repository"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert = `INSERT INTO return_requests(order_id, buyer_id, seller_id, status, reason, note, restock, created_at, updated_at) 
+	VALUES(?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?);`
+	queryGetById = `SELECT id, order_id, buyer_id, seller_id, status, reason, COALESCE(note, ''), COALESCE(reject_reason, ''),
+	COALESCE(carrier, ''), COALESCE(tracking_number, ''), restock, refund_id, created_at, updated_at, shipped_at, received_at
+	FROM return_requests WHERE id=?;`
+	queryGetByOrderId = `SELECT id, order_id, buyer_id, seller_id, status, reason, COALESCE(note, ''), COALESCE(reject_reason, ''),
+	COALESCE(carrier, ''), COALESCE(tracking_number, ''), restock, refund_id, created_at, updated_at, shipped_at, received_at
+	FROM return_requests WHERE order_id=? ORDER BY id;`
+	queryUpdate = `UPDATE return_requests SET status=?, reject_reason=NULLIF(?, ''), carrier=NULLIF(?, ''), tracking_number=NULLIF(?, ''),
+	restock=?, refund_id=?, updated_at=?, shipped_at=?, received_at=? WHERE id=? AND status=?;`
+
+	riInsert         = "INSERT INTO return_request_items(return_request_id, order_detail_id, quantity) VALUES(?, ?, ?);"
+	riGetByReturnIds = "SELECT id, return_request_id, order_detail_id, quantity FROM return_request_items WHERE return_request_id IN (%s) ORDER BY id;"
+	rpInsert         = "INSERT INTO return_request_photos(return_request_id, url) VALUES(?, ?);"
+	rpGetByReturnIds = "SELECT return_request_id, url FROM return_request_photos WHERE return_request_id IN (%s) ORDER BY id;"
+)
+
+var returnColumns = []string{"id", "order_id", "buyer_id", "seller_id", "status", "reason", "note", "reject_reason", "carrier",
+	"tracking_number", "restock", "refund_id", "created_at", "updated_at", "shipped_at", "received_at"}
+
+type TestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo entity.ReturnRepository
+	ret  entity.ReturnRequest
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = returnrepo.NewMysqlReturnRepository(suite.db)
+	suite.ret = entity.ReturnRequest{
+		OrderID:   1,
+		BuyerID:   3,
+		SellerID:  2,
+		Status:    entity.RETURN_REQUESTED,
+		Reason:    entity.RETURN_REASON_DAMAGED,
+		Note:      "broken screen",
+		Photos:    []string{"https://img.example.com/1.jpg"},
+		CreatedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Items:     []entity.ReturnItem{{OrderDetailID: 11, Quantity: 1}},
+	}
+}
+
+func TestReturnRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(int64(1), int64(3), int64(2), entity.RETURN_REQUESTED, entity.RETURN_REASON_DAMAGED, "broken screen", false,
+			"2021-05-01 10:00:00", "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(riInsert)).
+		WithArgs(int64(4), int64(11), int64(1)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(rpInsert)).
+		WithArgs(int64(4), "https://img.example.com/1.jpg").
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.ret)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(4), suite.ret.ID)
+	suite.Equal(int64(8), suite.ret.Items[0].ID)
+	suite.Equal(int64(4), suite.ret.Items[0].ReturnID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStorePhotoError() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).WillReturnResult(sqlmock.NewResult(4, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(riInsert)).WillReturnResult(sqlmock.NewResult(8, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(rpInsert)).WillReturnError(errors.New("data too long"))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Store(context.Background(), &suite.ret)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusInternalServerError, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows(returnColumns).
+			AddRow(4, 1, 3, 2, entity.RETURN_REFUNDED, "DAMAGED", "broken screen", "", "JNE", "JNE123", true, 6,
+				[]uint8("2021-05-01 10:00:00"), []uint8("2021-05-05 10:00:00"), []uint8("2021-05-02 10:00:00"),
+				[]uint8("2021-05-04 10:00:00")))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(riGetByReturnIds, "?"))).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "return_request_id", "order_detail_id", "quantity"}).AddRow(8, 4, 11, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(rpGetByReturnIds, "?"))).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"return_request_id", "url"}).AddRow(4, "https://img.example.com/1.jpg"))
+
+	res, repoErr := suite.repo.GetByID(context.Background(), 4)
+
+	suite.Nil(repoErr)
+	suite.Equal(entity.RETURN_REFUNDED, res.Status)
+	suite.Equal(entity.RETURN_REASON_DAMAGED, res.Reason)
+	suite.Equal("JNE123", res.TrackingNumber)
+	suite.True(res.Restock)
+	suite.Equal(int64(6), res.RefundID)
+	suite.Equal(time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC), *res.ShippedAt)
+	suite.Equal([]entity.ReturnItem{{ID: 8, ReturnID: 4, OrderDetailID: 11, Quantity: 1}}, res.Items)
+	suite.Equal([]string{"https://img.example.com/1.jpg"}, res.Photos)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByIDNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows(returnColumns))
+
+	_, repoErr := suite.repo.GetByID(context.Background(), 4)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(returnColumns).
+			AddRow(4, 1, 3, 2, entity.RETURN_CANCELLED, "OTHER", "changed my mind", "", "", "", false, nil,
+				[]uint8("2021-05-01 10:00:00"), []uint8("2021-05-01 11:00:00"), nil, nil).
+			AddRow(5, 1, 3, 2, entity.RETURN_REQUESTED, "DAMAGED", "", "", "", "", false, nil,
+				[]uint8("2021-05-02 10:00:00"), []uint8("2021-05-02 10:00:00"), nil, nil))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(riGetByReturnIds, "?, ?"))).
+		WithArgs(int64(4), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "return_request_id", "order_detail_id", "quantity"}).
+			AddRow(8, 4, 11, 1).
+			AddRow(9, 5, 11, 2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(rpGetByReturnIds, "?, ?"))).
+		WithArgs(int64(4), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"return_request_id", "url"}))
+
+	res, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.Len(res, 2)
+	suite.Nil(res[0].ShippedAt)
+	suite.Zero(res[0].RefundID)
+	suite.Equal(int64(2), res[1].Items[0].Quantity)
+	suite.Empty(res[1].Photos)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderIDNoReturns() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(returnColumns))
+
+	res, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.Empty(res)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdate() {
+	shippedAt := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+	suite.ret.ID = 4
+	suite.ret.Status = entity.RETURN_SHIPPED
+	suite.ret.Carrier = "JNE"
+	suite.ret.TrackingNumber = "JNE123"
+	suite.ret.UpdatedAt = shippedAt
+	suite.ret.ShippedAt = &shippedAt
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).
+		WithArgs(entity.RETURN_SHIPPED, "", "JNE", "JNE123", false, nil, "2021-05-02 10:00:00", "2021-05-02 10:00:00", nil,
+			int64(4), entity.RETURN_APPROVED).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repoErr := suite.repo.Update(context.Background(), &suite.ret, entity.RETURN_APPROVED)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestUpdateConflict() {
+	suite.ret.ID = 4
+	suite.ret.Status = entity.RETURN_CANCELLED
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repoErr := suite.repo.Update(context.Background(), &suite.ret, entity.RETURN_REQUESTED)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
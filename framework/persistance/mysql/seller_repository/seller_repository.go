@@ -1,21 +1,27 @@
 package sellerrepo
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
 )
 
 const (
-	queryGetAll  = "SELECT id, email, name, pickup_address FROM sellers;"
-	queryInsert  = "INSERT INTO sellers(email, name, password, pickup_address) VALUES(?, ?, ?, ?);"
-	queryGetById = "SELECT id, email, name, pickup_address FROM sellers WHERE id=?;"
-	queryUpdate  = "UPDATE sellers SET email=?, name=?, pickup_address=? WHERE id=?;"
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryGetAll  = "SELECT id, email, name, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers;"
+	queryInsert  = "INSERT INTO sellers(email, name, password, pickup_address, pickup_province, pickup_postal_code, tax_status, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?);"
+	queryGetById = "SELECT id, email, name, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers WHERE id=?;"
+	queryUpdate  = "UPDATE sellers SET email=?, name=?, pickup_address=?, pickup_province=?, pickup_postal_code=?, tax_status=? WHERE id=?;"
 	queryDelete  = "DELETE FROM sellers WHERE id=?;"
 
-	queryFindByEmail = "SELECT id, email, name, password, pickup_address FROM sellers WHERE email=?;"
+	queryFindByEmail = "SELECT id, email, name, password, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers WHERE email=?;"
 )
 
 type mysqlSellerRepository struct {
@@ -27,19 +33,20 @@ func NewMysqlSellerRepository(Conn *sql.DB) entity.SellerRepository {
 	return &mysqlSellerRepository{Conn: Conn}
 }
 
-func (m *mysqlSellerRepository) GetAll() ([]entity.Seller, resterrors.RestErr) {
-	dbRes, err := m.Conn.Query(queryGetAll)
+func (m *mysqlSellerRepository) GetAll(ctx context.Context) ([]entity.Seller, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, queryGetAll)
 	if err != nil {
 		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
-	defer m.Conn.Close()
+	defer dbRes.Close()
 
 	seller := entity.Seller{}
 	res := []entity.Seller{}
 	for dbRes.Next() {
 		var id int64
-		var email, name, pickupAddress string
-		err = dbRes.Scan(&id, &email, &name, &pickupAddress)
+		var email, name, pickupAddress, pickupProvince, pickupPostalCode string
+		var taxStatus entity.SellerTaxStatusEnum
+		err = dbRes.Scan(&id, &email, &name, &pickupAddress, &pickupProvince, &pickupPostalCode, &taxStatus)
 		if err != nil {
 			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
 		}
@@ -47,36 +54,50 @@ func (m *mysqlSellerRepository) GetAll() ([]entity.Seller, resterrors.RestErr) {
 		seller.ID = id
 		seller.Name = name
 		seller.PickUpAddress = pickupAddress
+		seller.PickUpProvince = pickupProvince
+		seller.PickUpPostalCode = pickupPostalCode
+		seller.TaxStatus = taxStatus
 
 		res = append(res, seller)
 	}
+
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
 	return res, nil
 }
 
-func (m *mysqlSellerRepository) GetByID(seller *entity.Seller) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryGetById)
+func (m *mysqlSellerRepository) GetByID(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryGetById)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes := stmt.QueryRow(seller.ID)
+	dbRes := stmt.QueryRowContext(ctx, seller.ID)
 
-	if err := dbRes.Scan(&seller.ID, &seller.Email, &seller.Name, &seller.PickUpAddress); err != nil {
+	if err := dbRes.Scan(&seller.ID, &seller.Email, &seller.Name, &seller.PickUpAddress, &seller.PickUpProvince, &seller.PickUpPostalCode, &seller.TaxStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resterrors.NewNotFoundError(fmt.Sprintf("seller with id %d not found", seller.ID))
+		}
 		return resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	return nil
 }
 
-func (m *mysqlSellerRepository) Store(seller *entity.Seller) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryInsert)
+func (m *mysqlSellerRepository) Store(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryInsert)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 	defer stmt.Close()
-	// email, name, password, pickup_address
-	dbRes, err := stmt.Exec(seller.Email, seller.Name, seller.Password, seller.PickUpAddress)
+	// email, name, password, pickup_address, pickup_province, pickup_postal_code, tax_status, created_at
+	dbRes, err := stmt.ExecContext(ctx, seller.Email, seller.Name, seller.Password, seller.PickUpAddress, seller.PickUpProvince, seller.PickUpPostalCode,
+		seller.TaxStatus, seller.CreatedAt.Format(dateTimeLayout))
 	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("user with email %s is already exist", seller.Email))
+		}
 		return resterrors.NewInternalServerError("error when trying to save data", err)
 	}
 
@@ -89,43 +110,51 @@ func (m *mysqlSellerRepository) Store(seller *entity.Seller) resterrors.RestErr
 	return nil
 }
 
-func (m *mysqlSellerRepository) Update(seller *entity.Seller) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryUpdate)
+func (m *mysqlSellerRepository) Update(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryUpdate)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(seller.Email, seller.Name, seller.PickUpAddress, seller.ID)
+	_, err = stmt.ExecContext(ctx, seller.Email, seller.Name, seller.PickUpAddress, seller.PickUpProvince, seller.PickUpPostalCode, seller.TaxStatus, seller.ID)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to update data", err)
 	}
 	return nil
 }
 
-func (m *mysqlSellerRepository) Delete(seller *entity.Seller) resterrors.RestErr {
-	stmt, err := m.Conn.Prepare(queryDelete)
+func (m *mysqlSellerRepository) Delete(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
+	stmt, err := m.Conn.PrepareContext(ctx, queryDelete)
 	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
 	defer stmt.Close()
 
-	if _, err = stmt.Exec(seller.ID); err != nil {
+	dbRes, err := stmt.ExecContext(ctx, seller.ID)
+	if err != nil {
 		return resterrors.NewInternalServerError("error when trying to delete data", err)
 	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("seller with id %d not found", seller.ID))
+	}
 	return nil
 }
 
-func (m *mysqlSellerRepository) GetByEmail(seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
-	stmt, err := m.Conn.Prepare(queryFindByEmail)
+func (m *mysqlSellerRepository) GetByEmail(ctx context.Context, seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
+	stmt, err := m.Conn.PrepareContext(ctx, queryFindByEmail)
 	if err != nil {
 		return *seller, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	defer stmt.Close()
 
-	dbRes := stmt.QueryRow(seller.Email)
+	dbRes := stmt.QueryRowContext(ctx, seller.Email)
 
-	if err := dbRes.Scan(&seller.ID, &seller.Email, &seller.Name, &seller.Password, &seller.PickUpAddress); err != nil {
+	if err := dbRes.Scan(&seller.ID, &seller.Email, &seller.Name, &seller.Password, &seller.PickUpAddress, &seller.PickUpProvince, &seller.PickUpPostalCode, &seller.TaxStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *seller, resterrors.NewNotFoundError(fmt.Sprintf("seller with email %s not found", seller.Email))
+		}
 		return *seller, resterrors.NewInternalServerError("error when trying to get data", err)
 	}
 	return *seller, nil
@@ -1,9 +1,11 @@
 package sellerrepo_test
 
 import (
+	"context"
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	sellerrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/seller_repository"
@@ -39,11 +41,14 @@ func (suite *TestSuite) SetupTest() {
 	suite.NoError(err)
 
 	suite.expectedSeller1 = entity.Seller{
-		ID:            1,
-		Email:         "seller1@mail.com",
-		Name:          "seller",
-		Password:      string(suite.hashedPassword),
-		PickUpAddress: "pickup address",
+		ID:               1,
+		Email:            "seller1@mail.com",
+		Name:             "seller",
+		Password:         string(suite.hashedPassword),
+		PickUpAddress:    "pickup address",
+		PickUpProvince:   "DKI Jakarta",
+		PickUpPostalCode: "12190",
+		TaxStatus:        entity.TAX_UNREGISTERED,
 	}
 
 	suite.expectedSeller2 = entity.Seller{
@@ -52,6 +57,7 @@ func (suite *TestSuite) SetupTest() {
 		Name:          "seller",
 		Password:      string(suite.hashedPassword),
 		PickUpAddress: "pickup address",
+		TaxStatus:     entity.TAX_UNREGISTERED,
 	}
 
 	suite.expectedSeller3 = entity.Seller{
@@ -60,6 +66,7 @@ func (suite *TestSuite) SetupTest() {
 		Name:          "seller",
 		Password:      string(suite.hashedPassword),
 		PickUpAddress: "pickup address",
+		TaxStatus:     entity.TAX_UNREGISTERED,
 	}
 }
 
@@ -68,47 +75,47 @@ func TestSellerRepo(t *testing.T) {
 }
 
 func (suite *TestSuite) TestGetAll() {
-	queryGetAll := "SELECT id, email, name, pickup_address FROM sellers;"
+	queryGetAll := "SELECT id, email, name, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers;"
 	prep := suite.mock.ExpectQuery(queryGetAll)
 
-	row1 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address"}).
-		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress)
-	row2 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address"}).
-		AddRow(suite.expectedSeller2.ID, suite.expectedSeller2.Email, suite.expectedSeller2.Name, suite.expectedSeller2.PickUpAddress)
-	row3 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address"}).
-		AddRow(suite.expectedSeller3.ID, suite.expectedSeller3.Email, suite.expectedSeller3.Name, suite.expectedSeller3.PickUpAddress)
+	row1 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address", "pickup_province", "pickup_postal_code", "tax_status"}).
+		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.PickUpProvince, suite.expectedSeller1.PickUpPostalCode, suite.expectedSeller1.TaxStatus)
+	row2 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address", "pickup_province", "pickup_postal_code", "tax_status"}).
+		AddRow(suite.expectedSeller2.ID, suite.expectedSeller2.Email, suite.expectedSeller2.Name, suite.expectedSeller2.PickUpAddress, suite.expectedSeller2.PickUpProvince, suite.expectedSeller2.PickUpPostalCode, suite.expectedSeller2.TaxStatus)
+	row3 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address", "pickup_province", "pickup_postal_code", "tax_status"}).
+		AddRow(suite.expectedSeller3.ID, suite.expectedSeller3.Email, suite.expectedSeller3.Name, suite.expectedSeller3.PickUpAddress, suite.expectedSeller3.PickUpProvince, suite.expectedSeller3.PickUpPostalCode, suite.expectedSeller3.TaxStatus)
 
 	var rows = []*sqlmock.Rows{}
 	rows = append(rows, row1, row2, row3)
 	prep.WillReturnRows(rows...)
 
-	res, repoErr := suite.repo.GetAll()
+	res, repoErr := suite.repo.GetAll(context.Background())
 	suite.NoError(repoErr)
 	suite.NotNil(res)
 }
 
 func (suite *TestSuite) TestGetByID() {
-	queryGetById := "SELECT id, email, name, pickup_address FROM sellers WHERE id=?;"
+	queryGetById := "SELECT id, email, name, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers WHERE id=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryGetById))
 
-	row1 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address"}).
-		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress)
+	row1 := sqlmock.NewRows([]string{"id", "email", "name", "pickup_address", "pickup_province", "pickup_postal_code", "tax_status"}).
+		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.PickUpProvince, suite.expectedSeller1.PickUpPostalCode, suite.expectedSeller1.TaxStatus)
 	prep.ExpectQuery().WithArgs(suite.expectedSeller1.ID).WillReturnRows(row1)
 
 	seller := new(entity.Seller)
 	seller.ID = suite.expectedSeller1.ID
 
-	repoErr := suite.repo.GetByID(seller)
+	repoErr := suite.repo.GetByID(context.Background(), seller)
 	suite.NoError(repoErr)
-	suite.NotNil(seller)
+	suite.Equal(entity.TAX_UNREGISTERED, seller.TaxStatus)
 }
 
 func (suite *TestSuite) TestStore() {
-	queryInsert := "INSERT INTO sellers(email, name, password, pickup_address) VALUES(?, ?, ?, ?);"
+	queryInsert := "INSERT INTO sellers(email, name, password, pickup_address, pickup_province, pickup_postal_code, tax_status, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?);"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryInsert))
 
 	prep.ExpectExec().
-		WithArgs(suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.Password, suite.expectedSeller1.PickUpAddress).
+		WithArgs(suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.Password, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.PickUpProvince, suite.expectedSeller1.PickUpPostalCode, suite.expectedSeller1.TaxStatus, "2021-05-01 08:30:00").
 		WillReturnResult(sqlmock.NewResult(suite.expectedSeller1.ID, 1))
 
 	seller := new(entity.Seller)
@@ -117,19 +124,24 @@ func (suite *TestSuite) TestStore() {
 	seller.Name = suite.expectedSeller1.Name
 	seller.Password = suite.expectedSeller1.Password
 	seller.PickUpAddress = suite.expectedSeller1.PickUpAddress
+	seller.PickUpProvince = suite.expectedSeller1.PickUpProvince
+	seller.PickUpPostalCode = suite.expectedSeller1.PickUpPostalCode
+	seller.TaxStatus = suite.expectedSeller1.TaxStatus
+	seller.CreatedAt = time.Date(2021, 5, 1, 8, 30, 0, 0, time.UTC)
 
-	repoErr := suite.repo.Store(seller)
+	repoErr := suite.repo.Store(context.Background(), seller)
 
 	suite.NoError(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
 	suite.NotNil(seller)
 }
 
 func (suite *TestSuite) TestUpdate() {
-	queryUpdate := "UPDATE sellers SET email=?, name=?, pickup_address=? WHERE id=?;"
+	queryUpdate := "UPDATE sellers SET email=?, name=?, pickup_address=?, pickup_province=?, pickup_postal_code=?, tax_status=? WHERE id=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryUpdate))
 
 	prep.ExpectExec().
-		WithArgs(suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.ID).
+		WithArgs(suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.PickUpProvince, suite.expectedSeller1.PickUpPostalCode, suite.expectedSeller1.TaxStatus, suite.expectedSeller1.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	seller := new(entity.Seller)
@@ -138,8 +150,11 @@ func (suite *TestSuite) TestUpdate() {
 	seller.Name = suite.expectedSeller1.Name
 	seller.Password = suite.expectedSeller1.Password
 	seller.PickUpAddress = suite.expectedSeller1.PickUpAddress
+	seller.PickUpProvince = suite.expectedSeller1.PickUpProvince
+	seller.PickUpPostalCode = suite.expectedSeller1.PickUpPostalCode
+	seller.TaxStatus = suite.expectedSeller1.TaxStatus
 
-	repoErr := suite.repo.Update(seller)
+	repoErr := suite.repo.Update(context.Background(), seller)
 	suite.NoError(repoErr)
 	suite.NotNil(seller)
 }
@@ -155,23 +170,23 @@ func (suite *TestSuite) TestDelete() {
 	seller := new(entity.Seller)
 	seller.ID = suite.expectedSeller1.ID
 
-	repoErr := suite.repo.Delete(seller)
+	repoErr := suite.repo.Delete(context.Background(), seller)
 	suite.NoError(repoErr)
 	suite.NotNil(seller)
 }
 
 func (suite *TestSuite) TestGetByEmail() {
-	queryFindByEmail := "SELECT id, email, name, password, pickup_address FROM sellers WHERE email=?;"
+	queryFindByEmail := "SELECT id, email, name, password, pickup_address, pickup_province, pickup_postal_code, tax_status FROM sellers WHERE email=?;"
 	prep := suite.mock.ExpectPrepare(regexp.QuoteMeta(queryFindByEmail))
 
-	row1 := sqlmock.NewRows([]string{"id", "email", "name", "password", "pickup_address"}).
-		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.Password, suite.expectedSeller1.PickUpAddress)
+	row1 := sqlmock.NewRows([]string{"id", "email", "name", "password", "pickup_address", "pickup_province", "pickup_postal_code", "tax_status"}).
+		AddRow(suite.expectedSeller1.ID, suite.expectedSeller1.Email, suite.expectedSeller1.Name, suite.expectedSeller1.Password, suite.expectedSeller1.PickUpAddress, suite.expectedSeller1.PickUpProvince, suite.expectedSeller1.PickUpPostalCode, suite.expectedSeller1.TaxStatus)
 	prep.ExpectQuery().WithArgs(suite.expectedSeller1.Email).WillReturnRows(row1)
 
 	seller := new(entity.Seller)
 	seller.Email = suite.expectedSeller1.Email
 
-	repoRes, repoErr := suite.repo.GetByEmail(seller)
+	repoRes, repoErr := suite.repo.GetByEmail(context.Background(), seller)
 	suite.NoError(repoErr)
 	suite.NotNil(repoRes)
 }
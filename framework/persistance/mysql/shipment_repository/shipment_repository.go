@@ -0,0 +1,184 @@
+package shipmentrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert       = "INSERT INTO shipments(order_id, courier, tracking_number, status, shipped_at) VALUES(?, ?, ?, ?, ?);"
+	queryGetByOrderId = "SELECT id, order_id, courier, tracking_number, status, shipped_at, delivered_at, checked_at FROM shipments WHERE order_id=?;"
+	queryGetInTransit = `SELECT id, order_id, courier, tracking_number, status, shipped_at, delivered_at, checked_at FROM shipments
+	WHERE status=? AND (checked_at IS NULL OR checked_at<?) ORDER BY checked_at, id LIMIT ?;`
+	queryUpdateTracking = "UPDATE shipments SET status=?, delivered_at=?, checked_at=? WHERE id=?;"
+
+	// events the shipment already has are skipped, couriers report every event on each call
+	teInsert = `INSERT IGNORE INTO shipment_tracking_events(shipment_id, code, description, location, occurred_at)
+	VALUES(?, ?, ?, NULLIF(?, ''), ?);`
+	teGetByShipmentId = `SELECT id, shipment_id, code, description, COALESCE(location, ''), occurred_at FROM shipment_tracking_events
+	WHERE shipment_id=? ORDER BY occurred_at, id;`
+)
+
+type mysqlShipmentRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlShipmentRepository will create a object with entity.ShipmentRepository interface representation
+func NewMysqlShipmentRepository(Conn *sql.DB) entity.ShipmentRepository {
+	return &mysqlShipmentRepository{Conn}
+}
+
+func (m *mysqlShipmentRepository) Store(ctx context.Context, shipment *entity.Shipment) resterrors.RestErr {
+	dbRes, err := m.Conn.ExecContext(ctx, queryInsert, shipment.OrderID, shipment.Courier, shipment.TrackingNumber,
+		shipment.Status, shipment.ShippedAt.Format(dateTimeLayout))
+	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("order with id %d was already shipped", shipment.OrderID))
+		}
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	shipmentID, err := dbRes.LastInsertId()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	shipment.ID = shipmentID
+	shipment.Events = []entity.TrackingEvent{}
+	return nil
+}
+
+func (m *mysqlShipmentRepository) GetByOrderID(ctx context.Context, orderID int64) (entity.Shipment, resterrors.RestErr) {
+	shipment := entity.Shipment{}
+	err := scanShipment(m.Conn.QueryRowContext(ctx, queryGetByOrderId, orderID), &shipment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return shipment, resterrors.NewNotFoundError(fmt.Sprintf("order with id %d wasn't shipped", orderID))
+		}
+		return shipment, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+
+	teRes, err := m.Conn.QueryContext(ctx, teGetByShipmentId, shipment.ID)
+	if err != nil {
+		return entity.Shipment{}, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer teRes.Close()
+
+	shipment.Events = []entity.TrackingEvent{}
+	for teRes.Next() {
+		te := entity.TrackingEvent{}
+		var occurredAt []uint8
+		if err = teRes.Scan(&te.ID, &te.ShipmentID, &te.Code, &te.Description, &te.Location, &occurredAt); err != nil {
+			return entity.Shipment{}, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if te.OccurredAt, err = helpers.GetTimeFromUint8(occurredAt); err != nil {
+			return entity.Shipment{}, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		shipment.Events = append(shipment.Events, te)
+	}
+	if err = teRes.Err(); err != nil {
+		return entity.Shipment{}, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return shipment, nil
+}
+
+func (m *mysqlShipmentRepository) GetInTransit(ctx context.Context, checkedBefore time.Time, limit int) ([]entity.Shipment, resterrors.RestErr) {
+	sRes, err := m.Conn.QueryContext(ctx, queryGetInTransit, entity.SHIPMENT_IN_TRANSIT, checkedBefore.Format(dateTimeLayout), limit)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer sRes.Close()
+
+	shipments := []entity.Shipment{}
+	for sRes.Next() {
+		shipment := entity.Shipment{}
+		if err = scanShipment(sRes, &shipment); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		shipments = append(shipments, shipment)
+	}
+	if err = sRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return shipments, nil
+}
+
+func (m *mysqlShipmentRepository) SaveTracking(ctx context.Context, shipment *entity.Shipment, events []entity.TrackingEvent) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	for _, te := range events {
+		_, err := tx.ExecContext(ctx, teInsert, shipment.ID, te.Code, te.Description, te.Location, te.OccurredAt.Format(dateTimeLayout))
+		if err != nil {
+			tx.Rollback()
+			return resterrors.NewInternalServerError("error when trying to save data", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, queryUpdateTracking, shipment.Status, formatNullableTime(shipment.DeliveredAt),
+		formatNullableTime(shipment.CheckedAt), shipment.ID)
+	if err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	// commit the change if all queries ran successfully
+	if err = tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShipment(row scanner, shipment *entity.Shipment) error {
+	var shippedAt, deliveredAt, checkedAt []uint8
+	err := row.Scan(&shipment.ID, &shipment.OrderID, &shipment.Courier, &shipment.TrackingNumber, &shipment.Status,
+		&shippedAt, &deliveredAt, &checkedAt)
+	if err != nil {
+		return err
+	}
+
+	if shipment.ShippedAt, err = helpers.GetTimeFromUint8(shippedAt); err != nil {
+		return err
+	}
+	if shipment.DeliveredAt, err = getNullableTime(deliveredAt); err != nil {
+		return err
+	}
+	if shipment.CheckedAt, err = getNullableTime(checkedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func getNullableTime(value []uint8) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := helpers.GetTimeFromUint8(value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func formatNullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(dateTimeLayout)
+}
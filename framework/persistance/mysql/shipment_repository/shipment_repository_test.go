@@ -0,0 +1,185 @@
+package shipmentrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	shipmentrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/shipment_repository"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert       = "INSERT INTO shipments(order_id, courier, tracking_number, status, shipped_at) VALUES(?, ?, ?, ?, ?);"
+	queryGetByOrderId = "SELECT id, order_id, courier, tracking_number, status, shipped_at, delivered_at, checked_at FROM shipments WHERE order_id=?;"
+	queryGetInTransit = `SELECT id, order_id, courier, tracking_number, status, shipped_at, delivered_at, checked_at FROM shipments
+	WHERE status=? AND (checked_at IS NULL OR checked_at<?) ORDER BY checked_at, id LIMIT ?;`
+	queryUpdateTracking = "UPDATE shipments SET status=?, delivered_at=?, checked_at=? WHERE id=?;"
+
+	teInsert = `INSERT IGNORE INTO shipment_tracking_events(shipment_id, code, description, location, occurred_at)
+	VALUES(?, ?, ?, NULLIF(?, ''), ?);`
+	teGetByShipmentId = `SELECT id, shipment_id, code, description, COALESCE(location, ''), occurred_at FROM shipment_tracking_events
+	WHERE shipment_id=? ORDER BY occurred_at, id;`
+)
+
+var shipmentColumns = []string{"id", "order_id", "courier", "tracking_number", "status", "shipped_at", "delivered_at", "checked_at"}
+
+type TestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	mock     sqlmock.Sqlmock
+	repo     entity.ShipmentRepository
+	shipment entity.Shipment
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = shipmentrepo.NewMysqlShipmentRepository(suite.db)
+	suite.shipment = entity.Shipment{
+		OrderID:        1,
+		Courier:        "JNE",
+		TrackingNumber: "JNE123",
+		Status:         entity.SHIPMENT_IN_TRANSIT,
+		ShippedAt:      time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestShipmentRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs(int64(1), "JNE", "JNE123", entity.SHIPMENT_IN_TRANSIT, "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	repoErr := suite.repo.Store(context.Background(), &suite.shipment)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(4), suite.shipment.ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreAlreadyShipped() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'order_id'"})
+
+	repoErr := suite.repo.Store(context.Background(), &suite.shipment)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(shipmentColumns).
+			AddRow(4, 1, "JNE", "JNE123", entity.SHIPMENT_DELIVERED, []uint8("2021-05-01 10:00:00"),
+				[]uint8("2021-05-03 09:00:00"), []uint8("2021-05-03 10:00:00")))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(teGetByShipmentId)).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "code", "description", "location", "occurred_at"}).
+			AddRow(7, 4, "PICKED_UP", "Picked up by courier", "Jakarta", []uint8("2021-05-01 12:00:00")).
+			AddRow(8, 4, "DELIVERED", "Delivered", "", []uint8("2021-05-03 09:00:00")))
+
+	res, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.Equal(entity.SHIPMENT_DELIVERED, res.Status)
+	suite.Equal(time.Date(2021, 5, 3, 9, 0, 0, 0, time.UTC), *res.DeliveredAt)
+	suite.Len(res.Events, 2)
+	suite.Equal("Jakarta", res.Events[0].Location)
+	suite.Equal("DELIVERED", res.Events[1].Code)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetByOrderIDNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(shipmentColumns))
+
+	_, repoErr := suite.repo.GetByOrderID(context.Background(), 1)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusNotFound, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestGetInTransit() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetInTransit)).
+		WithArgs(entity.SHIPMENT_IN_TRANSIT, "2021-05-02 10:00:00", 100).
+		WillReturnRows(sqlmock.NewRows(shipmentColumns).
+			AddRow(4, 1, "JNE", "JNE123", entity.SHIPMENT_IN_TRANSIT, []uint8("2021-05-01 10:00:00"), nil, nil).
+			AddRow(5, 2, "JNE", "JNE456", entity.SHIPMENT_IN_TRANSIT, []uint8("2021-05-01 11:00:00"), nil,
+				[]uint8("2021-05-01 12:00:00")))
+
+	res, repoErr := suite.repo.GetInTransit(context.Background(), time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC), 100)
+
+	suite.Nil(repoErr)
+	suite.Len(res, 2)
+	suite.Nil(res[0].CheckedAt)
+	suite.Nil(res[1].DeliveredAt)
+	suite.Equal(time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC), *res[1].CheckedAt)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestSaveTracking() {
+	checkedAt := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	deliveredAt := time.Date(2021, 5, 3, 9, 0, 0, 0, time.UTC)
+	suite.shipment.ID = 4
+	suite.shipment.Status = entity.SHIPMENT_DELIVERED
+	suite.shipment.DeliveredAt = &deliveredAt
+	suite.shipment.CheckedAt = &checkedAt
+	events := []entity.TrackingEvent{
+		{Code: "PICKED_UP", Description: "Picked up by courier", Location: "Jakarta", OccurredAt: time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{Code: "DELIVERED", Description: "Delivered", OccurredAt: deliveredAt},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(teInsert)).
+		WithArgs(int64(4), "PICKED_UP", "Picked up by courier", "Jakarta", "2021-05-01 12:00:00").
+		// already saved by an earlier poll
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(teInsert)).
+		WithArgs(int64(4), "DELIVERED", "Delivered", "", "2021-05-03 09:00:00").
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateTracking)).
+		WithArgs(entity.SHIPMENT_DELIVERED, "2021-05-03 09:00:00", "2021-05-03 10:00:00", int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.SaveTracking(context.Background(), &suite.shipment, events)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestSaveTrackingError() {
+	checkedAt := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	suite.shipment.ID = 4
+	suite.shipment.CheckedAt = &checkedAt
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryUpdateTracking)).
+		WithArgs(entity.SHIPMENT_IN_TRANSIT, nil, "2021-05-03 10:00:00", int64(4)).
+		WillReturnError(errors.New("lock wait timeout"))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.SaveTracking(context.Background(), &suite.shipment, nil)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusInternalServerError, repoErr.Status())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,167 @@
+package voucherrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsert = `INSERT INTO vouchers(code, type, value, seller_id, min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	queryGetByCode = `SELECT id, code, type, value, COALESCE(seller_id, 0), min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, used_count, created_at FROM vouchers WHERE code=?;`
+	queryGetBySellerId = `SELECT id, code, type, value, COALESCE(seller_id, 0), min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, used_count, created_at FROM vouchers WHERE seller_id=? ORDER BY id DESC;`
+	queryGetAll = `SELECT id, code, type, value, COALESCE(seller_id, 0), min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, used_count, created_at FROM vouchers ORDER BY id DESC;`
+	queryRelease = "UPDATE vouchers SET used_count=used_count-1 WHERE id=? AND used_count>0;"
+
+	vuGetByOrderId = "SELECT voucher_id FROM voucher_usages WHERE order_id=? FOR UPDATE;"
+	vuDelete       = "DELETE FROM voucher_usages WHERE order_id=?;"
+)
+
+type mysqlVoucherRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlVoucherRepository will create a object with entity.VoucherRepository interface representation
+func NewMysqlVoucherRepository(Conn *sql.DB) entity.VoucherRepository {
+	return &mysqlVoucherRepository{Conn}
+}
+
+func (m *mysqlVoucherRepository) Store(ctx context.Context, voucher *entity.Voucher) resterrors.RestErr {
+	var sellerID interface{}
+	if voucher.SellerID != 0 {
+		sellerID = voucher.SellerID
+	}
+
+	dbRes, err := m.Conn.ExecContext(ctx, queryInsert, voucher.Code, voucher.Type, voucher.Value, sellerID, voucher.MinSpend,
+		voucher.MaxDiscount, voucher.StartsAt.Format(dateTimeLayout), voucher.EndsAt.Format(dateTimeLayout), voucher.UsageLimit,
+		voucher.PerBuyerLimit, voucher.CreatedAt.Format(dateTimeLayout))
+	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("voucher %s is already exist", voucher.Code))
+		}
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	voucherID, err := dbRes.LastInsertId()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	voucher.ID = voucherID
+	return nil
+}
+
+func (m *mysqlVoucherRepository) GetByCode(ctx context.Context, code string) (entity.Voucher, resterrors.RestErr) {
+	res := entity.Voucher{}
+	err := scanVoucher(m.Conn.QueryRowContext(ctx, queryGetByCode, code), &res)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, resterrors.NewNotFoundError(fmt.Sprintf("voucher %s not found", code))
+		}
+		return res, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlVoucherRepository) GetBySellerID(ctx context.Context, sellerID int64) ([]entity.Voucher, resterrors.RestErr) {
+	return m.fetch(ctx, queryGetBySellerId, sellerID)
+}
+
+func (m *mysqlVoucherRepository) GetAll(ctx context.Context) ([]entity.Voucher, resterrors.RestErr) {
+	return m.fetch(ctx, queryGetAll)
+}
+
+func (m *mysqlVoucherRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]entity.Voucher, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.Voucher{}
+	for dbRes.Next() {
+		voucher := entity.Voucher{}
+		if err = scanVoucher(dbRes, &voucher); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, voucher)
+	}
+	if err = dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlVoucherRepository) Release(ctx context.Context, orderID int64) resterrors.RestErr {
+	// start transaction sequence
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	var voucherID int64
+	err = tx.QueryRowContext(ctx, vuGetByOrderId, orderID).Scan(&voucherID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, vuDelete, orderID); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	if _, err := tx.ExecContext(ctx, queryRelease, voucherID); err != nil {
+		tx.Rollback()
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	// commit the change if all queries ran successfully
+	if err = tx.Commit(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+	return nil
+}
+
+func scanVoucher(row interface{ Scan(...interface{}) error }, voucher *entity.Voucher) error {
+	var value, minSpend, maxDiscount, startsAt, endsAt, createdAt []uint8
+	err := row.Scan(&voucher.ID, &voucher.Code, &voucher.Type, &value, &voucher.SellerID, &minSpend, &maxDiscount, &startsAt,
+		&endsAt, &voucher.UsageLimit, &voucher.PerBuyerLimit, &voucher.UsedCount, &createdAt)
+	if err != nil {
+		return err
+	}
+
+	if voucher.Value, err = decimal.NewFromString(string(value)); err != nil {
+		return err
+	}
+	if voucher.MinSpend, err = decimal.NewFromString(string(minSpend)); err != nil {
+		return err
+	}
+	if voucher.MaxDiscount, err = decimal.NewFromString(string(maxDiscount)); err != nil {
+		return err
+	}
+	if voucher.StartsAt, err = helpers.GetTimeFromUint8(startsAt); err != nil {
+		return err
+	}
+	if voucher.EndsAt, err = helpers.GetTimeFromUint8(endsAt); err != nil {
+		return err
+	}
+	if voucher.CreatedAt, err = helpers.GetTimeFromUint8(createdAt); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package voucherrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	voucherrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/voucher_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsert = `INSERT INTO vouchers(code, type, value, seller_id, min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	queryGetByCode = `SELECT id, code, type, value, COALESCE(seller_id, 0), min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, used_count, created_at FROM vouchers WHERE code=?;`
+	queryGetBySellerId = `SELECT id, code, type, value, COALESCE(seller_id, 0), min_spend, max_discount, starts_at, ends_at, usage_limit, 
+	per_buyer_limit, used_count, created_at FROM vouchers WHERE seller_id=? ORDER BY id DESC;`
+	queryRelease = "UPDATE vouchers SET used_count=used_count-1 WHERE id=? AND used_count>0;"
+
+	vuGetByOrderId = "SELECT voucher_id FROM voucher_usages WHERE order_id=? FOR UPDATE;"
+	vuDelete       = "DELETE FROM voucher_usages WHERE order_id=?;"
+)
+
+var voucherColumns = []string{"id", "code", "type", "value", "seller_id", "min_spend", "max_discount", "starts_at", "ends_at",
+	"usage_limit", "per_buyer_limit", "used_count", "created_at"}
+
+type TestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	repo    entity.VoucherRepository
+	voucher entity.Voucher
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = voucherrepo.NewMysqlVoucherRepository(suite.db)
+	suite.voucher = entity.Voucher{
+		Code:          "HEMAT10",
+		Type:          entity.VOUCHER_PERCENTAGE,
+		Value:         decimal.NewFromInt(10),
+		MinSpend:      decimal.NewFromInt(100000),
+		MaxDiscount:   decimal.NewFromInt(50000),
+		StartsAt:      time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:        time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		UsageLimit:    100,
+		PerBuyerLimit: 1,
+		CreatedAt:     time.Date(2021, 4, 30, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestVoucherRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStore() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WithArgs("HEMAT10", entity.VOUCHER_PERCENTAGE, suite.voucher.Value, nil, suite.voucher.MinSpend, suite.voucher.MaxDiscount,
+			"2021-05-01 00:00:00", "2021-06-01 00:00:00", int64(100), int64(1), "2021-04-30 10:00:00").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	repoErr := suite.repo.Store(context.Background(), &suite.voucher)
+
+	suite.Nil(repoErr)
+	suite.Equal(int64(3), suite.voucher.ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreDuplicateCode() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsert)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'HEMAT10' for key 'code_UNIQUE'"})
+
+	repoErr := suite.repo.Store(context.Background(), &suite.voucher)
+
+	suite.NotNil(repoErr)
+	suite.Equal(http.StatusConflict, repoErr.Status())
+}
+
+func (suite *TestSuite) TestGetByCode() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByCode)).
+		WithArgs("HEMAT10").
+		WillReturnRows(sqlmock.NewRows(voucherColumns).
+			AddRow(3, "HEMAT10", "PERCENTAGE", []uint8("10.00"), 2, []uint8("100000.00"), []uint8("50000.00"),
+				[]uint8("2021-05-01 00:00:00"), []uint8("2021-06-01 00:00:00"), 100, 1, 7, []uint8("2021-04-30 10:00:00")))
+
+	res, repoErr := suite.repo.GetByCode(context.Background(), "HEMAT10")
+
+	suite.Nil(repoErr)
+	suite.Equal(entity.VOUCHER_PERCENTAGE, res.Type)
+	suite.Equal("10", res.Value.String())
+	suite.Equal(int64(2), res.SellerID)
+	suite.Equal(int64(7), res.UsedCount)
+	suite.Equal(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), res.EndsAt)
+}
+
+func (suite *TestSuite) TestGetByCodeNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetByCode)).
+		WithArgs("NOPE").
+		WillReturnRows(sqlmock.NewRows(voucherColumns))
+
+	_, repoErr := suite.repo.GetByCode(context.Background(), "NOPE")
+
+	suite.True(errors.Is(repoErr, resterrors.ErrNotFound))
+}
+
+func (suite *TestSuite) TestGetBySellerID() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetBySellerId)).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(voucherColumns).
+			AddRow(3, "HEMAT10", "PERCENTAGE", []uint8("10.00"), 2, []uint8("0.00"), []uint8("0.00"),
+				[]uint8("2021-05-01 00:00:00"), []uint8("2021-06-01 00:00:00"), 0, 0, 0, []uint8("2021-04-30 10:00:00")))
+
+	res, repoErr := suite.repo.GetBySellerID(context.Background(), 2)
+
+	suite.Nil(repoErr)
+	suite.Len(res, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestRelease() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(vuGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"voucher_id"}).AddRow(3))
+	suite.mock.ExpectExec(regexp.QuoteMeta(vuDelete)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryRelease)).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	repoErr := suite.repo.Release(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestReleaseWithoutVoucher() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(vuGetByOrderId)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"voucher_id"}))
+	suite.mock.ExpectRollback()
+
+	repoErr := suite.repo.Release(context.Background(), 1)
+
+	suite.Nil(repoErr)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
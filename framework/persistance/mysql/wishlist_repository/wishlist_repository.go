@@ -0,0 +1,165 @@
+package wishlistrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	mysqlutils "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/mysql_utils"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dateTimeLayout = "2006-01-02 15:04:05"
+
+	queryInsertItem        = "INSERT INTO wishlist_items(buyer_id, product_id, added_at) VALUES(?, ?, ?);"
+	queryGetItemsByBuyerId = `SELECT p.id, COALESCE(p.sku, ''), p.name, p.description, p.price, p.weight, COALESCE(p.category, ''),
+	p.seller_id, w.added_at FROM wishlist_items w JOIN products p ON p.id=w.product_id
+	WHERE w.buyer_id=? ORDER BY w.added_at DESC, p.id DESC;`
+	queryDeleteItem             = "DELETE FROM wishlist_items WHERE buyer_id=? AND product_id=?;"
+	queryGetBuyerIdsByProductId = "SELECT buyer_id FROM wishlist_items WHERE product_id=? ORDER BY buyer_id;"
+
+	queryInsertFollowed       = "INSERT INTO followed_sellers(buyer_id, seller_id, followed_at) VALUES(?, ?, ?);"
+	queryGetFollowedByBuyerId = `SELECT s.id, s.name, f.followed_at FROM followed_sellers f JOIN sellers s ON s.id=f.seller_id
+	WHERE f.buyer_id=? ORDER BY f.followed_at DESC, s.id DESC;`
+	queryDeleteFollowed = "DELETE FROM followed_sellers WHERE buyer_id=? AND seller_id=?;"
+)
+
+type mysqlWishlistRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlWishlistRepository will create a object with entity.WishlistRepository interface representation
+func NewMysqlWishlistRepository(Conn *sql.DB) entity.WishlistRepository {
+	return &mysqlWishlistRepository{Conn}
+}
+
+func (m *mysqlWishlistRepository) StoreItem(ctx context.Context, item *entity.WishlistItem) resterrors.RestErr {
+	_, err := m.Conn.ExecContext(ctx, queryInsertItem, item.BuyerID, item.Product.ID, item.AddedAt.Format(dateTimeLayout))
+	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("product with id %d is already in the wishlist", item.Product.ID))
+		}
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
+
+func (m *mysqlWishlistRepository) GetItemsByBuyerID(ctx context.Context, buyerID int64) ([]entity.WishlistItem, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, queryGetItemsByBuyerId, buyerID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.WishlistItem{}
+	for dbRes.Next() {
+		var price, addedAt []uint8
+		item := entity.WishlistItem{BuyerID: buyerID}
+		p := &item.Product
+		err := dbRes.Scan(&p.ID, &p.SKU, &p.Name, &p.Description, &price, &p.Weight, &p.Category, &p.Seller.ID, &addedAt)
+		if err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if p.Price, err = decimal.NewFromString(string(price)); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		if item.AddedAt, err = helpers.GetTimeFromUint8(addedAt); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, item)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlWishlistRepository) DeleteItem(ctx context.Context, buyerID, productID int64) resterrors.RestErr {
+	dbRes, err := m.Conn.ExecContext(ctx, queryDeleteItem, buyerID, productID)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("product with id %d is not in the wishlist", productID))
+	}
+	return nil
+}
+
+func (m *mysqlWishlistRepository) GetBuyerIDsByProductID(ctx context.Context, productID int64) ([]int64, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, queryGetBuyerIdsByProductId, productID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []int64{}
+	for dbRes.Next() {
+		var buyerID int64
+		if err := dbRes.Scan(&buyerID); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, buyerID)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlWishlistRepository) StoreFollowedSeller(ctx context.Context, followed *entity.FollowedSeller) resterrors.RestErr {
+	_, err := m.Conn.ExecContext(ctx, queryInsertFollowed, followed.BuyerID, followed.Seller.ID, followed.FollowedAt.Format(dateTimeLayout))
+	if err != nil {
+		if mysqlutils.IsDuplicateEntry(err) {
+			return resterrors.NewConflictError(fmt.Sprintf("seller with id %d is already followed", followed.Seller.ID))
+		}
+		return resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+	return nil
+}
+
+func (m *mysqlWishlistRepository) GetFollowedSellersByBuyerID(ctx context.Context, buyerID int64) ([]entity.FollowedSeller, resterrors.RestErr) {
+	dbRes, err := m.Conn.QueryContext(ctx, queryGetFollowedByBuyerId, buyerID)
+	if err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	defer dbRes.Close()
+
+	res := []entity.FollowedSeller{}
+	for dbRes.Next() {
+		var followedAt []uint8
+		followed := entity.FollowedSeller{BuyerID: buyerID}
+		if err := dbRes.Scan(&followed.Seller.ID, &followed.Seller.Name, &followedAt); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+
+		if followed.FollowedAt, err = helpers.GetTimeFromUint8(followedAt); err != nil {
+			return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+		}
+		res = append(res, followed)
+	}
+
+	if err := dbRes.Err(); err != nil {
+		return nil, resterrors.NewInternalServerError("error when trying to get data", err)
+	}
+	return res, nil
+}
+
+func (m *mysqlWishlistRepository) DeleteFollowedSeller(ctx context.Context, buyerID, sellerID int64) resterrors.RestErr {
+	dbRes, err := m.Conn.ExecContext(ctx, queryDeleteFollowed, buyerID, sellerID)
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+
+	if affected, err := dbRes.RowsAffected(); err == nil && affected == 0 {
+		return resterrors.NewNotFoundError(fmt.Sprintf("seller with id %d is not followed", sellerID))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package wishlistrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	wishlistrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/wishlist_repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	queryInsertItem        = "INSERT INTO wishlist_items(buyer_id, product_id, added_at) VALUES(?, ?, ?);"
+	queryGetItemsByBuyerId = `SELECT p.id, COALESCE(p.sku, ''), p.name, p.description, p.price, p.weight, COALESCE(p.category, ''),
+	p.seller_id, w.added_at FROM wishlist_items w JOIN products p ON p.id=w.product_id
+	WHERE w.buyer_id=? ORDER BY w.added_at DESC, p.id DESC;`
+	queryDeleteItem             = "DELETE FROM wishlist_items WHERE buyer_id=? AND product_id=?;"
+	queryGetBuyerIdsByProductId = "SELECT buyer_id FROM wishlist_items WHERE product_id=? ORDER BY buyer_id;"
+
+	queryInsertFollowed       = "INSERT INTO followed_sellers(buyer_id, seller_id, followed_at) VALUES(?, ?, ?);"
+	queryGetFollowedByBuyerId = `SELECT s.id, s.name, f.followed_at FROM followed_sellers f JOIN sellers s ON s.id=f.seller_id
+	WHERE f.buyer_id=? ORDER BY f.followed_at DESC, s.id DESC;`
+	queryDeleteFollowed = "DELETE FROM followed_sellers WHERE buyer_id=? AND seller_id=?;"
+)
+
+type TestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo entity.WishlistRepository
+	now  time.Time
+}
+
+// before each test
+func (suite *TestSuite) SetupTest() {
+	var err error
+	suite.db, suite.mock, err = sqlmock.New()
+	suite.NoError(err)
+
+	suite.repo = wishlistrepo.NewMysqlWishlistRepository(suite.db)
+	suite.now = time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func TestWishlistRepo(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
+
+func (suite *TestSuite) TestStoreItem() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertItem)).
+		WithArgs(int64(3), int64(7), "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	item := entity.WishlistItem{BuyerID: 3, Product: entity.Product{ID: 7}, AddedAt: suite.now}
+	suite.NoError(suite.repo.StoreItem(context.Background(), &item))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TestSuite) TestStoreItemDuplicate() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertItem)).
+		WithArgs(int64(3), int64(7), "2021-05-01 10:00:00").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	item := entity.WishlistItem{BuyerID: 3, Product: entity.Product{ID: 7}, AddedAt: suite.now}
+	err := suite.repo.StoreItem(context.Background(), &item)
+	suite.Error(err)
+	suite.Equal(http.StatusConflict, err.Status())
+}
+
+func (suite *TestSuite) TestGetItemsByBuyerID() {
+	rows := sqlmock.NewRows([]string{"id", "sku", "name", "description", "price", "weight", "category", "seller_id", "added_at"}).
+		AddRow(7, "KOPI-1", "Kopi Toraja", "desc", []uint8("50000.00"), 250, "coffee", 1, []uint8("2021-05-01 10:00:00"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetItemsByBuyerId)).WithArgs(int64(3)).WillReturnRows(rows)
+
+	res, err := suite.repo.GetItemsByBuyerID(context.Background(), 3)
+	suite.NoError(err)
+	suite.Len(res, 1)
+	suite.Equal(int64(3), res[0].BuyerID)
+	suite.Equal(int64(7), res[0].Product.ID)
+	suite.Equal("KOPI-1", res[0].Product.SKU)
+	suite.Equal(int64(1), res[0].Product.Seller.ID)
+	suite.True(decimal.NewFromInt(50000).Equal(res[0].Product.Price))
+	suite.Equal(suite.now, res[0].AddedAt)
+}
+
+func (suite *TestSuite) TestDeleteItem() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteItem)).WithArgs(int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.NoError(suite.repo.DeleteItem(context.Background(), 3, 7))
+
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteItem)).WithArgs(int64(3), int64(8)).WillReturnResult(sqlmock.NewResult(0, 0))
+	err := suite.repo.DeleteItem(context.Background(), 3, 8)
+	suite.Error(err)
+	suite.Equal(http.StatusNotFound, err.Status())
+}
+
+func (suite *TestSuite) TestGetBuyerIDsByProductID() {
+	rows := sqlmock.NewRows([]string{"buyer_id"}).AddRow(3).AddRow(5)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetBuyerIdsByProductId)).WithArgs(int64(7)).WillReturnRows(rows)
+
+	res, err := suite.repo.GetBuyerIDsByProductID(context.Background(), 7)
+	suite.NoError(err)
+	suite.Equal([]int64{3, 5}, res)
+}
+
+func (suite *TestSuite) TestStoreFollowedSeller() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertFollowed)).
+		WithArgs(int64(3), int64(1), "2021-05-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	followed := entity.FollowedSeller{BuyerID: 3, Seller: entity.Seller{ID: 1}, FollowedAt: suite.now}
+	suite.NoError(suite.repo.StoreFollowedSeller(context.Background(), &followed))
+
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryInsertFollowed)).
+		WithArgs(int64(3), int64(1), "2021-05-01 10:00:00").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	err := suite.repo.StoreFollowedSeller(context.Background(), &followed)
+	suite.Error(err)
+	suite.Equal(http.StatusConflict, err.Status())
+}
+
+func (suite *TestSuite) TestGetFollowedSellersByBuyerID() {
+	rows := sqlmock.NewRows([]string{"id", "name", "followed_at"}).AddRow(1, "john seller", []uint8("2021-05-01 10:00:00"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(queryGetFollowedByBuyerId)).WithArgs(int64(3)).WillReturnRows(rows)
+
+	res, err := suite.repo.GetFollowedSellersByBuyerID(context.Background(), 3)
+	suite.NoError(err)
+	suite.Equal([]entity.FollowedSeller{{BuyerID: 3, Seller: entity.Seller{ID: 1, Name: "john seller"}, FollowedAt: suite.now}}, res)
+}
+
+func (suite *TestSuite) TestDeleteFollowedSeller() {
+	suite.mock.ExpectExec(regexp.QuoteMeta(queryDeleteFollowed)).WithArgs(int64(3), int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repo.DeleteFollowedSeller(context.Background(), 3, 1)
+	suite.Error(err)
+	suite.Equal(http.StatusNotFound, err.Status())
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	admincontroller "github.com/hieronimusbudi/komodo-backend/controllers/admin_controller"
+	"github.com/hieronimusbudi/komodo-backend/dependencies"
+	adminrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/admin_repository"
+	adminusecase "github.com/hieronimusbudi/komodo-backend/usecases/admin_usecase"
+)
+
+// adminRoutes used to define route and inject dependencies to repository, usecase and controller
+func adminRoutes(app *fiber.App, d *dependencies.Dependencies) {
+	// inject connection to repository
+	r := adminrepo.NewMysqlAdminRepository(d.Conn)
+	// inject repository to usecase
+	u := adminusecase.NewAdminUsecase(r)
+	// inject usecase to controller
+	c := admincontroller.NewAdminController(u, d.Validate)
+
+	app.Post("/admins/login", c.Login)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	analyticscontroller "github.com/hieronimusbudi/komodo-backend/controllers/analytics_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// analyticsRoutes used to define route and inject dependencies to repository, usecase and controller
+func analyticsRoutes(app *fiber.App, c *analyticscontroller.AnalyticsController) {
+	app.Get("/sellers/me/analytics", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).SellerSales)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	invoicecontroller "github.com/hieronimusbudi/komodo-backend/controllers/invoice_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// invoiceRoutes used to define route and inject dependencies to repository, usecase and controller
+func invoiceRoutes(app *fiber.App, c *invoicecontroller.InvoiceController) {
+	app.Get("/orders/:id/invoice.pdf", middlerwares.ValidateRequest, (*c).Invoice)
+}
@@ -7,8 +7,12 @@ import (
 )
 
 // orderRoutes used to define route and inject dependencies to repository, usecase and controller
-func orderRoutes(app *fiber.App, c *ordercontroller.OrderController) {
+func orderRoutes(app *fiber.App, c *ordercontroller.OrderController, idempotency fiber.Handler) {
 	app.Get("/orders/find/byuser", middlerwares.ValidateRequest, (*c).GetByUserID)
-	app.Post("/orders", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Store)
+	app.Get("/sellers/me/orders/export", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Export)
+	app.Get("/orders/:id", middlerwares.ValidateRequest, (*c).GetByID)
+	app.Post("/orders", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, idempotency, (*c).Store)
 	app.Put("/orders/:id/accept", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).AcceptOrder)
+	app.Post("/orders/:id/cancel", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).CancelOrder)
+	app.Post("/orders/:id/reject", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).RejectOrder)
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	paymentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/payment_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// paymentRoutes used to define route and inject dependencies to repository, usecase and controller
+func paymentRoutes(app *fiber.App, c *paymentcontroller.PaymentController) {
+	app.Post("/orders/:id/payment", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Create)
+	app.Get("/orders/:id/payment", middlerwares.ValidateRequest, (*c).GetByOrderID)
+	app.Post("/orders/:id/refunds", middlerwares.ValidateRequest, (*c).Refund)
+	app.Get("/orders/:id/refunds", middlerwares.ValidateRequest, (*c).GetRefunds)
+	// gateways authenticate with the signature of the callback instead of a token
+	app.Post("/webhooks/payments/:provider", (*c).HandleWebhook)
+	app.Post("/admin/payment-webhook-events/:id/replay", middlerwares.ValidateRequest, middlerwares.AdminTypeChecker,
+		(*c).ReplayWebhookEvent)
+}
@@ -10,4 +10,7 @@ import (
 func productRoutes(app *fiber.App, c *productcontroller.ProductController) {
 	app.Get("/products", (*c).GetAll)
 	app.Post("/products", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Store)
+	app.Put("/products/:id", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Update)
+	app.Post("/sellers/me/products/import", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Import)
+	app.Get("/sellers/me/products/export", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Export)
 }
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	reportcontroller "github.com/hieronimusbudi/komodo-backend/controllers/report_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// reportRoutes used to define route and inject dependencies to repository, usecase and controller
+func reportRoutes(app *fiber.App, c *reportcontroller.ReportController) {
+	app.Get("/admin/reports/platform", middlerwares.ValidateRequest, middlerwares.AdminTypeChecker, (*c).PlatformReport)
+	app.Get("/admin/reports/platform/export", middlerwares.ValidateRequest, middlerwares.AdminTypeChecker,
+		(*c).ExportPlatformReport)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	returncontroller "github.com/hieronimusbudi/komodo-backend/controllers/return_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// returnRoutes used to define route and inject dependencies to repository, usecase and controller
+func returnRoutes(app *fiber.App, c *returncontroller.ReturnController) {
+	app.Post("/orders/:id/returns", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Request)
+	app.Get("/orders/:id/returns", middlerwares.ValidateRequest, (*c).GetByOrderID)
+	app.Get("/returns/:id", middlerwares.ValidateRequest, (*c).GetByID)
+	app.Post("/returns/:id/approve", middlerwares.ValidateRequest, (*c).Approve)
+	app.Post("/returns/:id/reject", middlerwares.ValidateRequest, (*c).Reject)
+	app.Post("/returns/:id/cancel", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Cancel)
+	app.Post("/returns/:id/shipment", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Ship)
+	app.Post("/returns/:id/receive", middlerwares.ValidateRequest, (*c).Receive)
+}
@@ -2,30 +2,51 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	analyticscontroller "github.com/hieronimusbudi/komodo-backend/controllers/analytics_controller"
+	invoicecontroller "github.com/hieronimusbudi/komodo-backend/controllers/invoice_controller"
 	ordercontroller "github.com/hieronimusbudi/komodo-backend/controllers/order_controller"
+	paymentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/payment_controller"
 	productcontroller "github.com/hieronimusbudi/komodo-backend/controllers/product_controller"
+	reportcontroller "github.com/hieronimusbudi/komodo-backend/controllers/report_controller"
+	returncontroller "github.com/hieronimusbudi/komodo-backend/controllers/return_controller"
+	shipmentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipment_controller"
+	shippingcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipping_controller"
+	vouchercontroller "github.com/hieronimusbudi/komodo-backend/controllers/voucher_controller"
+	wishlistcontroller "github.com/hieronimusbudi/komodo-backend/controllers/wishlist_controller"
 	"github.com/hieronimusbudi/komodo-backend/dependencies"
-	orderrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/order_repository"
-	productrepo "github.com/hieronimusbudi/komodo-backend/framework/persistance/mysql/product_repository"
-	orderusecase "github.com/hieronimusbudi/komodo-backend/usecases/order_usecase"
-	productusecase "github.com/hieronimusbudi/komodo-backend/usecases/product_usecase"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
 )
 
 // this function combines all routes and passes dependencies to routes
 func All(app *fiber.App, d *dependencies.Dependencies) {
+	app.Use(middlerwares.Timeout)
 
-	// product
-	rP := productrepo.NewMysqlProductRepository(d.Conn)
-	uP := productusecase.NewProductUsecase(rP)
-	cP := productcontroller.NewProductController(uP, d.Validate)
-
-	// order
-	rO := orderrepo.NewMysqlOrderRepository(d.Conn)
-	uO := orderusecase.NewOrderUsecase(rO, rP)
-	cO := ordercontroller.NewOrderController(uO, d.Validate)
+	u := d.Usecases
+	cP := productcontroller.NewProductController(u.Product, d.Validate)
+	cW := wishlistcontroller.NewWishlistController(u.Wishlist, d.Validate)
+	cS := shippingcontroller.NewShippingController(u.Shipping, d.Validate)
+	cV := vouchercontroller.NewVoucherController(u.Voucher, d.Validate)
+	cPay := paymentcontroller.NewPaymentController(u.Payment, d.Validate)
+	cO := ordercontroller.NewOrderController(u.Order, d.Validate)
+	idempotency := middlerwares.NewIdempotency(u.Idempotency)
+	cRet := returncontroller.NewReturnController(u.Return, d.Validate)
+	cShip := shipmentcontroller.NewShipmentController(u.Shipment, d.Validate)
+	cInv := invoicecontroller.NewInvoiceController(u.Invoice, d.Validate)
+	cA := analyticscontroller.NewAnalyticsController(u.Analytics, d.Validate)
+	cRep := reportcontroller.NewReportController(u.Report, d.Validate)
 
+	adminRoutes(app, d)
 	buyerRoutes(app, d)
 	sellerRoutes(app, d)
 	productRoutes(app, &cP)
-	orderRoutes(app, &cO)
+	orderRoutes(app, &cO, idempotency)
+	paymentRoutes(app, &cPay)
+	returnRoutes(app, &cRet)
+	voucherRoutes(app, &cV)
+	shippingRoutes(app, &cS)
+	shipmentRoutes(app, &cShip)
+	invoiceRoutes(app, &cInv)
+	analyticsRoutes(app, &cA)
+	reportRoutes(app, &cRep)
+	wishlistRoutes(app, &cW)
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	shipmentcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipment_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// shipmentRoutes used to define route and inject dependencies to repository, usecase and controller
+func shipmentRoutes(app *fiber.App, c *shipmentcontroller.ShipmentController) {
+	app.Post("/orders/:id/shipment", middlerwares.ValidateRequest, middlerwares.SellerTypeChecker, (*c).Ship)
+	app.Get("/orders/:id/tracking", middlerwares.ValidateRequest, (*c).Tracking)
+}
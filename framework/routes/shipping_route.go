@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	shippingcontroller "github.com/hieronimusbudi/komodo-backend/controllers/shipping_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// shippingRoutes used to define route and inject dependencies to repository, usecase and controller
+func shippingRoutes(app *fiber.App, c *shippingcontroller.ShippingController) {
+	app.Post("/shipping/quotes", middlerwares.ValidateRequest, (*c).Quote)
+}
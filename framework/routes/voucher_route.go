@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	vouchercontroller "github.com/hieronimusbudi/komodo-backend/controllers/voucher_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// voucherRoutes used to define route and inject dependencies to repository, usecase and controller
+func voucherRoutes(app *fiber.App, c *vouchercontroller.VoucherController) {
+	app.Post("/vouchers", middlerwares.ValidateRequest, (*c).Store)
+	app.Get("/vouchers", middlerwares.ValidateRequest, (*c).Fetch)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	wishlistcontroller "github.com/hieronimusbudi/komodo-backend/controllers/wishlist_controller"
+	"github.com/hieronimusbudi/komodo-backend/framework/middlerwares"
+)
+
+// wishlistRoutes used to define route and inject dependencies to repository, usecase and controller
+func wishlistRoutes(app *fiber.App, c *wishlistcontroller.WishlistController) {
+	app.Get("/buyers/me/wishlist", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).GetItems)
+	app.Post("/buyers/me/wishlist", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).AddItem)
+	app.Delete("/buyers/me/wishlist/:productId", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).RemoveItem)
+	app.Get("/buyers/me/following", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).GetFollowing)
+	app.Post("/buyers/me/following", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Follow)
+	app.Delete("/buyers/me/following/:sellerId", middlerwares.ValidateRequest, middlerwares.BuyerTypeChecker, (*c).Unfollow)
+}
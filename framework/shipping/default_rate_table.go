@@ -0,0 +1,55 @@
+package shipping
+
+import "github.com/shopspring/decimal"
+
+// defaultZones splits Indonesia by the first digits of its postal codes
+var defaultZones = []Zone{
+	{Name: "JABODETABEK", PostalPrefixes: []string{"10", "11", "12", "13", "14", "15", "16", "17"}, Provinces: []string{"DKI Jakarta"}},
+	{Name: "JAVA", PostalPrefixes: []string{"4", "5", "6"},
+		Provinces: []string{"Banten", "Jawa Barat", "Jawa Tengah", "DI Yogyakarta", "Jawa Timur"}},
+	{Name: "SUMATRA", PostalPrefixes: []string{"2", "3"},
+		Provinces: []string{"Aceh", "Sumatera Utara", "Sumatera Barat", "Riau", "Kepulauan Riau", "Jambi", "Bengkulu",
+			"Sumatera Selatan", "Kepulauan Bangka Belitung", "Lampung"}},
+	{Name: "KALIMANTAN", PostalPrefixes: []string{"7"},
+		Provinces: []string{"Kalimantan Barat", "Kalimantan Tengah", "Kalimantan Selatan", "Kalimantan Timur", "Kalimantan Utara"}},
+	{Name: "BALI_NUSA_TENGGARA", PostalPrefixes: []string{"80", "81", "82", "83", "84", "85", "86", "87"},
+		Provinces: []string{"Bali", "Nusa Tenggara Barat", "Nusa Tenggara Timur"}},
+	{Name: "SULAWESI", PostalPrefixes: []string{"90", "91", "92", "93", "94", "95", "96"},
+		Provinces: []string{"Sulawesi Utara", "Gorontalo", "Sulawesi Tengah", "Sulawesi Barat", "Sulawesi Selatan", "Sulawesi Tenggara"}},
+	{Name: "MALUKU_PAPUA", PostalPrefixes: []string{"97", "98", "99"},
+		Provinces: []string{"Maluku", "Maluku Utara", "Papua", "Papua Barat"}},
+}
+
+// DefaultRateTable is used when no rate table is configured. Regular and next day services cost less within a zone,
+// trucking is for heavy parcels
+func DefaultRateTable() RateTable {
+	regular := Service{Code: "JNE_REG", Carrier: "JNE", Name: "Reguler"}
+	nextDay := Service{Code: "JNE_YES", Carrier: "JNE", Name: "Yakin Esok Sampai"}
+	trucking := Service{Code: "JNE_JTR", Carrier: "JNE", Name: "JNE Trucking"}
+
+	for _, z := range defaultZones {
+		regular.Rates = append(regular.Rates, Rate{From: z.Name, To: z.Name, EstimatedDays: 2,
+			Tiers: tiers(1000, 10000, 2000, 18000, 5000, 40000, 10000, 75000, 20000, 140000)})
+		nextDay.Rates = append(nextDay.Rates, Rate{From: z.Name, To: z.Name, EstimatedDays: 1,
+			Tiers: tiers(1000, 18000, 2000, 34000, 5000, 80000)})
+		trucking.Rates = append(trucking.Rates, Rate{From: z.Name, To: z.Name, EstimatedDays: 4,
+			Tiers: tiers(30000, 90000, 100000, 250000)})
+	}
+	regular.Rates = append(regular.Rates, Rate{From: AnyZone, To: AnyZone, EstimatedDays: 4,
+		Tiers: tiers(1000, 20000, 2000, 38000, 5000, 90000, 10000, 170000, 20000, 320000)})
+	nextDay.Rates = append(nextDay.Rates, Rate{From: "JABODETABEK", To: "JAVA", EstimatedDays: 1,
+		Tiers: tiers(1000, 24000, 2000, 46000, 5000, 110000)})
+	trucking.Rates = append(trucking.Rates, Rate{From: AnyZone, To: AnyZone, EstimatedDays: 8,
+		Tiers: tiers(30000, 250000, 100000, 700000)})
+
+	return RateTable{Zones: defaultZones, Services: []Service{regular, nextDay, trucking}}
+}
+
+// tiers builds tiers from pairs of weight in grams and fee
+func tiers(weightsAndFees ...int64) []Tier {
+	res := []Tier{}
+	for i := 0; i+1 < len(weightsAndFees); i += 2 {
+		res = append(res, Tier{UpTo: weightsAndFees[i], Fee: decimal.NewFromInt(weightsAndFees[i+1])})
+	}
+	return res
+}
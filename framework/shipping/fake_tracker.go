@@ -0,0 +1,73 @@
+package shipping
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// FakeTrackerName is the COURIER_TRACKER name of the FakeTracker
+const FakeTrackerName = "fake"
+
+// fakeSteps are the events every parcel of the FakeTracker goes through, the last one delivers it
+var fakeSteps = []entity.TrackingEvent{
+	{Code: "PICKED_UP", Description: "Parcel picked up by courier"},
+	{Code: "IN_TRANSIT", Description: "Parcel arrived at sorting center"},
+	{Code: "OUT_FOR_DELIVERY", Description: "Parcel is out for delivery"},
+	{Code: "DELIVERED", Description: "Parcel delivered to recipient"},
+}
+
+// FakeTracker is an in memory entity.CourierTracker for tests and local development, it knows every tracking number
+type FakeTracker struct {
+	mu          sync.Mutex
+	autoAdvance bool
+	parcels     map[string][]entity.TrackingEvent
+}
+
+// NewFakeTracker creates a FakeTracker, with autoAdvance a parcel moves one step further each time it is tracked
+// until it is delivered, otherwise it only moves when Advance is called
+func NewFakeTracker(autoAdvance bool) *FakeTracker {
+	return &FakeTracker{
+		autoAdvance: autoAdvance,
+		parcels:     map[string][]entity.TrackingEvent{},
+	}
+}
+
+func (t *FakeTracker) Track(ctx context.Context, courier, trackingNumber string) (entity.CourierTracking, resterrors.RestErr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := courier + "/" + trackingNumber
+	if t.autoAdvance {
+		t.advance(key)
+	}
+
+	events := append([]entity.TrackingEvent{}, t.parcels[key]...)
+	res := entity.CourierTracking{Events: events}
+	if len(events) == len(fakeSteps) {
+		res.Delivered = true
+		res.DeliveredAt = events[len(events)-1].OccurredAt
+	}
+	return res, nil
+}
+
+// Advance moves a parcel one step further, it does nothing once the parcel is delivered
+func (t *FakeTracker) Advance(courier, trackingNumber string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance(courier + "/" + trackingNumber)
+}
+
+func (t *FakeTracker) advance(key string) {
+	events := t.parcels[key]
+	if len(events) == len(fakeSteps) {
+		return
+	}
+	te := fakeSteps[len(events)]
+	// couriers report times to the second
+	te.OccurredAt = time.Now().UTC().Truncate(time.Second)
+	t.parcels[key] = append(events, te)
+}
@@ -0,0 +1,52 @@
+package shipping_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/framework/shipping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeTracker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("auto-advance", func(t *testing.T) {
+		tracker := shipping.NewFakeTracker(true)
+		for i := 1; i <= 3; i++ {
+			res, err := tracker.Track(ctx, "JNE", "JNE123")
+			assert.Nil(t, err)
+			assert.Len(t, res.Events, i)
+			assert.False(t, res.Delivered)
+		}
+
+		res, err := tracker.Track(ctx, "JNE", "JNE123")
+		assert.Nil(t, err)
+		assert.True(t, res.Delivered)
+		assert.Equal(t, "DELIVERED", res.Events[3].Code)
+		assert.Equal(t, res.Events[3].OccurredAt, res.DeliveredAt)
+
+		// a delivered parcel doesn't move anymore
+		res, _ = tracker.Track(ctx, "JNE", "JNE123")
+		assert.Len(t, res.Events, 4)
+
+		// other parcels start from the beginning
+		res, _ = tracker.Track(ctx, "SICEPAT", "JNE123")
+		assert.Len(t, res.Events, 1)
+		assert.Equal(t, "PICKED_UP", res.Events[0].Code)
+	})
+
+	t.Run("manual-advance", func(t *testing.T) {
+		tracker := shipping.NewFakeTracker(false)
+		res, err := tracker.Track(ctx, "JNE", "JNE123")
+		assert.Nil(t, err)
+		assert.Empty(t, res.Events)
+
+		tracker.Advance("JNE", "JNE123")
+		tracker.Advance("JNE", "JNE123")
+		res, _ = tracker.Track(ctx, "JNE", "JNE123")
+		assert.Len(t, res.Events, 2)
+		assert.Equal(t, "IN_TRANSIT", res.Events[1].Code)
+		assert.False(t, res.Delivered)
+	})
+}
@@ -0,0 +1,186 @@
+package shipping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// AnyZone matches every zone in the From or To of a Rate
+const AnyZone = "*"
+
+// RateTable lists the zones parcels are sent between and the services that send them
+type RateTable struct {
+	Zones    []Zone    `json:"zones"`
+	Services []Service `json:"services"`
+}
+
+// Zone groups regions by the prefixes of their postal codes, Provinces are used for addresses without a postal code
+type Zone struct {
+	Name           string   `json:"name"`
+	PostalPrefixes []string `json:"postalPrefixes"`
+	Provinces      []string `json:"provinces"`
+}
+
+// Service is a carrier service, it only ships between the zones it has a Rate for
+type Service struct {
+	Code    string `json:"code"`
+	Carrier string `json:"carrier"`
+	Name    string `json:"name"`
+	Rates   []Rate `json:"rates"`
+}
+
+// Rate is what a service charges from zone From to zone To, or the other way around. Tiers are sorted by UpTo,
+// a parcel heavier than the last tier isn't shipped by the service
+type Rate struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	EstimatedDays int    `json:"estimatedDays"`
+	Tiers         []Tier `json:"tiers"`
+}
+
+// Tier is the fee of parcels up to UpTo grams
+type Tier struct {
+	UpTo int64           `json:"upTo"`
+	Fee  decimal.Decimal `json:"fee"`
+}
+
+// TableRateProvider is an entity.ShippingRateProvider quoting from a RateTable
+type TableRateProvider struct {
+	table RateTable
+}
+
+// NewTableRateProvider creates a TableRateProvider quoting from table
+func NewTableRateProvider(table RateTable) *TableRateProvider {
+	return &TableRateProvider{table: table}
+}
+
+// LoadRateTable reads a RateTable saved as JSON at path
+func LoadRateTable(path string) (RateTable, error) {
+	table := RateTable{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return table, err
+	}
+	if err := json.Unmarshal(b, &table); err != nil {
+		return table, fmt.Errorf("invalid shipping rate table %s: %w", path, err)
+	}
+	return table, nil
+}
+
+func (p *TableRateProvider) Rates(ctx context.Context, origin, destination entity.ShippingAddress, weight int64) ([]entity.ShippingRate, resterrors.RestErr) {
+	from, ok := p.zoneOf(origin)
+	if !ok {
+		return nil, resterrors.NewBadRequestError(fmt.Sprintf("can't ship from %s", describe(origin)))
+	}
+	to, ok := p.zoneOf(destination)
+	if !ok {
+		return nil, resterrors.NewBadRequestError(fmt.Sprintf("can't ship to %s", describe(destination)))
+	}
+
+	res := []entity.ShippingRate{}
+	for _, s := range p.table.Services {
+		rate, ok := bestRate(s.Rates, from, to)
+		if !ok {
+			continue
+		}
+		fee, ok := rate.fee(weight)
+		if !ok {
+			continue
+		}
+		res = append(res, entity.ShippingRate{
+			Service:       s.Code,
+			Carrier:       s.Carrier,
+			Name:          s.Name,
+			Fee:           fee,
+			Weight:        weight,
+			EstimatedDays: rate.EstimatedDays,
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Fee.LessThan(res[j].Fee)
+	})
+	return res, nil
+}
+
+// zoneOf finds the zone with the longest prefix of the postal code of address, then the one with its province
+func (p *TableRateProvider) zoneOf(address entity.ShippingAddress) (string, bool) {
+	postalCode := strings.TrimSpace(address.PostalCode)
+	zone, longest := "", 0
+	if postalCode != "" {
+		for _, z := range p.table.Zones {
+			for _, prefix := range z.PostalPrefixes {
+				if len(prefix) > longest && strings.HasPrefix(postalCode, prefix) {
+					zone, longest = z.Name, len(prefix)
+				}
+			}
+		}
+	}
+	if zone != "" {
+		return zone, true
+	}
+
+	province := strings.TrimSpace(address.Province)
+	for _, z := range p.table.Zones {
+		for _, pr := range z.Provinces {
+			if strings.EqualFold(pr, province) {
+				return z.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// bestRate picks the rate naming both zones over the one naming one of them over the one for any zones
+func bestRate(rates []Rate, from, to string) (Rate, bool) {
+	best, bestScore := Rate{}, -1
+	for _, r := range rates {
+		score := r.match(from, to)
+		if reversed := r.match(to, from); reversed > score {
+			score = reversed
+		}
+		if score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// match returns how many of from and to the rate names, or -1 when it doesn't ship between them
+func (r Rate) match(from, to string) int {
+	score := 0
+	for _, zone := range [][2]string{{r.From, from}, {r.To, to}} {
+		switch zone[0] {
+		case zone[1]:
+			score++
+		case AnyZone:
+		default:
+			return -1
+		}
+	}
+	return score
+}
+
+func (r Rate) fee(weight int64) (decimal.Decimal, bool) {
+	for _, t := range r.Tiers {
+		if weight <= t.UpTo {
+			return t.Fee, true
+		}
+	}
+	return decimal.Zero, false
+}
+
+func describe(address entity.ShippingAddress) string {
+	if address.PostalCode != "" {
+		return "postal code " + address.PostalCode
+	}
+	return address.Province
+}
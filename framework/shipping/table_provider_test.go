@@ -0,0 +1,105 @@
+package shipping_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/shipping"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	jakarta  = entity.ShippingAddress{PostalCode: "12190"}
+	bandung  = entity.ShippingAddress{Province: "Jawa Barat", PostalCode: "40115"}
+	makassar = entity.ShippingAddress{Province: "sulawesi selatan"}
+)
+
+func feeOf(t *testing.T, rates []entity.ShippingRate, service string) decimal.Decimal {
+	for _, r := range rates {
+		if r.Service == service {
+			return r.Fee
+		}
+	}
+	t.Fatalf("service %s not quoted", service)
+	return decimal.Zero
+}
+
+func TestTableRateProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := shipping.NewTableRateProvider(shipping.DefaultRateTable())
+
+	t.Run("same-zone", func(t *testing.T) {
+		rates, err := provider.Rates(ctx, jakarta, entity.ShippingAddress{PostalCode: "16424"}, 1500)
+		assert.Nil(t, err)
+		assert.Len(t, rates, 3)
+		assert.True(t, decimal.NewFromInt(18000).Equal(feeOf(t, rates, "JNE_REG")))
+		assert.True(t, decimal.NewFromInt(34000).Equal(feeOf(t, rates, "JNE_YES")))
+		// cheapest first
+		assert.Equal(t, "JNE_REG", rates[0].Service)
+		assert.Equal(t, 2, rates[0].EstimatedDays)
+		assert.Equal(t, int64(1500), rates[0].Weight)
+	})
+
+	t.Run("named-zones-both-ways", func(t *testing.T) {
+		there, err := provider.Rates(ctx, jakarta, bandung, 1000)
+		assert.Nil(t, err)
+		back, err := provider.Rates(ctx, bandung, jakarta, 1000)
+		assert.Nil(t, err)
+		assert.True(t, decimal.NewFromInt(24000).Equal(feeOf(t, there, "JNE_YES")))
+		assert.True(t, decimal.NewFromInt(24000).Equal(feeOf(t, back, "JNE_YES")))
+		assert.True(t, decimal.NewFromInt(20000).Equal(feeOf(t, there, "JNE_REG")))
+	})
+
+	t.Run("province-without-postal-code", func(t *testing.T) {
+		rates, err := provider.Rates(ctx, jakarta, makassar, 4000)
+		assert.Nil(t, err)
+		// next day doesn't go there
+		assert.Len(t, rates, 2)
+		assert.True(t, decimal.NewFromInt(90000).Equal(feeOf(t, rates, "JNE_REG")))
+	})
+
+	t.Run("too-heavy-for-a-service", func(t *testing.T) {
+		rates, err := provider.Rates(ctx, jakarta, bandung, 25000)
+		assert.Nil(t, err)
+		assert.Len(t, rates, 1)
+		assert.Equal(t, "JNE_JTR", rates[0].Service)
+	})
+
+	t.Run("unknown-region", func(t *testing.T) {
+		_, err := provider.Rates(ctx, jakarta, entity.ShippingAddress{Province: "Atlantis"}, 1000)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+}
+
+func TestLoadRateTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rates")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rates.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{
+		"zones": [{"name": "A", "postalPrefixes": ["1"]}, {"name": "B", "provinces": ["Bali"]}],
+		"services": [{"code": "SICEPAT_REG", "carrier": "SiCepat", "name": "Reguler", "rates": [
+			{"from": "A", "to": "B", "estimatedDays": 3, "tiers": [{"upTo": 1000, "fee": "15000"}, {"upTo": 5000, "fee": 55000}]}
+		]}]
+	}`), 0600))
+
+	table, err := shipping.LoadRateTable(path)
+	assert.NoError(t, err)
+
+	rates, rErr := shipping.NewTableRateProvider(table).Rates(context.Background(), entity.ShippingAddress{Province: "bali"},
+		entity.ShippingAddress{PostalCode: "10110"}, 1001)
+	assert.Nil(t, rErr)
+	assert.Len(t, rates, 1)
+	assert.True(t, decimal.NewFromInt(55000).Equal(rates[0].Fee))
+
+	_, err = shipping.LoadRateTable(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
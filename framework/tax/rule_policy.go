@@ -0,0 +1,102 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RoundHalfUp rounds halves away from zero, it is used when Rules have no Rounding
+	RoundHalfUp = "HALF_UP"
+	// RoundHalfEven rounds halves to the even cent
+	RoundHalfEven = "HALF_EVEN"
+)
+
+// Rules are the tax rates of line items. The rate of the tax status of the seller is used first, then the rate
+// of the category of the product, then DefaultRate. Rates are fractions, 0.11 is 11%
+type Rules struct {
+	Inclusive     bool                       `json:"inclusive"`
+	Rounding      string                     `json:"rounding"`
+	DefaultRate   decimal.Decimal            `json:"defaultRate"`
+	CategoryRates map[string]decimal.Decimal `json:"categoryRates"`
+	SellerRates   map[string]decimal.Decimal `json:"sellerRates"`
+}
+
+// DefaultRules are used when no rules are configured, an 11% VAT added to prices that sellers who aren't
+// registered for it don't collect
+func DefaultRules() Rules {
+	return Rules{
+		Rounding:    RoundHalfUp,
+		DefaultRate: decimal.RequireFromString("0.11"),
+		SellerRates: map[string]decimal.Decimal{string(entity.TAX_UNREGISTERED): decimal.Zero},
+	}
+}
+
+// LoadRules reads Rules saved as JSON at path
+func LoadRules(path string) (Rules, error) {
+	rules := Rules{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return rules, fmt.Errorf("invalid tax rules %s: %w", path, err)
+	}
+	switch strings.ToUpper(rules.Rounding) {
+	case "", RoundHalfUp, RoundHalfEven:
+	default:
+		return rules, fmt.Errorf("invalid tax rules %s: unknown rounding %s", path, rules.Rounding)
+	}
+	return rules, nil
+}
+
+// RulePolicy is an entity.TaxPolicy taxing by Rules
+type RulePolicy struct {
+	rules Rules
+}
+
+// NewRulePolicy creates a RulePolicy taxing by rules
+func NewRulePolicy(rules Rules) *RulePolicy {
+	return &RulePolicy{rules: rules}
+}
+
+func (p *RulePolicy) Rate(seller entity.Seller, product entity.Product) decimal.Decimal {
+	if rate, ok := lookup(p.rules.SellerRates, string(seller.TaxStatus)); ok {
+		return rate
+	}
+	if rate, ok := lookup(p.rules.CategoryRates, product.Category); ok {
+		return rate
+	}
+	return p.rules.DefaultRate
+}
+
+func (p *RulePolicy) Inclusive() bool {
+	return p.rules.Inclusive
+}
+
+// Round rounds amount to cents
+func (p *RulePolicy) Round(amount decimal.Decimal) decimal.Decimal {
+	if strings.EqualFold(p.rules.Rounding, RoundHalfEven) {
+		return amount.RoundBank(2)
+	}
+	return amount.Round(2)
+}
+
+// lookup finds the rate of key ignoring case, an empty key has no rate
+func lookup(rates map[string]decimal.Decimal, key string) (decimal.Decimal, bool) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return decimal.Zero, false
+	}
+	for k, rate := range rates {
+		if strings.EqualFold(k, key) {
+			return rate, true
+		}
+	}
+	return decimal.Zero, false
+}
@@ -0,0 +1,71 @@
+package tax_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/tax"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulePolicy(t *testing.T) {
+	registered := entity.Seller{TaxStatus: entity.TAX_REGISTERED}
+	unregistered := entity.Seller{TaxStatus: entity.TAX_UNREGISTERED}
+
+	t.Run("default-rules", func(t *testing.T) {
+		policy := tax.NewRulePolicy(tax.DefaultRules())
+		assert.False(t, policy.Inclusive())
+		assert.True(t, decimal.RequireFromString("0.11").Equal(policy.Rate(registered, entity.Product{Category: "books"})))
+		assert.True(t, decimal.Zero.Equal(policy.Rate(unregistered, entity.Product{})))
+	})
+
+	t.Run("seller-over-category-over-default", func(t *testing.T) {
+		policy := tax.NewRulePolicy(tax.Rules{
+			DefaultRate:   decimal.RequireFromString("0.1"),
+			CategoryRates: map[string]decimal.Decimal{"Books": decimal.Zero, "luxury": decimal.RequireFromString("0.2")},
+			SellerRates:   map[string]decimal.Decimal{"UNREGISTERED": decimal.RequireFromString("0.01")},
+		})
+		assert.True(t, decimal.Zero.Equal(policy.Rate(registered, entity.Product{Category: "books"})))
+		assert.True(t, decimal.RequireFromString("0.2").Equal(policy.Rate(registered, entity.Product{Category: "Luxury"})))
+		assert.True(t, decimal.RequireFromString("0.1").Equal(policy.Rate(registered, entity.Product{})))
+		assert.True(t, decimal.RequireFromString("0.01").Equal(policy.Rate(unregistered, entity.Product{Category: "luxury"})))
+	})
+
+	t.Run("rounding", func(t *testing.T) {
+		halfUp := tax.NewRulePolicy(tax.Rules{})
+		halfEven := tax.NewRulePolicy(tax.Rules{Rounding: tax.RoundHalfEven})
+		assert.Equal(t, "0.13", halfUp.Round(decimal.RequireFromString("0.125")).StringFixed(2))
+		assert.Equal(t, "0.12", halfEven.Round(decimal.RequireFromString("0.125")).StringFixed(2))
+		assert.Equal(t, "0.14", halfEven.Round(decimal.RequireFromString("0.135")).StringFixed(2))
+	})
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tax")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tax.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{
+		"inclusive": true, "rounding": "HALF_EVEN", "defaultRate": "0.11",
+		"categoryRates": {"groceries": 0}, "sellerRates": {"UNREGISTERED": "0"}
+	}`), 0600))
+
+	rules, err := tax.LoadRules(path)
+	assert.NoError(t, err)
+	policy := tax.NewRulePolicy(rules)
+	assert.True(t, policy.Inclusive())
+	assert.True(t, decimal.Zero.Equal(policy.Rate(entity.Seller{TaxStatus: entity.TAX_REGISTERED}, entity.Product{Category: "groceries"})))
+	assert.True(t, decimal.RequireFromString("0.11").Equal(policy.Rate(entity.Seller{TaxStatus: entity.TAX_REGISTERED}, entity.Product{})))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"rounding": "DOWN"}`), 0600))
+	_, err = tax.LoadRules(path)
+	assert.Error(t, err)
+
+	_, err = tax.LoadRules(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
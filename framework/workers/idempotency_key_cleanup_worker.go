@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+)
+
+type idempotencyKeyCleanupWorker struct {
+	idempotency entity.IdempotencyUseCase
+	interval    time.Duration
+}
+
+// NewIdempotencyKeyCleanupWorker will create a Worker that removes expired idempotency keys every interval.
+// Removing keys twice is harmless, so it runs on every instance without a lock
+func NewIdempotencyKeyCleanupWorker(u entity.IdempotencyUseCase, interval time.Duration) Worker {
+	return &idempotencyKeyCleanupWorker{
+		idempotency: u,
+		interval:    interval,
+	}
+}
+
+func (w *idempotencyKeyCleanupWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.cleanup(ctx)
+		}
+	}
+}
+
+func (w *idempotencyKeyCleanupWorker) cleanup(ctx context.Context) {
+	deleted, rErr := w.idempotency.DeleteExpired(ctx)
+	if rErr != nil {
+		log.Println("idempotency key cleanup error", rErr)
+		return
+	}
+	if deleted > 0 {
+		log.Println("idempotency key cleanup deleted keys", deleted)
+	}
+}
@@ -0,0 +1,37 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyKeyCleanupWorker(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockIdempotencyUCase := new(mocks.IdempotencyUseCase)
+		mockIdempotencyUCase.On("DeleteExpired", mock.Anything).Return(int64(2), nil)
+
+		w := workers.NewIdempotencyKeyCleanupWorker(mockIdempotencyUCase, 10*time.Millisecond)
+		stopped := runFor(w, 55*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.True(t, len(mockIdempotencyUCase.Calls) > 1)
+	})
+
+	t.Run("keeps running after an error", func(t *testing.T) {
+		mockIdempotencyUCase := new(mocks.IdempotencyUseCase)
+		mockIdempotencyUCase.On("DeleteExpired", mock.Anything).
+			Return(int64(0), resterrors.NewInternalServerError("error when trying to delete data", nil))
+
+		w := workers.NewIdempotencyKeyCleanupWorker(mockIdempotencyUCase, 10*time.Millisecond)
+		stopped := runFor(w, 55*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.True(t, len(mockIdempotencyUCase.Calls) > 1)
+	})
+}
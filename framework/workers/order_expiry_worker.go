@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+)
+
+const (
+	orderExpiryLock      = "order_expiry"
+	orderExpiryBatchSize = 100
+)
+
+type orderExpiryWorker struct {
+	orderUsecase entity.OrderUseCase
+	locker       entity.Locker
+	sla          time.Duration
+	interval     time.Duration
+}
+
+// NewOrderExpiryWorker will create a Worker that cancels orders still pending sla after they were placed,
+// it runs once on start and then every interval
+func NewOrderExpiryWorker(o entity.OrderUseCase, l entity.Locker, sla, interval time.Duration) Worker {
+	return &orderExpiryWorker{
+		orderUsecase: o,
+		locker:       l,
+		sla:          sla,
+		interval:     interval,
+	}
+}
+
+func (w *orderExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.expire(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expire runs one batch, only the instance holding the lock does the work so orders are not expired twice
+func (w *orderExpiryWorker) expire(ctx context.Context) {
+	unlock, ok, err := w.locker.TryLock(ctx, orderExpiryLock)
+	if err != nil {
+		log.Println("order expiry lock error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	placedBefore := time.Now().Add(-w.sla)
+	expired, err := w.orderUsecase.ExpirePendingOrders(ctx, placedBefore, orderExpiryBatchSize)
+	if err != nil {
+		log.Println("order expiry error", err)
+		return
+	}
+	if len(expired) > 0 {
+		log.Println("order expiry expired orders", len(expired))
+	}
+}
@@ -0,0 +1,92 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// runFor runs w until d passed and reports whether it stopped once ctx was done
+func runFor(w workers.Worker, d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(d + time.Second):
+		return false
+	}
+}
+
+func TestOrderExpiryWorker(t *testing.T) {
+	sla := time.Hour
+
+	t.Run("success", func(t *testing.T) {
+		unlocked := 0
+		mockOrderUsecase := new(mocks.OrderUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "order_expiry").Return(func() { unlocked++ }, true, nil)
+		mockOrderUsecase.On("ExpirePendingOrders", mock.Anything, mock.MatchedBy(func(placedBefore time.Time) bool {
+			return time.Since(placedBefore) >= sla
+		}), 100).Return([]entity.Order{{ID: 1}}, nil)
+
+		w := workers.NewOrderExpiryWorker(mockOrderUsecase, mockLocker, sla, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.Equal(t, 1, unlocked)
+		mockOrderUsecase.AssertNumberOfCalls(t, "ExpirePendingOrders", 1)
+	})
+
+	t.Run("success runs every interval", func(t *testing.T) {
+		mockOrderUsecase := new(mocks.OrderUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "order_expiry").Return(func() {}, true, nil)
+		mockOrderUsecase.On("ExpirePendingOrders", mock.Anything, mock.AnythingOfType("time.Time"), 100).Return([]entity.Order{}, nil)
+
+		w := workers.NewOrderExpiryWorker(mockOrderUsecase, mockLocker, sla, 10*time.Millisecond)
+		stopped := runFor(w, 55*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.True(t, len(mockOrderUsecase.Calls) > 1)
+	})
+
+	t.Run("lock held by another instance", func(t *testing.T) {
+		mockOrderUsecase := new(mocks.OrderUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "order_expiry").Return(nil, false, nil)
+
+		w := workers.NewOrderExpiryWorker(mockOrderUsecase, mockLocker, sla, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		mockOrderUsecase.AssertNotCalled(t, "ExpirePendingOrders", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error lock", func(t *testing.T) {
+		mockOrderUsecase := new(mocks.OrderUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "order_expiry").
+			Return(nil, false, resterrors.NewInternalServerError("error when trying to acquire lock", nil))
+
+		w := workers.NewOrderExpiryWorker(mockOrderUsecase, mockLocker, sla, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		mockOrderUsecase.AssertNotCalled(t, "ExpirePendingOrders", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,72 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+)
+
+const (
+	salesRollupLock = "sales_rollup"
+	// salesRollupDays are the days refreshed on every run, orders are still accepted or cancelled a few days after they were placed
+	salesRollupDays = 7
+	// salesRollupBackfillDays are refreshed on the first run, as far back as sales can be asked for
+	salesRollupBackfillDays = 366
+)
+
+type salesRollupWorker struct {
+	analyticsUsecase entity.AnalyticsUseCase
+	locker           entity.Locker
+	interval         time.Duration
+	backfilled       bool
+}
+
+// NewSalesRollupWorker will create a Worker that computes the daily sales of the sellers again, the last year on
+// its first run and the last week after that. It runs once on start and then every interval
+func NewSalesRollupWorker(a entity.AnalyticsUseCase, l entity.Locker, interval time.Duration) Worker {
+	return &salesRollupWorker{
+		analyticsUsecase: a,
+		locker:           l,
+		interval:         interval,
+	}
+}
+
+func (w *salesRollupWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh runs once, only the instance holding the lock does the work so the days are not computed twice at once
+func (w *salesRollupWorker) refresh(ctx context.Context) {
+	unlock, ok, err := w.locker.TryLock(ctx, salesRollupLock)
+	if err != nil {
+		log.Println("sales rollup lock error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	days := salesRollupDays
+	if !w.backfilled {
+		days = salesRollupBackfillDays
+	}
+	if err := w.analyticsUsecase.RefreshDailySales(ctx, days); err != nil {
+		log.Println("sales rollup error", err)
+		return
+	}
+	w.backfilled = true
+}
@@ -0,0 +1,56 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSalesRollupWorker(t *testing.T) {
+	t.Run("success backfills on the first run", func(t *testing.T) {
+		mockAnalyticsUsecase := new(mocks.AnalyticsUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "sales_rollup").Return(func() {}, true, nil)
+		mockAnalyticsUsecase.On("RefreshDailySales", mock.Anything, 366).Return(nil).Once()
+		mockAnalyticsUsecase.On("RefreshDailySales", mock.Anything, 7).Return(nil)
+
+		w := workers.NewSalesRollupWorker(mockAnalyticsUsecase, mockLocker, 10*time.Millisecond)
+		stopped := runFor(w, 55*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.Equal(t, 366, mockAnalyticsUsecase.Calls[0].Arguments.Int(1))
+		assert.Equal(t, 7, mockAnalyticsUsecase.Calls[1].Arguments.Int(1))
+	})
+
+	t.Run("error backfills again", func(t *testing.T) {
+		mockAnalyticsUsecase := new(mocks.AnalyticsUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "sales_rollup").Return(func() {}, true, nil)
+		mockAnalyticsUsecase.On("RefreshDailySales", mock.Anything, 366).
+			Return(resterrors.NewInternalServerError("error when trying to save data", nil))
+
+		w := workers.NewSalesRollupWorker(mockAnalyticsUsecase, mockLocker, 10*time.Millisecond)
+		stopped := runFor(w, 35*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.True(t, len(mockAnalyticsUsecase.Calls) > 1)
+		mockAnalyticsUsecase.AssertNotCalled(t, "RefreshDailySales", mock.Anything, 7)
+	})
+
+	t.Run("lock held by another instance", func(t *testing.T) {
+		mockAnalyticsUsecase := new(mocks.AnalyticsUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "sales_rollup").Return(nil, false, nil)
+
+		w := workers.NewSalesRollupWorker(mockAnalyticsUsecase, mockLocker, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		mockAnalyticsUsecase.AssertNotCalled(t, "RefreshDailySales", mock.Anything, mock.Anything)
+	})
+}
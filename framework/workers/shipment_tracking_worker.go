@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+)
+
+const (
+	shipmentTrackingLock      = "shipment_tracking"
+	shipmentTrackingBatchSize = 100
+)
+
+type shipmentTrackingWorker struct {
+	shipmentUsecase entity.ShipmentUseCase
+	locker          entity.Locker
+	interval        time.Duration
+}
+
+// NewShipmentTrackingWorker will create a Worker that asks couriers about the shipments in transit, a shipment is checked
+// at most once per interval. It runs once on start and then every interval
+func NewShipmentTrackingWorker(s entity.ShipmentUseCase, l entity.Locker, interval time.Duration) Worker {
+	return &shipmentTrackingWorker{
+		shipmentUsecase: s,
+		locker:          l,
+		interval:        interval,
+	}
+}
+
+func (w *shipmentTrackingWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.track(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// track runs one batch, only the instance holding the lock does the work so couriers are not asked twice
+func (w *shipmentTrackingWorker) track(ctx context.Context) {
+	unlock, ok, err := w.locker.TryLock(ctx, shipmentTrackingLock)
+	if err != nil {
+		log.Println("shipment tracking lock error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	checkedBefore := time.Now().Add(-w.interval)
+	checked, err := w.shipmentUsecase.TrackInTransit(ctx, checkedBefore, shipmentTrackingBatchSize)
+	if err != nil {
+		log.Println("shipment tracking error", err)
+		return
+	}
+	if len(checked) > 0 {
+		log.Println("shipment tracking checked shipments", len(checked))
+	}
+}
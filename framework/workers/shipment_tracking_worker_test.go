@@ -0,0 +1,58 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestShipmentTrackingWorker(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		unlocked := 0
+		mockShipmentUsecase := new(mocks.ShipmentUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "shipment_tracking").Return(func() { unlocked++ }, true, nil)
+		mockShipmentUsecase.On("TrackInTransit", mock.Anything, mock.MatchedBy(func(checkedBefore time.Time) bool {
+			return time.Since(checkedBefore) >= time.Hour
+		}), 100).Return([]entity.Shipment{{ID: 4}}, nil)
+
+		w := workers.NewShipmentTrackingWorker(mockShipmentUsecase, mockLocker, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		assert.Equal(t, 1, unlocked)
+		mockShipmentUsecase.AssertNumberOfCalls(t, "TrackInTransit", 1)
+	})
+
+	t.Run("lock held by another instance", func(t *testing.T) {
+		mockShipmentUsecase := new(mocks.ShipmentUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "shipment_tracking").Return(nil, false, nil)
+
+		w := workers.NewShipmentTrackingWorker(mockShipmentUsecase, mockLocker, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		mockShipmentUsecase.AssertNotCalled(t, "TrackInTransit", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockShipmentUsecase := new(mocks.ShipmentUseCase)
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "shipment_tracking").Return(func() {}, true, nil)
+		mockShipmentUsecase.On("TrackInTransit", mock.Anything, mock.AnythingOfType("time.Time"), 100).
+			Return(nil, resterrors.NewInternalServerError("error when trying to get data", nil))
+
+		w := workers.NewShipmentTrackingWorker(mockShipmentUsecase, mockLocker, time.Hour)
+		stopped := runFor(w, 50*time.Millisecond)
+
+		assert.True(t, stopped)
+		mockShipmentUsecase.AssertNumberOfCalls(t, "TrackInTransit", 1)
+	})
+}
@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/config"
+	"github.com/hieronimusbudi/komodo-backend/dependencies"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+)
+
+const (
+	defaultOrderExpirySLA           = 24 * time.Hour
+	defaultOrderExpiryInterval      = time.Minute
+	idempotencyCleanupInterval      = time.Hour
+	defaultShipmentTrackingInterval = 15 * time.Minute
+)
+
+// Worker is a background job, Run blocks until ctx is done
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// All starts every background worker with dependencies, the returned channel is closed
+// once all of them stopped after ctx is done
+func All(ctx context.Context, d *dependencies.Dependencies) <-chan struct{} {
+	u := d.Usecases
+
+	all := []Worker{
+		NewOrderExpiryWorker(u.Order, u.Locker,
+			helpers.ParseDuration(config.ORDER_EXPIRY_SLA, defaultOrderExpirySLA),
+			helpers.ParseDuration(config.ORDER_EXPIRY_INTERVAL, defaultOrderExpiryInterval)),
+		NewIdempotencyKeyCleanupWorker(u.Idempotency, idempotencyCleanupInterval),
+	}
+
+	// shipments are only tracked with a courier to ask
+	if d.CourierTracker != nil {
+		all = append(all, NewShipmentTrackingWorker(u.Shipment, u.Locker,
+			helpers.ParseDuration(config.SHIPMENT_TRACKING_INTERVAL, defaultShipmentTrackingInterval)))
+	} else {
+		log.Println("COURIER_TRACKER is not configured, shipments aren't tracked")
+	}
+
+	// the daily sales are only kept when they are refreshed
+	if salesRollupInterval := helpers.ParseDuration(config.SALES_ROLLUP_INTERVAL, 0); salesRollupInterval > 0 {
+		all = append(all, NewSalesRollupWorker(u.Analytics, u.Locker, salesRollupInterval))
+	}
+
+	return start(ctx, all)
+}
+
+func start(ctx context.Context, all []Worker) <-chan struct{} {
+	var wg sync.WaitGroup
+	for _, w := range all {
+		wg.Add(1)
+		go func(w Worker) {
+			defer wg.Done()
+			w.Run(ctx)
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
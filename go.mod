@@ -8,6 +8,7 @@ require (
 	github.com/go-playground/validator/v10 v10.5.0
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/gofiber/fiber/v2 v2.23.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/mattn/goveralls v0.0.11 // indirect
 	github.com/shopspring/decimal v1.2.0
 	github.com/stretchr/objx v0.1.1 // indirect
@@ -25,18 +25,44 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/hieronimusbudi/komodo-backend/config"
 	"github.com/hieronimusbudi/komodo-backend/dependencies"
 	"github.com/hieronimusbudi/komodo-backend/framework/routes"
+	"github.com/hieronimusbudi/komodo-backend/framework/workers"
 )
 
 func main() {
 	app := fiber.New()
 	dependencies := dependencies.NewDependencies()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	workersDone := workers.All(ctx, dependencies)
+
+	// stop accepting requests on SIGINT or SIGTERM, Listen returns once in-flight requests are done
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+		if err := app.Shutdown(); err != nil {
+			log.Println("shutdown error", err)
+		}
+	}()
+
 	routes.All(app, dependencies)
-	app.Listen(fmt.Sprintf(":%s", config.PORT))
+	if err := app.Listen(fmt.Sprintf(":%s", config.PORT)); err != nil {
+		log.Println("listen error", err)
+	}
+
+	// let the workers finish their current batch before the connection pool is closed
+	cancel()
+	<-workersDone
+	dependencies.Conn.Close()
 }
@@ -16,13 +16,23 @@ USE `ecommerce_go`;
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Dumping data for table `admins`
+--
+
+LOCK TABLES `admins` WRITE;
+/*!40000 ALTER TABLE `admins` DISABLE KEYS */;
+INSERT INTO `admins` VALUES (1,'admin@mail.com','john admin','$2a$10$e2kYEecnGMVYJmlbINDWfeLgu5Y9Q5.EJAVjHtUPOahCQkYbpWUyW','2021-05-01 00:00:00');
+/*!40000 ALTER TABLE `admins` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Dumping data for table `buyers`
 --
 
 LOCK TABLES `buyers` WRITE;
 /*!40000 ALTER TABLE `buyers` DISABLE KEYS */;
-INSERT INTO `buyers` VALUES (1,'buyer@mail.com','john buyer','$2a$10$ezSk09Ya2OyEPnc6m7cKfON1BlaZklJoVdXl6VSnOW6GU0SDLFL0G','Jl jalan');
+INSERT INTO `buyers` VALUES (1,'buyer@mail.com','john buyer','$2a$10$ezSk09Ya2OyEPnc6m7cKfON1BlaZklJoVdXl6VSnOW6GU0SDLFL0G','Jl jalan','2021-05-01 00:00:00');
 /*!40000 ALTER TABLE `buyers` ENABLE KEYS */;
 UNLOCK TABLES;
 
@@ -59,7 +69,7 @@ UNLOCK TABLES;
 
 LOCK TABLES `sellers` WRITE;
 /*!40000 ALTER TABLE `sellers` DISABLE KEYS */;
-INSERT INTO `sellers` VALUES (1,'seller@mail.com','john seller','$2a$10$1H0FijTkhaYb/jHBfzKSee1AD3lRvsy/IoFjoUb4uyRw/HSi2yUqS','Jl jalan');
+INSERT INTO `sellers` VALUES (1,'seller@mail.com','john seller','$2a$10$1H0FijTkhaYb/jHBfzKSee1AD3lRvsy/IoFjoUb4uyRw/HSi2yUqS','Jl jalan','DKI Jakarta','12190','UNREGISTERED','2021-05-01 00:00:00');
 /*!40000 ALTER TABLE `sellers` ENABLE KEYS */;
 UNLOCK TABLES;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
@@ -17,6 +17,24 @@ USE `ecommerce_go`;
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `admins`
+--
+
+DROP TABLE IF EXISTS `admins`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `admins` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email_UNIQUE` (`email`)
+) ENGINE=InnoDB AUTO_INCREMENT=2 DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `buyers`
 --
@@ -30,10 +48,91 @@ CREATE TABLE `buyers` (
   `name` varchar(255) NOT NULL,
   `password` varchar(255) NOT NULL,
   `sending_address` varchar(511) NOT NULL,
-  PRIMARY KEY (`id`)
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email_UNIQUE` (`email`),
+  KEY `created_at_idx` (`created_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=22 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `followed_sellers`
+--
+
+DROP TABLE IF EXISTS `followed_sellers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `followed_sellers` (
+  `buyer_id` int(11) NOT NULL,
+  `seller_id` int(11) NOT NULL,
+  `followed_at` datetime NOT NULL,
+  PRIMARY KEY (`buyer_id`,`seller_id`),
+  KEY `seller_id_idx` (`seller_id`),
+  CONSTRAINT `followed_sellers_buyer_id` FOREIGN KEY (`buyer_id`) REFERENCES `buyers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `followed_sellers_seller_id` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `idempotency_keys`
+--
+
+DROP TABLE IF EXISTS `idempotency_keys`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `idempotency_keys` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `idem_key` varchar(255) NOT NULL,
+  `buyer_id` int(11) NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `response_status` int(11) DEFAULT NULL,
+  `response_content_type` varchar(255) DEFAULT NULL,
+  `response_body` mediumblob,
+  `created_at` datetime NOT NULL,
+  `expires_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `buyer_id_idem_key_UNIQUE` (`buyer_id`,`idem_key`),
+  KEY `expires_at_idx` (`expires_at`),
+  CONSTRAINT `idempotency_keys_buyer_id` FOREIGN KEY (`buyer_id`) REFERENCES `buyers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `invoice_sequences`
+--
+
+DROP TABLE IF EXISTS `invoice_sequences`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `invoice_sequences` (
+  `seller_id` int(11) NOT NULL,
+  `last_number` int(11) NOT NULL,
+  PRIMARY KEY (`seller_id`),
+  CONSTRAINT `invoice_sequences_seller_id` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `invoices`
+--
+
+DROP TABLE IF EXISTS `invoices`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `invoices` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `seller_id` int(11) NOT NULL,
+  `number` int(11) NOT NULL,
+  `issued_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `order_id_UNIQUE` (`order_id`),
+  UNIQUE KEY `seller_id_number_UNIQUE` (`seller_id`,`number`),
+  CONSTRAINT `invoices_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `invoices_seller_id` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `order_details`
 --
@@ -46,6 +145,10 @@ CREATE TABLE `order_details` (
   `order_id` int(11) NOT NULL,
   `product_id` int(11) NOT NULL,
   `quantity` int(11) NOT NULL,
+  `price` decimal(15,2) NOT NULL,
+  `discount` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `tax_rate` decimal(7,4) NOT NULL DEFAULT '0.0000',
+  `tax` decimal(15,2) NOT NULL DEFAULT '0.00',
   PRIMARY KEY (`id`),
   KEY `order_id_idx` (`order_id`),
   KEY `product_id_idx` (`product_id`),
@@ -54,6 +157,28 @@ CREATE TABLE `order_details` (
 ) ENGINE=InnoDB AUTO_INCREMENT=98 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `order_status_history`
+--
+
+DROP TABLE IF EXISTS `order_status_history`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `order_status_history` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `previous_status` int(11) DEFAULT NULL,
+  `status` int(11) NOT NULL,
+  `actor_id` int(11) DEFAULT NULL,
+  `actor_type` int(11) DEFAULT NULL,
+  `reason` varchar(511) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `order_id_created_at_idx` (`order_id`,`created_at`),
+  CONSTRAINT `order_status_history_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `orders`
 --
@@ -71,14 +196,80 @@ CREATE TABLE `orders` (
   `total_price` decimal(15,2) NOT NULL,
   `status` int(11) NOT NULL,
   `order_date` datetime NOT NULL,
+  `reason_code` varchar(64) DEFAULT NULL,
+  `reason_note` varchar(511) DEFAULT NULL,
+  `version` int(11) NOT NULL DEFAULT 1,
+  `voucher_id` int(11) DEFAULT NULL,
+  `voucher_code` varchar(32) DEFAULT NULL,
+  `discount` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `shipping_service` varchar(32) DEFAULT NULL,
+  `shipping_fee` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `tax` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `tax_inclusive` tinyint(1) NOT NULL DEFAULT '0',
+  `shipping_origin_province` varchar(64) NOT NULL DEFAULT '',
+  `shipping_origin_postal_code` varchar(5) NOT NULL DEFAULT '',
+  `shipping_destination_province` varchar(64) NOT NULL DEFAULT '',
+  `shipping_destination_postal_code` varchar(5) NOT NULL DEFAULT '',
   PRIMARY KEY (`id`),
-  KEY `buyer_id_idx` (`buyer_id`),
-  KEY `seller_id_idx` (`seller_id`),
+  KEY `buyer_id_order_date_idx` (`buyer_id`,`order_date`),
+  KEY `seller_id_order_date_idx` (`seller_id`,`order_date`),
+  KEY `status_order_date_idx` (`status`,`order_date`),
   CONSTRAINT `buyer_id` FOREIGN KEY (`buyer_id`) REFERENCES `buyers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
   CONSTRAINT `seller_id` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=49 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `payment_webhook_events`
+--
+
+DROP TABLE IF EXISTS `payment_webhook_events`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `payment_webhook_events` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `provider` varchar(64) NOT NULL,
+  `provider_event_id` varchar(255) NOT NULL,
+  `event_type` varchar(255) NOT NULL,
+  `provider_payment_id` varchar(255) NOT NULL,
+  `status` int(11) NOT NULL,
+  `amount` decimal(15,2) NOT NULL,
+  `currency` char(3) NOT NULL DEFAULT '',
+  `payload` mediumblob NOT NULL,
+  `received_at` datetime NOT NULL,
+  `processed_at` datetime DEFAULT NULL,
+  `error` varchar(1023) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `provider_provider_event_id_UNIQUE` (`provider`,`provider_event_id`),
+  KEY `provider_payment_id_idx` (`provider`,`provider_payment_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `payments`
+--
+
+DROP TABLE IF EXISTS `payments`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `payments` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `provider` varchar(64) NOT NULL,
+  `provider_payment_id` varchar(255) NOT NULL,
+  `client_secret` varchar(255) DEFAULT NULL,
+  `amount` decimal(15,2) NOT NULL,
+  `currency` char(3) NOT NULL,
+  `status` int(11) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `provider_provider_payment_id_UNIQUE` (`provider`,`provider_payment_id`),
+  KEY `order_id_idx` (`order_id`),
+  CONSTRAINT `payments_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `products`
 --
@@ -88,16 +279,152 @@ DROP TABLE IF EXISTS `products`;
 /*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `products` (
   `id` int(11) NOT NULL AUTO_INCREMENT,
+  `sku` varchar(64) DEFAULT NULL,
   `name` varchar(255) NOT NULL,
   `description` varchar(511) DEFAULT NULL,
   `price` decimal(15,2) NOT NULL,
+  `weight` int(11) NOT NULL DEFAULT '0',
+  `category` varchar(64) DEFAULT NULL,
   `seller_id` int(11) NOT NULL,
   PRIMARY KEY (`id`),
+  UNIQUE KEY `seller_id_sku_UNIQUE` (`seller_id`,`sku`),
   KEY `products_ibfk_1` (`seller_id`),
   CONSTRAINT `products_ibfk_1` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=8 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `refund_items`
+--
+
+DROP TABLE IF EXISTS `refund_items`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `refund_items` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `refund_id` int(11) NOT NULL,
+  `order_detail_id` int(11) NOT NULL,
+  `quantity` int(11) NOT NULL,
+  `amount` decimal(15,2) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `refund_id_idx` (`refund_id`),
+  KEY `order_detail_id_idx` (`order_detail_id`),
+  CONSTRAINT `refund_items_refund_id` FOREIGN KEY (`refund_id`) REFERENCES `refunds` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `refund_items_order_detail_id` FOREIGN KEY (`order_detail_id`) REFERENCES `order_details` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `refunds`
+--
+
+DROP TABLE IF EXISTS `refunds`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `refunds` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `payment_id` int(11) NOT NULL,
+  `provider_refund_id` varchar(255) NOT NULL,
+  `amount` decimal(15,2) NOT NULL,
+  `reason` varchar(511) DEFAULT NULL,
+  `actor_id` int(11) DEFAULT NULL,
+  `actor_type` int(11) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `payment_id_idx` (`payment_id`),
+  KEY `order_id_idx` (`order_id`),
+  CONSTRAINT `refunds_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `refunds_payment_id` FOREIGN KEY (`payment_id`) REFERENCES `payments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `return_request_items`
+--
+
+DROP TABLE IF EXISTS `return_request_items`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `return_request_items` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `return_request_id` int(11) NOT NULL,
+  `order_detail_id` int(11) NOT NULL,
+  `quantity` int(11) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `return_request_id_idx` (`return_request_id`),
+  KEY `order_detail_id_idx` (`order_detail_id`),
+  CONSTRAINT `return_request_items_return_request_id` FOREIGN KEY (`return_request_id`) REFERENCES `return_requests` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `return_request_items_order_detail_id` FOREIGN KEY (`order_detail_id`) REFERENCES `order_details` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `return_request_photos`
+--
+
+DROP TABLE IF EXISTS `return_request_photos`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `return_request_photos` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `return_request_id` int(11) NOT NULL,
+  `url` varchar(1023) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `return_request_id_idx` (`return_request_id`),
+  CONSTRAINT `return_request_photos_return_request_id` FOREIGN KEY (`return_request_id`) REFERENCES `return_requests` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `return_requests`
+--
+
+DROP TABLE IF EXISTS `return_requests`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `return_requests` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `buyer_id` int(11) NOT NULL,
+  `seller_id` int(11) NOT NULL,
+  `status` int(11) NOT NULL,
+  `reason` varchar(32) NOT NULL,
+  `note` varchar(511) DEFAULT NULL,
+  `reject_reason` varchar(511) DEFAULT NULL,
+  `carrier` varchar(64) DEFAULT NULL,
+  `tracking_number` varchar(255) DEFAULT NULL,
+  `restock` tinyint(1) NOT NULL DEFAULT '0',
+  `refund_id` int(11) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `shipped_at` datetime DEFAULT NULL,
+  `received_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `order_id_idx` (`order_id`),
+  CONSTRAINT `return_requests_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `return_requests_refund_id` FOREIGN KEY (`refund_id`) REFERENCES `refunds` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `seller_daily_sales`
+--
+
+DROP TABLE IF EXISTS `seller_daily_sales`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `seller_daily_sales` (
+  `seller_id` int(11) NOT NULL,
+  `sales_date` date NOT NULL,
+  `orders` int(11) NOT NULL,
+  `revenue` decimal(15,2) NOT NULL,
+  `units_sold` int(11) NOT NULL,
+  PRIMARY KEY (`seller_id`,`sales_date`),
+  KEY `sales_date_idx` (`sales_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `sellers`
 --
@@ -111,9 +438,126 @@ CREATE TABLE `sellers` (
   `name` varchar(255) NOT NULL,
   `password` varchar(255) NOT NULL,
   `pickup_address` varchar(511) NOT NULL,
-  PRIMARY KEY (`id`)
+  `pickup_province` varchar(64) NOT NULL DEFAULT '',
+  `pickup_postal_code` varchar(5) NOT NULL DEFAULT '',
+  `tax_status` varchar(32) NOT NULL DEFAULT 'UNREGISTERED',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email_UNIQUE` (`email`),
+  KEY `created_at_idx` (`created_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `shipment_tracking_events`
+--
+
+DROP TABLE IF EXISTS `shipment_tracking_events`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `shipment_tracking_events` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `shipment_id` int(11) NOT NULL,
+  `code` varchar(64) NOT NULL,
+  `description` varchar(255) NOT NULL,
+  `location` varchar(255) DEFAULT NULL,
+  `occurred_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `shipment_id_code_occurred_at_idx` (`shipment_id`,`code`,`occurred_at`),
+  CONSTRAINT `shipment_tracking_events_shipment_id` FOREIGN KEY (`shipment_id`) REFERENCES `shipments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `shipments`
+--
+
+DROP TABLE IF EXISTS `shipments`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `shipments` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `courier` varchar(64) NOT NULL,
+  `tracking_number` varchar(64) NOT NULL,
+  `status` int(11) NOT NULL DEFAULT '0',
+  `shipped_at` datetime NOT NULL,
+  `delivered_at` datetime DEFAULT NULL,
+  `checked_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `order_id_idx` (`order_id`),
+  KEY `status_checked_at_idx` (`status`,`checked_at`),
+  CONSTRAINT `shipments_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `voucher_usages`
+--
+
+DROP TABLE IF EXISTS `voucher_usages`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `voucher_usages` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `voucher_id` int(11) NOT NULL,
+  `order_id` int(11) NOT NULL,
+  `buyer_id` int(11) NOT NULL,
+  `discount` decimal(15,2) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `order_id_UNIQUE` (`order_id`),
+  KEY `voucher_id_buyer_id_idx` (`voucher_id`,`buyer_id`),
+  CONSTRAINT `voucher_usages_voucher_id` FOREIGN KEY (`voucher_id`) REFERENCES `vouchers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `voucher_usages_order_id` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `vouchers`
+--
+
+DROP TABLE IF EXISTS `vouchers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `vouchers` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `code` varchar(32) NOT NULL,
+  `type` varchar(16) NOT NULL,
+  `value` decimal(15,2) NOT NULL,
+  `seller_id` int(11) DEFAULT NULL,
+  `min_spend` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `max_discount` decimal(15,2) NOT NULL DEFAULT '0.00',
+  `starts_at` datetime NOT NULL,
+  `ends_at` datetime NOT NULL,
+  `usage_limit` int(11) NOT NULL DEFAULT '0',
+  `per_buyer_limit` int(11) NOT NULL DEFAULT '0',
+  `used_count` int(11) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `code_UNIQUE` (`code`),
+  KEY `seller_id_idx` (`seller_id`),
+  CONSTRAINT `vouchers_seller_id` FOREIGN KEY (`seller_id`) REFERENCES `sellers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `wishlist_items`
+--
+
+DROP TABLE IF EXISTS `wishlist_items`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `wishlist_items` (
+  `buyer_id` int(11) NOT NULL,
+  `product_id` int(11) NOT NULL,
+  `added_at` datetime NOT NULL,
+  PRIMARY KEY (`buyer_id`,`product_id`),
+  KEY `product_id_idx` (`product_id`),
+  CONSTRAINT `wishlist_items_buyer_id` FOREIGN KEY (`buyer_id`) REFERENCES `buyers` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `wishlist_items_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
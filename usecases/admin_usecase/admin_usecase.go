@@ -0,0 +1,39 @@
+package adminusecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type adminUsecase struct {
+	adminRepo entity.AdminRepository
+}
+
+// NewAdminUsecase will create a object with entity.AdminUseCase interface representation
+func NewAdminUsecase(adminRepo entity.AdminRepository) entity.AdminUseCase {
+	return &adminUsecase{
+		adminRepo: adminRepo,
+	}
+}
+
+func (a *adminUsecase) Login(ctx context.Context, admin *entity.Admin) (entity.Admin, resterrors.RestErr) {
+	oriPass := admin.Password
+	repoRes, err := a.adminRepo.GetByEmail(ctx, &entity.Admin{Email: admin.Email})
+	if err != nil {
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return entity.Admin{}, resterrors.NewUnauthorizedError("invalid email or password")
+		}
+		return entity.Admin{}, err
+	}
+
+	// compare password
+	if cprErr := bcrypt.CompareHashAndPassword([]byte(repoRes.Password), []byte(oriPass)); cprErr != nil {
+		return entity.Admin{}, resterrors.NewUnauthorizedError("invalid email or password")
+	}
+
+	return repoRes, nil
+}
@@ -0,0 +1,61 @@
+package adminusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	adminusecase "github.com/hieronimusbudi/komodo-backend/usecases/admin_usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// the password of mockAdmin is 12345
+var mockAdmin = entity.Admin{
+	ID:       1,
+	Email:    "admin@mail.com",
+	Name:     "john admin",
+	Password: "$2a$10$e2kYEecnGMVYJmlbINDWfeLgu5Y9Q5.EJAVjHtUPOahCQkYbpWUyW",
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockAdminRepo := new(mocks.AdminRepository)
+		mockAdminRepo.On("GetByEmail", mock.Anything, &entity.Admin{Email: mockAdmin.Email}).Return(mockAdmin, nil).Once()
+
+		u := adminusecase.NewAdminUsecase(mockAdminRepo)
+		res, err := u.Login(context.Background(), &entity.Admin{Email: mockAdmin.Email, Password: "12345"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, mockAdmin.ID, res.ID)
+		mockAdminRepo.AssertExpectations(t)
+	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		mockAdminRepo := new(mocks.AdminRepository)
+		mockAdminRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(mockAdmin, nil).Once()
+
+		u := adminusecase.NewAdminUsecase(mockAdminRepo)
+		_, err := u.Login(context.Background(), &entity.Admin{Email: mockAdmin.Email, Password: "54321"})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
+
+	t.Run("error unknown email", func(t *testing.T) {
+		mockAdminRepo := new(mocks.AdminRepository)
+		mockAdminRepo.On("GetByEmail", mock.Anything, mock.Anything).
+			Return(entity.Admin{}, resterrors.NewNotFoundError("admin with email nobody@mail.com not found")).Once()
+
+		u := adminusecase.NewAdminUsecase(mockAdminRepo)
+		_, err := u.Login(context.Background(), &entity.Admin{Email: "nobody@mail.com", Password: "12345"})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
+}
@@ -0,0 +1,131 @@
+package analyticsusecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+const (
+	defaultSalesDays = 30
+	defaultTop       = 5
+	// maxSalesDays is the longest range of sales asked for at once
+	maxSalesDays = 366
+)
+
+type analyticsUsecase struct {
+	analyticsRepo entity.AnalyticsRepository
+	dailySales    bool
+}
+
+// NewAnalyticsUsecase will create a object with entity.AnalyticsUseCase interface representation. With dailySales
+// the sales of the days before today are read from the daily sales kept by RefreshDailySales
+func NewAnalyticsUsecase(a entity.AnalyticsRepository, dailySales bool) entity.AnalyticsUseCase {
+	return &analyticsUsecase{
+		analyticsRepo: a,
+		dailySales:    dailySales,
+	}
+}
+
+// today is the start of the current day
+func today() (time.Time, resterrors.RestErr) {
+	now, err := helpers.GetTimeNow()
+	if err != nil {
+		return time.Time{}, resterrors.NewInternalServerError("error when trying to get time", err)
+	}
+	return entity.SALES_GROUP_DAY.PeriodStart(now), nil
+}
+
+// checkSalesFilter fills the defaults of filter, the last 30 days by day with the top 5 products
+func checkSalesFilter(filter *entity.SalesFilter, today time.Time) resterrors.RestErr {
+	if filter.Group == "" {
+		filter.Group = entity.SALES_GROUP_DAY
+	}
+	if filter.Top <= 0 {
+		filter.Top = defaultTop
+	}
+	if filter.To.IsZero() {
+		filter.To = today.AddDate(0, 0, 1)
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, -defaultSalesDays)
+	}
+	if !filter.From.Before(filter.To) {
+		return resterrors.NewBadRequestError("from must be before to")
+	}
+	if filter.From.AddDate(0, 0, maxSalesDays).Before(filter.To) {
+		return resterrors.NewBadRequestError(fmt.Sprintf("the range can be at most %d days", maxSalesDays))
+	}
+	return nil
+}
+
+func (u *analyticsUsecase) GetSellerSales(ctx context.Context, sellerID int64, filter entity.SalesFilter) (entity.SalesAnalytics, resterrors.RestErr) {
+	today, err := today()
+	if err != nil {
+		return entity.SalesAnalytics{}, err
+	}
+	if err := checkSalesFilter(&filter, today); err != nil {
+		return entity.SalesAnalytics{}, err
+	}
+
+	sales, err := u.getSales(ctx, sellerID, filter, today)
+	if err != nil {
+		return entity.SalesAnalytics{}, err
+	}
+
+	// the sales of a period read from both sources are summed, periods without any are left at zero
+	byStart := map[string]entity.Sales{}
+	for _, s := range sales {
+		key := s.Start.Format("2006-01-02")
+		byStart[key] = byStart[key].Add(s)
+	}
+	res := entity.SalesAnalytics{Filter: filter, Periods: []entity.Sales{}}
+	for start := filter.Group.PeriodStart(filter.From); start.Before(filter.To); start = filter.Group.Next(start) {
+		period := byStart[start.Format("2006-01-02")]
+		period.Start = start
+		res.Periods = append(res.Periods, period)
+		res.Total = res.Total.Add(period)
+	}
+
+	res.TopProducts, err = u.analyticsRepo.GetTopProducts(ctx, sellerID, filter.From, filter.To, filter.Top)
+	if err != nil {
+		return entity.SalesAnalytics{}, err
+	}
+	return res, nil
+}
+
+// getSales reads the days before today from the daily sales when they are kept, the rest from the orders
+func (u *analyticsUsecase) getSales(ctx context.Context, sellerID int64, filter entity.SalesFilter, today time.Time) ([]entity.Sales, resterrors.RestErr) {
+	if !u.dailySales || !filter.From.Before(today) {
+		return u.analyticsRepo.GetSales(ctx, sellerID, filter.From, filter.To, filter.Group)
+	}
+
+	split := today
+	if filter.To.Before(split) {
+		split = filter.To
+	}
+	sales, err := u.analyticsRepo.GetDailySales(ctx, sellerID, filter.From, split, filter.Group)
+	if err != nil {
+		return nil, err
+	}
+	if split.Before(filter.To) {
+		todaySales, err := u.analyticsRepo.GetSales(ctx, sellerID, split, filter.To, filter.Group)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, todaySales...)
+	}
+	return sales, nil
+}
+
+func (u *analyticsUsecase) RefreshDailySales(ctx context.Context, days int) resterrors.RestErr {
+	today, err := today()
+	if err != nil {
+		return err
+	}
+	return u.analyticsRepo.RefreshDailySales(ctx, today.AddDate(0, 0, 1-days), today.AddDate(0, 0, 1))
+}
@@ -0,0 +1,152 @@
+package analyticsusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	analyticsusecase "github.com/hieronimusbudi/komodo-backend/usecases/analytics_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func today(t *testing.T) time.Time {
+	now, err := helpers.GetTimeNow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entity.SALES_GROUP_DAY.PeriodStart(now)
+}
+
+func TestGetSellerSales(t *testing.T) {
+	topProducts := []entity.ProductSales{{Product: entity.Product{ID: 7, Name: "Kopi Toraja"}, UnitsSold: 3,
+		Revenue: decimal.NewFromInt(150000)}}
+
+	t.Run("success", func(t *testing.T) {
+		mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+		from, to := day(2021, 5, 1), day(2021, 6, 1)
+		mockAnalyticsRepo.On("GetSales", mock.Anything, int64(2), from, to, entity.SALES_GROUP_WEEK).Return([]entity.Sales{
+			{Start: day(2021, 4, 26), Orders: 2, Revenue: decimal.NewFromInt(100000), UnitsSold: 2},
+			{Start: day(2021, 5, 17), Orders: 1, Revenue: decimal.NewFromInt(50001), UnitsSold: 1},
+		}, nil).Once()
+		mockAnalyticsRepo.On("GetTopProducts", mock.Anything, int64(2), from, to, 3).Return(topProducts, nil).Once()
+
+		u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, false)
+		res, err := u.GetSellerSales(context.Background(), 2, entity.SalesFilter{From: from, To: to, Group: entity.SALES_GROUP_WEEK, Top: 3})
+
+		assert.Nil(t, err)
+		// every week of May, the first and last ones start or end outside of it
+		if !assert.Len(t, res.Periods, 6) {
+			return
+		}
+		assert.Equal(t, day(2021, 4, 26), res.Periods[0].Start)
+		assert.Equal(t, int64(2), res.Periods[0].Orders)
+		assert.Equal(t, day(2021, 5, 10), res.Periods[2].Start)
+		assert.Equal(t, int64(0), res.Periods[2].Orders)
+		assert.True(t, res.Periods[2].Revenue.IsZero())
+		assert.Equal(t, day(2021, 5, 31), res.Periods[5].Start)
+		assert.Equal(t, int64(3), res.Total.Orders)
+		assert.Equal(t, int64(3), res.Total.UnitsSold)
+		assert.True(t, decimal.NewFromInt(150001).Equal(res.Total.Revenue))
+		assert.True(t, decimal.RequireFromString("50000.33").Equal(res.Total.AverageOrderValue()))
+		assert.Equal(t, topProducts, res.TopProducts)
+		mockAnalyticsRepo.AssertExpectations(t)
+	})
+
+	t.Run("success defaults", func(t *testing.T) {
+		mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+		to := today(t).AddDate(0, 0, 1)
+		from := to.AddDate(0, 0, -30)
+		mockAnalyticsRepo.On("GetSales", mock.Anything, int64(2), from, to, entity.SALES_GROUP_DAY).Return([]entity.Sales{}, nil).Once()
+		mockAnalyticsRepo.On("GetTopProducts", mock.Anything, int64(2), from, to, 5).Return([]entity.ProductSales{}, nil).Once()
+
+		u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, false)
+		res, err := u.GetSellerSales(context.Background(), 2, entity.SalesFilter{})
+
+		assert.Nil(t, err)
+		assert.Len(t, res.Periods, 30)
+		assert.Equal(t, entity.SalesFilter{From: from, To: to, Group: entity.SALES_GROUP_DAY, Top: 5}, res.Filter)
+		mockAnalyticsRepo.AssertExpectations(t)
+	})
+
+	t.Run("success daily sales until today", func(t *testing.T) {
+		mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+		today := today(t)
+		from, to := today.AddDate(0, 0, -10), today.AddDate(0, 0, 1)
+		mockAnalyticsRepo.On("GetDailySales", mock.Anything, int64(2), from, today, entity.SALES_GROUP_MONTH).Return([]entity.Sales{
+			{Start: entity.SALES_GROUP_MONTH.PeriodStart(from), Orders: 4, Revenue: decimal.NewFromInt(400000), UnitsSold: 5},
+		}, nil).Once()
+		// today is read from the orders as it isn't over yet
+		mockAnalyticsRepo.On("GetSales", mock.Anything, int64(2), today, to, entity.SALES_GROUP_MONTH).Return([]entity.Sales{
+			{Start: entity.SALES_GROUP_MONTH.PeriodStart(today), Orders: 1, Revenue: decimal.NewFromInt(100000), UnitsSold: 1},
+		}, nil).Once()
+		mockAnalyticsRepo.On("GetTopProducts", mock.Anything, int64(2), from, to, 5).Return([]entity.ProductSales{}, nil).Once()
+
+		u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, true)
+		res, err := u.GetSellerSales(context.Background(), 2, entity.SalesFilter{From: from, To: to, Group: entity.SALES_GROUP_MONTH})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), res.Total.Orders)
+		assert.True(t, decimal.NewFromInt(500000).Equal(res.Total.Revenue))
+		assert.Equal(t, entity.SALES_GROUP_MONTH.PeriodStart(from), res.Periods[0].Start)
+		mockAnalyticsRepo.AssertExpectations(t)
+	})
+
+	t.Run("success daily sales in the past", func(t *testing.T) {
+		mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+		from, to := day(2021, 5, 1), day(2021, 6, 1)
+		mockAnalyticsRepo.On("GetDailySales", mock.Anything, int64(2), from, to, entity.SALES_GROUP_DAY).Return([]entity.Sales{}, nil).Once()
+		mockAnalyticsRepo.On("GetTopProducts", mock.Anything, int64(2), from, to, 5).Return([]entity.ProductSales{}, nil).Once()
+
+		u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, true)
+		res, err := u.GetSellerSales(context.Background(), 2, entity.SalesFilter{From: from, To: to})
+
+		assert.Nil(t, err)
+		assert.Len(t, res.Periods, 31)
+		mockAnalyticsRepo.AssertNotCalled(t, "GetSales", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockAnalyticsRepo.AssertExpectations(t)
+	})
+
+	t.Run("error range", func(t *testing.T) {
+		mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+		u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, false)
+
+		_, err := u.GetSellerSales(context.Background(), 2, entity.SalesFilter{From: day(2021, 6, 1), To: day(2021, 5, 1)})
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+
+		_, err = u.GetSellerSales(context.Background(), 2, entity.SalesFilter{From: day(2020, 1, 1), To: day(2021, 6, 1)})
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		assert.Empty(t, mockAnalyticsRepo.Calls)
+	})
+}
+
+func TestRefreshDailySales(t *testing.T) {
+	mockAnalyticsRepo := new(mocks.AnalyticsRepository)
+	today := today(t)
+	mockAnalyticsRepo.On("RefreshDailySales", mock.Anything, today.AddDate(0, 0, -6), today.AddDate(0, 0, 1)).Return(nil).Once()
+
+	u := analyticsusecase.NewAnalyticsUsecase(mockAnalyticsRepo, true)
+	err := u.RefreshDailySales(context.Background(), 7)
+
+	assert.Nil(t, err)
+	mockAnalyticsRepo.AssertExpectations(t)
+}
+
+func TestSalesGroup(t *testing.T) {
+	// a Sunday
+	sunday := time.Date(2021, 5, 16, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, day(2021, 5, 16), entity.SALES_GROUP_DAY.PeriodStart(sunday))
+	assert.Equal(t, day(2021, 5, 10), entity.SALES_GROUP_WEEK.PeriodStart(sunday))
+	assert.Equal(t, day(2021, 5, 17), entity.SALES_GROUP_WEEK.PeriodStart(day(2021, 5, 17)))
+	assert.Equal(t, day(2021, 5, 1), entity.SALES_GROUP_MONTH.PeriodStart(sunday))
+	assert.Equal(t, day(2021, 2, 1), entity.SALES_GROUP_MONTH.Next(day(2021, 1, 1)))
+}
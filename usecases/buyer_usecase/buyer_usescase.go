@@ -1,9 +1,12 @@
 package buyerusecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -19,17 +22,17 @@ func NewBuyerUsecase(buyerRepo entity.BuyerRepository) entity.BuyerUseCase {
 	}
 }
 
-func (b *buyerUsecase) Register(buyer *entity.Buyer) resterrors.RestErr {
+func (b *buyerUsecase) Register(ctx context.Context, buyer *entity.Buyer) resterrors.RestErr {
 	// check existing user
 	sb := new(entity.Buyer)
 	sb.Email = buyer.Email
-	repoRes, err := b.buyerRepo.GetByEmail(sb)
+	repoRes, err := b.buyerRepo.GetByEmail(ctx, sb)
 	if err != nil {
-		if err.Causes() != "sql: no rows in result set" {
+		if !errors.Is(err, resterrors.ErrNotFound) {
 			return err
 		}
 	} else if repoRes.Email == buyer.Email {
-		return resterrors.NewBadRequestError(fmt.Sprintf("user with email %s is already exist", repoRes.Email))
+		return resterrors.NewConflictError(fmt.Sprintf("user with email %s is already exist", repoRes.Email))
 	}
 
 	// encrypt password
@@ -39,25 +42,35 @@ func (b *buyerUsecase) Register(buyer *entity.Buyer) resterrors.RestErr {
 	}
 
 	buyer.Password = string(hashedPassword)
+	// the account is timestamped by the app, like every other record
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	buyer.CreatedAt = tn
 
-	repoErr := b.buyerRepo.Store(buyer)
+	repoErr := b.buyerRepo.Store(ctx, buyer)
 	if repoErr != nil {
 		return repoErr
 	}
 	return nil
 }
 
-func (b *buyerUsecase) Login(buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
+func (b *buyerUsecase) Login(ctx context.Context, buyer *entity.Buyer) (entity.Buyer, resterrors.RestErr) {
 	oriPass := buyer.Password
-	repoRes, err := b.buyerRepo.GetByEmail(buyer)
+	repoRes, err := b.buyerRepo.GetByEmail(ctx, buyer)
 	if err != nil {
-		return *buyer, err
+		// an unknown email fails like a wrong password, so logins can't tell which emails are registered
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return entity.Buyer{}, resterrors.NewUnauthorizedError("invalid email or password")
+		}
+		return entity.Buyer{}, err
 	}
 
 	// compare password
 	cprErr := bcrypt.CompareHashAndPassword([]byte(repoRes.Password), []byte(oriPass))
 	if cprErr != nil {
-		return *buyer, resterrors.NewInternalServerError(cprErr.Error(), cprErr)
+		return entity.Buyer{}, resterrors.NewUnauthorizedError("invalid email or password")
 	}
 
 	return repoRes, nil
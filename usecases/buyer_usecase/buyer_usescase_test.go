@@ -1,10 +1,14 @@
 package buyerusecase_test
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	buyerusecase "github.com/hieronimusbudi/komodo-backend/usecases/buyer_usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -32,25 +36,56 @@ func TestRegister(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		tmpMockBuyer := mockBuyer
 
-		mockBuyerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerEmpty, nil).Once()
-		mockBuyerRepo.On("Store", mock.AnythingOfType("*entity.Buyer")).Return(nil).Once()
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerEmpty, nil).Once()
+		mockBuyerRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(nil).Once()
 
 		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
-		err := u.Register(&tmpMockBuyer)
+		err := u.Register(context.Background(), &tmpMockBuyer)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockBuyer.Email, tmpMockBuyer.Email)
+		assert.False(t, tmpMockBuyer.CreatedAt.IsZero())
+		mockBuyerRepo.AssertExpectations(t)
+	})
+
+	t.Run("success when user not found", func(t *testing.T) {
+		tmpMockBuyer := mockBuyer
+
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).
+			Return(mockBuyerEmpty, resterrors.NewNotFoundError("buyer not found")).Once()
+		mockBuyerRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(nil).Once()
+
+		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
+		err := u.Register(context.Background(), &tmpMockBuyer)
+
+		assert.NoError(t, err)
 		mockBuyerRepo.AssertExpectations(t)
 	})
 
 	t.Run("user is already exist", func(t *testing.T) {
 		tmpMockBuyer := mockBuyer
 
-		mockBuyerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerExist, nil).Once()
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerExist, nil).Once()
 		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
-		err := u.Register(&tmpMockBuyer)
+		err := u.Register(context.Background(), &tmpMockBuyer)
 
 		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockBuyerRepo.AssertExpectations(t)
+	})
+
+	t.Run("user registered concurrently", func(t *testing.T) {
+		tmpMockBuyer := mockBuyer
+
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).
+			Return(mockBuyerEmpty, resterrors.NewNotFoundError("buyer not found")).Once()
+		mockBuyerRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Buyer")).
+			Return(resterrors.NewConflictError("user with email buyer1@mail.com is already exist")).Once()
+
+		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
+		err := u.Register(context.Background(), &tmpMockBuyer)
+
+		assert.True(t, errors.Is(err, resterrors.ErrConflict))
 		mockBuyerRepo.AssertExpectations(t)
 	})
 }
@@ -75,13 +110,41 @@ func TestLogin(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockBuyerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerRepoResponse, nil).Once()
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerRepoResponse, nil).Once()
 
 		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
-		uRes, err := u.Login(&mockBuyer)
+		uRes, err := u.Login(context.Background(), &mockBuyer)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockBuyerRepoResponse.ID, uRes.ID)
 		mockBuyerRepo.AssertExpectations(t)
 	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		wrongPassword := mockBuyer
+		wrongPassword.Password = "54321"
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).Return(mockBuyerRepoResponse, nil).Once()
+
+		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
+		_, err := u.Login(context.Background(), &wrongPassword)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
+
+	t.Run("error unknown email", func(t *testing.T) {
+		unknown := mockBuyer
+		unknown.Email = "nobody@mail.com"
+		mockBuyerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Buyer")).
+			Return(entity.Buyer{}, resterrors.NewNotFoundError("buyer with email nobody@mail.com not found")).Once()
+
+		u := buyerusecase.NewBuyerUsecase(mockBuyerRepo)
+		_, err := u.Login(context.Background(), &unknown)
+
+		// the same error as a wrong password
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
 }
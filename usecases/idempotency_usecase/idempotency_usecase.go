@@ -0,0 +1,82 @@
+package idempotencyusecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type idempotencyUsecase struct {
+	idempotencyRepo entity.IdempotencyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyUsecase will create a object with entity.IdempotencyUseCase interface representation,
+// keys are kept for ttl after they are reserved
+func NewIdempotencyUsecase(idempotencyRepo entity.IdempotencyRepository, ttl time.Duration) entity.IdempotencyUseCase {
+	return &idempotencyUsecase{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+func (u *idempotencyUsecase) Reserve(ctx context.Context, buyerID int64, key string, requestHash string) (entity.IdempotencyKey, bool, resterrors.RestErr) {
+	tn, err := helpers.GetTimeNow()
+	if err != nil {
+		return entity.IdempotencyKey{}, false, resterrors.NewInternalServerError("error when trying to save data", err)
+	}
+
+	idemKey := entity.IdempotencyKey{
+		Key:         key,
+		BuyerID:     buyerID,
+		RequestHash: requestHash,
+		CreatedAt:   tn,
+		ExpiresAt:   tn.Add(u.ttl),
+	}
+	existing, reserved, rErr := u.idempotencyRepo.Reserve(ctx, &idemKey)
+	if rErr != nil {
+		return entity.IdempotencyKey{}, false, rErr
+	}
+	if reserved {
+		return idemKey, false, nil
+	}
+
+	if existing.RequestHash != requestHash {
+		return entity.IdempotencyKey{}, false,
+			resterrors.NewUnprocessableEntityError("Idempotency-Key was already used with another request", nil)
+	}
+	if !existing.Completed() {
+		return entity.IdempotencyKey{}, false, resterrors.NewConflictError("a request with the same Idempotency-Key is in progress")
+	}
+	return existing, true, nil
+}
+
+func (u *idempotencyUsecase) Complete(ctx context.Context, key *entity.IdempotencyKey) resterrors.RestErr {
+	rErr := u.idempotencyRepo.Complete(ctx, key)
+	if rErr == nil {
+		return nil
+	}
+
+	// released rather than left in progress, so retries are not rejected until the key expires
+	if dErr := u.idempotencyRepo.Delete(ctx, key); dErr != nil {
+		log.Println("idempotency key delete error", key.Key, dErr)
+	}
+	return rErr
+}
+
+func (u *idempotencyUsecase) Release(ctx context.Context, key *entity.IdempotencyKey) resterrors.RestErr {
+	return u.idempotencyRepo.Delete(ctx, key)
+}
+
+func (u *idempotencyUsecase) DeleteExpired(ctx context.Context) (int64, resterrors.RestErr) {
+	// expiry dates are saved the way helpers.GetTimeNow formats them
+	tn, err := helpers.GetTimeNow()
+	if err != nil {
+		return 0, resterrors.NewInternalServerError("error when trying to delete data", err)
+	}
+	return u.idempotencyRepo.DeleteExpired(ctx, tn)
+}
@@ -0,0 +1,139 @@
+package idempotencyusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	idempotencyusecase "github.com/hieronimusbudi/komodo-backend/usecases/idempotency_usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// savedKey is key-1 of buyer 7 as it is saved by a request with hash-1
+func savedKey(k *entity.IdempotencyKey) entity.IdempotencyKey {
+	return entity.IdempotencyKey{Key: "key-1", BuyerID: 7, RequestHash: "hash-1", CreatedAt: k.CreatedAt, ExpiresAt: k.ExpiresAt}
+}
+
+func TestReserve(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		mockIdempotencyRepo.On("Reserve", mock.Anything, mock.MatchedBy(func(k *entity.IdempotencyKey) bool {
+			return k.Key == "key-1" && k.BuyerID == 7 && k.RequestHash == "hash-1" && k.ExpiresAt.Sub(k.CreatedAt) == 24*time.Hour
+		})).Return(entity.IdempotencyKey{}, true, nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		key, replay, err := u.Reserve(context.Background(), 7, "key-1", "hash-1")
+
+		assert.Nil(t, err)
+		assert.False(t, replay)
+		assert.Equal(t, "key-1", key.Key)
+		assert.Equal(t, int64(7), key.BuyerID)
+		mockIdempotencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		// the key was completed by a request with the same hash
+		mockIdempotencyRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).
+			Return(func(ctx context.Context, k *entity.IdempotencyKey) entity.IdempotencyKey {
+				saved := savedKey(k)
+				saved.ResponseStatus = http.StatusCreated
+				saved.ResponseBody = []byte(`{"data":{"id":1}}`)
+				return saved
+			}, false, nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		key, replay, err := u.Reserve(context.Background(), 7, "key-1", "hash-1")
+
+		assert.Nil(t, err)
+		assert.True(t, replay)
+		assert.Equal(t, http.StatusCreated, key.ResponseStatus)
+		assert.Equal(t, `{"data":{"id":1}}`, string(key.ResponseBody))
+	})
+
+	t.Run("error-used-with-another-request", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		mockIdempotencyRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).
+			Return(func(ctx context.Context, k *entity.IdempotencyKey) entity.IdempotencyKey {
+				saved := savedKey(k)
+				saved.ResponseStatus = http.StatusCreated
+				return saved
+			}, false, nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		_, replay, err := u.Reserve(context.Background(), 7, "key-1", "hash-2")
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, err.Status())
+		assert.False(t, replay)
+	})
+
+	t.Run("error-in-progress", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		// the key was saved by a request with the same hash that hasn't finished yet
+		mockIdempotencyRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).
+			Return(func(ctx context.Context, k *entity.IdempotencyKey) entity.IdempotencyKey { return savedKey(k) }, false, nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		_, replay, err := u.Reserve(context.Background(), 7, "key-1", "hash-1")
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.False(t, replay)
+	})
+
+	t.Run("error-repository", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		mockIdempotencyRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).
+			Return(entity.IdempotencyKey{}, false, resterrors.NewInternalServerError("error when trying to save data", nil)).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		_, _, err := u.Reserve(context.Background(), 7, "key-1", "hash-1")
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, err.Status())
+	})
+}
+
+func TestComplete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		mockIdempotencyRepo.On("Complete", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).Return(nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		err := u.Complete(context.Background(), &entity.IdempotencyKey{Key: "key-1", BuyerID: 7, ResponseStatus: http.StatusCreated})
+
+		assert.Nil(t, err)
+		mockIdempotencyRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-releases-key", func(t *testing.T) {
+		mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+		mockIdempotencyRepo.On("Complete", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).
+			Return(resterrors.NewInternalServerError("error when trying to update data", nil)).Once()
+		mockIdempotencyRepo.On("Delete", mock.Anything, mock.AnythingOfType("*entity.IdempotencyKey")).Return(nil).Once()
+
+		u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+		err := u.Complete(context.Background(), &entity.IdempotencyKey{Key: "key-1", BuyerID: 7, ResponseStatus: http.StatusCreated})
+
+		assert.Error(t, err)
+		mockIdempotencyRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeleteExpired(t *testing.T) {
+	mockIdempotencyRepo := new(mocks.IdempotencyRepository)
+	mockIdempotencyRepo.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+
+	u := idempotencyusecase.NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+	deleted, err := u.DeleteExpired(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), deleted)
+	mockIdempotencyRepo.AssertExpectations(t)
+}
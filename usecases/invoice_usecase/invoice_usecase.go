@@ -0,0 +1,86 @@
+package invoiceusecase
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type invoiceUsecase struct {
+	invoiceRepo entity.InvoiceRepository
+	orders      entity.OrderUseCase
+	sellerRepo  entity.SellerRepository
+	renderer    entity.InvoiceRenderer
+}
+
+// NewInvoiceUsecase will create a object with entity.InvoiceUseCase interface representation,
+// orders loads the order of an invoice for the user asking for it and renderer writes invoices
+func NewInvoiceUsecase(invoiceRepo entity.InvoiceRepository, orders entity.OrderUseCase, sellerRepo entity.SellerRepository,
+	renderer entity.InvoiceRenderer) entity.InvoiceUseCase {
+	return &invoiceUsecase{
+		invoiceRepo: invoiceRepo,
+		orders:      orders,
+		sellerRepo:  sellerRepo,
+		renderer:    renderer,
+	}
+}
+
+func (u *invoiceUsecase) GetByOrderID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Invoice, resterrors.RestErr) {
+	// only the buyer and the seller of the order get its invoice
+	repoOrder, err := u.orders.GetByID(ctx, order, user)
+	if err != nil {
+		return entity.Invoice{}, err
+	}
+
+	switch repoOrder.Status {
+	case entity.ACCEPTED, entity.SHIPPED, entity.DELIVERED:
+	default:
+		return entity.Invoice{}, resterrors.NewConflictError("only accepted orders have an invoice")
+	}
+
+	// the invoice is sent from the pickup address of the seller
+	seller := entity.Seller{ID: repoOrder.Seller.ID}
+	if err := u.sellerRepo.GetByID(ctx, &seller); err != nil {
+		return entity.Invoice{}, err
+	}
+	repoOrder.Seller.PickUpAddress = seller.PickUpAddress
+
+	invoice, err := u.invoiceRepo.GetByOrderID(ctx, repoOrder.ID)
+	if err != nil && !errors.Is(err, resterrors.ErrNotFound) {
+		return entity.Invoice{}, err
+	}
+	if err != nil {
+		if invoice, err = u.issue(ctx, repoOrder); err != nil {
+			return entity.Invoice{}, err
+		}
+	}
+
+	invoice.Order = repoOrder
+	return invoice, nil
+}
+
+// issue numbers and saves the first invoice of order, an invoice issued meanwhile by another request is returned instead
+func (u *invoiceUsecase) issue(ctx context.Context, order entity.Order) (entity.Invoice, resterrors.RestErr) {
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.Invoice{}, resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+
+	invoice := entity.Invoice{OrderID: order.ID, SellerID: order.Seller.ID, IssuedAt: tn}
+	err := u.invoiceRepo.Store(ctx, &invoice)
+	if err != nil && errors.Is(err, resterrors.ErrConflict) {
+		return u.invoiceRepo.GetByOrderID(ctx, order.ID)
+	}
+	return invoice, err
+}
+
+func (u *invoiceUsecase) Render(ctx context.Context, invoice entity.Invoice, w io.Writer) resterrors.RestErr {
+	if err := u.renderer.Render(w, invoice); err != nil {
+		return resterrors.NewInternalServerError("error when trying to render invoice", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package invoiceusecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	invoiceusecase "github.com/hieronimusbudi/komodo-backend/usecases/invoice_usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	mockOrder = entity.Order{
+		ID:     1,
+		Buyer:  entity.Buyer{ID: 1},
+		Seller: entity.Seller{ID: 2},
+		Status: entity.ACCEPTED,
+	}
+	mockInvoice = entity.Invoice{
+		ID:       3,
+		OrderID:  1,
+		SellerID: 2,
+		Number:   7,
+		IssuedAt: time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	buyer = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+)
+
+// sellerRepo finds the seller of mockOrder
+func sellerRepo() *mocks.SellerRepository {
+	mockSellerRepo := new(mocks.SellerRepository)
+	mockSellerRepo.On("GetByID", mock.Anything, &entity.Seller{ID: 2}).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.Seller).PickUpAddress = "Jl. Sudirman No. 1"
+	}).Once()
+	return mockSellerRepo
+}
+
+func TestGetByOrderID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.InvoiceRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrders.On("GetByID", mock.Anything, &entity.Order{ID: 1}, buyer).Return(mockOrder, nil).Once()
+		mockInvoiceRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(mockInvoice, nil).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(mockInvoiceRepo, mockOrders, sellerRepo(), new(mocks.InvoiceRenderer))
+		invoice, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(7), invoice.Number)
+		assert.Equal(t, mockOrder.ID, invoice.Order.ID)
+		assert.Equal(t, "Jl. Sudirman No. 1", invoice.Order.Seller.PickUpAddress)
+		mockInvoiceRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success-issued", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.InvoiceRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrders.On("GetByID", mock.Anything, &entity.Order{ID: 1}, buyer).Return(mockOrder, nil).Once()
+		mockInvoiceRepo.On("GetByOrderID", mock.Anything, int64(1)).
+			Return(entity.Invoice{}, resterrors.NewNotFoundError("order with id 1 has no invoice")).Once()
+		mockInvoiceRepo.On("Store", mock.Anything, mock.MatchedBy(func(i *entity.Invoice) bool {
+			return i.OrderID == 1 && i.SellerID == 2 && !i.IssuedAt.IsZero()
+		})).Return(nil).Run(func(args mock.Arguments) {
+			i := args.Get(1).(*entity.Invoice)
+			i.ID = 3
+			i.Number = 1
+		}).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(mockInvoiceRepo, mockOrders, sellerRepo(), new(mocks.InvoiceRenderer))
+		invoice, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), invoice.ID)
+		assert.Equal(t, int64(1), invoice.Number)
+		assert.Equal(t, mockOrder.ID, invoice.Order.ID)
+		mockInvoiceRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-issued-meanwhile", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.InvoiceRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrders.On("GetByID", mock.Anything, &entity.Order{ID: 1}, buyer).Return(mockOrder, nil).Once()
+		mockInvoiceRepo.On("GetByOrderID", mock.Anything, int64(1)).
+			Return(entity.Invoice{}, resterrors.NewNotFoundError("order with id 1 has no invoice")).Once()
+		mockInvoiceRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Invoice")).
+			Return(resterrors.NewConflictError("order with id 1 already has an invoice")).Once()
+		mockInvoiceRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(mockInvoice, nil).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(mockInvoiceRepo, mockOrders, sellerRepo(), new(mocks.InvoiceRenderer))
+		invoice, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(7), invoice.Number)
+		mockInvoiceRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-not-accepted", func(t *testing.T) {
+		mockOrders := new(mocks.OrderUseCase)
+		pending := mockOrder
+		pending.Status = entity.PENDING
+		mockOrders.On("GetByID", mock.Anything, &entity.Order{ID: 1}, buyer).Return(pending, nil).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(new(mocks.InvoiceRepository), mockOrders, new(mocks.SellerRepository), new(mocks.InvoiceRenderer))
+		_, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+
+	t.Run("error-not-a-participant", func(t *testing.T) {
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrders.On("GetByID", mock.Anything, &entity.Order{ID: 1}, buyer).
+			Return(entity.Order{}, resterrors.NewForbiddenError("forbidden")).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(new(mocks.InvoiceRepository), mockOrders, new(mocks.SellerRepository), new(mocks.InvoiceRenderer))
+		_, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRenderer := new(mocks.InvoiceRenderer)
+		mockRenderer.On("Render", mock.Anything, mockInvoice).Return(nil).Run(func(args mock.Arguments) {
+			io.WriteString(args.Get(0).(io.Writer), "%PDF")
+		}).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(new(mocks.InvoiceRepository), new(mocks.OrderUseCase), new(mocks.SellerRepository), mockRenderer)
+		var buf bytes.Buffer
+		err := u.Render(context.Background(), mockInvoice, &buf)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "%PDF", buf.String())
+	})
+
+	t.Run("error-render", func(t *testing.T) {
+		mockRenderer := new(mocks.InvoiceRenderer)
+		mockRenderer.On("Render", mock.Anything, mockInvoice).Return(errors.New("unexpected")).Once()
+
+		u := invoiceusecase.NewInvoiceUsecase(new(mocks.InvoiceRepository), new(mocks.OrderUseCase), new(mocks.SellerRepository), mockRenderer)
+		err := u.Render(context.Background(), mockInvoice, new(bytes.Buffer))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, err.Status())
+	})
+}
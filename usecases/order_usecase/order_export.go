@@ -0,0 +1,78 @@
+package orderusecase
+
+import (
+	"context"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// exportPageSize is how many orders an export reads at a time
+const exportPageSize = maxPageSize
+
+var orderExportHeader = []interface{}{
+	"Order ID", "Order date", "Status", "Buyer ID", "Destination address", "Voucher code", "Discount",
+	"Shipping service", "Shipping fee", "Tax", "Tax inclusive", "Total price",
+	"Product ID", "Product name", "Quantity", "Unit price", "Tax rate", "Item tax",
+}
+
+func (u *orderUsecase) ExportBySellerID(ctx context.Context, sellerID int64, filter entity.OrderFilter) (entity.OrderExport, resterrors.RestErr) {
+	filter.Limit = exportPageSize
+	filter.Cursor = ""
+	if err := checkFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	return func(w entity.SheetWriter) resterrors.RestErr {
+		if err := w.WriteRow(orderExportHeader...); err != nil {
+			return resterrors.NewInternalServerError("error when trying to export orders", err)
+		}
+
+		// the pages follow each other with the cursor of the listing, orders placed meanwhile don't shift them
+		for {
+			if err := ctx.Err(); err != nil {
+				return resterrors.NewInternalServerError("order export stopped", err)
+			}
+			page, err := u.orderRepo.GetBySellerID(ctx, sellerID, filter)
+			if err != nil {
+				return err
+			}
+			for _, order := range page.Orders {
+				if err := writeOrderRows(w, order); err != nil {
+					return resterrors.NewInternalServerError("error when trying to export orders", err)
+				}
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+
+		if err := w.Close(); err != nil {
+			return resterrors.NewInternalServerError("error when trying to export orders", err)
+		}
+		return nil
+	}, nil
+}
+
+// writeOrderRows writes a row for each line item of order starting with the columns of the order,
+// an order without items still gets a row
+func writeOrderRows(w entity.SheetWriter, order entity.Order) error {
+	orderCells := []interface{}{
+		order.ID, order.OrderDate, order.Status.String(), order.Buyer.ID, order.DeliveryDestinationAddress,
+		order.VoucherCode, order.Discount, order.ShippingService, order.ShippingFee, order.Tax, order.TaxInclusive,
+		order.TotalPrice,
+	}
+	if len(order.Items) == 0 {
+		return w.WriteRow(append(orderCells, "", "", "", "", "", "")...)
+	}
+
+	for _, od := range order.Items {
+		row := append(append([]interface{}{}, orderCells...),
+			od.Product.ID, od.Product.Name, od.Quantity, od.Price, od.TaxRate.String(), od.Tax)
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,26 +1,53 @@
 package orderusecase
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type orderUsecase struct {
 	orderRepo   entity.OrderRepository
 	productRepo entity.ProductRepository
+	payments    entity.PaymentUseCase
+	vouchers    entity.VoucherUseCase
+	tax         entity.TaxUseCase
+	shipping    entity.ShippingUseCase
+	publisher   entity.EventPublisher
+	cancelHooks []entity.OrderCancelHook
 }
 
-// NewOrderUsecase will create a object with entity.OrderUseCase interface representation
-func NewOrderUsecase(orderRepo entity.OrderRepository, productRepo entity.ProductRepository) entity.OrderUseCase {
+// NewOrderUsecase will create a object with entity.OrderUseCase interface representation,
+// payments captures the payment of an order when it is accepted, vouchers discounts new orders, tax taxes their items,
+// shipping adds their shipping fee
+// and cancelHooks are run in order after an order was cancelled or rejected
+func NewOrderUsecase(orderRepo entity.OrderRepository, productRepo entity.ProductRepository, payments entity.PaymentUseCase,
+	vouchers entity.VoucherUseCase, tax entity.TaxUseCase, shipping entity.ShippingUseCase, publisher entity.EventPublisher,
+	cancelHooks ...entity.OrderCancelHook) entity.OrderUseCase {
 	return &orderUsecase{
 		orderRepo:   orderRepo,
 		productRepo: productRepo,
+		payments:    payments,
+		vouchers:    vouchers,
+		tax:         tax,
+		shipping:    shipping,
+		publisher:   publisher,
+		cancelHooks: cancelHooks,
 	}
 }
 
-func (u *orderUsecase) Store(order *entity.Order) resterrors.RestErr {
+func (u *orderUsecase) Store(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) resterrors.RestErr {
 	tn, err := helpers.GetTimeNow()
 	order.OrderDate = tn
 	if err != nil {
@@ -32,10 +59,14 @@ func (u *orderUsecase) Store(order *entity.Order) resterrors.RestErr {
 	totalQuantity := int64(0)
 	items := []entity.OrderDetail{}
 	for _, od := range order.Items {
-		p, err := u.productRepo.GetByID(&od.Product)
+		p, err := u.productRepo.GetByID(ctx, &od.Product)
 		if err != nil {
 			return err
 		}
+		// the seller of the order picks its vouchers and where it ships from, so every item must be sold by them
+		if p.Seller.ID != order.Seller.ID {
+			return resterrors.NewBadRequestError(fmt.Sprintf("product with id %d is not sold by the seller of the order", p.ID))
+		}
 
 		nOd := entity.OrderDetail{
 			ID: p.ID,
@@ -44,9 +75,12 @@ func (u *orderUsecase) Store(order *entity.Order) resterrors.RestErr {
 				Name:        p.Name,
 				Description: p.Description,
 				Price:       p.Price,
+				Weight:      p.Weight,
+				Category:    p.Category,
 				Seller:      p.Seller,
 			},
 			Quantity: od.Quantity,
+			Price:    p.Price,
 		}
 
 		qD := decimal.NewFromInt(nOd.Quantity)
@@ -58,8 +92,34 @@ func (u *orderUsecase) Store(order *entity.Order) resterrors.RestErr {
 	order.TotalPrice = totalPrice
 	order.TotalQuantity = totalQuantity
 	order.Items = append(items[:0:0], items...)
+	order.VoucherID = 0
+	order.Discount = decimal.Zero
+	order.ShippingFee = decimal.Zero
+	order.Tax = decimal.Zero
+	order.TaxInclusive = false
+
+	// the voucher is only checked here, its uses are counted when the order is saved
+	if order.VoucherCode != "" {
+		if err := u.vouchers.Apply(ctx, order); err != nil {
+			return err
+		}
+	}
+	// items are taxed on what is left of their price after the voucher
+	if err := u.tax.Apply(ctx, order); err != nil {
+		return err
+	}
+	// shipping isn't discounted or taxed, its fee is added last
+	if err := u.shipping.Apply(ctx, order); err != nil {
+		return err
+	}
+	order.StatusHistory = []entity.OrderStatusHistory{{
+		Status:    order.Status,
+		ActorID:   user.ID,
+		ActorType: user.Type,
+		CreatedAt: tn,
+	}}
 
-	repoErr := u.orderRepo.Store(order)
+	repoErr := u.orderRepo.Store(ctx, order)
 	if repoErr != nil {
 		return repoErr
 	}
@@ -67,76 +127,298 @@ func (u *orderUsecase) Store(order *entity.Order) resterrors.RestErr {
 	return nil
 }
 
-func (u *orderUsecase) GetByUserID(userID int64, userType helpers.UserTypeEnum) ([]entity.Order, resterrors.RestErr) {
-	var orders []entity.Order
-	var err resterrors.RestErr
+func (u *orderUsecase) GetByUserID(ctx context.Context, userID int64, userType helpers.UserTypeEnum, filter entity.OrderFilter) (entity.OrderPage, resterrors.RestErr) {
+	if err := checkFilter(&filter); err != nil {
+		return entity.OrderPage{}, err
+	}
 
+	// orders are returned with their line items and products already loaded
 	if userType == helpers.BUYER_TYPE {
-		orders, err = u.orderRepo.GetByBuyerID(userID)
+		return u.orderRepo.GetByBuyerID(ctx, userID, filter)
 	} else if userType == helpers.SELLER_TYPE {
-		orders, err = u.orderRepo.GetBySellerID(userID)
-	}
-
-	var newOrder entity.Order
-	var newOrders []entity.Order
-	var items []entity.OrderDetail
-
-	for _, o := range orders {
-		items = []entity.OrderDetail(nil)
-		newOrder = entity.Order{
-			ID:                         o.ID,
-			Buyer:                      o.Buyer,
-			Seller:                     o.Seller,
-			DeliverySourceAddress:      o.DeliverySourceAddress,
-			DeliveryDestinationAddress: o.DeliveryDestinationAddress,
-			TotalQuantity:              o.TotalQuantity,
-			TotalPrice:                 o.TotalPrice,
-			Status:                     o.Status,
-			OrderDate:                  o.OrderDate,
-			Items:                      items,
+		return u.orderRepo.GetBySellerID(ctx, userID, filter)
+	}
+
+	return entity.OrderPage{}, resterrors.NewBadRequestError("unknown user type")
+}
+
+// checkFilter sets the defaults of filter and checks it before it reaches the repository
+func checkFilter(filter *entity.OrderFilter) resterrors.RestErr {
+	if filter.Sort == "" {
+		filter.Sort = entity.SORT_ORDER_DATE_DESC
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return resterrors.NewBadRequestError("from must be before to")
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && filter.MinTotal.GreaterThan(*filter.MaxTotal) {
+		return resterrors.NewBadRequestError("minTotal must not be greater than maxTotal")
+	}
+	return nil
+}
+
+func (u *orderUsecase) AcceptOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	expectedVersion := order.Version
+	repoRes, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return repoRes, err
+	}
+
+	if user.Type != helpers.SELLER_TYPE || repoRes.Seller.ID != user.ID {
+		return entity.Order{}, resterrors.NewForbiddenError("only the seller of the order can accept it")
+	}
+
+	if vErr := checkVersion(expectedVersion, repoRes); vErr != nil {
+		return repoRes, vErr
+	}
+
+	if repoRes.Status != entity.PENDING {
+		return repoRes, resterrors.NewConflictError("only pending orders can be accepted")
+	}
+
+	// only paid orders can be accepted, capturing a captured payment does nothing so accepting can be retried
+	if payErr := u.payments.Capture(ctx, &repoRes); payErr != nil {
+		return repoRes, payErr
+	}
+
+	updateErr := u.changeStatus(ctx, &repoRes, entity.ACCEPTED, user)
+	if updateErr != nil {
+		return repoRes, updateErr
+	}
+
+	return repoRes, nil
+}
+
+func (u *orderUsecase) CancelOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	// the stored order is read into its own order, order keeps the reason and the version asked for
+	repoRes, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: order.ID})
+	if err != nil {
+		return repoRes, err
+	}
+
+	if user.Type != helpers.BUYER_TYPE || repoRes.Buyer.ID != user.ID {
+		return entity.Order{}, resterrors.NewForbiddenError("only the buyer of the order can cancel it")
+	}
+
+	if vErr := checkVersion(order.Version, repoRes); vErr != nil {
+		return repoRes, vErr
+	}
+
+	return u.cancel(ctx, repoRes, entity.CANCELLED, order.ReasonCode, order.ReasonNote, user)
+}
+
+func (u *orderUsecase) RejectOrder(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	if order.ReasonCode == "" {
+		return entity.Order{}, resterrors.NewBadRequestError("a reason is required to reject an order")
+	}
+
+	// the stored order is read into its own order, order keeps the reason and the version asked for
+	repoRes, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: order.ID})
+	if err != nil {
+		return repoRes, err
+	}
+
+	if user.Type != helpers.SELLER_TYPE || repoRes.Seller.ID != user.ID {
+		return entity.Order{}, resterrors.NewForbiddenError("only the seller of the order can reject it")
+	}
+
+	if vErr := checkVersion(order.Version, repoRes); vErr != nil {
+		return repoRes, vErr
+	}
+
+	return u.cancel(ctx, repoRes, entity.REJECTED, order.ReasonCode, order.ReasonNote, user)
+}
+
+// ExpirePendingOrders cancels up to limit pending orders placed before placedBefore and publishes an
+// ORDER_EXPIRED_EVENT for each of them. An order that fails to expire is logged and left for the next run
+func (u *orderUsecase) ExpirePendingOrders(ctx context.Context, placedBefore time.Time, limit int) ([]entity.Order, resterrors.RestErr) {
+	pending, err := u.orderRepo.GetPendingBefore(ctx, placedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := []entity.Order{}
+	for _, order := range pending {
+		if ctx.Err() != nil {
+			break
 		}
-		for _, od := range o.Items {
-			p, err := u.productRepo.GetByID(&od.Product)
-			if err != nil {
-				return nil, err
-			}
-
-			nOd := entity.OrderDetail{
-				ID: od.ID,
-				Product: entity.Product{
-					ID:          p.ID,
-					Name:        p.Name,
-					Description: p.Description,
-					Price:       p.Price,
-					Seller:      p.Seller,
-				},
-				Quantity: od.Quantity,
-			}
-
-			items = append(items, nOd)
+
+		// expired by the system, so there is no actor
+		res, cancelErr := u.cancel(ctx, order, entity.CANCELLED, entity.REASON_EXPIRED, "", helpers.UserJWTPayload{})
+		if cancelErr != nil {
+			log.Println("order expiry error", order.ID, cancelErr)
+			continue
 		}
+		expired = append(expired, res)
 
-		newOrder.Items = items
-		newOrders = append(newOrders, newOrder)
+		event := entity.Event{
+			Name:       entity.ORDER_EXPIRED_EVENT,
+			OccurredAt: time.Now().UTC(),
+			Payload: entity.OrderEventPayload{
+				OrderID:    res.ID,
+				BuyerID:    res.Buyer.ID,
+				SellerID:   res.Seller.ID,
+				Status:     res.Status,
+				ReasonCode: res.ReasonCode,
+			},
+		}
+		if pubErr := u.publisher.Publish(ctx, event); pubErr != nil {
+			log.Println("order event publish error", order.ID, pubErr)
+		}
 	}
 
+	return expired, nil
+}
+
+func (u *orderUsecase) MarkShipped(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	repoRes, err := u.orderRepo.GetByID(ctx, order)
 	if err != nil {
-		return nil, err
+		return repoRes, err
+	}
+
+	if user.Type != helpers.SELLER_TYPE || repoRes.Seller.ID != user.ID {
+		return entity.Order{}, resterrors.NewForbiddenError("only the seller of the order can ship it")
+	}
+
+	if repoRes.Status != entity.ACCEPTED {
+		return repoRes, resterrors.NewConflictError("only accepted orders can be shipped")
+	}
+
+	if updateErr := u.changeStatus(ctx, &repoRes, entity.SHIPPED, user); updateErr != nil {
+		return repoRes, updateErr
 	}
-	return newOrders, nil
+	return repoRes, nil
 }
 
-func (u *orderUsecase) AcceptOrder(order *entity.Order) (entity.Order, resterrors.RestErr) {
-	repoRes, err := u.orderRepo.GetByID(order)
+// MarkDelivered is run when the courier delivered the order, so there is no actor. An order whose shipment was saved
+// without moving it to SHIPPED is delivered from ACCEPTED
+func (u *orderUsecase) MarkDelivered(ctx context.Context, order *entity.Order) (entity.Order, resterrors.RestErr) {
+	repoRes, err := u.orderRepo.GetByID(ctx, order)
 	if err != nil {
 		return repoRes, err
 	}
 
-	repoRes.Status = entity.ACCEPTED
-	updateErr := u.orderRepo.Update(&repoRes)
-	if updateErr != nil {
+	switch repoRes.Status {
+	case entity.DELIVERED:
+		return repoRes, nil
+	case entity.ACCEPTED, entity.SHIPPED:
+	default:
+		return repoRes, resterrors.NewConflictError("only accepted or shipped orders can be delivered")
+	}
+
+	if updateErr := u.changeStatus(ctx, &repoRes, entity.DELIVERED, helpers.UserJWTPayload{}); updateErr != nil {
 		return repoRes, updateErr
 	}
 
+	event := entity.Event{
+		Name:       entity.ORDER_DELIVERED_EVENT,
+		OccurredAt: time.Now().UTC(),
+		Payload: entity.OrderEventPayload{
+			OrderID:  repoRes.ID,
+			BuyerID:  repoRes.Buyer.ID,
+			SellerID: repoRes.Seller.ID,
+			Status:   repoRes.Status,
+		},
+	}
+	if pubErr := u.publisher.Publish(ctx, event); pubErr != nil {
+		log.Println("order event publish error", repoRes.ID, pubErr)
+	}
 	return repoRes, nil
 }
+
+// cancel moves a pending order to status on behalf of actor and runs the cancel hooks. The order stays cancelled
+// when a hook fails, the failure is logged to be handled by hand
+func (u *orderUsecase) cancel(ctx context.Context, order entity.Order, status entity.OrderStatusEnum,
+	reasonCode entity.OrderReasonCodeEnum, reasonNote string, actor helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	if order.Status != entity.PENDING {
+		return order, resterrors.NewConflictError("only pending orders can be cancelled or rejected")
+	}
+
+	order.ReasonCode = reasonCode
+	order.ReasonNote = reasonNote
+	updateErr := u.changeStatus(ctx, &order, status, actor)
+	if updateErr != nil {
+		return order, updateErr
+	}
+
+	for _, hook := range u.cancelHooks {
+		if hookErr := hook.OrderCancelled(ctx, &order); hookErr != nil {
+			log.Println("order cancel hook error", order.ID, hookErr)
+		}
+	}
+
+	return order, nil
+}
+
+// changeStatus saves order with status and records the change made by actor in its history,
+// actor is the zero value for changes made by the system
+func (u *orderUsecase) changeStatus(ctx context.Context, order *entity.Order, status entity.OrderStatusEnum,
+	actor helpers.UserJWTPayload) resterrors.RestErr {
+	tn, err := helpers.GetTimeNow()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	previousStatus := order.Status
+	change := entity.OrderStatusHistory{
+		PreviousStatus: &previousStatus,
+		Status:         status,
+		ActorID:        actor.ID,
+		ActorType:      actor.Type,
+		Reason:         string(order.ReasonCode),
+		CreatedAt:      tn,
+	}
+
+	order.Status = status
+	if updateErr := u.orderRepo.UpdateStatus(ctx, order, change); updateErr != nil {
+		order.Status = previousStatus
+		return updateErr
+	}
+	return nil
+}
+
+func (u *orderUsecase) GetByID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Order, resterrors.RestErr) {
+	repoRes, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return repoRes, err
+	}
+
+	if !isParticipant(repoRes, user) {
+		return entity.Order{}, resterrors.NewForbiddenError("you are not allowed to access this order")
+	}
+
+	summary, err := u.payments.Summary(ctx, repoRes.ID)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	repoRes.Paid = summary.Paid
+	repoRes.Refunded = summary.Refunded
+
+	return repoRes, nil
+}
+
+// checkVersion fails when the client asked to change a version of order other than the stored one,
+// expectedVersion is 0 when the client didn't ask for a version
+func checkVersion(expectedVersion int64, order entity.Order) resterrors.RestErr {
+	if expectedVersion != 0 && expectedVersion != order.Version {
+		return resterrors.NewPreconditionFailedError(fmt.Sprintf("order with id %d is at version %d", order.ID, order.Version))
+	}
+	return nil
+}
+
+// isParticipant reports whether user is the buyer or the seller of order, admins take part in every order
+func isParticipant(order entity.Order, user helpers.UserJWTPayload) bool {
+	switch user.Type {
+	case helpers.BUYER_TYPE:
+		return order.Buyer.ID == user.ID
+	case helpers.SELLER_TYPE:
+		return order.Seller.ID == user.ID
+	case helpers.ADMIN_TYPE:
+		return true
+	}
+	return false
+}
@@ -1,17 +1,35 @@
 package orderusecase_test
 
 import (
+	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
 	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
 	orderusecase "github.com/hieronimusbudi/komodo-backend/usecases/order_usecase"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// noTax is a tax usecase leaving orders untaxed
+func noTax() *mocks.TaxUseCase {
+	mockTax := new(mocks.TaxUseCase)
+	mockTax.On("Apply", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil)
+	return mockTax
+}
+
+func noShipping() *mocks.ShippingUseCase {
+	mockShipping := new(mocks.ShippingUseCase)
+	mockShipping.On("Apply", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil)
+	return mockShipping
+}
+
 func TestStore(t *testing.T) {
 	mockOrderRepo := new(mocks.OrderRepository)
 	mockProductRepo := new(mocks.ProductRepository)
@@ -65,16 +83,149 @@ func TestStore(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		tmpMockOrder := mockOrder1
-		mockProductRepo.On("GetByID", mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
-		mockOrderRepo.On("Store", mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockOrderRepo.On("Store", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return len(o.StatusHistory) == 1 && o.StatusHistory[0].PreviousStatus == nil &&
+				o.StatusHistory[0].ActorID == mockBuyer1.ID && o.StatusHistory[0].ActorType == helpers.BUYER_TYPE
+		})).Return(nil).Once()
 
-		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo)
-		err := u.Store(&tmpMockOrder)
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), noTax(), noShipping(), new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockOrder1.Status, tmpMockOrder.Status)
+		assert.Equal(t, entity.PENDING, tmpMockOrder.StatusHistory[0].Status)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("with-voucher", func(t *testing.T) {
+		tmpMockOrder := mockOrder1
+		tmpMockOrder.VoucherCode = "hemat10"
+		mockVouchers := new(mocks.VoucherUseCase)
+		mockProductRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockVouchers.On("Apply", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Run(func(args mock.Arguments) {
+			o := args.Get(1).(*entity.Order)
+			o.VoucherID = 3
+			o.Discount = decimal.NewFromInt(10000)
+			o.TotalPrice = o.TotalPrice.Sub(o.Discount)
+		}).Once()
+		mockOrderRepo.On("Store", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.VoucherID == 3 && o.Discount.Equal(decimal.NewFromInt(10000))
+		})).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), mockVouchers, noTax(), noShipping(), new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
+
+		assert.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(1808181.1).Equal(tmpMockOrder.TotalPrice))
+		assert.True(t, decimal.NewFromFloat(1818181.1).Equal(tmpMockOrder.Subtotal()))
+		mockVouchers.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("voucher-rejected", func(t *testing.T) {
+		tmpMockOrder := mockOrder1
+		tmpMockOrder.VoucherCode = "EXPIRED"
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockVouchers := new(mocks.VoucherUseCase)
+		mockProductRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockVouchers.On("Apply", mock.Anything, mock.AnythingOfType("*entity.Order")).
+			Return(resterrors.NewBadRequestError("voucher EXPIRED can't be used now")).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), mockVouchers, new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("items-of-another-seller", func(t *testing.T) {
+		// the voucher of seller 1 can't discount the products of seller 2
+		tmpMockOrder := mockOrder1
+		tmpMockOrder.VoucherCode = "HEMAT10"
+		otherProduct := mockProduct1
+		otherProduct.ID = 2
+		otherProduct.Seller = entity.Seller{ID: 2}
+		tmpMockOrder.Items = []entity.OrderDetail{mockOrderDetail1, {Product: entity.Product{ID: 2}, Quantity: 1}}
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockVouchers := new(mocks.VoucherUseCase)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &mockProduct1).Return(mockProduct1, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 2}).Return(otherProduct, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), mockVouchers, new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockVouchers.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("with-shipping", func(t *testing.T) {
+		tmpMockOrder := mockOrder1
+		tmpMockOrder.VoucherCode = "HEMAT10"
+		tmpMockOrder.ShippingService = "JNE_REG"
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockVouchers := new(mocks.VoucherUseCase)
+		mockTax := new(mocks.TaxUseCase)
+		mockShipping := new(mocks.ShippingUseCase)
+		heavy := mockProduct1
+		heavy.Weight = 300
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(heavy, nil)
+		// the voucher is applied before the shipping fee is added
+		mockVouchers.On("Apply", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.ShippingFee.IsZero()
+		})).Return(nil).Run(func(args mock.Arguments) {
+			o := args.Get(1).(*entity.Order)
+			o.Discount = decimal.NewFromInt(10000)
+			o.TotalPrice = o.TotalPrice.Sub(o.Discount)
+		}).Once()
+		// the items are taxed after the discount, shipping isn't taxed
+		mockTax.On("Apply", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Discount.Equal(decimal.NewFromInt(10000)) && o.ShippingFee.IsZero()
+		})).Return(nil).Run(func(args mock.Arguments) {
+			o := args.Get(1).(*entity.Order)
+			o.Tax = decimal.NewFromInt(1000)
+			o.TotalPrice = o.TotalPrice.Add(o.Tax)
+		}).Once()
+		mockShipping.On("Apply", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Items[0].Product.Weight == 300 && o.Discount.Equal(decimal.NewFromInt(10000)) && o.Tax.Equal(decimal.NewFromInt(1000))
+		})).Return(nil).Run(func(args mock.Arguments) {
+			o := args.Get(1).(*entity.Order)
+			o.ShippingFee = decimal.NewFromInt(40000)
+			o.TotalPrice = o.TotalPrice.Add(o.ShippingFee)
+		}).Once()
+		mockOrderRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), mockVouchers, mockTax, mockShipping, new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
+
+		assert.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(1849181.1).Equal(tmpMockOrder.TotalPrice))
+		assert.True(t, decimal.NewFromFloat(1818181.1).Equal(tmpMockOrder.Subtotal()))
+		mockTax.AssertExpectations(t)
+		mockShipping.AssertExpectations(t)
 		mockOrderRepo.AssertExpectations(t)
 	})
+
+	t.Run("shipping-rejected", func(t *testing.T) {
+		tmpMockOrder := mockOrder1
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockShipping := new(mocks.ShippingUseCase)
+		mockProductRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockShipping.On("Apply", mock.Anything, mock.AnythingOfType("*entity.Order")).
+			Return(resterrors.NewBadRequestError("shipping service is required")).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), noTax(), mockShipping, new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockOrder, helpers.UserJWTPayload{ID: mockBuyer1.ID, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
 }
 
 func TestByUserID(t *testing.T) {
@@ -160,35 +311,83 @@ func TestByUserID(t *testing.T) {
 	}
 
 	t.Run("success get order by buyer", func(t *testing.T) {
-		mockOrdersForBuyer := []entity.Order{mockOrderForBuyer}
-		mockOrderRepo.On("GetByBuyerID", mock.AnythingOfType("int64")).Return(mockOrdersForBuyer, nil).Once()
-		mockProductRepo.On("GetByID", mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockOrdersForBuyer := entity.OrderPage{Orders: []entity.Order{mockOrderForBuyer}, Limit: 20}
+		mockOrderRepo.On("GetByBuyerID", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("entity.OrderFilter")).Return(mockOrdersForBuyer, nil).Once()
 
-		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo)
-		uRes, err := u.GetByUserID(mockBuyer1.ID, helpers.BUYER_TYPE)
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.GetByUserID(context.Background(), mockBuyer1.ID, helpers.BUYER_TYPE, entity.OrderFilter{})
 
 		assert.NoError(t, err)
-		assert.Equal(t, len(uRes), len(mockOrdersForBuyer))
+		assert.Equal(t, len(uRes.Orders), len(mockOrdersForBuyer.Orders))
+		assert.Equal(t, mockProduct1.Name, uRes.Orders[0].Items[0].Product.Name)
 		mockOrderRepo.AssertExpectations(t)
+		mockProductRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
 	})
 
 	t.Run("success get order by seller", func(t *testing.T) {
-		mockOrdersForSeller := []entity.Order{mockOrderForSeller}
-		mockOrderRepo.On("GetBySellerID", mock.AnythingOfType("int64")).Return(mockOrdersForSeller, nil).Once()
-		mockProductRepo.On("GetByID", mock.AnythingOfType("*entity.Product")).Return(mockProduct1, nil)
+		mockOrdersForSeller := entity.OrderPage{Orders: []entity.Order{mockOrderForSeller}, Limit: 20}
+		mockOrderRepo.On("GetBySellerID", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("entity.OrderFilter")).Return(mockOrdersForSeller, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.GetByUserID(context.Background(), mockSeller2.ID, helpers.SELLER_TYPE, entity.OrderFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(uRes.Orders), len(mockOrdersForSeller.Orders))
+		mockOrderRepo.AssertExpectations(t)
+		mockProductRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error unknown user type", func(t *testing.T) {
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.GetByUserID(context.Background(), mockBuyer1.ID, helpers.UserTypeEnum(99), entity.OrderFilter{})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+
+	t.Run("default sort and limit", func(t *testing.T) {
+		expectedFilter := entity.OrderFilter{Sort: entity.SORT_ORDER_DATE_DESC, Limit: 20}
+		mockOrderRepo.On("GetByBuyerID", mock.Anything, mockBuyer1.ID, expectedFilter).Return(entity.OrderPage{}, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.GetByUserID(context.Background(), mockBuyer1.ID, helpers.BUYER_TYPE, entity.OrderFilter{})
+
+		assert.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
 
-		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo)
-		uRes, err := u.GetByUserID(mockSeller2.ID, helpers.SELLER_TYPE)
+	t.Run("limit is capped", func(t *testing.T) {
+		expectedFilter := entity.OrderFilter{Sort: entity.SORT_TOTAL_PRICE_ASC, Limit: 100}
+		mockOrderRepo.On("GetBySellerID", mock.Anything, mockSeller1.ID, expectedFilter).Return(entity.OrderPage{}, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.GetByUserID(context.Background(), mockSeller1.ID, helpers.SELLER_TYPE,
+			entity.OrderFilter{Sort: entity.SORT_TOTAL_PRICE_ASC, Limit: 1000})
 
 		assert.NoError(t, err)
-		assert.Equal(t, len(uRes), len(mockOrdersForSeller))
 		mockOrderRepo.AssertExpectations(t)
 	})
+
+	t.Run("error invalid ranges", func(t *testing.T) {
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+
+		_, err := u.GetByUserID(context.Background(), mockBuyer1.ID, helpers.BUYER_TYPE,
+			entity.OrderFilter{From: time, To: time.AddDate(0, 0, -1)})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+
+		minTotal, maxTotal := decimal.NewFromInt(10), decimal.NewFromInt(5)
+		_, err = u.GetByUserID(context.Background(), mockBuyer1.ID, helpers.BUYER_TYPE,
+			entity.OrderFilter{MinTotal: &minTotal, MaxTotal: &maxTotal})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
 }
 
 func TestAcceptOrder(t *testing.T) {
 	mockOrderRepo := new(mocks.OrderRepository)
 	mockProductRepo := new(mocks.ProductRepository)
+	mockPayments := new(mocks.PaymentUseCase)
 
 	mockBuyer1 := entity.Buyer{
 		ID:             1,
@@ -237,16 +436,580 @@ func TestAcceptOrder(t *testing.T) {
 		},
 	}
 
+	seller := helpers.UserJWTPayload{ID: mockSeller1.ID, Type: helpers.SELLER_TYPE}
+
 	t.Run("success", func(t *testing.T) {
 		tmpMockOrder := mockOrder1
-		mockOrderRepo.On("GetByID", mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
-		mockOrderRepo.On("Update", mock.AnythingOfType("*entity.Order")).Return(nil).Once()
-
-		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo)
-		uRes, err := u.AcceptOrder(&tmpMockOrder)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+		mockPayments.On("Capture", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool { return o.ID == mockOrder1.ID })).
+			Return(nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*entity.Order"),
+			mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+				return *sh.PreviousStatus == entity.PENDING && sh.Status == entity.ACCEPTED &&
+					sh.ActorID == seller.ID && sh.ActorType == helpers.SELLER_TYPE
+			})).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.AcceptOrder(context.Background(), &tmpMockOrder, seller)
 
 		assert.NoError(t, err)
 		assert.Equal(t, uRes.Status, entity.ACCEPTED)
 		mockOrderRepo.AssertExpectations(t)
+		mockPayments.AssertExpectations(t)
+	})
+
+	t.Run("error order is not paid", func(t *testing.T) {
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+		mockPayments.On("Capture", mock.Anything, mock.AnythingOfType("*entity.Order")).
+			Return(resterrors.NewConflictError("order with id 1 is not paid")).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.AcceptOrder(context.Background(), &entity.Order{ID: mockOrder1.ID}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.Equal(t, entity.PENDING, uRes.Status)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+	})
+
+	t.Run("error not the seller of the order", func(t *testing.T) {
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.AcceptOrder(context.Background(), &entity.Order{ID: mockOrder1.ID},
+			helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+	})
+
+	t.Run("error order is not pending", func(t *testing.T) {
+		cancelledOrder := mockOrder1
+		cancelledOrder.Status = entity.CANCELLED
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(cancelledOrder, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.AcceptOrder(context.Background(), &entity.Order{ID: mockOrder1.ID}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+	})
+
+	t.Run("error outdated version", func(t *testing.T) {
+		storedOrder := mockOrder1
+		storedOrder.Version = 3
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(storedOrder, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.AcceptOrder(context.Background(), &entity.Order{ID: mockOrder1.ID, Version: 2}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, err.Status())
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+	})
+
+	t.Run("error changed by another request", func(t *testing.T) {
+		storedOrder := mockOrder1
+		storedOrder.Version = 3
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(storedOrder, nil).Once()
+		mockPayments.On("Capture", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool { return o.Version == 3 }),
+			mock.AnythingOfType("entity.OrderStatusHistory")).
+			Return(resterrors.NewConflictError("order with id 1 was changed by another request")).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.AcceptOrder(context.Background(), &entity.Order{ID: mockOrder1.ID, Version: 3}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.Equal(t, entity.PENDING, uRes.Status)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	mockOrder1 := entity.Order{
+		ID:         1,
+		Buyer:      entity.Buyer{ID: 1},
+		Seller:     entity.Seller{ID: 2},
+		TotalPrice: decimal.NewFromFloat(181818.11),
+		Status:     entity.PENDING,
+	}
+	buyer := helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockCancelHook := new(mocks.OrderCancelHook)
+		// the repository scans the stored order, without a reason, into the given one
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).
+			Run(func(args mock.Arguments) { *args.Get(1).(*entity.Order) = mockOrder1 }).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Status == entity.CANCELLED && o.ReasonCode == entity.REASON_OTHER && o.ReasonNote == "moving abroad"
+		}), mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+			return sh.Reason == string(entity.REASON_OTHER)
+		})).Return(nil).Once()
+		mockCancelHook.On("OrderCancelled", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher), mockCancelHook)
+		request := &entity.Order{ID: 1, ReasonCode: entity.REASON_OTHER, ReasonNote: "moving abroad"}
+		uRes, err := u.CancelOrder(context.Background(), request, buyer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CANCELLED, uRes.Status)
+		assert.Equal(t, entity.REASON_OTHER, uRes.ReasonCode)
+		assert.Equal(t, "moving abroad", uRes.ReasonNote)
+		assert.Equal(t, entity.REASON_OTHER, request.ReasonCode)
+		mockOrderRepo.AssertExpectations(t)
+		mockCancelHook.AssertExpectations(t)
+	})
+
+	t.Run("success when a hook fails", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		failingHook := new(mocks.OrderCancelHook)
+		nextHook := new(mocks.OrderCancelHook)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*entity.Order"), mock.AnythingOfType("entity.OrderStatusHistory")).Return(nil).Once()
+		failingHook.On("OrderCancelled", mock.Anything, mock.AnythingOfType("*entity.Order")).
+			Return(resterrors.NewInternalServerError("error when trying to restore", nil)).Once()
+		nextHook.On("OrderCancelled", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher), failingHook, nextHook)
+		uRes, err := u.CancelOrder(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CANCELLED, uRes.Status)
+		nextHook.AssertExpectations(t)
+	})
+
+	t.Run("error not the buyer of the order", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.CancelOrder(context.Background(), &entity.Order{ID: 1}, helpers.UserJWTPayload{ID: 3, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error order already accepted", func(t *testing.T) {
+		acceptedOrder := mockOrder1
+		acceptedOrder.Status = entity.ACCEPTED
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockCancelHook := new(mocks.OrderCancelHook)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(acceptedOrder, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher), mockCancelHook)
+		_, err := u.CancelOrder(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		mockCancelHook.AssertNotCalled(t, "OrderCancelled", mock.Anything, mock.Anything)
+	})
+	t.Run("error outdated version", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.CancelOrder(context.Background(), &entity.Order{ID: 1, Version: 5}, buyer)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRejectOrder(t *testing.T) {
+	mockOrder1 := entity.Order{
+		ID:         1,
+		Buyer:      entity.Buyer{ID: 1},
+		Seller:     entity.Seller{ID: 2},
+		TotalPrice: decimal.NewFromFloat(181818.11),
+		Status:     entity.PENDING,
+	}
+	seller := helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockCancelHook := new(mocks.OrderCancelHook)
+		// the repository scans the stored order, without a reason, into the given one
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).
+			Run(func(args mock.Arguments) { *args.Get(1).(*entity.Order) = mockOrder1 }).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Status == entity.REJECTED && o.ReasonCode == entity.REASON_OTHER && o.ReasonNote == "shop is closed"
+		}), mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+			return sh.Reason == string(entity.REASON_OTHER)
+		})).Return(nil).Once()
+		mockCancelHook.On("OrderCancelled", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher), mockCancelHook)
+		uRes, err := u.RejectOrder(context.Background(),
+			&entity.Order{ID: 1, ReasonCode: entity.REASON_OTHER, ReasonNote: "shop is closed"}, seller)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.REJECTED, uRes.Status)
+		assert.Equal(t, "shop is closed", uRes.ReasonNote)
+		mockOrderRepo.AssertExpectations(t)
+		mockCancelHook.AssertExpectations(t)
+	})
+
+	t.Run("error reason is required", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.RejectOrder(context.Background(), &entity.Order{ID: 1}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockOrderRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error not the seller of the order", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.RejectOrder(context.Background(), &entity.Order{ID: 1, ReasonCode: entity.REASON_OUT_OF_STOCK},
+			helpers.UserJWTPayload{ID: 1, Type: helpers.SELLER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestExpirePendingOrders(t *testing.T) {
+	placedBefore := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	pending := []entity.Order{
+		{ID: 1, Buyer: entity.Buyer{ID: 1}, Seller: entity.Seller{ID: 2}, Status: entity.PENDING},
+		{ID: 2, Buyer: entity.Buyer{ID: 3}, Seller: entity.Seller{ID: 2}, Status: entity.PENDING},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockCancelHook := new(mocks.OrderCancelHook)
+		mockOrderRepo.On("GetPendingBefore", mock.Anything, placedBefore, 100).Return(pending, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Status == entity.CANCELLED && o.ReasonCode == entity.REASON_EXPIRED
+		}), mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+			return sh.ActorID == 0 && sh.Reason == string(entity.REASON_EXPIRED)
+		})).Return(nil).Twice()
+		mockCancelHook.On("OrderCancelled", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(nil).Twice()
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+			return e.Name == entity.ORDER_EXPIRED_EVENT
+		})).Return(nil).Twice()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), mockPublisher, mockCancelHook)
+		uRes, err := u.ExpirePendingOrders(context.Background(), placedBefore, 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, uRes, 2)
+		assert.Equal(t, entity.CANCELLED, uRes[0].Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+		mockCancelHook.AssertExpectations(t)
+	})
+
+	t.Run("success when one order fails to expire", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockOrderRepo.On("GetPendingBefore", mock.Anything, placedBefore, 100).Return(pending, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool { return o.ID == 1 }),
+			mock.AnythingOfType("entity.OrderStatusHistory")).
+			Return(resterrors.NewInternalServerError("error when trying to update data", nil)).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool { return o.ID == 2 }),
+			mock.AnythingOfType("entity.OrderStatusHistory")).
+			Return(nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("entity.Event")).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), mockPublisher)
+		uRes, err := u.ExpirePendingOrders(context.Background(), placedBefore, 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, uRes, 1)
+		assert.Equal(t, int64(2), uRes[0].ID)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockOrderRepo.On("GetPendingBefore", mock.Anything, placedBefore, 100).
+			Return(nil, resterrors.NewInternalServerError("error when trying to get data", nil)).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), mockPublisher)
+		_, err := u.ExpirePendingOrders(context.Background(), placedBefore, 100)
+
+		assert.Error(t, err)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMarkShipped(t *testing.T) {
+	accepted := entity.Order{ID: 1, Buyer: entity.Buyer{ID: 1}, Seller: entity.Seller{ID: 2}, Status: entity.ACCEPTED}
+	seller := helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(accepted, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Status == entity.SHIPPED
+		}), mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+			return *sh.PreviousStatus == entity.ACCEPTED && sh.ActorID == 2
+		})).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		uRes, err := u.MarkShipped(context.Background(), &entity.Order{ID: 1}, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.SHIPPED, uRes.Status)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-not-accepted", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		pending := accepted
+		pending.Status = entity.PENDING
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(pending, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.MarkShipped(context.Background(), &entity.Order{ID: 1}, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+
+	t.Run("error-not-the-seller", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(accepted, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.MarkShipped(context.Background(), &entity.Order{ID: 1}, helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestMarkDelivered(t *testing.T) {
+	shipped := entity.Order{ID: 1, Buyer: entity.Buyer{ID: 1}, Seller: entity.Seller{ID: 2}, Status: entity.SHIPPED}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(shipped, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entity.Order) bool {
+			return o.Status == entity.DELIVERED
+		}), mock.MatchedBy(func(sh entity.OrderStatusHistory) bool {
+			return sh.ActorID == 0
+		})).Return(nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+			return e.Name == entity.ORDER_DELIVERED_EVENT
+		})).Return(nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), mockPublisher)
+		uRes, err := u.MarkDelivered(context.Background(), &entity.Order{ID: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.DELIVERED, uRes.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("success-already-delivered", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		delivered := shipped
+		delivered.Status = entity.DELIVERED
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(delivered, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), mockPublisher)
+		_, err := u.MarkDelivered(context.Background(), &entity.Order{ID: 1})
+
+		assert.Nil(t, err)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-cancelled", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		cancelled := shipped
+		cancelled.Status = entity.CANCELLED
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(cancelled, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.MarkDelivered(context.Background(), &entity.Order{ID: 1})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+
+	mockOrder1 := entity.Order{
+		ID:                         1,
+		Buyer:                      entity.Buyer{ID: 1, Name: "buyer", Email: "buyer1@mail.com"},
+		Seller:                     entity.Seller{ID: 2, Name: "seller", Email: "seller2@mail.com"},
+		DeliverySourceAddress:      "pickup address",
+		DeliveryDestinationAddress: "sending address",
+		TotalQuantity:              10,
+		TotalPrice:                 decimal.NewFromFloat(181818.11),
+		Status:                     entity.PENDING,
+	}
+
+	allowed := map[string]helpers.UserJWTPayload{
+		"buyer of the order":  {ID: 1, Type: helpers.BUYER_TYPE},
+		"seller of the order": {ID: 2, Type: helpers.SELLER_TYPE},
+		"admin":               {ID: 99, Type: helpers.ADMIN_TYPE},
+	}
+	for name, user := range allowed {
+		t.Run("success "+name, func(t *testing.T) {
+			mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+			mockPayments := new(mocks.PaymentUseCase)
+			mockPayments.On("Summary", mock.Anything, mockOrder1.ID).Return(entity.RefundSummary{
+				Paid:     mockOrder1.TotalPrice,
+				Refunded: decimal.NewFromInt(1000),
+			}, nil).Once()
+
+			u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, mockPayments, new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+			uRes, err := u.GetByID(context.Background(), &entity.Order{ID: mockOrder1.ID}, user)
+
+			assert.NoError(t, err)
+			assert.Equal(t, mockOrder1.ID, uRes.ID)
+			assert.True(t, mockOrder1.TotalPrice.Equal(uRes.Paid))
+			assert.Equal(t, "1000", uRes.Refunded.String())
+			mockOrderRepo.AssertExpectations(t)
+			mockPayments.AssertExpectations(t)
+		})
+	}
+
+	forbidden := map[string]helpers.UserJWTPayload{
+		"another buyer":                   {ID: 3, Type: helpers.BUYER_TYPE},
+		"another seller":                  {ID: 1, Type: helpers.SELLER_TYPE},
+		"buyer with the id of the seller": {ID: 2, Type: helpers.BUYER_TYPE},
+		"unknown user type":               {ID: 1, Type: helpers.UserTypeEnum(99)},
+	}
+	for name, user := range forbidden {
+		t.Run("error "+name, func(t *testing.T) {
+			mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder1, nil).Once()
+
+			u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+			uRes, err := u.GetByID(context.Background(), &entity.Order{ID: mockOrder1.ID}, user)
+
+			assert.Error(t, err)
+			assert.Equal(t, http.StatusForbidden, err.Status())
+			assert.Empty(t, uRes.Items)
+			assert.Zero(t, uRes.ID)
+		})
+	}
+
+	t.Run("error not found", func(t *testing.T) {
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).
+			Return(entity.Order{}, resterrors.NewNotFoundError("order with id 1 not found")).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, mockProductRepo, new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		_, err := u.GetByID(context.Background(), &entity.Order{ID: mockOrder1.ID}, allowed["admin"])
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+	})
+}
+
+func TestExportBySellerID(t *testing.T) {
+	orderDate := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	item := func(id int64, name string) entity.OrderDetail {
+		return entity.OrderDetail{ID: id, Product: entity.Product{ID: id, Name: name}, Quantity: 2,
+			Price: decimal.RequireFromString("50000.5"), TaxRate: decimal.RequireFromString("0.11"), Tax: decimal.NewFromInt(11000)}
+	}
+	order1 := entity.Order{ID: 1, Buyer: entity.Buyer{ID: 3}, Seller: entity.Seller{ID: 2}, Status: entity.ACCEPTED,
+		OrderDate: orderDate, TotalPrice: decimal.RequireFromString("222001.1"), Discount: decimal.Zero,
+		ShippingFee: decimal.Zero, Tax: decimal.NewFromInt(22000),
+		Items: []entity.OrderDetail{item(1, "Kopi Toraja"), item(2, "Kopi Gayo")}}
+	order2 := entity.Order{ID: 2, Buyer: entity.Buyer{ID: 3}, Seller: entity.Seller{ID: 2}, Status: entity.DELIVERED,
+		OrderDate: orderDate, TotalPrice: decimal.NewFromInt(111000), Items: []entity.OrderDetail{item(3, "Teh")}}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+		firstPage := entity.OrderFilter{From: from, Sort: entity.SORT_ORDER_DATE_DESC, Limit: 100}
+		mockOrderRepo.On("GetBySellerID", mock.Anything, int64(2), firstPage).
+			Return(entity.OrderPage{Orders: []entity.Order{order1}, Limit: 100, NextCursor: "next"}, nil).Once()
+		secondPage := firstPage
+		secondPage.Cursor = "next"
+		mockOrderRepo.On("GetBySellerID", mock.Anything, int64(2), secondPage).
+			Return(entity.OrderPage{Orders: []entity.Order{order2}, Limit: 100}, nil).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		export, err := u.ExportBySellerID(context.Background(), 2, entity.OrderFilter{From: from, Limit: 5, Cursor: "ignored"})
+		assert.Nil(t, err)
+
+		w := new(testhelpers.SheetRows)
+		assert.Nil(t, export(w))
+
+		// the header and a row for each line item
+		assert.Len(t, w.Rows, 4)
+		assert.True(t, w.Closed)
+		assert.Equal(t, "Order ID", w.Rows[0][0])
+		assert.Equal(t, []interface{}{int64(1), orderDate, "ACCEPTED", int64(3), "", "", decimal.Zero, "", decimal.Zero,
+			decimal.NewFromInt(22000), false, decimal.RequireFromString("222001.1"), int64(2), "Kopi Gayo", int64(2),
+			decimal.RequireFromString("50000.5"), "0.11", decimal.NewFromInt(11000)}, w.Rows[2])
+		assert.Equal(t, "DELIVERED", w.Rows[3][2])
+		assert.Equal(t, len(w.Rows[0]), len(w.Rows[3]))
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("error stops once the context is done", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetBySellerID", mock.Anything, int64(2), mock.AnythingOfType("entity.OrderFilter")).
+			Return(entity.OrderPage{Orders: []entity.Order{order1}, Limit: 100, NextCursor: "next"}, nil).Once()
+
+		// the client went away while the first page was written
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w := &testhelpers.SheetRows{AfterWrite: func(rows int) {
+			if rows == 3 {
+				cancel()
+			}
+		}}
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		export, err := u.ExportBySellerID(ctx, 2, entity.OrderFilter{})
+		assert.Nil(t, err)
+
+		assert.Error(t, export(w))
+		assert.False(t, w.Closed)
+		mockOrderRepo.AssertNumberOfCalls(t, "GetBySellerID", 1)
+	})
+
+	t.Run("error invalid ranges", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		export, err := u.ExportBySellerID(context.Background(), 2, entity.OrderFilter{From: orderDate, To: orderDate})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		assert.Nil(t, export)
+		mockOrderRepo.AssertNotCalled(t, "GetBySellerID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error get page", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetBySellerID", mock.Anything, int64(2), mock.AnythingOfType("entity.OrderFilter")).
+			Return(entity.OrderPage{}, resterrors.NewInternalServerError("error when trying to get data", nil)).Once()
+
+		u := orderusecase.NewOrderUsecase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.PaymentUseCase), new(mocks.VoucherUseCase), new(mocks.TaxUseCase), new(mocks.ShippingUseCase), new(mocks.EventPublisher))
+		export, err := u.ExportBySellerID(context.Background(), 2, entity.OrderFilter{})
+		assert.Nil(t, err)
+
+		w := new(testhelpers.SheetRows)
+		rErr := export(w)
+		assert.Error(t, rErr)
+		assert.False(t, w.Closed)
 	})
 }
@@ -0,0 +1,549 @@
+package paymentusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+// paymentTransitions are the status changes a gateway can report for a payment, reports of other changes
+// arrive out of order and are ignored
+var paymentTransitions = map[entity.PaymentStatusEnum][]entity.PaymentStatusEnum{
+	entity.PAYMENT_PENDING:    {entity.PAYMENT_AUTHORIZED, entity.PAYMENT_CAPTURED, entity.PAYMENT_FAILED, entity.PAYMENT_CANCELLED},
+	entity.PAYMENT_AUTHORIZED: {entity.PAYMENT_CAPTURED, entity.PAYMENT_CANCELLED},
+	entity.PAYMENT_CAPTURED:   {entity.PAYMENT_REFUNDED},
+}
+
+// orderRefundLock is the lock held while an order is refunded
+const orderRefundLock = "order_refund_%d"
+
+type paymentUsecase struct {
+	paymentRepo entity.PaymentRepository
+	eventRepo   entity.PaymentWebhookEventRepository
+	refundRepo  entity.RefundRepository
+	orderRepo   entity.OrderRepository
+	gateway     entity.PaymentGateway
+	locker      entity.Locker
+	currency    string
+}
+
+// NewPaymentUsecase will create a object with entity.PaymentUseCase interface representation,
+// payments are made through gateway in currency and locker keeps refunds of an order from running together
+func NewPaymentUsecase(paymentRepo entity.PaymentRepository, eventRepo entity.PaymentWebhookEventRepository,
+	refundRepo entity.RefundRepository, orderRepo entity.OrderRepository, gateway entity.PaymentGateway,
+	locker entity.Locker, currency string) entity.PaymentUseCase {
+	return &paymentUsecase{
+		paymentRepo: paymentRepo,
+		eventRepo:   eventRepo,
+		refundRepo:  refundRepo,
+		orderRepo:   orderRepo,
+		gateway:     gateway,
+		locker:      locker,
+		currency:    currency,
+	}
+}
+
+func (u *paymentUsecase) Create(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Payment, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	if user.Type != helpers.BUYER_TYPE || repoOrder.Buyer.ID != user.ID {
+		return entity.Payment{}, resterrors.NewForbiddenError("only the buyer of the order can pay it")
+	}
+
+	if repoOrder.Status != entity.PENDING {
+		return entity.Payment{}, resterrors.NewConflictError("only pending orders can be paid")
+	}
+
+	// a failed or cancelled payment can be tried again with a new one
+	existing, err := u.paymentRepo.GetByOrderID(ctx, repoOrder.ID)
+	if err == nil && existing.Status.Active() {
+		return entity.Payment{}, resterrors.NewConflictError(fmt.Sprintf("order with id %d already has a payment", repoOrder.ID))
+	}
+	if err != nil && !errors.Is(err, resterrors.ErrNotFound) {
+		return entity.Payment{}, err
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.Payment{}, resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+
+	payment := entity.Payment{
+		OrderID:   repoOrder.ID,
+		Provider:  u.gateway.Name(),
+		Amount:    repoOrder.TotalPrice,
+		Currency:  u.currency,
+		CreatedAt: tn,
+		UpdatedAt: tn,
+	}
+	intent, err := u.gateway.CreateIntent(ctx, &payment)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+	payment.ProviderPaymentID = intent.ProviderPaymentID
+	payment.ClientSecret = intent.ClientSecret
+	payment.Status = intent.Status
+
+	if err := u.paymentRepo.Store(ctx, &payment); err != nil {
+		return entity.Payment{}, err
+	}
+	return payment, nil
+}
+
+func (u *paymentUsecase) GetByOrderID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Payment, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	isBuyer := user.Type == helpers.BUYER_TYPE && repoOrder.Buyer.ID == user.ID
+	isSeller := user.Type == helpers.SELLER_TYPE && repoOrder.Seller.ID == user.ID
+	if !isBuyer && !isSeller && user.Type != helpers.ADMIN_TYPE {
+		return entity.Payment{}, resterrors.NewForbiddenError("you are not allowed to access this order")
+	}
+
+	payment, err := u.paymentRepo.GetByOrderID(ctx, repoOrder.ID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	// the client secret lets its holder pay, only the buyer gets it
+	if !isBuyer {
+		payment.ClientSecret = ""
+	}
+	return payment, nil
+}
+
+func (u *paymentUsecase) Capture(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	payment, err := u.paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return resterrors.NewConflictError(fmt.Sprintf("order with id %d is not paid", order.ID))
+		}
+		return err
+	}
+
+	switch payment.Status {
+	case entity.PAYMENT_CAPTURED:
+		return nil
+	case entity.PAYMENT_AUTHORIZED:
+		if err := u.gateway.Capture(ctx, &payment); err != nil {
+			return err
+		}
+		return u.changeStatus(ctx, &payment, entity.PAYMENT_CAPTURED)
+	}
+	return resterrors.NewConflictError(fmt.Sprintf("order with id %d is not paid", order.ID))
+}
+
+func (u *paymentUsecase) OrderCancelled(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	payment, err := u.paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	switch payment.Status {
+	case entity.PAYMENT_PENDING:
+		// nothing was paid yet, a payment made after this is refunded when the gateway calls us back
+		return u.changeStatus(ctx, &payment, entity.PAYMENT_CANCELLED)
+	case entity.PAYMENT_AUTHORIZED:
+		if _, err := u.gateway.Refund(ctx, &payment, payment.Amount); err != nil {
+			return err
+		}
+		return u.changeStatus(ctx, &payment, entity.PAYMENT_CANCELLED)
+	case entity.PAYMENT_CAPTURED:
+		return u.refundLeft(ctx, &payment, "order cancelled")
+	}
+	return nil
+}
+
+func (u *paymentUsecase) Refund(ctx context.Context, order *entity.Order, request entity.Refund, user helpers.UserJWTPayload) (entity.Refund, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return entity.Refund{}, err
+	}
+
+	isSeller := user.Type == helpers.SELLER_TYPE && repoOrder.Seller.ID == user.ID
+	if !isSeller && user.Type != helpers.ADMIN_TYPE {
+		return entity.Refund{}, resterrors.NewForbiddenError("only the seller of the order or an admin can refund it")
+	}
+
+	unlock, err := u.lockRefunds(ctx, repoOrder.ID)
+	if err != nil {
+		return entity.Refund{}, err
+	}
+	defer unlock()
+
+	payment, err := u.paymentRepo.GetByOrderID(ctx, repoOrder.ID)
+	if err != nil && !errors.Is(err, resterrors.ErrNotFound) {
+		return entity.Refund{}, err
+	}
+	if err != nil || payment.Status != entity.PAYMENT_CAPTURED {
+		if err == nil && payment.Status == entity.PAYMENT_REFUNDED {
+			return entity.Refund{}, resterrors.NewConflictError(fmt.Sprintf("order with id %d is fully refunded", repoOrder.ID))
+		}
+		return entity.Refund{}, resterrors.NewConflictError(fmt.Sprintf("order with id %d is not paid", repoOrder.ID))
+	}
+
+	refunds, err := u.refundRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return entity.Refund{}, err
+	}
+
+	refund := entity.Refund{
+		OrderID:   repoOrder.ID,
+		PaymentID: payment.ID,
+		Amount:    payment.Amount.Sub(refundedAmount(refunds)),
+		Reason:    request.Reason,
+		ActorID:   user.ID,
+		ActorType: user.Type,
+	}
+	if len(request.Items) > 0 {
+		refund.Items, refund.Amount, err = refundItems(repoOrder, refunds, request.Items)
+		if err != nil {
+			return entity.Refund{}, err
+		}
+	}
+
+	if err := u.refund(ctx, &payment, refunds, &refund); err != nil {
+		return entity.Refund{}, err
+	}
+	return refund, nil
+}
+
+func (u *paymentUsecase) GetRefunds(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.RefundSummary, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return entity.RefundSummary{}, err
+	}
+
+	isBuyer := user.Type == helpers.BUYER_TYPE && repoOrder.Buyer.ID == user.ID
+	isSeller := user.Type == helpers.SELLER_TYPE && repoOrder.Seller.ID == user.ID
+	if !isBuyer && !isSeller && user.Type != helpers.ADMIN_TYPE {
+		return entity.RefundSummary{}, resterrors.NewForbiddenError("you are not allowed to access this order")
+	}
+
+	return u.Summary(ctx, repoOrder.ID)
+}
+
+func (u *paymentUsecase) Summary(ctx context.Context, orderID int64) (entity.RefundSummary, resterrors.RestErr) {
+	summary := entity.RefundSummary{Refunds: []entity.Refund{}}
+	payment, err := u.paymentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return summary, nil
+		}
+		return summary, err
+	}
+
+	// only a captured payment took the money of the buyer
+	if payment.Status != entity.PAYMENT_CAPTURED && payment.Status != entity.PAYMENT_REFUNDED {
+		return summary, nil
+	}
+
+	refunds, err := u.refundRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return summary, err
+	}
+	summary.Paid = payment.Amount
+	summary.Refunded = refundedAmount(refunds)
+	summary.Refunds = refunds
+	return summary, nil
+}
+
+func (u *paymentUsecase) HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	if provider != u.gateway.Name() {
+		return entity.PaymentWebhookEvent{}, resterrors.NewNotFoundError(fmt.Sprintf("unknown payment provider %s", provider))
+	}
+
+	event, err := u.gateway.ParseWebhook(ctx, header, body)
+	if err != nil {
+		return entity.PaymentWebhookEvent{}, err
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.PaymentWebhookEvent{}, resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	event.Provider = provider
+	event.ReceivedAt = tn
+
+	// gateways send an event again until it is handled, the stored one is handled again when the last attempt failed
+	existing, ok, err := u.eventRepo.Store(ctx, &event)
+	if err != nil {
+		return entity.PaymentWebhookEvent{}, err
+	}
+	if !ok {
+		if existing.ProcessedAt != nil {
+			return existing, nil
+		}
+		event = existing
+	}
+
+	return u.process(ctx, event)
+}
+
+func (u *paymentUsecase) ReplayWebhookEvent(ctx context.Context, eventID int64) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	event, err := u.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+
+	if event.Provider != u.gateway.Name() {
+		return event, resterrors.NewConflictError(fmt.Sprintf("payment provider %s is not configured", event.Provider))
+	}
+	return u.process(ctx, event)
+}
+
+// process applies event and saves the result, the event is marked processed when it was applied
+func (u *paymentUsecase) process(ctx context.Context, event entity.PaymentWebhookEvent) (entity.PaymentWebhookEvent, resterrors.RestErr) {
+	applyErr := u.apply(ctx, event)
+	if applyErr != nil {
+		event.ProcessedAt = nil
+		event.Error = applyErr.Message()
+	} else {
+		tn, err := helpers.GetTimeNow()
+		if err != nil {
+			return event, resterrors.NewInternalServerError("error when trying to update data", err)
+		}
+		event.ProcessedAt = &tn
+		event.Error = ""
+	}
+
+	if err := u.eventRepo.UpdateResult(ctx, &event); err != nil {
+		log.Println("payment webhook event result error", event.ID, err)
+		if applyErr == nil {
+			return event, err
+		}
+	}
+	return event, applyErr
+}
+
+// apply changes the payment of event to the status reported by the gateway. A payment made for an order
+// that was cancelled meanwhile is given back to the buyer. An authorization or capture of another amount or currency
+// than the payment is refused, the event keeps the error until an admin looks into it
+func (u *paymentUsecase) apply(ctx context.Context, event entity.PaymentWebhookEvent) resterrors.RestErr {
+	payment, err := u.paymentRepo.GetByProviderPaymentID(ctx, event.Provider, event.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	if (event.Status == entity.PAYMENT_AUTHORIZED || event.Status == entity.PAYMENT_CAPTURED) &&
+		(!event.Amount.Equal(payment.Amount) || !strings.EqualFold(event.Currency, payment.Currency)) {
+		return resterrors.NewConflictError(fmt.Sprintf("payment %s of %s reported %s %s instead of %s %s",
+			event.ProviderPaymentID, event.Provider, event.Amount.StringFixed(2), event.Currency,
+			payment.Amount.StringFixed(2), payment.Currency))
+	}
+
+	order, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: payment.OrderID})
+	if err != nil {
+		return err
+	}
+
+	orderCancelled := order.Status == entity.CANCELLED || order.Status == entity.REJECTED
+	if orderCancelled && event.Status == entity.PAYMENT_CAPTURED {
+		return u.refundLatePayment(ctx, &payment)
+	}
+	if orderCancelled && event.Status == entity.PAYMENT_AUTHORIZED &&
+		(payment.Status == entity.PAYMENT_PENDING || payment.Status == entity.PAYMENT_CANCELLED) {
+		// the payment is released, nothing was taken from the buyer
+		authorized := payment
+		authorized.Status = entity.PAYMENT_AUTHORIZED
+		if _, err := u.gateway.Refund(ctx, &authorized, payment.Amount); err != nil {
+			return err
+		}
+		if payment.Status == entity.PAYMENT_CANCELLED {
+			return nil
+		}
+		return u.changeStatus(ctx, &payment, entity.PAYMENT_CANCELLED)
+	}
+
+	if payment.Status == event.Status {
+		return nil
+	}
+	for _, to := range paymentTransitions[payment.Status] {
+		if to == event.Status {
+			return u.changeStatus(ctx, &payment, to)
+		}
+	}
+	log.Println("payment webhook event ignored", event.ID, payment.ID, payment.Status, event.Status)
+	return nil
+}
+
+// refundLatePayment gives back a payment captured after its order was cancelled. The payment is saved as captured
+// first, so a refund that failed is made again when the gateway sends the event again
+func (u *paymentUsecase) refundLatePayment(ctx context.Context, payment *entity.Payment) resterrors.RestErr {
+	switch payment.Status {
+	case entity.PAYMENT_PENDING, entity.PAYMENT_AUTHORIZED, entity.PAYMENT_CANCELLED:
+		if err := u.changeStatus(ctx, payment, entity.PAYMENT_CAPTURED); err != nil {
+			return err
+		}
+	case entity.PAYMENT_CAPTURED:
+	default:
+		return nil
+	}
+	return u.refundLeft(ctx, payment, "order cancelled")
+}
+
+// lockRefunds makes refunds of the order with orderID one at a time, so refunds made together can't give back
+// more than was paid. unlock releases the lock
+func (u *paymentUsecase) lockRefunds(ctx context.Context, orderID int64) (func(), resterrors.RestErr) {
+	unlock, ok, err := u.locker.TryLock(ctx, fmt.Sprintf(orderRefundLock, orderID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, resterrors.NewConflictError(fmt.Sprintf("a refund of order with id %d is in progress", orderID))
+	}
+	return unlock, nil
+}
+
+// refundLeft refunds what is left of a captured payment without a user asking for it, reason says why
+func (u *paymentUsecase) refundLeft(ctx context.Context, payment *entity.Payment, reason string) resterrors.RestErr {
+	unlock, err := u.lockRefunds(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	refunds, err := u.refundRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return err
+	}
+
+	refund := entity.Refund{
+		OrderID:   payment.OrderID,
+		PaymentID: payment.ID,
+		Amount:    payment.Amount.Sub(refundedAmount(refunds)),
+		Reason:    reason,
+	}
+	return u.refund(ctx, payment, refunds, &refund)
+}
+
+// refund gives back refund.Amount of a captured payment at the gateway and saves it, refunds are the earlier
+// refunds of payment. The payment is refunded once nothing is left of it
+func (u *paymentUsecase) refund(ctx context.Context, payment *entity.Payment, refunds []entity.Refund, refund *entity.Refund) resterrors.RestErr {
+	left := payment.Amount.Sub(refundedAmount(refunds))
+	if !left.IsPositive() {
+		return resterrors.NewConflictError(fmt.Sprintf("order with id %d is fully refunded", payment.OrderID))
+	}
+	if refund.Amount.GreaterThan(left) {
+		return resterrors.NewConflictError(fmt.Sprintf("refund of %s is more than the %s left to refund of order with id %d",
+			refund.Amount.StringFixed(2), left.StringFixed(2), payment.OrderID))
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+
+	gRefund, err := u.gateway.Refund(ctx, payment, refund.Amount)
+	if err != nil {
+		return err
+	}
+	refund.ProviderRefundID = gRefund.ProviderRefundID
+	refund.CreatedAt = tn
+	if err := u.refundRepo.Store(ctx, refund); err != nil {
+		// the money was given back already, the refund has to be recorded by hand
+		log.Println("refund not saved", payment.ID, gRefund.ProviderRefundID, refund.Amount, err)
+		return err
+	}
+
+	if refund.Amount.Equal(left) {
+		return u.changeStatus(ctx, payment, entity.PAYMENT_REFUNDED)
+	}
+	return nil
+}
+
+// refundItems prices the requested items of order less their share of the discount and with the tax added to them,
+// a line item can't be refunded more times than it was ordered
+func refundItems(order entity.Order, refunds []entity.Refund, requested []entity.RefundItem) ([]entity.RefundItem, decimal.Decimal, resterrors.RestErr) {
+	refundedQuantity := map[int64]int64{}
+	for _, r := range refunds {
+		for _, ri := range r.Items {
+			refundedQuantity[ri.OrderDetailID] += ri.Quantity
+		}
+	}
+
+	details := map[int64]entity.OrderDetail{}
+	for _, od := range order.Items {
+		details[od.ID] = od
+	}
+
+	items := []entity.RefundItem{}
+	amount := decimal.Zero
+	for _, ri := range requested {
+		od, ok := details[ri.OrderDetailID]
+		if !ok {
+			return nil, amount, resterrors.NewBadRequestError(fmt.Sprintf("order item with id %d is not in order with id %d", ri.OrderDetailID, order.ID))
+		}
+
+		refunded := refundedQuantity[od.ID]
+		left := od.Quantity - refunded
+		if ri.Quantity > left {
+			return nil, amount, resterrors.NewConflictError(fmt.Sprintf("only %d of order item with id %d can be refunded", left, od.ID))
+		}
+		refundedQuantity[od.ID] += ri.Quantity
+
+		// the items take their share of the discount of the line with them
+		riAmount := od.Price.Mul(decimal.NewFromInt(ri.Quantity)).Sub(lineShare(od.Discount, od.Quantity, refunded, ri.Quantity))
+		if !order.TaxInclusive {
+			// the tax added to the line is given back with the items it was charged on
+			riAmount = riAmount.Add(lineShare(od.Tax, od.Quantity, refunded, ri.Quantity))
+		}
+		items = append(items, entity.RefundItem{OrderDetailID: od.ID, Quantity: ri.Quantity, Amount: riAmount})
+		amount = amount.Add(riAmount)
+	}
+	return items, amount, nil
+}
+
+// lineShare is the part of amount, spread over the quantity items of a line, that goes with the next count items
+// after the refunded ones. It is the difference of rounded running totals so the shares of all the items add up to amount
+func lineShare(amount decimal.Decimal, quantity, refunded, count int64) decimal.Decimal {
+	if quantity <= 0 {
+		return decimal.Zero
+	}
+	q := decimal.NewFromInt(quantity)
+	upTo := amount.Mul(decimal.NewFromInt(refunded + count)).Div(q).Round(2)
+	before := amount.Mul(decimal.NewFromInt(refunded)).Div(q).Round(2)
+	return upTo.Sub(before)
+}
+
+// refundedAmount is the sum of refunds
+func refundedAmount(refunds []entity.Refund) decimal.Decimal {
+	amount := decimal.Zero
+	for _, r := range refunds {
+		amount = amount.Add(r.Amount)
+	}
+	return amount
+}
+
+// changeStatus saves payment with status when it wasn't changed since it was read
+func (u *paymentUsecase) changeStatus(ctx context.Context, payment *entity.Payment, status entity.PaymentStatusEnum) resterrors.RestErr {
+	tn, err := helpers.GetTimeNow()
+	if err != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", err)
+	}
+
+	from := payment.Status
+	payment.Status = status
+	payment.UpdatedAt = tn
+	if updateErr := u.paymentRepo.UpdateStatus(ctx, payment, from); updateErr != nil {
+		payment.Status = from
+		return updateErr
+	}
+	return nil
+}
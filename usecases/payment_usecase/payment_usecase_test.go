@@ -0,0 +1,751 @@
+package paymentusecase_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/hieronimusbudi/komodo-backend/framework/payments"
+	paymentusecase "github.com/hieronimusbudi/komodo-backend/usecases/payment_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	mockOrder = entity.Order{
+		ID:         1,
+		Buyer:      entity.Buyer{ID: 1},
+		Seller:     entity.Seller{ID: 2},
+		TotalPrice: decimal.NewFromFloat(181818.11),
+		Status:     entity.PENDING,
+	}
+	buyer  = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{}, resterrors.NewNotFoundError("payment of order with id 1 not found")).Once()
+		mockPaymentRepo.On("Store", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.OrderID == mockOrder.ID && p.Provider == payments.FakeGatewayName && p.Amount.Equal(mockOrder.TotalPrice) &&
+				p.Currency == "IDR" && p.Status == entity.PAYMENT_PENDING && p.ProviderPaymentID != ""
+		})).Return(nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		payment, err := u.Create(context.Background(), &entity.Order{ID: mockOrder.ID}, buyer)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, payment.ClientSecret)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("success after a failed payment", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{ID: 1, Status: entity.PAYMENT_FAILED}, nil).Once()
+		mockPaymentRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Payment")).Return(nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(true, "whsec"), new(mocks.Locker), "IDR")
+		payment, err := u.Create(context.Background(), &entity.Order{ID: mockOrder.ID}, buyer)
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.PAYMENT_AUTHORIZED, payment.Status)
+	})
+
+	t.Run("error not the buyer of the order", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(true, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.Create(context.Background(), &entity.Order{ID: mockOrder.ID},
+			helpers.UserJWTPayload{ID: 3, Type: helpers.BUYER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+
+	t.Run("error order already has a payment", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{ID: 1, Status: entity.PAYMENT_AUTHORIZED}, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(true, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.Create(context.Background(), &entity.Order{ID: mockOrder.ID}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockPaymentRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByOrderID(t *testing.T) {
+	t.Run("success client secret only for the buyer", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{ID: 1, ClientSecret: "secret"}, nil)
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(true, "whsec"), new(mocks.Locker), "IDR")
+		buyerRes, err := u.GetByOrderID(context.Background(), &entity.Order{ID: mockOrder.ID}, buyer)
+		assert.Nil(t, err)
+		sellerRes, err := u.GetByOrderID(context.Background(), &entity.Order{ID: mockOrder.ID}, seller)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "secret", buyerRes.ClientSecret)
+		assert.Empty(t, sellerRes.ClientSecret)
+	})
+
+	t.Run("error not a participant", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(true, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.GetByOrderID(context.Background(), &entity.Order{ID: mockOrder.ID},
+			helpers.UserJWTPayload{ID: 3, Type: helpers.SELLER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestCapture(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		authorized := entity.Payment{ID: 1, OrderID: mockOrder.ID, ProviderPaymentID: "pi_1", Status: entity.PAYMENT_AUTHORIZED}
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockGateway := new(mocks.PaymentGateway)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return(authorized, nil).Once()
+		mockGateway.On("Capture", mock.Anything, mock.AnythingOfType("*entity.Payment")).Return(nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_CAPTURED
+		}), entity.PAYMENT_AUTHORIZED).Return(nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), new(mocks.OrderRepository), mockGateway, new(mocks.Locker), "IDR")
+		err := u.Capture(context.Background(), &mockOrder)
+
+		assert.Nil(t, err)
+		mockGateway.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("success already captured", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockGateway := new(mocks.PaymentGateway)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{ID: 1, Status: entity.PAYMENT_CAPTURED}, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), new(mocks.OrderRepository), mockGateway, new(mocks.Locker), "IDR")
+		err := u.Capture(context.Background(), &mockOrder)
+
+		assert.Nil(t, err)
+		mockGateway.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error not paid", func(t *testing.T) {
+		for _, payment := range []struct {
+			res entity.Payment
+			err resterrors.RestErr
+		}{
+			{entity.Payment{}, resterrors.NewNotFoundError("payment of order with id 1 not found")},
+			{entity.Payment{ID: 1, Status: entity.PAYMENT_PENDING}, nil},
+			{entity.Payment{ID: 1, Status: entity.PAYMENT_FAILED}, nil},
+		} {
+			mockPaymentRepo := new(mocks.PaymentRepository)
+			mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return(payment.res, payment.err).Once()
+
+			u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), new(mocks.OrderRepository), new(mocks.PaymentGateway), new(mocks.Locker), "IDR")
+			err := u.Capture(context.Background(), &mockOrder)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusConflict, err.Status())
+		}
+	})
+}
+
+func TestOrderCancelled(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status entity.PaymentStatusEnum
+		refund bool
+		to     entity.PaymentStatusEnum
+	}{
+		{"success pending is cancelled", entity.PAYMENT_PENDING, false, entity.PAYMENT_CANCELLED},
+		{"success authorized is released", entity.PAYMENT_AUTHORIZED, true, entity.PAYMENT_CANCELLED},
+		{"success captured is refunded", entity.PAYMENT_CAPTURED, true, entity.PAYMENT_REFUNDED},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			payment := entity.Payment{ID: 1, OrderID: mockOrder.ID, Amount: mockOrder.TotalPrice, Status: tc.status}
+			mockPaymentRepo := new(mocks.PaymentRepository)
+			mockGateway := new(mocks.PaymentGateway)
+			mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return(payment, nil).Once()
+			if tc.refund {
+				mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), mockOrder.TotalPrice).
+					Return(entity.PaymentRefund{}, nil).Once()
+			}
+			mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+				return p.Status == tc.to
+			}), tc.status).Return(nil).Once()
+			mockRefundRepo := new(mocks.RefundRepository)
+			mockLocker := new(mocks.Locker)
+			if tc.status == entity.PAYMENT_CAPTURED {
+				mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+				mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(1)).Return([]entity.Refund{}, nil).Once()
+				mockRefundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *entity.Refund) bool {
+					return r.Amount.Equal(mockOrder.TotalPrice) && r.Reason == "order cancelled" && r.ActorID == 0
+				})).Return(nil).Once()
+			}
+
+			u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), mockRefundRepo, new(mocks.OrderRepository), mockGateway, mockLocker, "IDR")
+			err := u.OrderCancelled(context.Background(), &mockOrder)
+
+			assert.Nil(t, err)
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+			mockRefundRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("success no payment", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).
+			Return(entity.Payment{}, resterrors.NewNotFoundError("payment of order with id 1 not found")).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), new(mocks.OrderRepository), new(mocks.PaymentGateway), new(mocks.Locker), "IDR")
+		err := u.OrderCancelled(context.Background(), &mockOrder)
+
+		assert.Nil(t, err)
+	})
+}
+
+// signedWebhook returns a callback of the fake gateway reporting status for the payment providerPaymentID
+func signedWebhook(eventID string, providerPaymentID string, status string) (http.Header, []byte) {
+	body := []byte(`{"id":"` + eventID + `","type":"payment_intent.` + status + `","data":{"id":"` + providerPaymentID +
+		`","status":"` + status + `","amount":"181818.11","currency":"IDR"}}`)
+	header := http.Header{}
+	header.Set(payments.HeaderWebhookSignature, payments.SignWebhook("whsec", time.Now(), body))
+	return header, body
+}
+
+func TestHandleWebhook(t *testing.T) {
+	pending := entity.Payment{ID: 1, OrderID: mockOrder.ID, Provider: payments.FakeGatewayName, ProviderPaymentID: "fake_pi_1",
+		Amount: mockOrder.TotalPrice, Currency: "IDR", Status: entity.PAYMENT_PENDING}
+
+	t.Run("success", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockEventRepo.On("Store", mock.Anything, mock.MatchedBy(func(e *entity.PaymentWebhookEvent) bool {
+			return e.Provider == payments.FakeGatewayName && e.ProviderEventID == "evt_1" && len(e.Payload) > 0
+		})).Return(entity.PaymentWebhookEvent{}, true, nil).Once()
+		mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_1").Return(pending, nil).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, &entity.Order{ID: mockOrder.ID}).Return(mockOrder, nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_AUTHORIZED
+		}), entity.PAYMENT_PENDING).Return(nil).Once()
+		mockEventRepo.On("UpdateResult", mock.Anything, mock.MatchedBy(func(e *entity.PaymentWebhookEvent) bool {
+			return e.ProcessedAt != nil && e.Error == ""
+		})).Return(nil).Once()
+
+		header, body := signedWebhook("evt_1", "fake_pi_1", "requires_capture")
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		event, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, header, body)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, event.ProcessedAt)
+		mockPaymentRepo.AssertExpectations(t)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("success duplicate is ignored", func(t *testing.T) {
+		processedAt := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		mockEventRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).
+			Return(entity.PaymentWebhookEvent{ID: 3, ProviderEventID: "evt_1", ProcessedAt: &processedAt}, false, nil).Once()
+
+		header, body := signedWebhook("evt_1", "fake_pi_1", "requires_capture")
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), new(mocks.OrderRepository), payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		event, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, header, body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), event.ID)
+		mockPaymentRepo.AssertNotCalled(t, "GetByProviderPaymentID", mock.Anything, mock.Anything, mock.Anything)
+		mockEventRepo.AssertNotCalled(t, "UpdateResult", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success paid after the order was cancelled is refunded", func(t *testing.T) {
+		cancelledOrder := mockOrder
+		cancelledOrder.Status = entity.CANCELLED
+		cancelledPayment := pending
+		cancelledPayment.Status = entity.PAYMENT_CANCELLED
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockGateway := new(mocks.PaymentGateway)
+		mockGateway.On("Name").Return(payments.FakeGatewayName)
+		mockGateway.On("ParseWebhook", mock.Anything, mock.Anything, mock.Anything).Return(entity.PaymentWebhookEvent{
+			ProviderEventID: "evt_2", ProviderPaymentID: "fake_pi_1", Status: entity.PAYMENT_CAPTURED, Amount: mockOrder.TotalPrice,
+			Currency: "IDR"}, nil).Once()
+		mockEventRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).
+			Return(entity.PaymentWebhookEvent{}, true, nil).Once()
+		mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_1").Return(cancelledPayment, nil).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, &entity.Order{ID: mockOrder.ID}).Return(cancelledOrder, nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_CAPTURED
+		}), entity.PAYMENT_CANCELLED).Return(nil).Once()
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(1)).Return([]entity.Refund{}, nil).Once()
+		mockGateway.On("Refund", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_CAPTURED
+		}), mockOrder.TotalPrice).Return(entity.PaymentRefund{ProviderRefundID: "fake_re_1"}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *entity.Refund) bool {
+			return r.ProviderRefundID == "fake_re_1" && r.Amount.Equal(mockOrder.TotalPrice)
+		})).Return(nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_REFUNDED
+		}), entity.PAYMENT_CAPTURED).Return(nil).Once()
+		mockEventRepo.On("UpdateResult", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).Return(nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, mockRefundRepo, mockOrderRepo, mockGateway, mockLocker, "IDR")
+		_, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, http.Header{}, []byte(`{}`))
+
+		assert.Nil(t, err)
+		mockGateway.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("success out of order event is ignored", func(t *testing.T) {
+		captured := pending
+		captured.Status = entity.PAYMENT_CAPTURED
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockEventRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).
+			Return(entity.PaymentWebhookEvent{}, true, nil).Once()
+		mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_1").Return(captured, nil).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, &entity.Order{ID: mockOrder.ID}).Return(mockOrder, nil).Once()
+		mockEventRepo.On("UpdateResult", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).Return(nil).Once()
+
+		header, body := signedWebhook("evt_1", "fake_pi_1", "requires_capture")
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, header, body)
+
+		assert.Nil(t, err)
+		mockPaymentRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error unknown payment is recorded", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		mockEventRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).
+			Return(entity.PaymentWebhookEvent{}, true, nil).Once()
+		mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_9").
+			Return(entity.Payment{}, resterrors.NewNotFoundError("payment fake_pi_9 of fake not found")).Once()
+		mockEventRepo.On("UpdateResult", mock.Anything, mock.MatchedBy(func(e *entity.PaymentWebhookEvent) bool {
+			return e.ProcessedAt == nil && e.Error == "payment fake_pi_9 of fake not found"
+		})).Return(nil).Once()
+
+		header, body := signedWebhook("evt_1", "fake_pi_9", "requires_capture")
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), new(mocks.OrderRepository), payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, header, body)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("error amount or currency mismatch is recorded", func(t *testing.T) {
+		for _, reported := range []entity.PaymentWebhookEvent{
+			{Amount: decimal.NewFromInt(1000), Currency: "IDR"},
+			{Amount: mockOrder.TotalPrice, Currency: "USD"},
+		} {
+			mockPaymentRepo := new(mocks.PaymentRepository)
+			mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+			mockGateway := new(mocks.PaymentGateway)
+			mockGateway.On("Name").Return(payments.FakeGatewayName)
+			mockGateway.On("ParseWebhook", mock.Anything, mock.Anything, mock.Anything).Return(entity.PaymentWebhookEvent{
+				ProviderEventID: "evt_2", ProviderPaymentID: "fake_pi_1", Status: entity.PAYMENT_CAPTURED, Amount: reported.Amount,
+				Currency: reported.Currency}, nil).Once()
+			mockEventRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.PaymentWebhookEvent")).
+				Return(entity.PaymentWebhookEvent{}, true, nil).Once()
+			mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_1").Return(pending, nil).Once()
+			mockEventRepo.On("UpdateResult", mock.Anything, mock.MatchedBy(func(e *entity.PaymentWebhookEvent) bool {
+				return e.ProcessedAt == nil && e.Error != ""
+			})).Return(nil).Once()
+
+			u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), new(mocks.OrderRepository), mockGateway, new(mocks.Locker), "IDR")
+			_, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, http.Header{}, []byte(`{}`))
+
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusConflict, err.Status())
+			mockEventRepo.AssertExpectations(t)
+			mockPaymentRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("error invalid signature", func(t *testing.T) {
+		mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+		_, body := signedWebhook("evt_1", "fake_pi_1", "requires_capture")
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), mockEventRepo, new(mocks.RefundRepository), new(mocks.OrderRepository), payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.HandleWebhook(context.Background(), payments.FakeGatewayName, http.Header{}, body)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		mockEventRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error unknown provider", func(t *testing.T) {
+		header, body := signedWebhook("evt_1", "fake_pi_1", "requires_capture")
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository), new(mocks.OrderRepository), payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+		_, err := u.HandleWebhook(context.Background(), "acme", header, body)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+	})
+}
+
+func TestReplayWebhookEvent(t *testing.T) {
+	stored := entity.PaymentWebhookEvent{ID: 3, Provider: payments.FakeGatewayName, ProviderEventID: "evt_1",
+		ProviderPaymentID: "fake_pi_1", Status: entity.PAYMENT_AUTHORIZED, Amount: mockOrder.TotalPrice, Currency: "IDR", Error: "timeout"}
+	mockPaymentRepo := new(mocks.PaymentRepository)
+	mockEventRepo := new(mocks.PaymentWebhookEventRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockEventRepo.On("GetByID", mock.Anything, int64(3)).Return(stored, nil).Once()
+	mockPaymentRepo.On("GetByProviderPaymentID", mock.Anything, payments.FakeGatewayName, "fake_pi_1").
+		Return(entity.Payment{ID: 1, OrderID: mockOrder.ID, Amount: mockOrder.TotalPrice, Currency: "IDR", Status: entity.PAYMENT_PENDING}, nil).Once()
+	mockOrderRepo.On("GetByID", mock.Anything, &entity.Order{ID: mockOrder.ID}).Return(mockOrder, nil).Once()
+	mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*entity.Payment"), entity.PAYMENT_PENDING).Return(nil).Once()
+	mockEventRepo.On("UpdateResult", mock.Anything, mock.MatchedBy(func(e *entity.PaymentWebhookEvent) bool {
+		return e.ID == 3 && e.ProcessedAt != nil && e.Error == ""
+	})).Return(nil).Once()
+
+	u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, mockEventRepo, new(mocks.RefundRepository), mockOrderRepo, payments.NewFakeGateway(false, "whsec"), new(mocks.Locker), "IDR")
+	event, err := u.ReplayWebhookEvent(context.Background(), 3)
+
+	assert.Nil(t, err)
+	assert.Empty(t, event.Error)
+	mockPaymentRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestRefund(t *testing.T) {
+	acceptedOrder := entity.Order{
+		ID:         1,
+		Buyer:      entity.Buyer{ID: 1},
+		Seller:     entity.Seller{ID: 2},
+		TotalPrice: decimal.RequireFromString("181818.10"),
+		Status:     entity.ACCEPTED,
+		Items: []entity.OrderDetail{
+			{ID: 11, Quantity: 2, Price: decimal.RequireFromString("50000.50")},
+			{ID: 12, Quantity: 1, Price: decimal.RequireFromString("81817.10")},
+		},
+	}
+	captured := entity.Payment{ID: 7, OrderID: 1, ProviderPaymentID: "fake_pi_1", Amount: acceptedOrder.TotalPrice,
+		Status: entity.PAYMENT_CAPTURED}
+	earlier := []entity.Refund{{ID: 1, PaymentID: 7, Amount: decimal.RequireFromString("50000.50"),
+		Items: []entity.RefundItem{{OrderDetailID: 11, Quantity: 1, Amount: decimal.RequireFromString("50000.50")}}}}
+
+	// newUsecase returns a usecase refunding the captured payment of acceptedOrder after the earlier refunds
+	newUsecase := func(payment entity.Payment, refunds []entity.Refund) (entity.PaymentUseCase, *mocks.PaymentRepository, *mocks.RefundRepository, *mocks.PaymentGateway) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockGateway := new(mocks.PaymentGateway)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(acceptedOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(payment, nil).Once()
+		mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(7)).Return(refunds, nil).Once()
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), mockRefundRepo,
+			mockOrderRepo, mockGateway, mockLocker, "IDR")
+		return u, mockPaymentRepo, mockRefundRepo, mockGateway
+	}
+
+	t.Run("success items", func(t *testing.T) {
+		u, mockPaymentRepo, mockRefundRepo, mockGateway := newUsecase(captured, earlier)
+		mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), decimal.RequireFromString("131817.60")).
+			Return(entity.PaymentRefund{ProviderRefundID: "fake_re_2"}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Refund")).Return(nil).Once()
+		// nothing is left of the payment after the refund
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_REFUNDED
+		}), entity.PAYMENT_CAPTURED).Return(nil).Once()
+
+		refund, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Reason: "damaged",
+			Items:  []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}, {OrderDetailID: 12, Quantity: 1}},
+		}, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "131817.6", refund.Amount.String())
+		assert.Equal(t, "fake_re_2", refund.ProviderRefundID)
+		assert.Equal(t, seller.ID, refund.ActorID)
+		assert.Len(t, refund.Items, 2)
+		assert.Equal(t, "81817.1", refund.Items[1].Amount.String())
+		mockGateway.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("success full refund of what is left", func(t *testing.T) {
+		admin := helpers.UserJWTPayload{ID: 99, Type: helpers.ADMIN_TYPE}
+		u, mockPaymentRepo, mockRefundRepo, mockGateway := newUsecase(captured, earlier)
+		mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), decimal.RequireFromString("131817.60")).
+			Return(entity.PaymentRefund{ProviderRefundID: "fake_re_2"}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *entity.Refund) bool {
+			return len(r.Items) == 0 && r.ActorType == helpers.ADMIN_TYPE
+		})).Return(nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+			return p.Status == entity.PAYMENT_REFUNDED
+		}), entity.PAYMENT_CAPTURED).Return(nil).Once()
+
+		refund, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{}, admin)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "131817.6", refund.Amount.String())
+		mockPaymentRepo.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("success partial refund keeps the payment captured", func(t *testing.T) {
+		u, mockPaymentRepo, mockRefundRepo, mockGateway := newUsecase(captured, []entity.Refund{})
+		mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), decimal.RequireFromString("50000.50")).
+			Return(entity.PaymentRefund{ProviderRefundID: "fake_re_1"}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Refund")).Return(nil).Once()
+
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Items: []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}},
+		}, seller)
+
+		assert.Nil(t, err)
+		mockPaymentRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("success items with their tax", func(t *testing.T) {
+		taxedOrder := acceptedOrder
+		taxedOrder.Tax = decimal.RequireFromString("11000.11")
+		taxedOrder.TotalPrice = decimal.RequireFromString("192818.21")
+		taxedOrder.Items = []entity.OrderDetail{
+			{ID: 11, Quantity: 2, Price: decimal.RequireFromString("50000.50"), Tax: decimal.RequireFromString("11000.11")},
+		}
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockGateway := new(mocks.PaymentGateway)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(taxedOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).
+			Return(entity.Payment{ID: 7, OrderID: 1, Amount: taxedOrder.TotalPrice, Status: entity.PAYMENT_CAPTURED}, nil).Once()
+		mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(7)).Return([]entity.Refund{}, nil).Once()
+		// half of the tax of the line goes back with one of its two items
+		mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), decimal.RequireFromString("55500.56")).
+			Return(entity.PaymentRefund{ProviderRefundID: "fake_re_1"}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Refund")).Return(nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), mockRefundRepo,
+			mockOrderRepo, mockGateway, mockLocker, "IDR")
+		refund, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Items: []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}},
+		}, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "55500.56", refund.Items[0].Amount.String())
+		mockGateway.AssertExpectations(t)
+	})
+
+	t.Run("success voucher order refunded item by item", func(t *testing.T) {
+		// 3 x 10000 and 1 x 20000 with a 10000 discount split 3333.33 and 6666.67, taxed 11% after the discount
+		voucherOrder := acceptedOrder
+		voucherOrder.Discount = decimal.RequireFromString("10000.00")
+		voucherOrder.Tax = decimal.RequireFromString("4400.00")
+		voucherOrder.TotalPrice = decimal.RequireFromString("44400.00")
+		voucherOrder.Items = []entity.OrderDetail{
+			{ID: 11, Quantity: 3, Price: decimal.RequireFromString("10000.00"), Discount: decimal.RequireFromString("3333.33"),
+				Tax: decimal.RequireFromString("2933.33")},
+			{ID: 12, Quantity: 1, Price: decimal.RequireFromString("20000.00"), Discount: decimal.RequireFromString("6666.67"),
+				Tax: decimal.RequireFromString("1466.67")},
+		}
+		payment := entity.Payment{ID: 7, OrderID: 1, Amount: voucherOrder.TotalPrice, Status: entity.PAYMENT_CAPTURED}
+
+		// the rounding of the shares of the items of a line adds up to the line, the last refund gives back what was paid
+		steps := []struct {
+			item   int64
+			amount string
+		}{{11, "9866.67"}, {11, "9866.66"}, {11, "9866.67"}, {12, "14800.00"}}
+		refunds := []entity.Refund{}
+		for i, step := range steps {
+			mockPaymentRepo := new(mocks.PaymentRepository)
+			mockRefundRepo := new(mocks.RefundRepository)
+			mockOrderRepo := new(mocks.OrderRepository)
+			mockGateway := new(mocks.PaymentGateway)
+			mockLocker := new(mocks.Locker)
+			mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(voucherOrder, nil).Once()
+			mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+			mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(payment, nil).Once()
+			mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(7)).Return(refunds, nil).Once()
+			amount := decimal.RequireFromString(step.amount)
+			mockGateway.On("Refund", mock.Anything, mock.AnythingOfType("*entity.Payment"), mock.MatchedBy(amount.Equal)).
+				Return(entity.PaymentRefund{ProviderRefundID: fmt.Sprintf("fake_re_%d", i+1)}, nil).Once()
+			mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Refund")).Return(nil).Once()
+			if i == len(steps)-1 {
+				mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *entity.Payment) bool {
+					return p.Status == entity.PAYMENT_REFUNDED
+				}), entity.PAYMENT_CAPTURED).Return(nil).Once()
+			}
+
+			u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), mockRefundRepo,
+				mockOrderRepo, mockGateway, mockLocker, "IDR")
+			refund, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+				Items: []entity.RefundItem{{OrderDetailID: step.item, Quantity: 1}},
+			}, seller)
+
+			assert.Nil(t, err)
+			assert.True(t, amount.Equal(refund.Amount), refund.Amount.String())
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+			refunds = append(refunds, refund)
+		}
+	})
+
+	t.Run("error more than ordered", func(t *testing.T) {
+		u, _, mockRefundRepo, mockGateway := newUsecase(captured, earlier)
+
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Items: []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}, {OrderDetailID: 11, Quantity: 1}},
+		}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.Equal(t, "only 0 of order item with id 11 can be refunded", err.Message())
+		mockGateway.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error more than paid", func(t *testing.T) {
+		fullRefund := []entity.Refund{{ID: 1, PaymentID: 7, Amount: decimal.RequireFromString("181800")}}
+		u, _, _, mockGateway := newUsecase(captured, fullRefund)
+
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Items: []entity.RefundItem{{OrderDetailID: 12, Quantity: 1}},
+		}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.Equal(t, "refund of 81817.10 is more than the 18.10 left to refund of order with id 1", err.Message())
+		mockGateway.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error item not in the order", func(t *testing.T) {
+		u, _, _, _ := newUsecase(captured, earlier)
+
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{
+			Items: []entity.RefundItem{{OrderDetailID: 99, Quantity: 1}},
+		}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+
+	t.Run("error not captured", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(acceptedOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(func() {}, true, nil).Once()
+		authorized := captured
+		authorized.Status = entity.PAYMENT_AUTHORIZED
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(authorized, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository),
+			mockOrderRepo, new(mocks.PaymentGateway), mockLocker, "IDR")
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		assert.Equal(t, "order with id 1 is not paid", err.Message())
+	})
+
+	t.Run("error refund in progress", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(acceptedOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_refund_1").Return(nil, false, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), new(mocks.PaymentWebhookEventRepository),
+			new(mocks.RefundRepository), mockOrderRepo, new(mocks.PaymentGateway), mockLocker, "IDR")
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+
+	t.Run("error buyer", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(acceptedOrder, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(new(mocks.PaymentRepository), new(mocks.PaymentWebhookEventRepository),
+			new(mocks.RefundRepository), mockOrderRepo, new(mocks.PaymentGateway), new(mocks.Locker), "IDR")
+		_, err := u.Refund(context.Background(), &entity.Order{ID: 1}, entity.Refund{}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestSummary(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).
+			Return(entity.Payment{ID: 7, Amount: decimal.RequireFromString("181818.10"), Status: entity.PAYMENT_CAPTURED}, nil).Once()
+		mockRefundRepo.On("GetByPaymentID", mock.Anything, int64(7)).Return([]entity.Refund{
+			{ID: 1, Amount: decimal.RequireFromString("50000.50")},
+			{ID: 2, Amount: decimal.RequireFromString("0.60")},
+		}, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), mockRefundRepo,
+			new(mocks.OrderRepository), new(mocks.PaymentGateway), new(mocks.Locker), "IDR")
+		summary, err := u.Summary(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "181818.1", summary.Paid.String())
+		assert.Equal(t, "50001.1", summary.Refunded.String())
+		assert.Equal(t, "131817", summary.Net().String())
+		assert.Len(t, summary.Refunds, 2)
+	})
+
+	t.Run("success not captured", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockPaymentRepo.On("GetByOrderID", mock.Anything, int64(1)).
+			Return(entity.Payment{ID: 7, Amount: decimal.RequireFromString("181818.10"), Status: entity.PAYMENT_AUTHORIZED}, nil).Once()
+
+		u := paymentusecase.NewPaymentUsecase(mockPaymentRepo, new(mocks.PaymentWebhookEventRepository), new(mocks.RefundRepository),
+			new(mocks.OrderRepository), new(mocks.PaymentGateway), new(mocks.Locker), "IDR")
+		summary, err := u.Summary(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.True(t, summary.Paid.IsZero())
+		assert.Empty(t, summary.Refunds)
+	})
+}
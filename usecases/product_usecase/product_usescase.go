@@ -1,34 +1,141 @@
 package productusecase
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
 )
 
 type productUsecase struct {
 	productRepo entity.ProductRepository
+	publisher   entity.EventPublisher
+	priceHooks  []entity.ProductPriceHook
 }
 
-// NewProductUsecase will create a object with entity.ProductUseCase interface representation
-func NewProductUsecase(productRepo entity.ProductRepository) entity.ProductUseCase {
+// NewProductUsecase will create a object with entity.ProductUseCase interface representation,
+// publisher tells whatever keeps the stock about released items and priceHooks are run in order
+// after the price of a product was lowered
+func NewProductUsecase(productRepo entity.ProductRepository, publisher entity.EventPublisher,
+	priceHooks ...entity.ProductPriceHook) entity.ProductUseCase {
 	return &productUsecase{
 		productRepo: productRepo,
+		publisher:   publisher,
+		priceHooks:  priceHooks,
 	}
 }
 
-func (p *productUsecase) Store(product *entity.Product) resterrors.RestErr {
-	repoErr := p.productRepo.Store(product)
+func (p *productUsecase) Store(ctx context.Context, product *entity.Product) resterrors.RestErr {
+	repoErr := p.productRepo.Store(ctx, product)
 	if repoErr != nil {
 		return repoErr
 	}
 	return nil
 }
 
-func (p *productUsecase) GetAll() ([]entity.Product, resterrors.RestErr) {
-	products, err := p.productRepo.GetAll()
+func (p *productUsecase) GetAll(ctx context.Context) ([]entity.Product, resterrors.RestErr) {
+	products, err := p.productRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	return products, nil
 }
+
+func (p *productUsecase) Update(ctx context.Context, product *entity.Product, sellerID int64) resterrors.RestErr {
+	// the repository refuses products of other sellers and keeps the SKU, it is kept for imports
+	product.Seller = entity.Seller{ID: sellerID}
+	oldPrice, err := p.productRepo.Update(ctx, product)
+	if err != nil {
+		return err
+	}
+
+	if product.Price.LessThan(oldPrice) {
+		p.priceDropped(ctx, *product, oldPrice)
+	}
+	return nil
+}
+
+// priceDropped runs the price hooks, the product is saved when a hook fails so the failure is logged to be handled by hand
+func (p *productUsecase) priceDropped(ctx context.Context, product entity.Product, oldPrice decimal.Decimal) {
+	for _, hook := range p.priceHooks {
+		if hookErr := hook.PriceDropped(ctx, product, oldPrice); hookErr != nil {
+			log.Println("product price hook error", product.ID, hookErr)
+		}
+	}
+}
+
+func (p *productUsecase) Import(ctx context.Context, sellerID int64, products []entity.Product, dryRun bool) (entity.ProductImport, resterrors.RestErr) {
+	// a SKU picks the product a row updates, so it can only be given once
+	rows := make(map[string]bool, len(products))
+	for _, product := range products {
+		if product.SKU == "" {
+			return entity.ProductImport{}, resterrors.NewBadRequestError("every imported product needs a sku")
+		}
+		if rows[product.SKU] {
+			return entity.ProductImport{}, resterrors.NewBadRequestError(fmt.Sprintf("sku %s is imported more than once", product.SKU))
+		}
+		rows[product.SKU] = true
+	}
+
+	previous, err := p.productRepo.UpsertBySKU(ctx, sellerID, products, dryRun)
+	if err != nil {
+		return entity.ProductImport{}, err
+	}
+
+	// the hooks are only run once the import is committed
+	if !dryRun {
+		for _, product := range products {
+			if oldPrice, ok := previous[product.ID]; ok && product.Price.LessThan(oldPrice) {
+				p.priceDropped(ctx, product, oldPrice)
+			}
+		}
+	}
+	return entity.ProductImport{DryRun: dryRun, Created: len(products) - len(previous), Updated: len(previous)}, nil
+}
+
+func (p *productUsecase) ExportBySellerID(ctx context.Context, sellerID int64, w entity.SheetWriter) resterrors.RestErr {
+	products, err := p.productRepo.GetBySellerID(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(entity.ProductColumns))
+	for i, column := range entity.ProductColumns {
+		header[i] = column
+	}
+	if err := w.WriteRow(header...); err != nil {
+		return resterrors.NewInternalServerError("error when trying to export products", err)
+	}
+	for _, product := range products {
+		// a product is found by its SKU when it is imported again
+		if product.SKU == "" {
+			continue
+		}
+		if err := w.WriteRow(product.SKU, product.Name, product.Description, product.Price, product.Weight, product.Category); err != nil {
+			return resterrors.NewInternalServerError("error when trying to export products", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to export products", err)
+	}
+	return nil
+}
+
+func (p *productUsecase) OrderCancelled(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	payload := entity.StockEventPayload{
+		OrderID:  order.ID,
+		SellerID: order.Seller.ID,
+		Status:   order.Status,
+		Items:    []entity.StockEventItemPayload{},
+	}
+	for _, od := range order.Items {
+		payload.Items = append(payload.Items, entity.StockEventItemPayload{ProductID: od.Product.ID, Quantity: od.Quantity})
+	}
+
+	return p.publisher.Publish(ctx, entity.Event{Name: entity.STOCK_RELEASED_EVENT, OccurredAt: time.Now().UTC(), Payload: payload})
+}
@@ -1,10 +1,14 @@
 package productusecase_test
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
 	productusecase "github.com/hieronimusbudi/komodo-backend/usecases/product_usecase"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -31,10 +35,10 @@ func TestStore(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		tmpMockProduct := mockProduct
-		mockProductRepo.On("Store", mock.AnythingOfType("*entity.Product")).Return(nil).Once()
+		mockProductRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(nil).Once()
 
-		u := productusecase.NewProductUsecase(mockProductRepo)
-		err := u.Store(&tmpMockProduct)
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		err := u.Store(context.Background(), &tmpMockProduct)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockProduct.Name, tmpMockProduct.Name)
@@ -73,13 +77,199 @@ func TestGetAll(t *testing.T) {
 	mockProducts = append(mockProducts, mockProduct1, mockProduct2)
 
 	t.Run("success", func(t *testing.T) {
-		mockProductRepo.On("GetAll").Return(mockProducts, nil).Once()
+		mockProductRepo.On("GetAll", mock.Anything, mock.Anything).Return(mockProducts, nil).Once()
 
-		u := productusecase.NewProductUsecase(mockProductRepo)
-		uRes, err := u.GetAll()
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		uRes, err := u.GetAll(context.Background())
 
 		assert.NoError(t, err)
 		assert.Equal(t, len(uRes), len(mockProducts))
 		mockProductRepo.AssertExpectations(t)
 	})
 }
+
+func TestImport(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	products := []entity.Product{
+		{ID: 1, SKU: "KOPI-1", Name: "Kopi Toraja", Description: "desc", Price: decimal.NewFromInt(50000)},
+		{ID: 2, SKU: "KOPI-2", Name: "Kopi Gayo", Description: "desc", Price: decimal.NewFromInt(45000)},
+		{ID: 3, SKU: "KOPI-3", Name: "Kopi Kintamani", Description: "desc", Price: decimal.NewFromInt(40000)},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockPriceHook := new(mocks.ProductPriceHook)
+		// the first product got cheaper, the second one got dearer and the third one is new
+		previous := map[int64]decimal.Decimal{1: decimal.NewFromInt(60000), 2: decimal.NewFromInt(40000)}
+		mockProductRepo.On("UpsertBySKU", mock.Anything, int64(1), products, false).Return(previous, nil).Once()
+		mockPriceHook.On("PriceDropped", mock.Anything, products[0], mock.MatchedBy(decimal.NewFromInt(60000).Equal)).
+			Return(nil).Once()
+
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher), mockPriceHook)
+		res, err := u.Import(context.Background(), 1, products, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ProductImport{DryRun: false, Created: 1, Updated: 2}, res)
+		mockProductRepo.AssertExpectations(t)
+		mockPriceHook.AssertExpectations(t)
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		mockPriceHook := new(mocks.ProductPriceHook)
+		previous := map[int64]decimal.Decimal{1: decimal.NewFromInt(60000)}
+		mockProductRepo.On("UpsertBySKU", mock.Anything, int64(1), products, true).Return(previous, nil).Once()
+
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher), mockPriceHook)
+		res, err := u.Import(context.Background(), 1, products, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ProductImport{DryRun: true, Created: 2, Updated: 1}, res)
+		mockProductRepo.AssertExpectations(t)
+		// nothing was saved so nobody is told about the lower price
+		mockPriceHook.AssertNotCalled(t, "PriceDropped", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing-sku", func(t *testing.T) {
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		_, err := u.Import(context.Background(), 1, []entity.Product{{Name: "Kopi Toraja"}}, false)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+
+	t.Run("duplicate-sku", func(t *testing.T) {
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		_, err := u.Import(context.Background(), 1, []entity.Product{products[0], products[0]}, false)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockProductRepo.AssertNotCalled(t, "UpsertBySKU", mock.Anything, int64(1), []entity.Product{products[0], products[0]}, false)
+	})
+}
+
+func TestExportBySellerID(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	product := entity.Product{ID: 1, SKU: "KOPI-1", Name: "Kopi Toraja", Description: "desc",
+		Price: decimal.NewFromInt(50000), Weight: 250, Category: "coffee", Seller: entity.Seller{ID: 1}}
+
+	t.Run("success", func(t *testing.T) {
+		mockProductRepo.On("GetBySellerID", mock.Anything, int64(1)).Return([]entity.Product{product}, nil).Once()
+
+		w := new(testhelpers.SheetRows)
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		err := u.ExportBySellerID(context.Background(), 1, w)
+
+		assert.NoError(t, err)
+		assert.True(t, w.Closed)
+		// the header can be imported again
+		assert.Equal(t, []interface{}{"sku", "name", "description", "price", "weight", "category"}, w.Rows[0])
+		assert.Equal(t, []interface{}{"KOPI-1", "Kopi Toraja", "desc", decimal.NewFromInt(50000), int64(250), "coffee"}, w.Rows[1])
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("success without sku", func(t *testing.T) {
+		withoutSKU := product
+		withoutSKU.ID, withoutSKU.SKU = 2, ""
+		mockProductRepo.On("GetBySellerID", mock.Anything, int64(1)).Return([]entity.Product{withoutSKU, product}, nil).Once()
+
+		w := new(testhelpers.SheetRows)
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher))
+		err := u.ExportBySellerID(context.Background(), 1, w)
+
+		// the product without a SKU would be created again by an import
+		assert.NoError(t, err)
+		assert.Len(t, w.Rows, 2)
+		assert.Equal(t, "KOPI-1", w.Rows[1][0])
+		mockProductRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	oldPrice := decimal.NewFromInt(50000)
+	sellerOne := mock.MatchedBy(func(p *entity.Product) bool { return p.ID == 1 && p.Seller.ID == 1 })
+
+	t.Run("success price dropped", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockPriceHook := new(mocks.ProductPriceHook)
+		mockProductRepo.On("Update", mock.Anything, sellerOne).Return(oldPrice, nil).Once()
+		mockPriceHook.On("PriceDropped", mock.Anything, mock.MatchedBy(func(p entity.Product) bool {
+			return p.ID == 1 && p.Price.Equal(decimal.NewFromInt(45000))
+		}), oldPrice).Return(nil).Once()
+
+		product := entity.Product{ID: 1, Name: "Kopi Toraja", Description: "desc", Price: decimal.NewFromInt(45000), Weight: 250}
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher), mockPriceHook)
+		err := u.Update(context.Background(), &product, 1)
+
+		assert.Nil(t, err)
+		mockProductRepo.AssertExpectations(t)
+		mockPriceHook.AssertExpectations(t)
+	})
+
+	t.Run("success price raised", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockPriceHook := new(mocks.ProductPriceHook)
+		mockProductRepo.On("Update", mock.Anything, sellerOne).Return(oldPrice, nil).Once()
+
+		product := entity.Product{ID: 1, Name: "Kopi Toraja", Description: "desc", Price: decimal.NewFromInt(55000)}
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher), mockPriceHook)
+		err := u.Update(context.Background(), &product, 1)
+
+		assert.Nil(t, err)
+		mockPriceHook.AssertNotCalled(t, "PriceDropped", mock.Anything, mock.Anything, mock.Anything)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("error other seller", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockPriceHook := new(mocks.ProductPriceHook)
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Product")).
+			Return(decimal.Decimal{}, resterrors.NewForbiddenError("product with id 1 is not sold by the seller")).Once()
+
+		product := entity.Product{ID: 1, Name: "Kopi Toraja", Description: "desc", Price: decimal.NewFromInt(45000)}
+		u := productusecase.NewProductUsecase(mockProductRepo, new(mocks.EventPublisher), mockPriceHook)
+		err := u.Update(context.Background(), &product, 2)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+		mockPriceHook.AssertNotCalled(t, "PriceDropped", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderCancelled(t *testing.T) {
+	order := &entity.Order{
+		ID:     1,
+		Seller: entity.Seller{ID: 2},
+		Status: entity.REJECTED,
+		Items: []entity.OrderDetail{
+			{ID: 11, Product: entity.Product{ID: 5}, Quantity: 2},
+			{ID: 12, Product: entity.Product{ID: 6}, Quantity: 1},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockPublisher := new(mocks.EventPublisher)
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+			payload, ok := e.Payload.(entity.StockEventPayload)
+			return ok && e.Name == entity.STOCK_RELEASED_EVENT && payload.OrderID == 1 && payload.SellerID == 2 &&
+				len(payload.Items) == 2 && payload.Items[0] == entity.StockEventItemPayload{ProductID: 5, Quantity: 2} &&
+				payload.Items[1] == entity.StockEventItemPayload{ProductID: 6, Quantity: 1}
+		})).Return(nil).Once()
+
+		u := productusecase.NewProductUsecase(new(mocks.ProductRepository), mockPublisher)
+		err := u.OrderCancelled(context.Background(), order)
+
+		assert.Nil(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("error publish failed", func(t *testing.T) {
+		mockPublisher := new(mocks.EventPublisher)
+		mockPublisher.On("Publish", mock.Anything, mock.Anything).
+			Return(resterrors.NewInternalServerError("error when trying to publish event", nil)).Once()
+
+		u := productusecase.NewProductUsecase(new(mocks.ProductRepository), mockPublisher)
+		err := u.OrderCancelled(context.Background(), order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, err.Status())
+	})
+}
@@ -0,0 +1,176 @@
+package reportusecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultReportDays = 30
+	// maxReportDays is the longest range reported at once
+	maxReportDays = 366
+	// maxCachedReports is how many reports are kept at most, the cache is emptied when it is full
+	maxCachedReports = 100
+)
+
+var reportExportHeader = []interface{}{
+	"Period start", "GMV", "New buyers", "New sellers", "Orders placed", "Orders accepted", "Orders completed",
+	"Orders cancelled", "Orders rejected", "Acceptance rate (%)", "Completion rate (%)", "Cancellation rate (%)",
+	"Rejection rate (%)",
+}
+
+type cachedReport struct {
+	report  entity.PlatformReport
+	expires time.Time
+}
+
+type reportUsecase struct {
+	reportRepo entity.ReportRepository
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedReport
+}
+
+// NewReportUsecase will create a object with entity.ReportUseCase interface representation,
+// a report is kept for ttl after it was computed and isn't kept at all when ttl is zero
+func NewReportUsecase(r entity.ReportRepository, ttl time.Duration) entity.ReportUseCase {
+	return &reportUsecase{
+		reportRepo: r,
+		ttl:        ttl,
+		cache:      map[string]cachedReport{},
+	}
+}
+
+// checkReportFilter fills the defaults of filter, the last 30 days by day
+func checkReportFilter(filter *entity.ReportFilter, today time.Time) resterrors.RestErr {
+	if filter.Group == "" {
+		filter.Group = entity.SALES_GROUP_DAY
+	}
+	if filter.To.IsZero() {
+		filter.To = today.AddDate(0, 0, 1)
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, -defaultReportDays)
+	}
+	if !filter.From.Before(filter.To) {
+		return resterrors.NewBadRequestError("from must be before to")
+	}
+	if filter.From.AddDate(0, 0, maxReportDays).Before(filter.To) {
+		return resterrors.NewBadRequestError(fmt.Sprintf("the range can be at most %d days", maxReportDays))
+	}
+	return nil
+}
+
+func (u *reportUsecase) GetPlatformReport(ctx context.Context, filter entity.ReportFilter) (entity.PlatformReport, resterrors.RestErr) {
+	now, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.PlatformReport{}, resterrors.NewInternalServerError("error when trying to get time", tErr)
+	}
+	if err := checkReportFilter(&filter, entity.SALES_GROUP_DAY.PeriodStart(now)); err != nil {
+		return entity.PlatformReport{}, err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"), filter.Group)
+	if report, ok := u.cached(key); ok {
+		return report, nil
+	}
+
+	orders, err := u.reportRepo.GetOrderReport(ctx, filter.From, filter.To, filter.Group)
+	if err != nil {
+		return entity.PlatformReport{}, err
+	}
+	signups, err := u.reportRepo.GetSignups(ctx, filter.From, filter.To, filter.Group)
+	if err != nil {
+		return entity.PlatformReport{}, err
+	}
+
+	// the orders and the signups of a period are summed, periods without any are left at zero
+	byStart := map[string]entity.ReportPeriod{}
+	for _, p := range append(orders, signups...) {
+		key := p.Start.Format("2006-01-02")
+		byStart[key] = byStart[key].Add(p)
+	}
+	report := entity.PlatformReport{Filter: filter, GeneratedAt: now, Periods: []entity.ReportPeriod{}}
+	for start := filter.Group.PeriodStart(filter.From); start.Before(filter.To); start = filter.Group.Next(start) {
+		period := byStart[start.Format("2006-01-02")]
+		period.Start = start
+		report.Periods = append(report.Periods, period)
+		report.Total = report.Total.Add(period)
+	}
+
+	u.store(key, report)
+	return report, nil
+}
+
+// cached returns the report kept for key unless it expired
+func (u *reportUsecase) cached(key string) (entity.PlatformReport, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	c, ok := u.cache[key]
+	if !ok || !time.Now().Before(c.expires) {
+		return entity.PlatformReport{}, false
+	}
+	return c.report, true
+}
+
+// store keeps report for key until the TTL passed, the expired reports are dropped meanwhile
+func (u *reportUsecase) store(key string, report entity.PlatformReport) {
+	if u.ttl <= 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	for k, c := range u.cache {
+		if !now.Before(c.expires) {
+			delete(u.cache, k)
+		}
+	}
+	if len(u.cache) >= maxCachedReports {
+		u.cache = map[string]cachedReport{}
+	}
+	u.cache[key] = cachedReport{report: report, expires: now.Add(u.ttl)}
+}
+
+func (u *reportUsecase) ExportPlatformReport(ctx context.Context, filter entity.ReportFilter, w entity.SheetWriter) resterrors.RestErr {
+	report, err := u.GetPlatformReport(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteRow(reportExportHeader...); err != nil {
+		return resterrors.NewInternalServerError("error when trying to export report", err)
+	}
+	for _, period := range report.Periods {
+		if err := writeReportRow(w, period.Start.Format("2006-01-02"), period); err != nil {
+			return resterrors.NewInternalServerError("error when trying to export report", err)
+		}
+	}
+	if err := writeReportRow(w, "Total", report.Total); err != nil {
+		return resterrors.NewInternalServerError("error when trying to export report", err)
+	}
+	if err := w.Close(); err != nil {
+		return resterrors.NewInternalServerError("error when trying to export report", err)
+	}
+	return nil
+}
+
+// writeReportRow writes period named name, rates are written as percentages
+func writeReportRow(w entity.SheetWriter, name string, period entity.ReportPeriod) error {
+	hundred := decimal.NewFromInt(100)
+	f := period.Funnel
+	return w.WriteRow(name, period.GMV, period.NewBuyers, period.NewSellers, f.Placed, f.Accepted, f.Completed,
+		f.Cancelled, f.Rejected, f.AcceptanceRate().Mul(hundred), f.CompletionRate().Mul(hundred),
+		f.CancellationRate().Mul(hundred), f.RejectionRate().Mul(hundred))
+}
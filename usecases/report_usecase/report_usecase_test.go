@@ -0,0 +1,171 @@
+package reportusecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	testhelpers "github.com/hieronimusbudi/komodo-backend/framework/helpers/test_helpers"
+	reportusecase "github.com/hieronimusbudi/komodo-backend/usecases/report_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetPlatformReport(t *testing.T) {
+	from, to := day(2021, 5, 1), day(2021, 5, 4)
+	filter := entity.ReportFilter{From: from, To: to, Group: entity.SALES_GROUP_DAY}
+	orders := []entity.ReportPeriod{
+		{Start: day(2021, 5, 1), GMV: decimal.NewFromInt(300000),
+			Funnel: entity.OrderFunnel{Placed: 4, Accepted: 3, Completed: 2, Cancelled: 1}},
+		{Start: day(2021, 5, 3), GMV: decimal.Zero, Funnel: entity.OrderFunnel{Placed: 1, Rejected: 1}},
+	}
+	signups := []entity.ReportPeriod{
+		{Start: day(2021, 5, 1), GMV: decimal.Zero, NewBuyers: 2},
+		{Start: day(2021, 5, 2), GMV: decimal.Zero, NewBuyers: 1, NewSellers: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockReportRepo := new(mocks.ReportRepository)
+		mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(orders, nil).Once()
+		mockReportRepo.On("GetSignups", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(signups, nil).Once()
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, 0)
+		res, err := u.GetPlatformReport(context.Background(), filter)
+
+		assert.Nil(t, err)
+		assert.Equal(t, filter, res.Filter)
+		assert.False(t, res.GeneratedAt.IsZero())
+		if !assert.Len(t, res.Periods, 3) {
+			return
+		}
+		assert.Equal(t, int64(2), res.Periods[0].NewBuyers)
+		assert.Equal(t, int64(4), res.Periods[0].Funnel.Placed)
+		assert.Equal(t, day(2021, 5, 2), res.Periods[1].Start)
+		assert.Equal(t, int64(0), res.Periods[1].Funnel.Placed)
+		assert.Equal(t, int64(1), res.Periods[1].NewSellers)
+		assert.Equal(t, entity.OrderFunnel{Placed: 5, Accepted: 3, Completed: 2, Cancelled: 1, Rejected: 1}, res.Total.Funnel)
+		assert.Equal(t, int64(3), res.Total.NewBuyers)
+		assert.True(t, decimal.NewFromInt(300000).Equal(res.Total.GMV))
+		assert.True(t, decimal.RequireFromString("0.6").Equal(res.Total.Funnel.AcceptanceRate()))
+		assert.True(t, decimal.RequireFromString("0.2").Equal(res.Total.Funnel.RejectionRate()))
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("success defaults", func(t *testing.T) {
+		now, tErr := helpers.GetTimeNow()
+		if tErr != nil {
+			t.Fatal(tErr)
+		}
+		to := entity.SALES_GROUP_DAY.PeriodStart(now).AddDate(0, 0, 1)
+		from := to.AddDate(0, 0, -30)
+
+		mockReportRepo := new(mocks.ReportRepository)
+		mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return([]entity.ReportPeriod{}, nil).Once()
+		mockReportRepo.On("GetSignups", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return([]entity.ReportPeriod{}, nil).Once()
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, 0)
+		res, err := u.GetPlatformReport(context.Background(), entity.ReportFilter{})
+
+		assert.Nil(t, err)
+		assert.Len(t, res.Periods, 30)
+		assert.True(t, res.Total.Funnel.AcceptanceRate().IsZero())
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		mockReportRepo := new(mocks.ReportRepository)
+		mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(orders, nil).Once()
+		mockReportRepo.On("GetSignups", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(signups, nil).Once()
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, time.Hour)
+		first, err := u.GetPlatformReport(context.Background(), filter)
+		assert.Nil(t, err)
+		second, err := u.GetPlatformReport(context.Background(), filter)
+		assert.Nil(t, err)
+
+		// the second report is the first one, the repository is only asked once
+		assert.Equal(t, first, second)
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("cache expired", func(t *testing.T) {
+		mockReportRepo := new(mocks.ReportRepository)
+		mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(orders, nil).Twice()
+		mockReportRepo.On("GetSignups", mock.Anything, from, to, entity.SALES_GROUP_DAY).Return(signups, nil).Twice()
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, 10*time.Millisecond)
+		_, err := u.GetPlatformReport(context.Background(), filter)
+		assert.Nil(t, err)
+		time.Sleep(20 * time.Millisecond)
+		_, err = u.GetPlatformReport(context.Background(), filter)
+		assert.Nil(t, err)
+
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockReportRepo := new(mocks.ReportRepository)
+		mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_DAY).
+			Return(nil, resterrors.NewInternalServerError("error when trying to get data", errors.New("connection refused"))).Once()
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, time.Hour)
+		_, err := u.GetPlatformReport(context.Background(), filter)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, err.Status())
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("error range", func(t *testing.T) {
+		mockReportRepo := new(mocks.ReportRepository)
+
+		u := reportusecase.NewReportUsecase(mockReportRepo, 0)
+		_, err := u.GetPlatformReport(context.Background(), entity.ReportFilter{From: day(2020, 1, 1), To: day(2021, 6, 1)})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+
+		_, err = u.GetPlatformReport(context.Background(), entity.ReportFilter{From: to, To: from})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockReportRepo.AssertExpectations(t)
+	})
+}
+
+func TestExportPlatformReport(t *testing.T) {
+	from, to := day(2021, 5, 1), day(2021, 6, 1)
+	filter := entity.ReportFilter{From: from, To: to, Group: entity.SALES_GROUP_MONTH}
+	mockReportRepo := new(mocks.ReportRepository)
+	mockReportRepo.On("GetOrderReport", mock.Anything, from, to, entity.SALES_GROUP_MONTH).Return([]entity.ReportPeriod{
+		{Start: from, GMV: decimal.NewFromInt(300000), Funnel: entity.OrderFunnel{Placed: 4, Accepted: 3, Completed: 2, Cancelled: 1}},
+	}, nil).Once()
+	mockReportRepo.On("GetSignups", mock.Anything, from, to, entity.SALES_GROUP_MONTH).Return([]entity.ReportPeriod{}, nil).Once()
+
+	w := new(testhelpers.SheetRows)
+	u := reportusecase.NewReportUsecase(mockReportRepo, 0)
+	err := u.ExportPlatformReport(context.Background(), filter, w)
+
+	assert.Nil(t, err)
+	assert.True(t, w.Closed)
+	// header, May, total
+	if !assert.Len(t, w.Rows, 3) {
+		return
+	}
+	assert.Equal(t, "Period start", w.Rows[0][0])
+	assert.Equal(t, "2021-05-01", w.Rows[1][0])
+	assert.Equal(t, "Total", w.Rows[2][0])
+	assert.Equal(t, int64(4), w.Rows[2][4])
+	assert.True(t, decimal.NewFromInt(75).Equal(w.Rows[2][9].(decimal.Decimal)))
+	assert.True(t, decimal.NewFromInt(25).Equal(w.Rows[2][11].(decimal.Decimal)))
+	mockReportRepo.AssertExpectations(t)
+}
@@ -0,0 +1,362 @@
+package returnusecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+// returnTransitions are the status changes allowed for a return
+var returnTransitions = map[entity.ReturnStatusEnum][]entity.ReturnStatusEnum{
+	entity.RETURN_REQUESTED: {entity.RETURN_APPROVED, entity.RETURN_REJECTED, entity.RETURN_CANCELLED},
+	entity.RETURN_APPROVED:  {entity.RETURN_SHIPPED, entity.RETURN_CANCELLED},
+	entity.RETURN_SHIPPED:   {entity.RETURN_RECEIVED},
+	entity.RETURN_RECEIVED:  {entity.RETURN_REFUNDED},
+}
+
+const (
+	// orderReturnLock is the lock held while a return of an order is requested
+	orderReturnLock = "order_return_%d"
+	// returnRefundLock is the lock held while a received return is refunded
+	returnRefundLock = "return_refund_%d"
+)
+
+type returnUsecase struct {
+	returnRepo entity.ReturnRepository
+	orderRepo  entity.OrderRepository
+	payments   entity.PaymentUseCase
+	publisher  entity.EventPublisher
+	locker     entity.Locker
+}
+
+// NewReturnUsecase will create a object with entity.ReturnUseCase interface representation,
+// payments refunds received returns and locker keeps returns of an order from being requested together
+func NewReturnUsecase(returnRepo entity.ReturnRepository, orderRepo entity.OrderRepository, payments entity.PaymentUseCase,
+	publisher entity.EventPublisher, locker entity.Locker) entity.ReturnUseCase {
+	return &returnUsecase{
+		returnRepo: returnRepo,
+		orderRepo:  orderRepo,
+		payments:   payments,
+		publisher:  publisher,
+		locker:     locker,
+	}
+}
+
+func (u *returnUsecase) Request(ctx context.Context, ret *entity.ReturnRequest, user helpers.UserJWTPayload) resterrors.RestErr {
+	repoOrder, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: ret.OrderID})
+	if err != nil {
+		return err
+	}
+
+	if user.Type != helpers.BUYER_TYPE || repoOrder.Buyer.ID != user.ID {
+		return resterrors.NewForbiddenError("only the buyer of the order can return its items")
+	}
+
+	if repoOrder.Status != entity.DELIVERED {
+		return resterrors.NewConflictError("only delivered orders can be returned")
+	}
+
+	// the quantities left to return are read and saved under the lock so two requests can't return the same items
+	unlock, ok, lErr := u.locker.TryLock(ctx, fmt.Sprintf(orderReturnLock, repoOrder.ID))
+	if lErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", lErr)
+	}
+	if !ok {
+		return resterrors.NewConflictError(fmt.Sprintf("a return of order with id %d is already in progress", repoOrder.ID))
+	}
+	defer unlock()
+
+	returns, err := u.returnRepo.GetByOrderID(ctx, repoOrder.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkItems(repoOrder, returns, ret.Items); err != nil {
+		return err
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	ret.BuyerID = repoOrder.Buyer.ID
+	ret.SellerID = repoOrder.Seller.ID
+	ret.Status = entity.RETURN_REQUESTED
+	ret.Restock = false
+	ret.CreatedAt = tn
+	ret.UpdatedAt = tn
+	if ret.Photos == nil {
+		ret.Photos = []string{}
+	}
+
+	return u.returnRepo.Store(ctx, ret)
+}
+
+func (u *returnUsecase) GetByOrderID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) ([]entity.ReturnRequest, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	isBuyer := user.Type == helpers.BUYER_TYPE && repoOrder.Buyer.ID == user.ID
+	isSeller := user.Type == helpers.SELLER_TYPE && repoOrder.Seller.ID == user.ID
+	if !isBuyer && !isSeller && user.Type != helpers.ADMIN_TYPE {
+		return nil, resterrors.NewForbiddenError("you are not allowed to access this order")
+	}
+
+	return u.returnRepo.GetByOrderID(ctx, repoOrder.ID)
+}
+
+func (u *returnUsecase) GetByID(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	if !isBuyer(ret, user) && !isSeller(ret, user) && user.Type != helpers.ADMIN_TYPE {
+		return entity.ReturnRequest{}, resterrors.NewForbiddenError("you are not allowed to access this return")
+	}
+	return ret, nil
+}
+
+func (u *returnUsecase) Approve(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.getForSeller(ctx, returnID, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	if err := u.changeStatus(ctx, &ret, entity.RETURN_APPROVED); err != nil {
+		return entity.ReturnRequest{}, err
+	}
+	return ret, nil
+}
+
+func (u *returnUsecase) Reject(ctx context.Context, returnID int64, reason string, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.getForSeller(ctx, returnID, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	ret.RejectReason = reason
+	if err := u.changeStatus(ctx, &ret, entity.RETURN_REJECTED); err != nil {
+		return entity.ReturnRequest{}, err
+	}
+	return ret, nil
+}
+
+func (u *returnUsecase) Cancel(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.getForBuyer(ctx, returnID, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	if err := u.changeStatus(ctx, &ret, entity.RETURN_CANCELLED); err != nil {
+		return entity.ReturnRequest{}, err
+	}
+	return ret, nil
+}
+
+func (u *returnUsecase) Ship(ctx context.Context, returnID int64, carrier string, trackingNumber string, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.getForBuyer(ctx, returnID, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	ret.Carrier = carrier
+	ret.TrackingNumber = trackingNumber
+	if err := u.changeStatus(ctx, &ret, entity.RETURN_SHIPPED); err != nil {
+		return entity.ReturnRequest{}, err
+	}
+	return ret, nil
+}
+
+func (u *returnUsecase) Receive(ctx context.Context, returnID int64, restock bool, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.getForSeller(ctx, returnID, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	// a received return is only refunded again, it was received when its refund failed
+	if ret.Status != entity.RETURN_RECEIVED {
+		ret.Restock = restock
+		if err := u.changeStatus(ctx, &ret, entity.RETURN_RECEIVED); err != nil {
+			return entity.ReturnRequest{}, err
+		}
+		u.publishReceived(ctx, ret)
+	}
+
+	refund, err := u.refund(ctx, ret, user)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	ret.RefundID = refund.ID
+	if err := u.changeStatus(ctx, &ret, entity.RETURN_REFUNDED); err != nil {
+		return entity.ReturnRequest{}, err
+	}
+	return ret, nil
+}
+
+// refund refunds the items of ret. The refund and the move to RETURN_REFUNDED aren't saved together, so the
+// refund a failed move left behind is looked up by its reason and returned instead of refunding the items twice
+func (u *returnUsecase) refund(ctx context.Context, ret entity.ReturnRequest, user helpers.UserJWTPayload) (entity.Refund, resterrors.RestErr) {
+	unlock, ok, lErr := u.locker.TryLock(ctx, fmt.Sprintf(returnRefundLock, ret.ID))
+	if lErr != nil {
+		return entity.Refund{}, lErr
+	}
+	if !ok {
+		return entity.Refund{}, resterrors.NewConflictError(fmt.Sprintf("a refund of return with id %d is already in progress", ret.ID))
+	}
+	defer unlock()
+
+	reason := fmt.Sprintf("return %d", ret.ID)
+	summary, err := u.payments.Summary(ctx, ret.OrderID)
+	if err != nil {
+		return entity.Refund{}, err
+	}
+	for _, refund := range summary.Refunds {
+		if refund.Reason == reason {
+			return refund, nil
+		}
+	}
+
+	request := entity.Refund{Reason: reason}
+	for _, ri := range ret.Items {
+		request.Items = append(request.Items, entity.RefundItem{OrderDetailID: ri.OrderDetailID, Quantity: ri.Quantity})
+	}
+	return u.payments.Refund(ctx, &entity.Order{ID: ret.OrderID}, request, user)
+}
+
+// getForBuyer returns the return with id returnID when user is its buyer
+func (u *returnUsecase) getForBuyer(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	if !isBuyer(ret, user) {
+		return entity.ReturnRequest{}, resterrors.NewForbiddenError("only the buyer of the order can change this return")
+	}
+	return ret, nil
+}
+
+// getForSeller returns the return with id returnID when user is its seller or an admin
+func (u *returnUsecase) getForSeller(ctx context.Context, returnID int64, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return entity.ReturnRequest{}, err
+	}
+
+	if !isSeller(ret, user) && user.Type != helpers.ADMIN_TYPE {
+		return entity.ReturnRequest{}, resterrors.NewForbiddenError("only the seller of the order or an admin can change this return")
+	}
+	return ret, nil
+}
+
+// changeStatus moves ret to status when the state machine allows it
+func (u *returnUsecase) changeStatus(ctx context.Context, ret *entity.ReturnRequest, status entity.ReturnStatusEnum) resterrors.RestErr {
+	allowed := false
+	for _, to := range returnTransitions[ret.Status] {
+		if to == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return resterrors.NewConflictError(fmt.Sprintf("return with id %d can't be moved from status %d to %d", ret.ID, ret.Status, status))
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to update data", tErr)
+	}
+
+	from := ret.Status
+	ret.Status = status
+	ret.UpdatedAt = tn
+	switch status {
+	case entity.RETURN_SHIPPED:
+		ret.ShippedAt = &tn
+	case entity.RETURN_RECEIVED:
+		ret.ReceivedAt = &tn
+	}
+
+	if err := u.returnRepo.Update(ctx, ret, from); err != nil {
+		ret.Status = from
+		return err
+	}
+	return nil
+}
+
+// publishReceived tells listeners the items of ret are back at the seller, a failed publish doesn't fail the return
+func (u *returnUsecase) publishReceived(ctx context.Context, ret entity.ReturnRequest) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: ret.OrderID})
+	if err != nil {
+		log.Println("return event publish error", ret.ID, err)
+		return
+	}
+
+	products := map[int64]int64{}
+	for _, od := range repoOrder.Items {
+		products[od.ID] = od.Product.ID
+	}
+
+	payload := entity.ReturnEventPayload{
+		ReturnID: ret.ID,
+		OrderID:  ret.OrderID,
+		BuyerID:  ret.BuyerID,
+		SellerID: ret.SellerID,
+		Status:   ret.Status,
+		Restock:  ret.Restock,
+		Items:    []entity.ReturnEventItemPayload{},
+	}
+	for _, ri := range ret.Items {
+		payload.Items = append(payload.Items, entity.ReturnEventItemPayload{ProductID: products[ri.OrderDetailID], Quantity: ri.Quantity})
+	}
+
+	event := entity.Event{Name: entity.RETURN_RECEIVED_EVENT, OccurredAt: time.Now().UTC(), Payload: payload}
+	if pubErr := u.publisher.Publish(ctx, event); pubErr != nil {
+		log.Println("return event publish error", ret.ID, pubErr)
+	}
+}
+
+// checkItems validates requested against the lines of order, an item can't be returned more often than it was ordered
+// counting the items of active returns
+func checkItems(order entity.Order, returns []entity.ReturnRequest, requested []entity.ReturnItem) resterrors.RestErr {
+	left := map[int64]int64{}
+	for _, od := range order.Items {
+		left[od.ID] = od.Quantity
+	}
+	for _, ret := range returns {
+		if !ret.Status.Active() {
+			continue
+		}
+		for _, ri := range ret.Items {
+			left[ri.OrderDetailID] -= ri.Quantity
+		}
+	}
+
+	for _, ri := range requested {
+		remaining, ok := left[ri.OrderDetailID]
+		if !ok {
+			return resterrors.NewBadRequestError(fmt.Sprintf("order item with id %d is not part of order with id %d", ri.OrderDetailID, order.ID))
+		}
+		if ri.Quantity > remaining {
+			return resterrors.NewConflictError(fmt.Sprintf("only %d of order item with id %d can be returned", remaining, ri.OrderDetailID))
+		}
+		left[ri.OrderDetailID] -= ri.Quantity
+	}
+	return nil
+}
+
+func isBuyer(ret entity.ReturnRequest, user helpers.UserJWTPayload) bool {
+	return user.Type == helpers.BUYER_TYPE && ret.BuyerID == user.ID
+}
+
+func isSeller(ret entity.ReturnRequest, user helpers.UserJWTPayload) bool {
+	return user.Type == helpers.SELLER_TYPE && ret.SellerID == user.ID
+}
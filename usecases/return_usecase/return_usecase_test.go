@@ -0,0 +1,377 @@
+package returnusecase_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	returnusecase "github.com/hieronimusbudi/komodo-backend/usecases/return_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	mockOrder = entity.Order{
+		ID:     1,
+		Buyer:  entity.Buyer{ID: 1},
+		Seller: entity.Seller{ID: 2},
+		Status: entity.DELIVERED,
+		Items: []entity.OrderDetail{
+			{ID: 11, Product: entity.Product{ID: 5}, Quantity: 2, Price: decimal.NewFromFloat(50000.5)},
+			{ID: 12, Product: entity.Product{ID: 6}, Quantity: 1, Price: decimal.NewFromFloat(81817.1)},
+		},
+	}
+	mockReturn = entity.ReturnRequest{
+		ID:       4,
+		OrderID:  1,
+		BuyerID:  1,
+		SellerID: 2,
+		Status:   entity.RETURN_REQUESTED,
+		Reason:   entity.RETURN_REASON_DAMAGED,
+		Photos:   []string{},
+		Items:    []entity.ReturnItem{{ID: 8, ReturnID: 4, OrderDetailID: 11, Quantity: 1}},
+	}
+	buyer  = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+)
+
+func returnWithStatus(status entity.ReturnStatusEnum) entity.ReturnRequest {
+	ret := mockReturn
+	ret.Status = status
+	return ret
+}
+
+func TestRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_return_1").Return(func() {}, true, nil).Once()
+		// a cancelled return gives its items back
+		cancelled := returnWithStatus(entity.RETURN_CANCELLED)
+		cancelled.Items = []entity.ReturnItem{{OrderDetailID: 11, Quantity: 2}}
+		mockReturnRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return([]entity.ReturnRequest{cancelled}, nil).Once()
+		mockReturnRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *entity.ReturnRequest) bool {
+			return r.BuyerID == 1 && r.SellerID == 2 && r.Status == entity.RETURN_REQUESTED && !r.CreatedAt.IsZero()
+		})).Return(nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, mockOrderRepo, new(mocks.PaymentUseCase), new(mocks.EventPublisher), mockLocker)
+		err := u.Request(context.Background(), &entity.ReturnRequest{
+			OrderID: 1,
+			Reason:  entity.RETURN_REASON_DAMAGED,
+			Items:   []entity.ReturnItem{{OrderDetailID: 11, Quantity: 2}, {OrderDetailID: 12, Quantity: 1}},
+		}, buyer)
+
+		assert.Nil(t, err)
+		mockReturnRepo.AssertExpectations(t)
+	})
+
+	t.Run("error more than left to return", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_return_1").Return(func() {}, true, nil).Once()
+		mockReturnRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return([]entity.ReturnRequest{mockReturn}, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, mockOrderRepo, new(mocks.PaymentUseCase), new(mocks.EventPublisher), mockLocker)
+		err := u.Request(context.Background(), &entity.ReturnRequest{
+			OrderID: 1,
+			Items:   []entity.ReturnItem{{OrderDetailID: 11, Quantity: 2}},
+		}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockReturnRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error item not in the order", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockLocker := new(mocks.Locker)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "order_return_1").Return(func() {}, true, nil).Once()
+		mockReturnRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return([]entity.ReturnRequest{}, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, mockOrderRepo, new(mocks.PaymentUseCase), new(mocks.EventPublisher), mockLocker)
+		err := u.Request(context.Background(), &entity.ReturnRequest{
+			OrderID: 1,
+			Items:   []entity.ReturnItem{{OrderDetailID: 99, Quantity: 1}},
+		}, buyer)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+
+	for _, status := range []entity.OrderStatusEnum{entity.PENDING, entity.ACCEPTED, entity.SHIPPED} {
+		t.Run(fmt.Sprintf("error order %s not delivered", status), func(t *testing.T) {
+			notDelivered := mockOrder
+			notDelivered.Status = status
+			mockOrderRepo := new(mocks.OrderRepository)
+			mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(notDelivered, nil).Once()
+
+			u := returnusecase.NewReturnUsecase(new(mocks.ReturnRepository), mockOrderRepo, new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+			err := u.Request(context.Background(), &entity.ReturnRequest{OrderID: 1}, buyer)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusConflict, err.Status())
+			assert.Equal(t, "only delivered orders can be returned", err.Message())
+		})
+	}
+
+	t.Run("error not the buyer of the order", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(new(mocks.ReturnRepository), mockOrderRepo, new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+		err := u.Request(context.Background(), &entity.ReturnRequest{OrderID: 1}, helpers.UserJWTPayload{ID: 3, Type: helpers.BUYER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(mockReturn, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+		res, err := u.GetByID(context.Background(), mockReturn.ID, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, mockReturn.ID, res.ID)
+	})
+
+	t.Run("error not a participant", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(mockReturn, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+		_, err := u.GetByID(context.Background(), mockReturn.ID, helpers.UserJWTPayload{ID: 3, Type: helpers.SELLER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestTransitions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		from   entity.ReturnStatusEnum
+		to     entity.ReturnStatusEnum
+		user   helpers.UserJWTPayload
+		call   func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr)
+		status int
+	}{
+		{
+			name: "approve", from: entity.RETURN_REQUESTED, to: entity.RETURN_APPROVED, user: seller,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Approve(context.Background(), mockReturn.ID, user)
+			},
+		},
+		{
+			name: "reject", from: entity.RETURN_REQUESTED, to: entity.RETURN_REJECTED, user: seller,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Reject(context.Background(), mockReturn.ID, "used item", user)
+			},
+		},
+		{
+			name: "cancel approved", from: entity.RETURN_APPROVED, to: entity.RETURN_CANCELLED, user: buyer,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Cancel(context.Background(), mockReturn.ID, user)
+			},
+		},
+		{
+			name: "ship", from: entity.RETURN_APPROVED, to: entity.RETURN_SHIPPED, user: buyer,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Ship(context.Background(), mockReturn.ID, "JNE", "JNE123", user)
+			},
+		},
+		{
+			name: "error ship not approved", from: entity.RETURN_REQUESTED, user: buyer, status: http.StatusConflict,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Ship(context.Background(), mockReturn.ID, "JNE", "JNE123", user)
+			},
+		},
+		{
+			name: "error cancel shipped", from: entity.RETURN_SHIPPED, user: buyer, status: http.StatusConflict,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Cancel(context.Background(), mockReturn.ID, user)
+			},
+		},
+		{
+			name: "error buyer approves", from: entity.RETURN_REQUESTED, user: buyer, status: http.StatusForbidden,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Approve(context.Background(), mockReturn.ID, user)
+			},
+		},
+		{
+			name: "error seller ships", from: entity.RETURN_APPROVED, user: seller, status: http.StatusForbidden,
+			call: func(u entity.ReturnUseCase, user helpers.UserJWTPayload) (entity.ReturnRequest, resterrors.RestErr) {
+				return u.Ship(context.Background(), mockReturn.ID, "JNE", "JNE123", user)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockReturnRepo := new(mocks.ReturnRepository)
+			mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(tc.from), nil).Once()
+			if tc.status == 0 {
+				mockReturnRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *entity.ReturnRequest) bool {
+					return r.Status == tc.to
+				}), tc.from).Return(nil).Once()
+			}
+
+			u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+			res, err := tc.call(u, tc.user)
+
+			if tc.status != 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.status, err.Status())
+				mockReturnRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.to, res.Status)
+			mockReturnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceive(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPayments := new(mocks.PaymentUseCase)
+		mockPublisher := new(mocks.EventPublisher)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_SHIPPED), nil).Once()
+		mockReturnRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *entity.ReturnRequest) bool {
+			return r.Status == entity.RETURN_RECEIVED && r.Restock && r.ReceivedAt != nil
+		}), entity.RETURN_SHIPPED).Return(nil).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+			payload, ok := e.Payload.(entity.ReturnEventPayload)
+			return ok && e.Name == entity.RETURN_RECEIVED_EVENT && payload.Restock &&
+				len(payload.Items) == 1 && payload.Items[0].ProductID == 5 && payload.Items[0].Quantity == 1
+		})).Return(nil).Once()
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "return_refund_4").Return(func() {}, true, nil).Once()
+		mockPayments.On("Summary", mock.Anything, mockOrder.ID).
+			Return(entity.RefundSummary{Refunds: []entity.Refund{{ID: 5, Reason: "order cancelled"}}}, nil).Once()
+		mockPayments.On("Refund", mock.Anything, &entity.Order{ID: mockOrder.ID}, entity.Refund{
+			Reason: "return 4",
+			Items:  []entity.RefundItem{{OrderDetailID: 11, Quantity: 1}},
+		}, seller).Return(entity.Refund{ID: 6}, nil).Once()
+		mockReturnRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *entity.ReturnRequest) bool {
+			return r.Status == entity.RETURN_REFUNDED && r.RefundID == 6
+		}), entity.RETURN_RECEIVED).Return(nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, mockOrderRepo, mockPayments, mockPublisher, mockLocker)
+		res, err := u.Receive(context.Background(), mockReturn.ID, true, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.RETURN_REFUNDED, res.Status)
+		assert.Equal(t, int64(6), res.RefundID)
+		mockReturnRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("success received return is refunded again", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockPayments := new(mocks.PaymentUseCase)
+		mockPublisher := new(mocks.EventPublisher)
+		mockLocker := new(mocks.Locker)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_RECEIVED), nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "return_refund_4").Return(func() {}, true, nil).Once()
+		mockPayments.On("Summary", mock.Anything, mockOrder.ID).Return(entity.RefundSummary{Refunds: []entity.Refund{}}, nil).Once()
+		mockPayments.On("Refund", mock.Anything, mock.Anything, mock.Anything, seller).Return(entity.Refund{ID: 6}, nil).Once()
+		mockReturnRepo.On("Update", mock.Anything, mock.Anything, entity.RETURN_RECEIVED).Return(nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), mockPayments, mockPublisher, mockLocker)
+		res, err := u.Receive(context.Background(), mockReturn.ID, true, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.RETURN_REFUNDED, res.Status)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("success retry doesn't refund a refunded return again", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockPayments := new(mocks.PaymentUseCase)
+		mockLocker := new(mocks.Locker)
+		// the refund was made but the return couldn't be moved to refunded
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_RECEIVED), nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "return_refund_4").Return(func() {}, true, nil).Once()
+		mockPayments.On("Summary", mock.Anything, mockOrder.ID).
+			Return(entity.RefundSummary{Refunds: []entity.Refund{{ID: 6, Reason: "return 4"}}}, nil).Once()
+		mockReturnRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *entity.ReturnRequest) bool {
+			return r.Status == entity.RETURN_REFUNDED && r.RefundID == 6
+		}), entity.RETURN_RECEIVED).Return(nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), mockPayments, new(mocks.EventPublisher), mockLocker)
+		res, err := u.Receive(context.Background(), mockReturn.ID, false, seller)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), res.RefundID)
+		mockPayments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockReturnRepo.AssertExpectations(t)
+	})
+
+	t.Run("error refund in progress", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockPayments := new(mocks.PaymentUseCase)
+		mockLocker := new(mocks.Locker)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_RECEIVED), nil).Once()
+		mockLocker.On("TryLock", mock.Anything, "return_refund_4").Return(nil, false, nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), mockPayments, new(mocks.EventPublisher), mockLocker)
+		_, err := u.Receive(context.Background(), mockReturn.ID, false, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockPayments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error refund failed keeps the return received", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockPayments := new(mocks.PaymentUseCase)
+		mockPublisher := new(mocks.EventPublisher)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_SHIPPED), nil).Once()
+		mockReturnRepo.On("Update", mock.Anything, mock.Anything, entity.RETURN_SHIPPED).Return(nil).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil).Once()
+		mockLocker := new(mocks.Locker)
+		mockLocker.On("TryLock", mock.Anything, "return_refund_4").Return(func() {}, true, nil).Once()
+		mockPayments.On("Summary", mock.Anything, mockOrder.ID).Return(entity.RefundSummary{Refunds: []entity.Refund{}}, nil).Once()
+		mockPayments.On("Refund", mock.Anything, mock.Anything, mock.Anything, seller).
+			Return(entity.Refund{}, resterrors.NewConflictError("order with id 1 is not paid")).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, mockOrderRepo, mockPayments, mockPublisher, mockLocker)
+		_, err := u.Receive(context.Background(), mockReturn.ID, false, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockReturnRepo.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("error not shipped", func(t *testing.T) {
+		mockReturnRepo := new(mocks.ReturnRepository)
+		mockReturnRepo.On("GetByID", mock.Anything, mockReturn.ID).Return(returnWithStatus(entity.RETURN_APPROVED), nil).Once()
+
+		u := returnusecase.NewReturnUsecase(mockReturnRepo, new(mocks.OrderRepository), new(mocks.PaymentUseCase), new(mocks.EventPublisher), new(mocks.Locker))
+		_, err := u.Receive(context.Background(), mockReturn.ID, false, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+}
@@ -1,9 +1,12 @@
 package sellerusecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
 	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -19,17 +22,17 @@ func NewSellerUsecase(sellerRepo entity.SellerRepository) entity.SellerUseCase {
 	}
 }
 
-func (s *sellerUsecase) Register(seller *entity.Seller) resterrors.RestErr {
+func (s *sellerUsecase) Register(ctx context.Context, seller *entity.Seller) resterrors.RestErr {
 	// check existing user
 	ss := new(entity.Seller)
 	ss.Email = seller.Email
-	repoRes, err := s.sellerRepo.GetByEmail(ss)
+	repoRes, err := s.sellerRepo.GetByEmail(ctx, ss)
 	if err != nil {
-		if err.Causes() != "sql: no rows in result set" {
+		if !errors.Is(err, resterrors.ErrNotFound) {
 			return err
 		}
 	} else if repoRes.Email == seller.Email {
-		return resterrors.NewBadRequestError(fmt.Sprintf("user with email %s is already exist", repoRes.Email))
+		return resterrors.NewConflictError(fmt.Sprintf("user with email %s is already exist", repoRes.Email))
 	}
 
 	// encrypt password
@@ -39,25 +42,39 @@ func (s *sellerUsecase) Register(seller *entity.Seller) resterrors.RestErr {
 	}
 
 	seller.Password = string(hashedPassword)
+	// sellers collect no tax until they say they are registered for it
+	if seller.TaxStatus == "" {
+		seller.TaxStatus = entity.TAX_UNREGISTERED
+	}
+	// the account is timestamped by the app, like every other record
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	seller.CreatedAt = tn
 
-	repoErr := s.sellerRepo.Store(seller)
+	repoErr := s.sellerRepo.Store(ctx, seller)
 	if repoErr != nil {
 		return repoErr
 	}
 	return nil
 }
 
-func (s *sellerUsecase) Login(seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
+func (s *sellerUsecase) Login(ctx context.Context, seller *entity.Seller) (entity.Seller, resterrors.RestErr) {
 	oriPass := seller.Password
-	repoRes, err := s.sellerRepo.GetByEmail(seller)
+	repoRes, err := s.sellerRepo.GetByEmail(ctx, seller)
 	if err != nil {
-		return *seller, err
+		// an unknown email fails like a wrong password, so logins can't tell which emails are registered
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return entity.Seller{}, resterrors.NewUnauthorizedError("invalid email or password")
+		}
+		return entity.Seller{}, err
 	}
 
 	// compare hashed password and requested password
 	cprErr := bcrypt.CompareHashAndPassword([]byte(repoRes.Password), []byte(oriPass))
 	if cprErr != nil {
-		return *seller, resterrors.NewInternalServerError(cprErr.Error(), cprErr)
+		return entity.Seller{}, resterrors.NewUnauthorizedError("invalid email or password")
 	}
 
 	return repoRes, nil
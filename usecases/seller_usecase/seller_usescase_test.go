@@ -1,10 +1,14 @@
 package sellerusecase_test
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/hieronimusbudi/komodo-backend/entity"
 	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
 	sellerusecase "github.com/hieronimusbudi/komodo-backend/usecases/seller_usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -33,25 +37,43 @@ func TestRegister(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		tmpMockSeller := mockSeller
 
-		mockSellerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Seller")).Return(mockSellerEmpty, nil).Once()
-		mockSellerRepo.On("Store", mock.AnythingOfType("*entity.Seller")).Return(nil).Once()
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(mockSellerEmpty, nil).Once()
+		mockSellerRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(nil).Once()
 
 		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
-		err := u.Register(&tmpMockSeller)
+		err := u.Register(context.Background(), &tmpMockSeller)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockSeller.Email, tmpMockSeller.Email)
+		assert.False(t, tmpMockSeller.CreatedAt.IsZero())
+		assert.Equal(t, entity.TAX_UNREGISTERED, tmpMockSeller.TaxStatus)
 		mockSellerRepo.AssertExpectations(t)
 	})
 
 	t.Run("user is already exist", func(t *testing.T) {
 		tmpMockSeller := mockSeller
 
-		mockSellerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Seller")).Return(mockSellerExist, nil).Once()
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(mockSellerExist, nil).Once()
 		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
-		err := u.Register(&tmpMockSeller)
+		err := u.Register(context.Background(), &tmpMockSeller)
 
 		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockSellerRepo.AssertExpectations(t)
+	})
+
+	t.Run("user registered concurrently", func(t *testing.T) {
+		tmpMockSeller := mockSeller
+
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).
+			Return(mockSellerEmpty, resterrors.NewNotFoundError("seller not found")).Once()
+		mockSellerRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Seller")).
+			Return(resterrors.NewConflictError("user with email seller1@mail.com is already exist")).Once()
+
+		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
+		err := u.Register(context.Background(), &tmpMockSeller)
+
+		assert.True(t, errors.Is(err, resterrors.ErrConflict))
 		mockSellerRepo.AssertExpectations(t)
 	})
 }
@@ -76,13 +98,41 @@ func TestLogin(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockSellerRepo.On("GetByEmail", mock.AnythingOfType("*entity.Seller")).Return(mockSellerRepoResponse, nil).Once()
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(mockSellerRepoResponse, nil).Once()
 
 		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
-		uRes, err := u.Login(&mockSeller)
+		uRes, err := u.Login(context.Background(), &mockSeller)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockSellerRepoResponse.ID, uRes.ID)
 		mockSellerRepo.AssertExpectations(t)
 	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		wrongPassword := mockSeller
+		wrongPassword.Password = "54321"
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(mockSellerRepoResponse, nil).Once()
+
+		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
+		_, err := u.Login(context.Background(), &wrongPassword)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
+
+	t.Run("error unknown email", func(t *testing.T) {
+		unknown := mockSeller
+		unknown.Email = "nobody@mail.com"
+		mockSellerRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("*entity.Seller")).
+			Return(entity.Seller{}, resterrors.NewNotFoundError("seller with email nobody@mail.com not found")).Once()
+
+		u := sellerusecase.NewSellerUsecase(mockSellerRepo)
+		_, err := u.Login(context.Background(), &unknown)
+
+		// the same error as a wrong password
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.Status())
+		assert.Equal(t, "invalid email or password", err.Message())
+	})
 }
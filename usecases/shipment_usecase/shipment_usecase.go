@@ -0,0 +1,133 @@
+package shipmentusecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type shipmentUsecase struct {
+	shipmentRepo entity.ShipmentRepository
+	orderRepo    entity.OrderRepository
+	orders       entity.OrderUseCase
+	tracker      entity.CourierTracker
+}
+
+// NewShipmentUsecase will create a object with entity.ShipmentUseCase interface representation,
+// orders moves the order of a shipment along and tracker asks couriers where parcels are
+func NewShipmentUsecase(shipmentRepo entity.ShipmentRepository, orderRepo entity.OrderRepository, orders entity.OrderUseCase,
+	tracker entity.CourierTracker) entity.ShipmentUseCase {
+	return &shipmentUsecase{
+		shipmentRepo: shipmentRepo,
+		orderRepo:    orderRepo,
+		orders:       orders,
+		tracker:      tracker,
+	}
+}
+
+func (u *shipmentUsecase) Ship(ctx context.Context, shipment *entity.Shipment, user helpers.UserJWTPayload) resterrors.RestErr {
+	repoOrder, err := u.orderRepo.GetByID(ctx, &entity.Order{ID: shipment.OrderID})
+	if err != nil {
+		return err
+	}
+
+	if user.Type != helpers.SELLER_TYPE || repoOrder.Seller.ID != user.ID {
+		return resterrors.NewForbiddenError("only the seller of the order can ship it")
+	}
+
+	if repoOrder.Status != entity.ACCEPTED {
+		return resterrors.NewConflictError("only accepted orders can be shipped")
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	shipment.Status = entity.SHIPMENT_IN_TRANSIT
+	shipment.ShippedAt = tn
+
+	// an order has one shipment, so shipping it twice fails here
+	if err := u.shipmentRepo.Store(ctx, shipment); err != nil {
+		return err
+	}
+
+	// the parcel is on its way whatever happens to the order, an order left ACCEPTED is still delivered by the tracker
+	if _, err := u.orders.MarkShipped(ctx, &entity.Order{ID: repoOrder.ID}, user); err != nil {
+		log.Println("order ship error", repoOrder.ID, err)
+	}
+	return nil
+}
+
+func (u *shipmentUsecase) GetByOrderID(ctx context.Context, order *entity.Order, user helpers.UserJWTPayload) (entity.Shipment, resterrors.RestErr) {
+	repoOrder, err := u.orderRepo.GetByID(ctx, order)
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	isBuyer := user.Type == helpers.BUYER_TYPE && repoOrder.Buyer.ID == user.ID
+	isSeller := user.Type == helpers.SELLER_TYPE && repoOrder.Seller.ID == user.ID
+	if !isBuyer && !isSeller && user.Type != helpers.ADMIN_TYPE {
+		return entity.Shipment{}, resterrors.NewForbiddenError("you are not allowed to access this order")
+	}
+
+	return u.shipmentRepo.GetByOrderID(ctx, repoOrder.ID)
+}
+
+// TrackInTransit saves the events of each shipment with CheckedAt, a shipment the courier can't track is checked again
+// after the others. The order of a delivered shipment is delivered before the shipment is saved, so a shipment whose order
+// failed to move is saved in transit and is delivered once it is checked again
+func (u *shipmentUsecase) TrackInTransit(ctx context.Context, checkedBefore time.Time, limit int) ([]entity.Shipment, resterrors.RestErr) {
+	inTransit, err := u.shipmentRepo.GetInTransit(ctx, checkedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := []entity.Shipment{}
+	for _, shipment := range inTransit {
+		if ctx.Err() != nil {
+			break
+		}
+
+		tn, tErr := helpers.GetTimeNow()
+		if tErr != nil {
+			return checked, resterrors.NewInternalServerError("error when trying to update data", tErr)
+		}
+		shipment.CheckedAt = &tn
+
+		tracking, trackErr := u.tracker.Track(ctx, shipment.Courier, shipment.TrackingNumber)
+		if trackErr != nil {
+			log.Println("shipment tracking error", shipment.ID, trackErr)
+			if saveErr := u.shipmentRepo.SaveTracking(ctx, &shipment, nil); saveErr != nil {
+				log.Println("shipment tracking save error", shipment.ID, saveErr)
+			}
+			continue
+		}
+
+		if tracking.Delivered {
+			if _, deliverErr := u.orders.MarkDelivered(ctx, &entity.Order{ID: shipment.OrderID}); deliverErr != nil {
+				log.Println("order delivery error", shipment.OrderID, deliverErr)
+				// saved in transit with CheckedAt so the shipments after it are checked before it is delivered again
+				if saveErr := u.shipmentRepo.SaveTracking(ctx, &shipment, tracking.Events); saveErr != nil {
+					log.Println("shipment tracking save error", shipment.ID, saveErr)
+				}
+				continue
+			}
+			deliveredAt := tracking.DeliveredAt
+			shipment.Status = entity.SHIPMENT_DELIVERED
+			shipment.DeliveredAt = &deliveredAt
+		}
+
+		if saveErr := u.shipmentRepo.SaveTracking(ctx, &shipment, tracking.Events); saveErr != nil {
+			log.Println("shipment tracking save error", shipment.ID, saveErr)
+			continue
+		}
+		shipment.Events = tracking.Events
+		checked = append(checked, shipment)
+	}
+
+	return checked, nil
+}
@@ -0,0 +1,200 @@
+package shipmentusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	shipmentusecase "github.com/hieronimusbudi/komodo-backend/usecases/shipment_usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	mockOrder = entity.Order{
+		ID:     1,
+		Buyer:  entity.Buyer{ID: 1},
+		Seller: entity.Seller{ID: 2},
+		Status: entity.ACCEPTED,
+	}
+	mockShipment = entity.Shipment{
+		ID:             4,
+		OrderID:        1,
+		Courier:        "JNE",
+		TrackingNumber: "JNE123",
+		Status:         entity.SHIPMENT_IN_TRANSIT,
+		ShippedAt:      time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	buyer  = helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE}
+	seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+)
+
+func TestShip(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockShipmentRepo.On("Store", mock.Anything, mock.MatchedBy(func(s *entity.Shipment) bool {
+			return s.Status == entity.SHIPMENT_IN_TRANSIT && !s.ShippedAt.IsZero()
+		})).Return(nil).Once()
+		shipped := mockOrder
+		shipped.Status = entity.SHIPPED
+		mockOrders.On("MarkShipped", mock.Anything, &entity.Order{ID: 1}, seller).Return(shipped, nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, mockOrderRepo, mockOrders, new(mocks.CourierTracker))
+		err := u.Ship(context.Background(), &entity.Shipment{OrderID: 1, Courier: "JNE", TrackingNumber: "JNE123"}, seller)
+
+		assert.Nil(t, err)
+		mockShipmentRepo.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("error-not-the-seller", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(new(mocks.ShipmentRepository), mockOrderRepo, new(mocks.OrderUseCase), new(mocks.CourierTracker))
+		err := u.Ship(context.Background(), &entity.Shipment{OrderID: 1}, helpers.UserJWTPayload{ID: 3, Type: helpers.SELLER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+
+	t.Run("error-not-accepted", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		pending := mockOrder
+		pending.Status = entity.PENDING
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(pending, nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(new(mocks.ShipmentRepository), mockOrderRepo, new(mocks.OrderUseCase), new(mocks.CourierTracker))
+		err := u.Ship(context.Background(), &entity.Shipment{OrderID: 1}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+	})
+
+	t.Run("error-already-shipped", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockShipmentRepo.On("Store", mock.Anything, mock.AnythingOfType("*entity.Shipment")).
+			Return(resterrors.NewConflictError("order with id 1 was already shipped")).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, mockOrderRepo, mockOrders, new(mocks.CourierTracker))
+		err := u.Ship(context.Background(), &entity.Shipment{OrderID: 1}, seller)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockOrders.AssertNotCalled(t, "MarkShipped", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByOrderID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+		mockShipmentRepo.On("GetByOrderID", mock.Anything, int64(1)).Return(mockShipment, nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, mockOrderRepo, new(mocks.OrderUseCase), new(mocks.CourierTracker))
+		res, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, buyer)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "JNE123", res.TrackingNumber)
+	})
+
+	t.Run("error-not-a-participant", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Order")).Return(mockOrder, nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(new(mocks.ShipmentRepository), mockOrderRepo, new(mocks.OrderUseCase), new(mocks.CourierTracker))
+		_, err := u.GetByOrderID(context.Background(), &entity.Order{ID: 1}, helpers.UserJWTPayload{ID: 9, Type: helpers.BUYER_TYPE})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+	})
+}
+
+func TestTrackInTransit(t *testing.T) {
+	checkedBefore := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+	deliveredAt := time.Date(2021, 5, 3, 9, 0, 0, 0, time.UTC)
+	pickedUp := entity.TrackingEvent{Code: "PICKED_UP", Description: "Parcel picked up by courier", OccurredAt: mockShipment.ShippedAt}
+	delivered := entity.TrackingEvent{Code: "DELIVERED", Description: "Parcel delivered to recipient", OccurredAt: deliveredAt}
+
+	t.Run("success", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockTracker := new(mocks.CourierTracker)
+		other := mockShipment
+		other.ID, other.OrderID, other.TrackingNumber = 5, 2, "JNE456"
+		mockShipmentRepo.On("GetInTransit", mock.Anything, checkedBefore, 100).Return([]entity.Shipment{mockShipment, other}, nil).Once()
+		mockTracker.On("Track", mock.Anything, "JNE", "JNE123").
+			Return(entity.CourierTracking{Events: []entity.TrackingEvent{pickedUp, delivered}, Delivered: true, DeliveredAt: deliveredAt}, nil).Once()
+		mockTracker.On("Track", mock.Anything, "JNE", "JNE456").
+			Return(entity.CourierTracking{Events: []entity.TrackingEvent{pickedUp}}, nil).Once()
+		mockOrders.On("MarkDelivered", mock.Anything, &entity.Order{ID: 1}).Return(entity.Order{ID: 1, Status: entity.DELIVERED}, nil).Once()
+		mockShipmentRepo.On("SaveTracking", mock.Anything, mock.MatchedBy(func(s *entity.Shipment) bool {
+			return s.ID == 4 && s.Status == entity.SHIPMENT_DELIVERED && deliveredAt.Equal(*s.DeliveredAt) && s.CheckedAt != nil
+		}), []entity.TrackingEvent{pickedUp, delivered}).Return(nil).Once()
+		mockShipmentRepo.On("SaveTracking", mock.Anything, mock.MatchedBy(func(s *entity.Shipment) bool {
+			return s.ID == 5 && s.Status == entity.SHIPMENT_IN_TRANSIT && s.DeliveredAt == nil
+		}), []entity.TrackingEvent{pickedUp}).Return(nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, new(mocks.OrderRepository), mockOrders, mockTracker)
+		res, err := u.TrackInTransit(context.Background(), checkedBefore, 100)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+		assert.Len(t, res[0].Events, 2)
+		mockShipmentRepo.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("order-not-delivered", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockOrders := new(mocks.OrderUseCase)
+		mockTracker := new(mocks.CourierTracker)
+		mockShipmentRepo.On("GetInTransit", mock.Anything, checkedBefore, 100).Return([]entity.Shipment{mockShipment}, nil).Once()
+		mockTracker.On("Track", mock.Anything, "JNE", "JNE123").
+			Return(entity.CourierTracking{Events: []entity.TrackingEvent{delivered}, Delivered: true, DeliveredAt: deliveredAt}, nil).Once()
+		mockOrders.On("MarkDelivered", mock.Anything, &entity.Order{ID: 1}).
+			Return(entity.Order{}, resterrors.NewPreconditionFailedError("order with id 1 is at version 3")).Once()
+		// the shipment stays in transit but is checked, so it doesn't hold back the others
+		mockShipmentRepo.On("SaveTracking", mock.Anything, mock.MatchedBy(func(s *entity.Shipment) bool {
+			return s.ID == 4 && s.Status == entity.SHIPMENT_IN_TRANSIT && s.DeliveredAt == nil && s.CheckedAt != nil
+		}), []entity.TrackingEvent{delivered}).Return(nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, new(mocks.OrderRepository), mockOrders, mockTracker)
+		res, err := u.TrackInTransit(context.Background(), checkedBefore, 100)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+		mockShipmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("courier-error", func(t *testing.T) {
+		mockShipmentRepo := new(mocks.ShipmentRepository)
+		mockTracker := new(mocks.CourierTracker)
+		mockShipmentRepo.On("GetInTransit", mock.Anything, checkedBefore, 100).Return([]entity.Shipment{mockShipment}, nil).Once()
+		mockTracker.On("Track", mock.Anything, "JNE", "JNE123").
+			Return(entity.CourierTracking{}, resterrors.NewBadGatewayError("courier unavailable", nil)).Once()
+		// only CheckedAt is saved so the other shipments are checked first on the next run
+		mockShipmentRepo.On("SaveTracking", mock.Anything, mock.MatchedBy(func(s *entity.Shipment) bool {
+			return s.CheckedAt != nil && s.Status == entity.SHIPMENT_IN_TRANSIT
+		}), []entity.TrackingEvent(nil)).Return(nil).Once()
+
+		u := shipmentusecase.NewShipmentUsecase(mockShipmentRepo, new(mocks.OrderRepository), new(mocks.OrderUseCase), mockTracker)
+		res, err := u.TrackInTransit(context.Background(), checkedBefore, 100)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+		mockShipmentRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,117 @@
+package shippingusecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+)
+
+type shippingUsecase struct {
+	rates       entity.ShippingRateProvider
+	productRepo entity.ProductRepository
+	sellerRepo  entity.SellerRepository
+}
+
+// NewShippingUsecase will create a object with entity.ShippingUseCase interface representation,
+// parcels are sent from the pickup region of the seller saved in sellerRepo
+func NewShippingUsecase(rates entity.ShippingRateProvider, productRepo entity.ProductRepository,
+	sellerRepo entity.SellerRepository) entity.ShippingUseCase {
+	return &shippingUsecase{
+		rates:       rates,
+		productRepo: productRepo,
+		sellerRepo:  sellerRepo,
+	}
+}
+
+func (u *shippingUsecase) Quote(ctx context.Context, quote entity.ShippingQuote) ([]entity.ShippingRate, resterrors.RestErr) {
+	items := []entity.OrderDetail{}
+	for _, od := range quote.Items {
+		p, err := u.productRepo.GetByID(ctx, &entity.Product{ID: od.Product.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 0 && p.Seller.ID != items[0].Product.Seller.ID {
+			return nil, resterrors.NewBadRequestError("items of a shipping quote must be sold by one seller")
+		}
+		items = append(items, entity.OrderDetail{Product: p, Quantity: od.Quantity})
+	}
+	if len(items) == 0 {
+		return nil, resterrors.NewBadRequestError("a shipping quote needs items")
+	}
+
+	origin, err := u.originOf(ctx, items[0].Product.Seller.ID)
+	if err != nil {
+		return nil, err
+	}
+	return u.rates.Rates(ctx, origin, quote.Destination, weightOf(items))
+}
+
+func (u *shippingUsecase) Apply(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	if order.ShippingService == "" {
+		return resterrors.NewBadRequestError("shipping service is required")
+	}
+
+	// parcels leave from where the items are sold, not from the seller the order names
+	sellerID, err := sellerOf(order.Items)
+	if err != nil {
+		return err
+	}
+	origin, err := u.originOf(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+	order.ShippingOrigin = origin
+
+	rates, err := u.rates.Rates(ctx, order.ShippingOrigin, order.ShippingDestination, weightOf(order.Items))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rates {
+		if r.Service == order.ShippingService {
+			order.ShippingFee = r.Fee
+			order.TotalPrice = order.TotalPrice.Add(r.Fee)
+			return nil
+		}
+	}
+	return resterrors.NewBadRequestError(fmt.Sprintf("shipping service %s can't ship this order", order.ShippingService))
+}
+
+// originOf returns the pickup region of the seller, a seller without one can't ship
+func (u *shippingUsecase) originOf(ctx context.Context, sellerID int64) (entity.ShippingAddress, resterrors.RestErr) {
+	seller := entity.Seller{ID: sellerID}
+	if err := u.sellerRepo.GetByID(ctx, &seller); err != nil {
+		return entity.ShippingAddress{}, err
+	}
+
+	origin := seller.PickUpRegion()
+	if origin.Province == "" && origin.PostalCode == "" {
+		return entity.ShippingAddress{}, resterrors.NewBadRequestError(fmt.Sprintf("seller with id %d has no pickup region to ship from", sellerID))
+	}
+	return origin, nil
+}
+
+// sellerOf returns the seller of items, they must all be sold by the same one
+func sellerOf(items []entity.OrderDetail) (int64, resterrors.RestErr) {
+	if len(items) == 0 {
+		return 0, resterrors.NewBadRequestError("an order needs items to be shipped")
+	}
+	sellerID := items[0].Product.Seller.ID
+	for _, od := range items[1:] {
+		if od.Product.Seller.ID != sellerID {
+			return 0, resterrors.NewBadRequestError("items of an order must be sold by one seller")
+		}
+	}
+	return sellerID, nil
+}
+
+// weightOf returns the weight of items in grams
+func weightOf(items []entity.OrderDetail) int64 {
+	weight := int64(0)
+	for _, od := range items {
+		weight += od.Product.Weight * od.Quantity
+	}
+	return weight
+}
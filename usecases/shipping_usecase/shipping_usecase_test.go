@@ -0,0 +1,197 @@
+package shippingusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	shippingusecase "github.com/hieronimusbudi/komodo-backend/usecases/shipping_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	origin      = entity.ShippingAddress{Province: "DKI Jakarta", PostalCode: "12190"}
+	destination = entity.ShippingAddress{Province: "Jawa Barat"}
+	mockRates   = []entity.ShippingRate{
+		{Service: "JNE_REG", Carrier: "JNE", Fee: decimal.NewFromInt(20000), Weight: 1700, EstimatedDays: 4},
+		{Service: "JNE_YES", Carrier: "JNE", Fee: decimal.NewFromInt(46000), Weight: 1700, EstimatedDays: 1},
+	}
+)
+
+// sellers returns a seller repository with seller 1 picking up at origin and seller 2 without a pickup region
+func sellers() *mocks.SellerRepository {
+	mockSellerRepo := new(mocks.SellerRepository)
+	mockSellerRepo.On("GetByID", mock.Anything, mock.MatchedBy(func(s *entity.Seller) bool { return s.ID == 1 })).
+		Return(nil).Run(func(args mock.Arguments) {
+		s := args.Get(1).(*entity.Seller)
+		s.PickUpProvince = origin.Province
+		s.PickUpPostalCode = origin.PostalCode
+	})
+	mockSellerRepo.On("GetByID", mock.Anything, mock.MatchedBy(func(s *entity.Seller) bool { return s.ID == 2 })).Return(nil)
+	return mockSellerRepo
+}
+
+func TestQuote(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 1}).
+			Return(entity.Product{ID: 1, Weight: 500, Seller: entity.Seller{ID: 1}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 2}).
+			Return(entity.Product{ID: 2, Weight: 700, Seller: entity.Seller{ID: 1}}, nil).Once()
+		mockProvider.On("Rates", mock.Anything, origin, destination, int64(1700)).Return(mockRates, nil).Once()
+
+		u := shippingusecase.NewShippingUsecase(mockProvider, mockProductRepo, sellers())
+		rates, err := u.Quote(context.Background(), entity.ShippingQuote{
+			Destination: destination,
+			Items: []entity.OrderDetail{
+				{Product: entity.Product{ID: 1}, Quantity: 2},
+				{Product: entity.Product{ID: 2}, Quantity: 1},
+			},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, mockRates, rates)
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("product-not-found", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 9}).
+			Return(entity.Product{}, resterrors.NewNotFoundError("product with id 9 not found")).Once()
+
+		u := shippingusecase.NewShippingUsecase(mockProvider, mockProductRepo, sellers())
+		_, err := u.Quote(context.Background(), entity.ShippingQuote{Items: []entity.OrderDetail{{Product: entity.Product{ID: 9}, Quantity: 1}}})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		mockProvider.AssertNotCalled(t, "Rates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("several-sellers", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 1}).
+			Return(entity.Product{ID: 1, Weight: 500, Seller: entity.Seller{ID: 1}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 3}).
+			Return(entity.Product{ID: 3, Weight: 500, Seller: entity.Seller{ID: 2}}, nil).Once()
+
+		u := shippingusecase.NewShippingUsecase(mockProvider, mockProductRepo, sellers())
+		_, err := u.Quote(context.Background(), entity.ShippingQuote{
+			Destination: destination,
+			Items: []entity.OrderDetail{
+				{Product: entity.Product{ID: 1}, Quantity: 1},
+				{Product: entity.Product{ID: 3}, Quantity: 1},
+			},
+		})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockProvider.AssertNotCalled(t, "Rates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestApply(t *testing.T) {
+	newOrder := func(service string) entity.Order {
+		return entity.Order{
+			Seller:              entity.Seller{ID: 1},
+			TotalPrice:          decimal.NewFromInt(100000),
+			ShippingService:     service,
+			ShippingDestination: destination,
+			Items: []entity.OrderDetail{
+				{Product: entity.Product{ID: 1, Weight: 500, Seller: entity.Seller{ID: 1}}, Quantity: 2},
+				{Product: entity.Product{ID: 2, Weight: 700, Seller: entity.Seller{ID: 1}}, Quantity: 1},
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProvider.On("Rates", mock.Anything, origin, destination, int64(1700)).Return(mockRates, nil).Once()
+
+		order := newOrder("JNE_YES")
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.Nil(t, err)
+		assert.Equal(t, origin, order.ShippingOrigin)
+		assert.True(t, decimal.NewFromInt(46000).Equal(order.ShippingFee))
+		assert.True(t, decimal.NewFromInt(146000).Equal(order.TotalPrice))
+		assert.True(t, decimal.NewFromInt(100000).Equal(order.Subtotal()))
+	})
+
+	t.Run("service-not-available", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProvider.On("Rates", mock.Anything, origin, destination, int64(1700)).Return(mockRates, nil).Once()
+
+		order := newOrder("JNE_JTR")
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		assert.True(t, order.ShippingFee.IsZero())
+	})
+
+	t.Run("no-service", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+
+		order := newOrder("")
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockProvider.AssertNotCalled(t, "Rates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("seller-without-pickup-region", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+
+		order := newOrder("JNE_REG")
+		order.Seller.ID = 2
+		order.Items[0].Product.Seller.ID = 2
+		order.Items[1].Product.Seller.ID = 2
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		assert.True(t, order.ShippingFee.IsZero())
+		mockProvider.AssertNotCalled(t, "Rates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("origin-of-the-items", func(t *testing.T) {
+		// the seller named by the order doesn't choose where the items ship from
+		mockProvider := new(mocks.ShippingRateProvider)
+		mockProvider.On("Rates", mock.Anything, origin, destination, int64(1700)).Return(mockRates, nil).Once()
+
+		order := newOrder("JNE_YES")
+		order.Seller.ID = 2
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.Nil(t, err)
+		assert.Equal(t, origin, order.ShippingOrigin)
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("items-of-several-sellers", func(t *testing.T) {
+		mockProvider := new(mocks.ShippingRateProvider)
+
+		order := newOrder("JNE_YES")
+		order.Items[1].Product.Seller.ID = 2
+		u := shippingusecase.NewShippingUsecase(mockProvider, new(mocks.ProductRepository), sellers())
+		err := u.Apply(context.Background(), &order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+		mockProvider.AssertNotCalled(t, "Rates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,83 @@
+package taxusecase
+
+import (
+	"context"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type taxUsecase struct {
+	policy     entity.TaxPolicy
+	sellerRepo entity.SellerRepository
+}
+
+// NewTaxUsecase will create a object with entity.TaxUseCase interface representation
+func NewTaxUsecase(policy entity.TaxPolicy, sellerRepo entity.SellerRepository) entity.TaxUseCase {
+	return &taxUsecase{
+		policy:     policy,
+		sellerRepo: sellerRepo,
+	}
+}
+
+func (u *taxUsecase) Apply(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	shares := discountShares(order.Items, order.Discount)
+	inclusive := u.policy.Inclusive()
+	sellers := map[int64]entity.Seller{}
+
+	tax := decimal.Zero
+	for i := range order.Items {
+		od := &order.Items[i]
+		seller, ok := sellers[od.Product.Seller.ID]
+		if !ok {
+			seller = entity.Seller{ID: od.Product.Seller.ID}
+			if err := u.sellerRepo.GetByID(ctx, &seller); err != nil {
+				return err
+			}
+			sellers[seller.ID] = seller
+		}
+
+		od.Discount = shares[i]
+		od.TaxRate = u.policy.Rate(seller, od.Product)
+		base := od.Price.Mul(decimal.NewFromInt(od.Quantity)).Sub(od.Discount)
+		if inclusive {
+			// the price already holds the tax, take it out of it
+			od.Tax = u.policy.Round(base.Mul(od.TaxRate).Div(decimal.NewFromInt(1).Add(od.TaxRate)))
+		} else {
+			od.Tax = u.policy.Round(base.Mul(od.TaxRate))
+		}
+		tax = tax.Add(od.Tax)
+	}
+
+	order.Tax = tax
+	order.TaxInclusive = inclusive
+	if !inclusive {
+		order.TotalPrice = order.TotalPrice.Add(tax)
+	}
+	return nil
+}
+
+// discountShares splits discount between items by the price of their lines, rounded to cents.
+// The last item gets what is left so the shares always add up to discount
+func discountShares(items []entity.OrderDetail, discount decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(items))
+	total := decimal.Zero
+	for _, od := range items {
+		total = total.Add(od.Price.Mul(decimal.NewFromInt(od.Quantity)))
+	}
+	if discount.IsZero() || total.IsZero() {
+		return shares
+	}
+
+	left := discount
+	for i, od := range items {
+		if i == len(items)-1 {
+			shares[i] = left
+			break
+		}
+		shares[i] = discount.Mul(od.Price.Mul(decimal.NewFromInt(od.Quantity))).Div(total).Round(2)
+		left = left.Sub(shares[i])
+	}
+	return shares
+}
@@ -0,0 +1,133 @@
+package taxusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	taxusecase "github.com/hieronimusbudi/komodo-backend/usecases/tax_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	rate    = decimal.RequireFromString("0.11")
+	book    = entity.Product{ID: 1, Category: "books", Seller: entity.Seller{ID: 1}}
+	lamp    = entity.Product{ID: 2, Category: "home", Seller: entity.Seller{ID: 1}}
+	sticker = entity.Product{ID: 3, Seller: entity.Seller{ID: 2}}
+)
+
+func newOrder() *entity.Order {
+	return &entity.Order{
+		// 3 x 10000 + 1 x 20000 + 1 x 50000 with a 10000 discount
+		TotalPrice: decimal.NewFromInt(90000),
+		Discount:   decimal.NewFromInt(10000),
+		Items: []entity.OrderDetail{
+			{Product: book, Quantity: 3, Price: decimal.NewFromInt(10000)},
+			{Product: lamp, Quantity: 1, Price: decimal.NewFromInt(20000)},
+			{Product: sticker, Quantity: 1, Price: decimal.NewFromInt(50000)},
+		},
+	}
+}
+
+func newPolicy(inclusive bool) *mocks.TaxPolicy {
+	mockPolicy := new(mocks.TaxPolicy)
+	mockPolicy.On("Inclusive").Return(inclusive)
+	mockPolicy.On("Rate", mock.MatchedBy(func(s entity.Seller) bool { return s.TaxStatus == entity.TAX_REGISTERED }), mock.Anything).Return(rate)
+	mockPolicy.On("Rate", mock.MatchedBy(func(s entity.Seller) bool { return s.TaxStatus == entity.TAX_UNREGISTERED }), mock.Anything).Return(decimal.Zero)
+	mockPolicy.On("Round", mock.Anything).Return(func(amount decimal.Decimal) decimal.Decimal { return amount.Round(2) })
+	return mockPolicy
+}
+
+func newSellerRepo() *mocks.SellerRepository {
+	mockSellerRepo := new(mocks.SellerRepository)
+	mockSellerRepo.On("GetByID", mock.Anything, &entity.Seller{ID: 1}).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.Seller).TaxStatus = entity.TAX_REGISTERED
+	}).Once()
+	mockSellerRepo.On("GetByID", mock.Anything, &entity.Seller{ID: 2}).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.Seller).TaxStatus = entity.TAX_UNREGISTERED
+	}).Once()
+	return mockSellerRepo
+}
+
+func TestApply(t *testing.T) {
+	t.Run("exclusive", func(t *testing.T) {
+		mockSellerRepo := newSellerRepo()
+		u := taxusecase.NewTaxUsecase(newPolicy(false), mockSellerRepo)
+
+		order := newOrder()
+		err := u.Apply(context.Background(), order)
+
+		assert.Nil(t, err)
+		// the discount is split 3000, 2000 and 5000 between the lines
+		assert.Equal(t, "3000.00", order.Items[0].Discount.StringFixed(2))
+		assert.Equal(t, "2000.00", order.Items[1].Discount.StringFixed(2))
+		assert.Equal(t, "5000.00", order.Items[2].Discount.StringFixed(2))
+		assert.Equal(t, "2970.00", order.Items[0].Tax.StringFixed(2))
+		assert.Equal(t, "1980.00", order.Items[1].Tax.StringFixed(2))
+		assert.True(t, rate.Equal(order.Items[0].TaxRate))
+		assert.True(t, order.Items[2].Tax.IsZero())
+		assert.True(t, order.Items[2].TaxRate.IsZero())
+		assert.Equal(t, "4950.00", order.Tax.StringFixed(2))
+		assert.False(t, order.TaxInclusive)
+		assert.Equal(t, "94950.00", order.TotalPrice.StringFixed(2))
+		assert.Equal(t, "100000.00", order.Subtotal().StringFixed(2))
+		// a seller is only loaded once
+		mockSellerRepo.AssertExpectations(t)
+	})
+
+	t.Run("inclusive", func(t *testing.T) {
+		u := taxusecase.NewTaxUsecase(newPolicy(true), newSellerRepo())
+
+		order := newOrder()
+		err := u.Apply(context.Background(), order)
+
+		assert.Nil(t, err)
+		// 27000 * 0.11 / 1.11 and 18000 * 0.11 / 1.11
+		assert.Equal(t, "2675.68", order.Items[0].Tax.StringFixed(2))
+		assert.Equal(t, "1783.78", order.Items[1].Tax.StringFixed(2))
+		assert.Equal(t, "4459.46", order.Tax.StringFixed(2))
+		assert.True(t, order.TaxInclusive)
+		assert.Equal(t, "90000.00", order.TotalPrice.StringFixed(2))
+		assert.Equal(t, "100000.00", order.Subtotal().StringFixed(2))
+	})
+
+	t.Run("uneven-discount", func(t *testing.T) {
+		u := taxusecase.NewTaxUsecase(newPolicy(false), newSellerRepo())
+
+		order := &entity.Order{
+			TotalPrice: decimal.NewFromInt(29900),
+			Discount:   decimal.NewFromInt(100),
+			Items: []entity.OrderDetail{
+				{Product: book, Quantity: 1, Price: decimal.NewFromInt(10000)},
+				{Product: lamp, Quantity: 2, Price: decimal.NewFromInt(10000)},
+			},
+		}
+		err := u.Apply(context.Background(), order)
+
+		assert.Nil(t, err)
+		// shares of 33.33 and 66.67
+		assert.Equal(t, "1096.33", order.Items[0].Tax.StringFixed(2))
+		assert.Equal(t, "2192.67", order.Items[1].Tax.StringFixed(2))
+		assert.Equal(t, "3289.00", order.Tax.StringFixed(2))
+	})
+
+	t.Run("seller-not-found", func(t *testing.T) {
+		mockSellerRepo := new(mocks.SellerRepository)
+		mockSellerRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Seller")).
+			Return(resterrors.NewNotFoundError("seller not found")).Once()
+		u := taxusecase.NewTaxUsecase(newPolicy(false), mockSellerRepo)
+
+		order := newOrder()
+		err := u.Apply(context.Background(), order)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.True(t, order.Tax.IsZero())
+		assert.Equal(t, "90000.00", order.TotalPrice.StringFixed(2))
+	})
+}
@@ -0,0 +1,94 @@
+package voucherusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type voucherUsecase struct {
+	voucherRepo entity.VoucherRepository
+}
+
+// NewVoucherUsecase will create a object with entity.VoucherUseCase interface representation
+func NewVoucherUsecase(voucherRepo entity.VoucherRepository) entity.VoucherUseCase {
+	return &voucherUsecase{voucherRepo: voucherRepo}
+}
+
+func (u *voucherUsecase) Store(ctx context.Context, voucher *entity.Voucher, user helpers.UserJWTPayload) resterrors.RestErr {
+	switch user.Type {
+	case helpers.SELLER_TYPE:
+		voucher.SellerID = user.ID
+	case helpers.ADMIN_TYPE:
+	default:
+		return resterrors.NewForbiddenError("only sellers and admins can create vouchers")
+	}
+
+	if voucher.Type == entity.VOUCHER_PERCENTAGE && voucher.Value.GreaterThan(decimal.NewFromInt(100)) {
+		return resterrors.NewBadRequestError("a percentage voucher can't take off more than 100")
+	}
+	if !voucher.EndsAt.After(voucher.StartsAt) {
+		return resterrors.NewBadRequestError("endsAt must be after startsAt")
+	}
+
+	tn, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return resterrors.NewInternalServerError("error when trying to save data", tErr)
+	}
+	// codes are typed by buyers, they are matched without case
+	voucher.Code = strings.ToUpper(voucher.Code)
+	voucher.UsedCount = 0
+	voucher.CreatedAt = tn
+
+	return u.voucherRepo.Store(ctx, voucher)
+}
+
+func (u *voucherUsecase) Fetch(ctx context.Context, user helpers.UserJWTPayload) ([]entity.Voucher, resterrors.RestErr) {
+	switch user.Type {
+	case helpers.SELLER_TYPE:
+		return u.voucherRepo.GetBySellerID(ctx, user.ID)
+	case helpers.ADMIN_TYPE:
+		return u.voucherRepo.GetAll(ctx)
+	}
+	return nil, resterrors.NewForbiddenError("only sellers and admins can list vouchers")
+}
+
+func (u *voucherUsecase) Apply(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	code := strings.ToUpper(order.VoucherCode)
+	voucher, err := u.voucherRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, resterrors.ErrNotFound) {
+			return resterrors.NewBadRequestError(fmt.Sprintf("voucher %s doesn't exist", code))
+		}
+		return err
+	}
+
+	if voucher.SellerID != 0 && voucher.SellerID != order.Seller.ID {
+		return resterrors.NewBadRequestError(fmt.Sprintf("voucher %s can't be used for orders of this seller", code))
+	}
+	if order.OrderDate.Before(voucher.StartsAt) || !order.OrderDate.Before(voucher.EndsAt) {
+		return resterrors.NewBadRequestError(fmt.Sprintf("voucher %s can't be used now", code))
+	}
+
+	subtotal := order.Subtotal()
+	if subtotal.LessThan(voucher.MinSpend) {
+		return resterrors.NewBadRequestError(fmt.Sprintf("voucher %s needs a minimum spend of %s", code, voucher.MinSpend.StringFixed(2)))
+	}
+
+	order.VoucherID = voucher.ID
+	order.VoucherCode = voucher.Code
+	order.Discount = voucher.Discount(subtotal)
+	order.TotalPrice = subtotal.Sub(order.Discount)
+	return nil
+}
+
+// OrderCancelled gives the use of a voucher back to the buyer when their order is cancelled or rejected
+func (u *voucherUsecase) OrderCancelled(ctx context.Context, order *entity.Order) resterrors.RestErr {
+	return u.voucherRepo.Release(ctx, order.ID)
+}
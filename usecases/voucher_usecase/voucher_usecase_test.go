@@ -0,0 +1,178 @@
+package voucherusecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	voucherusecase "github.com/hieronimusbudi/komodo-backend/usecases/voucher_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	startsAt    = time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	endsAt      = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	mockVoucher = entity.Voucher{
+		ID:          3,
+		Code:        "HEMAT10",
+		Type:        entity.VOUCHER_PERCENTAGE,
+		Value:       decimal.NewFromInt(10),
+		SellerID:    2,
+		MinSpend:    decimal.NewFromInt(50000),
+		MaxDiscount: decimal.NewFromInt(25000),
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	}
+	seller = helpers.UserJWTPayload{ID: 2, Type: helpers.SELLER_TYPE}
+)
+
+func orderOf(totalPrice float64, orderDate time.Time) entity.Order {
+	return entity.Order{
+		Seller:      entity.Seller{ID: 2},
+		TotalPrice:  decimal.NewFromFloat(totalPrice),
+		OrderDate:   orderDate,
+		VoucherCode: "hemat10",
+	}
+}
+
+func TestApply(t *testing.T) {
+	orderDate := time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)
+	fixed := mockVoucher
+	fixed.Type = entity.VOUCHER_FIXED
+	fixed.Value = decimal.NewFromInt(100000)
+	fixed.MaxDiscount = decimal.Zero
+	platform := mockVoucher
+	platform.SellerID = 0
+
+	testCases := []struct {
+		name         string
+		voucher      entity.Voucher
+		order        entity.Order
+		wantDiscount decimal.Decimal
+		wantStatus   int
+	}{
+		{name: "percentage", voucher: mockVoucher, order: orderOf(120000.55, orderDate), wantDiscount: decimal.NewFromFloat(12000.06)},
+		{name: "percentage capped", voucher: mockVoucher, order: orderOf(300000, orderDate), wantDiscount: decimal.NewFromInt(25000)},
+		{name: "fixed not more than subtotal", voucher: fixed, order: orderOf(60000, orderDate), wantDiscount: decimal.NewFromInt(60000)},
+		{name: "platform voucher", voucher: platform, order: entity.Order{Seller: entity.Seller{ID: 9}, TotalPrice: decimal.NewFromInt(100000),
+			OrderDate: orderDate, VoucherCode: "HEMAT10"}, wantDiscount: decimal.NewFromInt(10000)},
+		{name: "other seller", voucher: mockVoucher, order: entity.Order{Seller: entity.Seller{ID: 9}, TotalPrice: decimal.NewFromInt(100000),
+			OrderDate: orderDate, VoucherCode: "HEMAT10"}, wantStatus: http.StatusBadRequest},
+		{name: "not started", voucher: mockVoucher, order: orderOf(100000, startsAt.Add(-time.Second)), wantStatus: http.StatusBadRequest},
+		{name: "ended", voucher: mockVoucher, order: orderOf(100000, endsAt), wantStatus: http.StatusBadRequest},
+		{name: "under min spend", voucher: mockVoucher, order: orderOf(49999.99, orderDate), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockVoucherRepo := new(mocks.VoucherRepository)
+			mockVoucherRepo.On("GetByCode", mock.Anything, "HEMAT10").Return(tc.voucher, nil).Once()
+
+			order := tc.order
+			subtotal := order.TotalPrice
+			u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+			err := u.Apply(context.Background(), &order)
+
+			if tc.wantStatus != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tc.wantStatus, err.Status())
+				assert.Equal(t, int64(0), order.VoucherID)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.voucher.ID, order.VoucherID)
+			assert.Equal(t, "HEMAT10", order.VoucherCode)
+			assert.True(t, tc.wantDiscount.Equal(order.Discount), order.Discount.String())
+			assert.True(t, subtotal.Sub(tc.wantDiscount).Equal(order.TotalPrice))
+			assert.True(t, subtotal.Equal(order.Subtotal()))
+		})
+	}
+
+	t.Run("not-found", func(t *testing.T) {
+		mockVoucherRepo := new(mocks.VoucherRepository)
+		mockVoucherRepo.On("GetByCode", mock.Anything, "HEMAT10").Return(entity.Voucher{}, resterrors.NewNotFoundError("voucher not found")).Once()
+
+		order := orderOf(100000, orderDate)
+		u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+		err := u.Apply(context.Background(), &order)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("seller", func(t *testing.T) {
+		mockVoucherRepo := new(mocks.VoucherRepository)
+		mockVoucherRepo.On("Store", mock.Anything, mock.MatchedBy(func(v *entity.Voucher) bool {
+			return v.SellerID == seller.ID && v.Code == "HEMAT10" && v.UsedCount == 0
+		})).Return(nil).Once()
+
+		voucher := mockVoucher
+		voucher.Code = "hemat10"
+		voucher.SellerID = 9
+		voucher.UsedCount = 4
+		u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+		err := u.Store(context.Background(), &voucher, seller)
+
+		assert.NoError(t, err)
+		mockVoucherRepo.AssertExpectations(t)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		mockVoucherRepo := new(mocks.VoucherRepository)
+		mockVoucherRepo.On("Store", mock.Anything, mock.MatchedBy(func(v *entity.Voucher) bool {
+			return v.SellerID == 0
+		})).Return(nil).Once()
+
+		voucher := mockVoucher
+		voucher.SellerID = 0
+		u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+		err := u.Store(context.Background(), &voucher, helpers.UserJWTPayload{ID: 1, Type: helpers.ADMIN_TYPE})
+
+		assert.NoError(t, err)
+		mockVoucherRepo.AssertExpectations(t)
+	})
+
+	t.Run("buyer", func(t *testing.T) {
+		mockVoucherRepo := new(mocks.VoucherRepository)
+
+		voucher := mockVoucher
+		u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+		err := u.Store(context.Background(), &voucher, helpers.UserJWTPayload{ID: 1, Type: helpers.BUYER_TYPE})
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, err.Status())
+		mockVoucherRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("percentage-over-100", func(t *testing.T) {
+		mockVoucherRepo := new(mocks.VoucherRepository)
+
+		voucher := mockVoucher
+		voucher.Value = decimal.NewFromInt(101)
+		u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+		err := u.Store(context.Background(), &voucher, seller)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.Status())
+	})
+}
+
+func TestOrderCancelled(t *testing.T) {
+	mockVoucherRepo := new(mocks.VoucherRepository)
+	mockVoucherRepo.On("Release", mock.Anything, int64(1)).Return(nil).Once()
+
+	u := voucherusecase.NewVoucherUsecase(mockVoucherRepo)
+	err := u.OrderCancelled(context.Background(), &entity.Order{ID: 1})
+
+	assert.NoError(t, err)
+	mockVoucherRepo.AssertExpectations(t)
+}
@@ -0,0 +1,119 @@
+package wishlistusecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/framework/helpers"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	"github.com/shopspring/decimal"
+)
+
+type wishlistUsecase struct {
+	wishlistRepo entity.WishlistRepository
+	productRepo  entity.ProductRepository
+	sellerRepo   entity.SellerRepository
+	publisher    entity.EventPublisher
+}
+
+// NewWishlistUsecase will create a object with entity.WishlistUseCase interface representation,
+// publisher tells buyers about price drops and sellers about their new followers
+func NewWishlistUsecase(w entity.WishlistRepository, p entity.ProductRepository, s entity.SellerRepository,
+	publisher entity.EventPublisher) entity.WishlistUseCase {
+	return &wishlistUsecase{
+		wishlistRepo: w,
+		productRepo:  p,
+		sellerRepo:   s,
+		publisher:    publisher,
+	}
+}
+
+func (u *wishlistUsecase) AddItem(ctx context.Context, buyerID, productID int64) (entity.WishlistItem, resterrors.RestErr) {
+	product, err := u.productRepo.GetByID(ctx, &entity.Product{ID: productID})
+	if err != nil {
+		return entity.WishlistItem{}, err
+	}
+
+	now, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.WishlistItem{}, resterrors.NewInternalServerError("error when trying to get time", tErr)
+	}
+
+	item := entity.WishlistItem{BuyerID: buyerID, Product: product, AddedAt: now}
+	if err := u.wishlistRepo.StoreItem(ctx, &item); err != nil {
+		return entity.WishlistItem{}, err
+	}
+	return item, nil
+}
+
+func (u *wishlistUsecase) GetItems(ctx context.Context, buyerID int64) ([]entity.WishlistItem, resterrors.RestErr) {
+	return u.wishlistRepo.GetItemsByBuyerID(ctx, buyerID)
+}
+
+func (u *wishlistUsecase) RemoveItem(ctx context.Context, buyerID, productID int64) resterrors.RestErr {
+	return u.wishlistRepo.DeleteItem(ctx, buyerID, productID)
+}
+
+func (u *wishlistUsecase) Follow(ctx context.Context, buyerID, sellerID int64) (entity.FollowedSeller, resterrors.RestErr) {
+	seller := entity.Seller{ID: sellerID}
+	if err := u.sellerRepo.GetByID(ctx, &seller); err != nil {
+		return entity.FollowedSeller{}, err
+	}
+
+	now, tErr := helpers.GetTimeNow()
+	if tErr != nil {
+		return entity.FollowedSeller{}, resterrors.NewInternalServerError("error when trying to get time", tErr)
+	}
+
+	followed := entity.FollowedSeller{BuyerID: buyerID, Seller: entity.Seller{ID: seller.ID, Name: seller.Name}, FollowedAt: now}
+	if err := u.wishlistRepo.StoreFollowedSeller(ctx, &followed); err != nil {
+		return entity.FollowedSeller{}, err
+	}
+
+	event := entity.Event{
+		Name:       entity.SELLER_FOLLOWED_EVENT,
+		OccurredAt: time.Now().UTC(),
+		Payload:    entity.FollowEventPayload{BuyerID: buyerID, SellerID: sellerID},
+	}
+	if pubErr := u.publisher.Publish(ctx, event); pubErr != nil {
+		log.Println("follow event publish error", sellerID, pubErr)
+	}
+	return followed, nil
+}
+
+func (u *wishlistUsecase) GetFollowing(ctx context.Context, buyerID int64) ([]entity.FollowedSeller, resterrors.RestErr) {
+	return u.wishlistRepo.GetFollowedSellersByBuyerID(ctx, buyerID)
+}
+
+func (u *wishlistUsecase) Unfollow(ctx context.Context, buyerID, sellerID int64) resterrors.RestErr {
+	return u.wishlistRepo.DeleteFollowedSeller(ctx, buyerID, sellerID)
+}
+
+func (u *wishlistUsecase) PriceDropped(ctx context.Context, product entity.Product, oldPrice decimal.Decimal) resterrors.RestErr {
+	buyerIDs, err := u.wishlistRepo.GetBuyerIDsByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	// an event is published for each buyer, one failing doesn't keep the others from being told
+	for _, buyerID := range buyerIDs {
+		event := entity.Event{
+			Name:       entity.WISHLIST_PRICE_DROPPED_EVENT,
+			OccurredAt: time.Now().UTC(),
+			Payload: entity.PriceDropEventPayload{
+				BuyerID:   buyerID,
+				ProductID: product.ID,
+				SellerID:  product.Seller.ID,
+				Name:      product.Name,
+				OldPrice:  oldPrice,
+				NewPrice:  product.Price,
+			},
+		}
+		if pubErr := u.publisher.Publish(ctx, event); pubErr != nil {
+			log.Println("price drop event publish error", product.ID, buyerID, pubErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package wishlistusecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hieronimusbudi/komodo-backend/entity"
+	"github.com/hieronimusbudi/komodo-backend/entity/mocks"
+	resterrors "github.com/hieronimusbudi/komodo-backend/framework/helpers/rest_errors"
+	wishlistusecase "github.com/hieronimusbudi/komodo-backend/usecases/wishlist_usecase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddItem(t *testing.T) {
+	product := entity.Product{ID: 7, Name: "Kopi Toraja", Price: decimal.NewFromInt(50000), Seller: entity.Seller{ID: 1}}
+
+	t.Run("success", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 7}).Return(product, nil).Once()
+		mockWishlistRepo.On("StoreItem", mock.Anything, mock.MatchedBy(func(item *entity.WishlistItem) bool {
+			return item.BuyerID == 3 && item.Product.ID == 7 && !item.AddedAt.IsZero()
+		})).Return(nil).Once()
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, mockProductRepo, new(mocks.SellerRepository), new(mocks.EventPublisher))
+		res, err := u.AddItem(context.Background(), 3, 7)
+
+		assert.Nil(t, err)
+		assert.Equal(t, product, res.Product)
+		mockWishlistRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("error product not found", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("GetByID", mock.Anything, &entity.Product{ID: 8}).
+			Return(entity.Product{}, resterrors.NewNotFoundError("product with id 8 not found")).Once()
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, mockProductRepo, new(mocks.SellerRepository), new(mocks.EventPublisher))
+		_, err := u.AddItem(context.Background(), 3, 8)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		mockWishlistRepo.AssertNotCalled(t, "StoreItem", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFollow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockSellerRepo := new(mocks.SellerRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockSellerRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Seller).Name = "john seller"
+		}).Once()
+		mockWishlistRepo.On("StoreFollowedSeller", mock.Anything, mock.AnythingOfType("*entity.FollowedSeller")).Return(nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+			return e.Name == entity.SELLER_FOLLOWED_EVENT && e.Payload == entity.FollowEventPayload{BuyerID: 3, SellerID: 1}
+		})).Return(nil).Once()
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, new(mocks.ProductRepository), mockSellerRepo, mockPublisher)
+		res, err := u.Follow(context.Background(), 3, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.Seller{ID: 1, Name: "john seller"}, res.Seller)
+		mockWishlistRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("error already followed", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockSellerRepo := new(mocks.SellerRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockSellerRepo.On("GetByID", mock.Anything, mock.AnythingOfType("*entity.Seller")).Return(nil).Once()
+		mockWishlistRepo.On("StoreFollowedSeller", mock.Anything, mock.AnythingOfType("*entity.FollowedSeller")).
+			Return(resterrors.NewConflictError("seller with id 1 is already followed")).Once()
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, new(mocks.ProductRepository), mockSellerRepo, mockPublisher)
+		_, err := u.Follow(context.Background(), 3, 1)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusConflict, err.Status())
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPriceDropped(t *testing.T) {
+	product := entity.Product{ID: 7, Name: "Kopi Toraja", Price: decimal.NewFromInt(45000), Seller: entity.Seller{ID: 1}}
+	oldPrice := decimal.NewFromInt(50000)
+
+	t.Run("success", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockWishlistRepo.On("GetBuyerIDsByProductID", mock.Anything, int64(7)).Return([]int64{3, 5}, nil).Once()
+		for _, buyerID := range []int64{3, 5} {
+			payload := entity.PriceDropEventPayload{BuyerID: buyerID, ProductID: 7, SellerID: 1, Name: "Kopi Toraja",
+				OldPrice: oldPrice, NewPrice: product.Price}
+			mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e entity.Event) bool {
+				return e.Name == entity.WISHLIST_PRICE_DROPPED_EVENT && e.Payload == payload
+			})).Return(nil).Once()
+		}
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, new(mocks.ProductRepository), new(mocks.SellerRepository), mockPublisher)
+		err := u.PriceDropped(context.Background(), product, oldPrice)
+
+		assert.Nil(t, err)
+		mockWishlistRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("success publish error", func(t *testing.T) {
+		mockWishlistRepo := new(mocks.WishlistRepository)
+		mockPublisher := new(mocks.EventPublisher)
+		mockWishlistRepo.On("GetBuyerIDsByProductID", mock.Anything, int64(7)).Return([]int64{3, 5}, nil).Once()
+		mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("entity.Event")).
+			Return(resterrors.NewInternalServerError("error when trying to publish event", errors.New("broker down"))).Twice()
+
+		u := wishlistusecase.NewWishlistUsecase(mockWishlistRepo, new(mocks.ProductRepository), new(mocks.SellerRepository), mockPublisher)
+		err := u.PriceDropped(context.Background(), product, oldPrice)
+
+		// every buyer is still tried
+		assert.Nil(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+}